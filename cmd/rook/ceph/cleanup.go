@@ -19,6 +19,7 @@ package ceph
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/rook/rook/cmd/rook/rook"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -174,7 +175,13 @@ func startRadosNamespaceCleanup(cmd *cobra.Command, args []string) error {
 		rook.TerminateFatal(fmt.Errorf("cephblockpool radosNamespace is not available in the pod environment variables"))
 	}
 
-	err := cleanup.RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace)
+	// the order is not set by operators that predate the tiered clean up
+	var order []string
+	if value := os.Getenv(opcontroller.CephBlockPoolRadosNamespaceCleanupOrderEnv); value != "" {
+		order = strings.Split(value, ",")
+	}
+
+	err := cleanup.RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, order)
 	if err != nil {
 		rook.TerminateFatal(fmt.Errorf("failed to cleanup cephBlockPoolRadosNamespace %q resources in the pool %q. %v", radosNamespace, poolName, err))
 	}
@@ -16,9 +16,17 @@ limitations under the License.
 
 package v1
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 const (
 	ImplicitNamespaceKey = "<implicit>"
 	ImplicitNamespaceVal = ""
+	// RadosNamespaceDescriptionKey is the application metadata key of the description of a rados namespace
+	RadosNamespaceDescriptionKey = "description"
 )
 
 func GetRadosNamespaceName(cephBlockPoolRadosNamespace *CephBlockPoolRadosNamespace) string {
@@ -29,3 +37,123 @@ func GetRadosNamespaceName(cephBlockPoolRadosNamespace *CephBlockPoolRadosNamesp
 	}
 	return cephBlockPoolRadosNamespace.Name
 }
+
+// GetMirroringPeerDirections returns the mirroring directions of the peers of a pool requested by its rados
+// namespaces: the direction of all the peers and the direction of the peers of each site. Only the directions
+// set in the specs are returned. The peers belong to the pool, so an error is returned if the rados namespaces
+// request different directions for the same peers.
+func GetMirroringPeerDirections(radosNamespaces []CephBlockPoolRadosNamespace) (RadosNamespaceMirroringDirection, map[string]RadosNamespaceMirroringDirection, error) {
+	mirrored := []*CephBlockPoolRadosNamespace{}
+	sites := []string{}
+	for i := range radosNamespaces {
+		radosNamespace := &radosNamespaces[i]
+		if radosNamespace.Spec.Mirroring == nil || !radosNamespace.DeletionTimestamp.IsZero() {
+			continue
+		}
+		mirrored = append(mirrored, radosNamespace)
+		for _, peer := range radosNamespace.Spec.Mirroring.Peers {
+			if !slices.Contains(sites, peer.SiteName) {
+				sites = append(sites, peer.SiteName)
+			}
+		}
+	}
+	slices.Sort(sites)
+
+	conflicts := []string{}
+	// the direction of the peers is the direction requested by the first rados namespace requesting one
+	resolve := func(peers string, requested func(mirroring *RadosNamespaceMirroring) RadosNamespaceMirroringDirection) RadosNamespaceMirroringDirection {
+		var direction RadosNamespaceMirroringDirection
+		owner := ""
+		for _, radosNamespace := range mirrored {
+			d := requested(radosNamespace.Spec.Mirroring)
+			if d == "" {
+				continue
+			}
+			if direction == "" {
+				direction, owner = d, radosNamespace.Name
+			} else if d != direction {
+				conflicts = append(conflicts, fmt.Sprintf("rados namespace %q requests the %q direction of %s while %q requests %q", owner, direction, peers, radosNamespace.Name, d))
+			}
+		}
+		return direction
+	}
+
+	direction := resolve("all the peers", func(mirroring *RadosNamespaceMirroring) RadosNamespaceMirroringDirection {
+		return mirroring.Direction
+	})
+	siteDirections := map[string]RadosNamespaceMirroringDirection{}
+	for _, site := range sites {
+		// the direction of all the peers applies to the site if the rados namespace does not override it
+		siteDirection := resolve(fmt.Sprintf("the peer site %q", site), func(mirroring *RadosNamespaceMirroring) RadosNamespaceMirroringDirection {
+			for _, peer := range mirroring.Peers {
+				if peer.SiteName == site && peer.Direction != "" {
+					return peer.Direction
+				}
+			}
+			return mirroring.Direction
+		})
+		if siteDirection != "" {
+			siteDirections[site] = siteDirection
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return "", nil, fmt.Errorf("conflicting mirroring peer directions: %s", strings.Join(conflicts, "; "))
+	}
+	return direction, siteDirections, nil
+}
+
+// Validate checks the constraints between the fields of the rados namespace spec. All the violations are
+// reported in a single error so that they can be fixed at once.
+func (s *CephBlockPoolRadosNamespaceSpec) Validate() error {
+	violations := []string{}
+
+	if s.Name == ImplicitNamespaceKey {
+		if len(s.ApplicationMetadata) > 0 {
+			violations = append(violations, "application metadata is not supported for the implicit rados namespace")
+		}
+		if s.Description != "" {
+			violations = append(violations, "description is not supported for the implicit rados namespace")
+		}
+		if s.Compression != nil {
+			violations = append(violations, "compression settings are not supported for the implicit rados namespace, set the compression of the pool instead")
+		}
+		if s.SetAsPoolDefault {
+			violations = append(violations, "setAsPoolDefault is not supported for the implicit rados namespace, which is already used when no rados namespace is set")
+		}
+	}
+
+	// the description is stored in the application metadata of the rados namespace
+	if _, ok := s.ApplicationMetadata[RadosNamespaceDescriptionKey]; ok && s.Description != "" {
+		violations = append(violations, fmt.Sprintf("the %q application metadata key is reserved for the description", RadosNamespaceDescriptionKey))
+	}
+
+	if s.Mirroring != nil {
+		// snapshot-based mirroring is configured per image, the images of a rados namespace in pool mode use
+		// journal-based mirroring and the snapshot schedules would have no effect
+		if len(s.Mirroring.SnapshotSchedules) > 0 && s.Mirroring.Mode == RadosNamespaceMirroringModePool {
+			violations = append(violations, fmt.Sprintf("snapshot schedules require the snapshot-based mirroring of the %q mode, they have no effect in the %q mode",
+				RadosNamespaceMirroringModeImage, RadosNamespaceMirroringModePool))
+		}
+		// all the images of a rados namespace are mirrored in pool mode
+		if s.Mirroring.ImageFilter != nil && s.Mirroring.Mode != RadosNamespaceMirroringModeImage {
+			violations = append(violations, fmt.Sprintf("the image filter requires the %q mirroring mode", RadosNamespaceMirroringModeImage))
+		}
+		// the images of a mirroring secondary are read-only, the default rados namespace of the pool must accept
+		// the provisioning of new images
+		if s.SetAsPoolDefault && s.Mirroring.Direction == RadosNamespaceMirroringDirectionRxOnly {
+			violations = append(violations, fmt.Sprintf("setAsPoolDefault is not supported with the %q mirroring direction of a mirroring secondary", RadosNamespaceMirroringDirectionRxOnly))
+		}
+		// the rados namespace is mirrored to another rados namespace of the peer, where it is not the default of
+		// the pool, so the provisioning that does not set a rados namespace would not land in it after a failover
+		if s.SetAsPoolDefault && s.Mirroring.RemoteNamespace != nil && *s.Mirroring.RemoteNamespace != "" {
+			violations = append(violations, fmt.Sprintf("setAsPoolDefault and the mirroring remote namespace %q are mutually exclusive", *s.Mirroring.RemoteNamespace))
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d invalid settings: %s", len(violations), strings.Join(violations, "; "))
+}
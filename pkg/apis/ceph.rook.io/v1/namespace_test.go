@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCephBlockPoolRadosNamespaceSpecValidate(t *testing.T) {
+	remoteNamespace := "namespace-b"
+
+	t.Run("valid spec", func(t *testing.T) {
+		spec := CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:       "replicapool",
+			Name:                "namespace-a",
+			ApplicationMetadata: map[string]string{"team": "storage"},
+			SetAsPoolDefault:    true,
+			Compression:         &RadosNamespaceCompression{Hint: "compressible"},
+			Mirroring: &RadosNamespaceMirroring{
+				Mode:              RadosNamespaceMirroringModeImage,
+				Direction:         RadosNamespaceMirroringDirectionRxTx,
+				SnapshotSchedules: []SnapshotScheduleSpec{{Interval: "1h"}},
+				ImageFilter:       &RadosNamespaceMirroringImageFilter{Include: []string{"db-*"}},
+			},
+		}
+		assert.NoError(t, spec.Validate())
+	})
+
+	t.Run("implicit rados namespace", func(t *testing.T) {
+		spec := CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:       "replicapool",
+			Name:                ImplicitNamespaceKey,
+			ApplicationMetadata: map[string]string{"team": "storage"},
+			Description:         "shared by all the teams",
+			SetAsPoolDefault:    true,
+			Compression:         &RadosNamespaceCompression{Hint: "incompressible"},
+		}
+		err := spec.Validate()
+		assert.ErrorContains(t, err, "4 invalid settings")
+		assert.ErrorContains(t, err, "application metadata is not supported for the implicit rados namespace")
+		assert.ErrorContains(t, err, "description is not supported for the implicit rados namespace")
+		assert.ErrorContains(t, err, "compression settings are not supported for the implicit rados namespace")
+		assert.ErrorContains(t, err, "setAsPoolDefault is not supported for the implicit rados namespace")
+	})
+
+	t.Run("pool default of a mirroring secondary with a remote namespace", func(t *testing.T) {
+		spec := CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:    "replicapool",
+			SetAsPoolDefault: true,
+			Mirroring: &RadosNamespaceMirroring{
+				Mode:            RadosNamespaceMirroringModeImage,
+				Direction:       RadosNamespaceMirroringDirectionRxOnly,
+				RemoteNamespace: &remoteNamespace,
+			},
+		}
+		err := spec.Validate()
+		assert.ErrorContains(t, err, "2 invalid settings")
+		assert.ErrorContains(t, err, `setAsPoolDefault is not supported with the "rx-only" mirroring direction`)
+		assert.ErrorContains(t, err, `setAsPoolDefault and the mirroring remote namespace "namespace-b" are mutually exclusive`)
+	})
+
+	t.Run("pool mode settings", func(t *testing.T) {
+		spec := CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &RadosNamespaceMirroring{
+				Mode:              RadosNamespaceMirroringModePool,
+				SnapshotSchedules: []SnapshotScheduleSpec{{Interval: "1h"}},
+				ImageFilter:       &RadosNamespaceMirroringImageFilter{Exclude: []string{"*-tmp"}},
+			},
+		}
+		err := spec.Validate()
+		assert.ErrorContains(t, err, "2 invalid settings")
+		assert.ErrorContains(t, err, "snapshot schedules require the snapshot-based mirroring")
+		assert.ErrorContains(t, err, `the image filter requires the "image" mirroring mode`)
+	})
+
+	t.Run("remote namespace without pool default", func(t *testing.T) {
+		spec := CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring:     &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, RemoteNamespace: &remoteNamespace},
+		}
+		assert.NoError(t, spec.Validate())
+	})
+	t.Run("description with the description application metadata key", func(t *testing.T) {
+		spec := CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:       "replicapool",
+			ApplicationMetadata: map[string]string{RadosNamespaceDescriptionKey: "tenant a"},
+		}
+		// the key can be used without a description
+		assert.NoError(t, spec.Validate())
+
+		spec.Description = "volumes of tenant a"
+		assert.ErrorContains(t, spec.Validate(), `the "description" application metadata key is reserved for the description`)
+	})
+}
+
+func TestGetMirroringPeerDirections(t *testing.T) {
+	newRadosNamespace := func(name string, mirroring *RadosNamespaceMirroring) CephBlockPoolRadosNamespace {
+		radosNamespace := CephBlockPoolRadosNamespace{Spec: CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", Mirroring: mirroring}}
+		radosNamespace.Name = name
+		return radosNamespace
+	}
+
+	t.Run("no direction", func(t *testing.T) {
+		direction, siteDirections, err := GetMirroringPeerDirections([]CephBlockPoolRadosNamespace{
+			newRadosNamespace("namespace-a", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage}),
+			newRadosNamespace("namespace-b", nil),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, direction)
+		assert.Empty(t, siteDirections)
+	})
+
+	t.Run("directions of all the peers and of a site", func(t *testing.T) {
+		direction, siteDirections, err := GetMirroringPeerDirections([]CephBlockPoolRadosNamespace{
+			newRadosNamespace("namespace-a", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxOnly}),
+			newRadosNamespace("namespace-b", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxOnly,
+				Peers: []RadosNamespaceMirroringPeer{{SiteName: "site-b"}}}),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, RadosNamespaceMirroringDirectionRxOnly, direction)
+		assert.Equal(t, map[string]RadosNamespaceMirroringDirection{"site-b": RadosNamespaceMirroringDirectionRxOnly}, siteDirections)
+	})
+
+	t.Run("conflicting directions of all the peers", func(t *testing.T) {
+		_, _, err := GetMirroringPeerDirections([]CephBlockPoolRadosNamespace{
+			newRadosNamespace("namespace-a", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxOnly}),
+			newRadosNamespace("namespace-b", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxTx}),
+		})
+		assert.ErrorContains(t, err, `rados namespace "namespace-a" requests the "rx-only" direction of all the peers while "namespace-b" requests "rx-tx"`)
+	})
+
+	t.Run("site direction conflicting with the direction of all the peers", func(t *testing.T) {
+		_, _, err := GetMirroringPeerDirections([]CephBlockPoolRadosNamespace{
+			newRadosNamespace("namespace-a", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxOnly}),
+			newRadosNamespace("namespace-b", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage,
+				Peers: []RadosNamespaceMirroringPeer{{SiteName: "site-b", Direction: RadosNamespaceMirroringDirectionTxOnly}}}),
+		})
+		assert.ErrorContains(t, err, `direction of the peer site "site-b"`)
+	})
+
+	t.Run("deleted rados namespace is ignored", func(t *testing.T) {
+		deleted := newRadosNamespace("namespace-b", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxTx})
+		now := metav1.Now()
+		deleted.DeletionTimestamp = &now
+		direction, _, err := GetMirroringPeerDirections([]CephBlockPoolRadosNamespace{
+			newRadosNamespace("namespace-a", &RadosNamespaceMirroring{Mode: RadosNamespaceMirroringModeImage, Direction: RadosNamespaceMirroringDirectionRxOnly}),
+			deleted,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, RadosNamespaceMirroringDirectionRxOnly, direction)
+	})
+}
@@ -640,6 +640,64 @@ const (
 	// RadosNamespaceEmptyReason represents when a rados namespace does not contain images or snapshots that are blocking
 	// deletion.
 	RadosNamespaceEmptyReason ConditionReason = "RadosNamespaceEmpty"
+	// CephVersionUnknownReason represents when the ceph version of the cluster cannot be determined.
+	CephVersionUnknownReason ConditionReason = "CephVersionUnknown"
+	// CephVersionDetectedReason represents when the ceph version of the cluster was determined.
+	CephVersionDetectedReason ConditionReason = "CephVersionDetected"
+	// RadosNamespaceMirrorPrimaryReason represents when a rados namespace is the mirroring primary of a healthy peer,
+	// which blocks deletion.
+	RadosNamespaceMirrorPrimaryReason ConditionReason = "RadosNamespaceMirrorPrimary"
+	// RadosNamespaceNotMirrorPrimaryReason represents when a rados namespace is not the mirroring primary of a
+	// healthy peer, which does not block deletion.
+	RadosNamespaceNotMirrorPrimaryReason ConditionReason = "RadosNamespaceNotMirrorPrimary"
+	// PoolMirroringDisabledReason represents when mirroring is requested for a rados namespace while it is disabled
+	// on the parent pool.
+	PoolMirroringDisabledReason ConditionReason = "PoolMirroringDisabled"
+	// PoolMirroringUnsupportedReason represents a rados namespace whose mirroring mode is not supported by its
+	// erasure coded parent pool.
+	PoolMirroringUnsupportedReason ConditionReason = "PoolMirroringUnsupported"
+	// PausedReason represents when the reconcile of a resource is paused.
+	PausedReason ConditionReason = "Paused"
+	// SnapshotScheduleFailedReason represents when mirroring is enabled on a rados namespace but its snapshot
+	// schedules could not be set.
+	SnapshotScheduleFailedReason ConditionReason = "SnapshotScheduleFailed"
+	// WaitingForCephClusterReason represents when the reconcile of a resource waits for the CephCluster to be ready.
+	WaitingForCephClusterReason ConditionReason = "WaitingForCephCluster"
+	// WaitingForBlockPoolReason represents when the reconcile of a rados namespace waits for its CephBlockPool to
+	// be ready.
+	WaitingForBlockPoolReason ConditionReason = "WaitingForBlockPool"
+	// ClusterInfoIncompleteReason represents when the reconcile of a resource waits for the info of the CephCluster
+	// to be complete.
+	ClusterInfoIncompleteReason ConditionReason = "ClusterInfoIncomplete"
+	// PoolDefaultUnsetReason represents when setAsPoolDefault is no longer set on a rados namespace.
+	PoolDefaultUnsetReason ConditionReason = "PoolDefaultUnset"
+	// PoolDefaultUnsupportedReason represents when a rados namespace cannot be the default rados namespace of its pool
+	// because ceph-csi has no default rados namespace per pool.
+	PoolDefaultUnsupportedReason ConditionReason = "PoolDefaultUnsupported"
+	// ImplicitNamespaceIgnoredReason represents when a rados namespace CR of the implicit rados namespace is not
+	// reconciled because the operator is configured to ignore them.
+	ImplicitNamespaceIgnoredReason ConditionReason = "ImplicitNamespaceIgnored"
+	// ReconcileStaleReason represents when a resource was not reconciled successfully for longer than the
+	// staleness threshold.
+	ReconcileStaleReason ConditionReason = "ReconcileStale"
+	// WaitingForMirrorHealthReason represents a rados namespace whose csi config is withheld until its mirroring
+	// is healthy
+	WaitingForMirrorHealthReason ConditionReason = "WaitingForMirrorHealth"
+	// WaitingForMirroringTargetReason represents a rados namespace that is not reported as ready until its
+	// mirroring reaches the target health
+	WaitingForMirroringTargetReason ConditionReason = "WaitingForMirroringTarget"
+	// CSIOperatorNotReadyReason represents a rados namespace whose csi operator client profile cannot be created
+	// because the CRDs of the csi operator are not installed
+	CSIOperatorNotReadyReason ConditionReason = "CSIOperatorNotReady"
+	// MirrorDaemonMissingReason represents a rados namespace whose snapshot schedules are set while no
+	// rbd-mirror daemon is deployed in the cluster to replicate the snapshots
+	MirrorDaemonMissingReason ConditionReason = "MirrorDaemonMissing"
+	// ClusterFullReason represents a rados namespace that cannot be created because the ceph cluster is full
+	ClusterFullReason ConditionReason = "ClusterFull"
+	// MirroringEnabledReason represents a resource whose mirroring is enabled
+	MirroringEnabledReason ConditionReason = "MirroringEnabled"
+	// MirroringDisabledReason represents a resource whose mirroring is not enabled
+	MirroringDisabledReason ConditionReason = "MirroringDisabled"
 )
 
 // ConditionType represent a resource's status
@@ -665,6 +723,17 @@ const (
 	ConditionPoolDeletionIsBlocked ConditionType = "PoolDeletionIsBlocked"
 	// ConditionRadosNSDeletionIsBlocked represents when deletion of the object is blocked.
 	ConditionRadosNSDeletionIsBlocked ConditionType = "RadosNamespaceDeletionIsBlocked"
+	// ConditionDeletionBlockedMirrorPrimary represents when deletion of the object is blocked because it is
+	// the mirroring primary of a healthy peer.
+	ConditionDeletionBlockedMirrorPrimary ConditionType = "DeletionBlockedMirrorPrimary"
+	// ConditionPoolDefault reports whether setAsPoolDefault is applied to a rados namespace.
+	ConditionPoolDefault ConditionType = "PoolDefault"
+	// ConditionIgnored represents when a resource is not reconciled by the operator.
+	ConditionIgnored ConditionType = "Ignored"
+	// ConditionStale represents when the last successful reconcile of a resource is too old.
+	ConditionStale ConditionType = "Stale"
+	// ConditionMirroring represents whether the mirroring of a resource is enabled.
+	ConditionMirroring ConditionType = "Mirroring"
 )
 
 // ClusterState represents the state of a Ceph Cluster
@@ -1001,6 +1070,26 @@ type MirroringStatusSpec struct {
 	// Details contains potential status errors
 	// +optional
 	Details string `json:"details,omitempty"`
+	// Peers is the mirroring status of the images on each peer site, only reported for a rados namespace
+	// mirrored toward several peers. The health of the summary aggregates the health of all the peers.
+	// +optional
+	Peers []MirroringPeerStatus `json:"peers,omitempty"`
+}
+
+// MirroringPeerStatus is the mirroring status of the images of a pool/radosNamespace on a peer site
+type MirroringPeerStatus struct {
+	// SiteName is the site name of the peer
+	SiteName string `json:"siteName"`
+	// Health is the mirroring health of the images on the peer, either OK, WARNING or ERROR
+	// +optional
+	Health string `json:"health,omitempty"`
+	// States is the number of images in each mirroring state on the peer
+	// +optional
+	// +nullable
+	States StatesSpec `json:"states,omitempty"`
+	// Details contains the reason of an unhealthy peer
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // MirroringStatus is the pool/radosNamespace mirror status
@@ -1087,6 +1176,9 @@ type MirroringInfo struct {
 	// Peers are the list of peer sites connected to that cluster
 	// +optional
 	Peers []PeersSpec `json:"peers,omitempty"`
+	// RemoteNamespace is the rados namespace of the peers where the rados namespace is mirrored
+	// +optional
+	RemoteNamespace string `json:"remote_namespace,omitempty"`
 }
 
 // PeersSpec contains peer details
@@ -3638,6 +3730,91 @@ type RadosNamespaceMirroring struct {
 	// SnapshotSchedules is the scheduling of snapshot for mirrored images
 	// +optional
 	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+	// SnapshotSchedulesPaused pauses the snapshot schedules of the rados namespace without removing them from
+	// the spec. The schedules are removed from ceph while paused and set again once resumed.
+	// +optional
+	SnapshotSchedulesPaused bool `json:"snapshotSchedulesPaused,omitempty"`
+	// Direction is the mirroring direction of the peers of the CephBlockPool; either rx-only, tx-only or rx-tx.
+	// The peers belong to the pool: the direction is set when the CephBlockPool imports a bootstrap peer and the
+	// direction of the peers already configured is not changed. The rados namespaces of a pool must not request
+	// different directions. The direction of the peers is left as is if not set.
+	// +kubebuilder:validation:Enum="";rx-only;tx-only;rx-tx
+	// +optional
+	Direction RadosNamespaceMirroringDirection `json:"direction,omitempty"`
+	// Peers are the peer sites of the CephBlockPool toward which the rados namespace is mirrored, to mirror it
+	// toward several sites. The peers must be configured on the CephBlockPool and their site names must be
+	// unique. The direction of a peer overrides Direction, and the mirroring status of each peer is reported
+	// in the status.
+	// +optional
+	Peers []RadosNamespaceMirroringPeer `json:"peers,omitempty"`
+	// DrainOnDisable disables the mirroring of the mirrored images of the rados namespace when the mirroring
+	// of the rados namespace is disabled, instead of failing until the images are disabled manually. The
+	// images are disabled in batches across reconciles.
+	// +optional
+	DrainOnDisable bool `json:"drainOnDisable,omitempty"`
+	// ImageFilter selects the images of the rados namespace for which mirroring is enabled in the image mode.
+	// Mirroring is enabled on the matching images and disabled on the others.
+	// +optional
+	ImageFilter *RadosNamespaceMirroringImageFilter `json:"imageFilter,omitempty"`
+	// HealthCheck overrides the mirroring health check settings of the CephBlockPool for the rados namespace
+	// +optional
+	HealthCheck *RadosNamespaceMirroringHealthCheck `json:"healthCheck,omitempty"`
+	// WaitUntil is the mirroring health that the rados namespace must reach before it is reported as ready. The
+	// reconcile is requeued with a backoff until the mirroring checker reports the target health. The rados
+	// namespace is ready as soon as mirroring is enabled if not set.
+	// +kubebuilder:validation:Enum="";healthy
+	// +optional
+	WaitUntil RadosNamespaceMirroringTarget `json:"waitUntil,omitempty"`
+}
+
+// RadosNamespaceMirroringPeer represents a peer site toward which a rados namespace is mirrored
+type RadosNamespaceMirroringPeer struct {
+	// SiteName is the site name of the peer, as reported in the mirroring info of the CephBlockPool
+	// +kubebuilder:validation:MinLength=1
+	SiteName string `json:"siteName"`
+	// Direction is the mirroring direction of the peer; either rx-only, tx-only or rx-tx.
+	// The direction of the mirroring of the rados namespace is used if not set.
+	// +kubebuilder:validation:Enum="";rx-only;tx-only;rx-tx
+	// +optional
+	Direction RadosNamespaceMirroringDirection `json:"direction,omitempty"`
+}
+
+// RadosNamespaceMirroringHealthCheck represents the mirroring health check settings of a rados namespace
+type RadosNamespaceMirroringHealthCheck struct {
+	// Interval is the interval between two mirroring health checks of the rados namespace, like 60s for 60
+	// seconds. The interval of the mirror status check of the CephBlockPool is used if not set.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// RadosNamespaceMirroringImageFilter represents the glob patterns of the image names to mirror
+type RadosNamespaceMirroringImageFilter struct {
+	// Include is the list of glob patterns of the image names to mirror. All the images are included if empty.
+	// +optional
+	Include []string `json:"include,omitempty"`
+	// Exclude is the list of glob patterns of the image names not to mirror, which take precedence over Include.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// RadosNamespaceCompression represents the compression settings of a rados namespace. The compression mode and
+// algorithm are settings of the CephBlockPool, a rados namespace can only hint whether its data is compressible.
+type RadosNamespaceCompression struct {
+	// Hint is the compression hint of the writes to the images of the rados namespace (options are: none,
+	// compressible, incompressible), set as the rbd_compression_hint option of the rados namespace. The data hinted
+	// as compressible is compressed by the passive compression mode of the pool, the data hinted as incompressible
+	// is not compressed by the aggressive compression mode of the pool. The hint of the pool is used if not set.
+	// +kubebuilder:validation:Enum=none;compressible;incompressible;""
+	// +optional
+	Hint string `json:"hint,omitempty"`
+}
+
+// RadosNamespaceConfigEntry represents an rbd config option set on a rados namespace
+type RadosNamespaceConfigEntry struct {
+	// Key is the name of the rbd config option. Only the options allowed by the operator can be set.
+	Key string `json:"key"`
+	// Value is the value of the rbd config option
+	Value string `json:"value"`
 }
 
 // RadosNamespaceMirroringMode represents the mode of the RadosNamespace
@@ -3650,6 +3827,58 @@ const (
 	RadosNamespaceMirroringModeImage RadosNamespaceMirroringMode = "image"
 )
 
+// RadosNamespaceMirroringDirection represents the mirroring direction of the RadosNamespace peer
+type RadosNamespaceMirroringDirection string
+
+const (
+	// RadosNamespaceMirroringDirectionRxOnly represents a peer that only receives images
+	RadosNamespaceMirroringDirectionRxOnly RadosNamespaceMirroringDirection = "rx-only"
+	// RadosNamespaceMirroringDirectionTxOnly represents a peer that only sends images
+	RadosNamespaceMirroringDirectionTxOnly RadosNamespaceMirroringDirection = "tx-only"
+	// RadosNamespaceMirroringDirectionRxTx represents a peer that both receives and sends images
+	RadosNamespaceMirroringDirectionRxTx RadosNamespaceMirroringDirection = "rx-tx"
+)
+
+// RadosNamespaceMirroringTarget represents the mirroring health that a RadosNamespace must reach to be ready
+type RadosNamespaceMirroringTarget string
+
+const (
+	// RadosNamespaceMirroringTargetHealthy represents a mirroring whose health is reported as OK
+	RadosNamespaceMirroringTargetHealthy RadosNamespaceMirroringTarget = "healthy"
+)
+
+// RadosNamespaceDeletionPolicy represents whether the rados namespace is deleted from ceph with the CR
+type RadosNamespaceDeletionPolicy string
+
+const (
+	// RadosNamespaceDeletionPolicyDelete deletes the rados namespace from ceph when the CR is deleted
+	RadosNamespaceDeletionPolicyDelete RadosNamespaceDeletionPolicy = "Delete"
+	// RadosNamespaceDeletionPolicyRetain retains the rados namespace and its images in ceph when the CR is deleted
+	RadosNamespaceDeletionPolicyRetain RadosNamespaceDeletionPolicy = "Retain"
+)
+
+// RadosNamespaceCSISpec represents the ceph-csi settings of a rados namespace
+type RadosNamespaceCSISpec struct {
+	// WaitForMirrorHealthy withholds the csi config of the rados namespace until the mirroring checker
+	// reports healthy mirroring, so that the volumes of a DR secondary are not mounted while they are stale.
+	// It has no effect if mirroring is not configured.
+	// +optional
+	WaitForMirrorHealthy bool `json:"waitForMirrorHealthy,omitempty"`
+	// ReadAffinity overrides the read affinity settings of the CephCluster in the csi config of the rados
+	// namespace. The settings of the CephCluster are used if not set.
+	// +optional
+	ReadAffinity *ReadAffinitySpec `json:"readAffinity,omitempty"`
+}
+
+// RadosNamespaceExternalSpec represents the settings of a rados namespace of an external cluster
+type RadosNamespaceExternalSpec struct {
+	// Monitors overrides the mon endpoints of the csi config of the rados namespace, e.g. when the tenants of
+	// the external cluster reach the mons through different network paths. The mons of the cluster are used
+	// if not set.
+	// +optional
+	Monitors []string `json:"monitors,omitempty"`
+}
+
 // CephBlockPoolRadosNamespaceSpec represents the specification of a CephBlockPool Rados Namespace
 type CephBlockPoolRadosNamespaceSpec struct {
 	// The name of the CephBlockPoolRadosNamespaceSpec namespace. If not set, the default is the name of the CR.
@@ -3660,9 +3889,72 @@ type CephBlockPoolRadosNamespaceSpec struct {
 	// the CephBlockPool CR.
 	// +kubebuilder:validation:XValidation:message="blockPoolName is immutable",rule="self == oldSelf"
 	BlockPoolName string `json:"blockPoolName"`
+	// BlockPoolNamespace is the namespace of the CephBlockPool, when the pool is in another namespace than the
+	// CR. The CephCluster of that namespace is used, which the operator only allows for the namespaces listed in the
+	// ROOK_RADOS_NAMESPACE_CROSS_NAMESPACE_POOLS operator setting. The namespace of the CR is used if not set.
+	// +kubebuilder:validation:XValidation:message="blockPoolNamespace is immutable",rule="self == oldSelf"
+	// +optional
+	BlockPoolNamespace string `json:"blockPoolNamespace,omitempty"`
 	// Mirroring configuration of CephBlockPoolRadosNamespace
 	// +optional
 	Mirroring *RadosNamespaceMirroring `json:"mirroring,omitempty"`
+	// ApplicationMetadata is the application metadata of the rados namespace. It is stored in the
+	// rbd application metadata of the pool with the keys scoped to the rados namespace.
+	// +optional
+	ApplicationMetadata map[string]string `json:"applicationMetadata,omitempty"`
+	// Description is a human-readable description of the rados namespace. It is stored in ceph with the
+	// application metadata of the rados namespace, under the "description" key, and removed when cleared.
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Description string `json:"description,omitempty"`
+	// ExternalAllowDelete allows the operator to delete the rados namespace from an external cluster
+	// when the CR is deleted, if the rados namespace is empty. By default the rados namespace of an
+	// external cluster is never deleted.
+	// +optional
+	ExternalAllowDelete bool `json:"externalAllowDelete,omitempty"`
+	// External configures the rados namespace of an external cluster
+	// +optional
+	External *RadosNamespaceExternalSpec `json:"external,omitempty"`
+	// MapOptions are the krbd map options used by ceph-csi to map the volumes provisioned in the rados
+	// namespace, as a comma separated list, e.g. "lock_on_read,queue_depth=1024"
+	// +optional
+	MapOptions string `json:"mapOptions,omitempty"`
+	// UnmapOptions are the krbd unmap options used by ceph-csi to unmap the volumes provisioned in the
+	// rados namespace, as a comma separated list, e.g. "force"
+	// +optional
+	UnmapOptions string `json:"unmapOptions,omitempty"`
+	// SetAsPoolDefault is not supported since ceph-csi has no default rados namespace per pool, the volumes are
+	// provisioned in the rados namespace of the clusterID of the StorageClass. When set, the PoolDefault condition
+	// reports it as unsupported with the clusterID to set in the StorageClass.
+	// +optional
+	SetAsPoolDefault bool `json:"setAsPoolDefault,omitempty"`
+	// Compression overrides the compression hint of the pool for the rados namespace
+	// +optional
+	Compression *RadosNamespaceCompression `json:"compression,omitempty"`
+	// CSI configures how the rados namespace is exposed to ceph-csi
+	// +optional
+	CSI *RadosNamespaceCSISpec `json:"csi,omitempty"`
+	// ClusterID overrides the cluster ID of the rados namespace in the csi config, instead of the hash of the
+	// namespace, pool and rados namespace names. It must be unique among the rados namespaces.
+	// +kubebuilder:validation:XValidation:message="clusterID is immutable",rule="self == oldSelf"
+	// +kubebuilder:validation:MaxLength=36
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9]([a-zA-Z0-9_.-]*[a-zA-Z0-9])?$`
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// CephClusterName is the name of the CephCluster of the rados namespace, when several CephClusters run in the
+	// namespace. The CephCluster of the namespace is used if not set.
+	// +kubebuilder:validation:XValidation:message="cephClusterName is immutable",rule="self == oldSelf"
+	// +optional
+	CephClusterName string `json:"cephClusterName,omitempty"`
+	// PostCreateConfig is the list of rbd config options set on the rados namespace once it is created, with
+	// "rbd config namespace set". The options removed from the list are removed from the rados namespace.
+	// +optional
+	PostCreateConfig []RadosNamespaceConfigEntry `json:"postCreateConfig,omitempty"`
+	// DeletionPolicy is whether the rados namespace is deleted from ceph when the CR is deleted. With "Retain",
+	// the CR is deleted but the rados namespace and its images are retained in ceph. Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletionPolicy RadosNamespaceDeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // CephBlockPoolRadosNamespaceStatus represents the Status of Ceph BlockPool
@@ -3680,6 +3972,34 @@ type CephBlockPoolRadosNamespaceStatus struct {
 	// +optional
 	SnapshotScheduleStatus *SnapshotScheduleStatusSpec `json:"snapshotScheduleStatus,omitempty"`
 	Conditions             []Condition                 `json:"conditions,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastReconcileTime is the time of the last successful reconcile of the rados namespace.
+	// +optional
+	// +nullable
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+	// ClonedSettings are the settings copied from another rados namespace with the ceph.rook.io/clone-from
+	// annotation when the rados namespace was created. They apply to the settings that are not set in the spec.
+	// +optional
+	ClonedSettings *RadosNamespaceClonedSettings `json:"clonedSettings,omitempty"`
+}
+
+// RadosNamespaceClonedSettings represents the settings of a CephBlockPoolRadosNamespace copied from another
+// rados namespace of the pool
+type RadosNamespaceClonedSettings struct {
+	// From is the rados namespace the settings were copied from, as "<pool>/<name>"
+	From string `json:"from"`
+	// Compression is the compression of the rados namespace the settings were copied from
+	// +optional
+	Compression *RadosNamespaceCompression `json:"compression,omitempty"`
+	// PostCreateConfig are the rbd config options of the rados namespace the settings were copied from, including
+	// its image features (rbd_default_features) and QoS limits (rbd_qos_*)
+	// +optional
+	PostCreateConfig []RadosNamespaceConfigEntry `json:"postCreateConfig,omitempty"`
+	// Mirroring is the mirroring of the rados namespace the settings were copied from
+	// +optional
+	Mirroring *RadosNamespaceMirroring `json:"mirroring,omitempty"`
 }
 
 // Represents the source of a volume to mount.
@@ -464,6 +464,33 @@ func (in *CephBlockPoolRadosNamespaceSpec) DeepCopyInto(out *CephBlockPoolRadosN
 		*out = new(RadosNamespaceMirroring)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ApplicationMetadata != nil {
+		in, out := &in.ApplicationMetadata, &out.ApplicationMetadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.External != nil {
+		in, out := &in.External, &out.External
+		*out = new(RadosNamespaceExternalSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(RadosNamespaceCompression)
+		**out = **in
+	}
+	if in.CSI != nil {
+		in, out := &in.CSI, &out.CSI
+		*out = new(RadosNamespaceCSISpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostCreateConfig != nil {
+		in, out := &in.PostCreateConfig, &out.PostCreateConfig
+		*out = make([]RadosNamespaceConfigEntry, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -509,6 +536,15 @@ func (in *CephBlockPoolRadosNamespaceStatus) DeepCopyInto(out *CephBlockPoolRado
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ClonedSettings != nil {
+		in, out := &in.ClonedSettings, &out.ClonedSettings
+		*out = new(RadosNamespaceClonedSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3353,6 +3389,23 @@ func (in *MirroringPeerSpec) DeepCopy() *MirroringPeerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringPeerStatus) DeepCopyInto(out *MirroringPeerStatus) {
+	*out = *in
+	out.States = in.States
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringPeerStatus.
+func (in *MirroringPeerStatus) DeepCopy() *MirroringPeerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringPeerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MirroringSpec) DeepCopyInto(out *MirroringSpec) {
 	*out = *in
@@ -3404,6 +3457,11 @@ func (in *MirroringStatus) DeepCopy() *MirroringStatus {
 func (in *MirroringStatusSpec) DeepCopyInto(out *MirroringStatusSpec) {
 	*out = *in
 	in.MirroringStatus.DeepCopyInto(&out.MirroringStatus)
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]MirroringPeerStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -4619,6 +4677,111 @@ func (in *RGWServiceSpec) DeepCopy() *RGWServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceCSISpec) DeepCopyInto(out *RadosNamespaceCSISpec) {
+	*out = *in
+	if in.ReadAffinity != nil {
+		in, out := &in.ReadAffinity, &out.ReadAffinity
+		*out = new(ReadAffinitySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceCSISpec.
+func (in *RadosNamespaceCSISpec) DeepCopy() *RadosNamespaceCSISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceCSISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceClonedSettings) DeepCopyInto(out *RadosNamespaceClonedSettings) {
+	*out = *in
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(RadosNamespaceCompression)
+		**out = **in
+	}
+	if in.PostCreateConfig != nil {
+		in, out := &in.PostCreateConfig, &out.PostCreateConfig
+		*out = make([]RadosNamespaceConfigEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(RadosNamespaceMirroring)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceClonedSettings.
+func (in *RadosNamespaceClonedSettings) DeepCopy() *RadosNamespaceClonedSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceClonedSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceCompression) DeepCopyInto(out *RadosNamespaceCompression) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceCompression.
+func (in *RadosNamespaceCompression) DeepCopy() *RadosNamespaceCompression {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceCompression)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceConfigEntry) DeepCopyInto(out *RadosNamespaceConfigEntry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceConfigEntry.
+func (in *RadosNamespaceConfigEntry) DeepCopy() *RadosNamespaceConfigEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceConfigEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceExternalSpec) DeepCopyInto(out *RadosNamespaceExternalSpec) {
+	*out = *in
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceExternalSpec.
+func (in *RadosNamespaceExternalSpec) DeepCopy() *RadosNamespaceExternalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceExternalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RadosNamespaceMirroring) DeepCopyInto(out *RadosNamespaceMirroring) {
 	*out = *in
@@ -4632,6 +4795,21 @@ func (in *RadosNamespaceMirroring) DeepCopyInto(out *RadosNamespaceMirroring) {
 		*out = make([]SnapshotScheduleSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.Peers != nil {
+		in, out := &in.Peers, &out.Peers
+		*out = make([]RadosNamespaceMirroringPeer, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageFilter != nil {
+		in, out := &in.ImageFilter, &out.ImageFilter
+		*out = new(RadosNamespaceMirroringImageFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(RadosNamespaceMirroringHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4645,6 +4823,69 @@ func (in *RadosNamespaceMirroring) DeepCopy() *RadosNamespaceMirroring {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceMirroringHealthCheck) DeepCopyInto(out *RadosNamespaceMirroringHealthCheck) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceMirroringHealthCheck.
+func (in *RadosNamespaceMirroringHealthCheck) DeepCopy() *RadosNamespaceMirroringHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceMirroringHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceMirroringImageFilter) DeepCopyInto(out *RadosNamespaceMirroringImageFilter) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceMirroringImageFilter.
+func (in *RadosNamespaceMirroringImageFilter) DeepCopy() *RadosNamespaceMirroringImageFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceMirroringImageFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceMirroringPeer) DeepCopyInto(out *RadosNamespaceMirroringPeer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceMirroringPeer.
+func (in *RadosNamespaceMirroringPeer) DeepCopy() *RadosNamespaceMirroringPeer {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceMirroringPeer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadAffinitySpec) DeepCopyInto(out *ReadAffinitySpec) {
 	*out = *in
@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -31,10 +32,18 @@ const (
 	ClientBlocklistDuration = "1200"
 )
 
-func RadosNamespaceCleanup(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName, radosNamespace string) error {
+// RadosNamespaceCleanup removes the ceph resources of the rados namespace. The tiers in order are removed in
+// turn, e.g. the snapshots of all the images before the images. Without any order, the snapshots and each image
+// are removed image by image.
+func RadosNamespaceCleanup(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName, radosNamespace string, order []string) error {
 	logger.Infof("starting clean up of CephBlockPoolRadosNamespace %q resources in cephblockpool %q", radosNamespace, poolName)
 
-	err := cleanupImages(context, clusterInfo, poolName, radosNamespace)
+	var err error
+	if len(order) == 0 {
+		err = cleanupImages(context, clusterInfo, poolName, radosNamespace)
+	} else {
+		err = cleanupRadosNamespaceTiers(context, clusterInfo, poolName, radosNamespace, order)
+	}
 	if err != nil {
 		logger.Errorf("failed to clean up CephBlockPoolRadosNamespace %q resources in cephblockpool %q", radosNamespace, poolName)
 		return err
@@ -91,6 +100,98 @@ func cleanupImages(context *clusterd.Context, clusterInfo *cephclient.ClusterInf
 	return retErr
 }
 
+// cleanupRadosNamespaceTiers removes the resources of each tier of the rados namespace in order, a tier is only
+// removed once the previous tiers were removed successfully
+func cleanupRadosNamespaceTiers(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName, radosNamespace string, order []string) error {
+	for _, tier := range order {
+		switch tier {
+		case opcontroller.RadosNamespaceCleanupSnapshots, opcontroller.RadosNamespaceCleanupImages, opcontroller.RadosNamespaceCleanupNamespace:
+		default:
+			return errors.Errorf("unknown clean up tier %q for rados namespace %q in cephblockpool %q", tier, radosNamespace, poolName)
+		}
+	}
+
+	msg := fmt.Sprintf("cephblockpool %q in rados namespace %q", poolName, radosNamespace)
+	images, err := cephclient.ListImagesInRadosNamespace(context, clusterInfo, poolName, radosNamespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list images in %s", msg)
+	}
+
+	err = blocklistClientIPs(context, clusterInfo, images, poolName, radosNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to add client IPs to the blocklist")
+	}
+
+	for _, tier := range order {
+		logger.Infof("cleaning up the %s in %s", tier, msg)
+		switch tier {
+		case opcontroller.RadosNamespaceCleanupSnapshots:
+			err = cleanupSnapshots(context, clusterInfo, images, poolName, radosNamespace, msg)
+		case opcontroller.RadosNamespaceCleanupImages:
+			err = cleanupImagesWithoutSnapshots(context, clusterInfo, images, poolName, radosNamespace, msg)
+		case opcontroller.RadosNamespaceCleanupNamespace:
+			cleanupRadosNamespace(context, clusterInfo, poolName, radosNamespace)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to clean up the %s in %s", tier, msg)
+		}
+	}
+	return nil
+}
+
+// cleanupSnapshots removes the snapshots of all the images
+func cleanupSnapshots(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, images []cephclient.CephBlockImage, poolName, radosNamespace, msg string) error {
+	var retErr error
+	for _, image := range images {
+		snaps, err := cephclient.ListSnapshotsInRadosNamespace(context, clusterInfo, poolName, image.Name, radosNamespace)
+		if err != nil {
+			retErr = errors.Wrapf(err, "failed to list snapshots for the image %q in %s", image.Name, msg)
+			logger.Error(retErr)
+			continue
+		}
+
+		for _, snap := range snaps {
+			err := cephclient.DeleteSnapshotInRadosNamespace(context, clusterInfo, poolName, image.Name, snap.Name, radosNamespace)
+			if err != nil {
+				retErr = errors.Wrapf(err, "failed to delete snapshot %q of the image %q in %s", snap.Name, image.Name, msg)
+				logger.Error(retErr)
+			} else {
+				logger.Infof("successfully deleted snapshot %q of image %q in %s", snap.Name, image.Name, msg)
+			}
+		}
+	}
+	return retErr
+}
+
+// cleanupImagesWithoutSnapshots removes the images whose snapshots were already removed
+func cleanupImagesWithoutSnapshots(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, images []cephclient.CephBlockImage, poolName, radosNamespace, msg string) error {
+	var retErr error
+	for _, image := range images {
+		err := cephclient.MoveImageToTrashInRadosNamespace(context, clusterInfo, poolName, image.Name, radosNamespace)
+		if err != nil {
+			retErr = errors.Wrapf(err, "failed to move image %q to trash in %s", image.Name, msg)
+			logger.Error(retErr)
+			continue
+		}
+		err = cephclient.DeleteImageFromTrashInRadosNamespace(context, clusterInfo, poolName, image.ID, radosNamespace)
+		if err != nil {
+			retErr = errors.Wrapf(err, "failed to add task to remove image %q from trash in %s", image.Name, msg)
+			logger.Error(retErr)
+		}
+	}
+	return retErr
+}
+
+// cleanupRadosNamespace attempts to remove the rados namespace. The images are removed from the trash
+// asynchronously, in which case the operator removes the rados namespace once the trash is purged.
+func cleanupRadosNamespace(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName, radosNamespace string) {
+	_, err := cephclient.DeleteRadosNamespace(context, clusterInfo, poolName, radosNamespace)
+	if err != nil {
+		logger.Infof("rados namespace %q in cephblockpool %q will be removed by the operator once its images are removed from the trash. %v", radosNamespace, poolName, err)
+	}
+}
+
 func BlockPoolCleanup(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string) error {
 	logger.Infof("starting clean up of CephBlockPool %q resource", poolName)
 
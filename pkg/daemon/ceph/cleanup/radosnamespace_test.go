@@ -17,11 +17,13 @@ limitations under the License.
 package cleanup
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -47,7 +49,7 @@ func TestRadosNamespace(t *testing.T) {
 			return "", errors.New("unknown command")
 		}
 		context := &clusterd.Context{Executor: executor}
-		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace)
+		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, nil)
 		assert.NoError(t, err)
 	})
 
@@ -90,11 +92,96 @@ func TestRadosNamespace(t *testing.T) {
 			return "", errors.New("unknown command")
 		}
 		context := &clusterd.Context{Executor: executor}
-		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace)
+		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, nil)
 		assert.NoError(t, err)
 	})
 }
 
+func TestRadosNamespaceTieredCleanup(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	poolName := "test-pool"
+	radosNamespace := "test-namespace"
+	order := []string{opcontroller.RadosNamespaceCleanupSnapshots, opcontroller.RadosNamespaceCleanupImages, opcontroller.RadosNamespaceCleanupNamespace}
+
+	newExecutor := func(stats string, commands *[]string) *exectest.MockExecutor {
+		return &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				logger.Infof("Command: %s %v", command, args)
+				switch {
+				case args[0] == "ls" && args[1] == "-l":
+					return `[{"image":"image1","id":"id1"},{"image":"image2","id":"id2"}]`, nil
+				case args[0] == "status":
+					return `{}`, nil
+				case args[0] == "snap" && args[1] == "ls":
+					return mockSnapshotsResponse, nil
+				case args[0] == "pool" && args[1] == "stats":
+					return stats, nil
+				case args[0] == "snap" && args[1] == "rm",
+					args[0] == "trash" && args[1] == "mv",
+					args[0] == "namespace" && args[1] == "remove":
+					*commands = append(*commands, strings.Join(args[:3], " "))
+					return "", nil
+				case args[0] == "rbd" && args[1] == "task":
+					*commands = append(*commands, strings.Join(args[:6], " "))
+					return "", nil
+				}
+				return "", errors.New("unknown command")
+			},
+		}
+	}
+
+	t.Run("snapshots of all the images are removed before the images and the namespace", func(t *testing.T) {
+		commands := []string{}
+		context := &clusterd.Context{Executor: newExecutor(`{"images":{"count":0,"snap_count":0}}`, &commands)}
+		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, order)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"snap rm test-pool/image1@snap1",
+			"snap rm test-pool/image2@snap1",
+			"trash mv test-pool/image1",
+			"rbd task add trash remove test-pool/test-namespace/id1",
+			"trash mv test-pool/image2",
+			"rbd task add trash remove test-pool/test-namespace/id2",
+			"namespace remove --pool",
+		}, commands)
+	})
+
+	t.Run("namespace with images in the trash is left to the operator", func(t *testing.T) {
+		commands := []string{}
+		context := &clusterd.Context{Executor: newExecutor(`{"images":{"count":2,"snap_count":0}}`, &commands)}
+		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, order)
+		assert.NoError(t, err)
+		assert.NotContains(t, commands, "namespace remove --pool")
+	})
+
+	t.Run("images are not removed when the snapshots failed to be removed", func(t *testing.T) {
+		context := &clusterd.Context{Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				switch {
+				case args[0] == "ls" && args[1] == "-l":
+					return mockImageLSResponse, nil
+				case args[0] == "status":
+					return `{}`, nil
+				case args[0] == "snap" && args[1] == "ls":
+					return mockSnapshotsResponse, nil
+				case args[0] == "snap" && args[1] == "rm":
+					return "", errors.New("image has clones")
+				}
+				assert.Fail(t, "unexpected command", "%v", args)
+				return "", errors.New("unknown command")
+			},
+		}}
+		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, order)
+		assert.ErrorContains(t, err, "failed to clean up the snapshots")
+	})
+
+	t.Run("unknown tier", func(t *testing.T) {
+		context := &clusterd.Context{Executor: &exectest.MockExecutor{}}
+		err := RadosNamespaceCleanup(context, clusterInfo, poolName, radosNamespace, []string{"volumes"})
+		assert.ErrorContains(t, err, `unknown clean up tier "volumes"`)
+	})
+}
+
 func TestBlockPoolCleanup(t *testing.T) {
 	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
 	poolName := "test-pool"
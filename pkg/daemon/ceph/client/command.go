@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"strconv"
@@ -216,24 +217,43 @@ func (c *CephToolCommand) run() ([]byte, error) {
 			if stderr != "" {
 				err = errors.Errorf("err=%s: stderr=%s", err.Error(), stderr)
 			}
-		} else if c.timeout == 0 {
-			output, err = c.context.Executor.ExecuteCommandWithOutput(command, args...)
 		} else {
-			output, err = c.context.Executor.ExecuteCommandWithTimeout(c.timeout, command, args...)
-		}
-	} else if c.timeout == 0 {
-		if c.combinedOutput {
-			output, err = c.context.Executor.ExecuteCommandWithCombinedOutput(command, args...)
-		} else {
-			output, err = c.context.Executor.ExecuteCommandWithOutput(command, args...)
+			output, err = c.executeCommand(false, command, args)
 		}
 	} else {
-		output, err = c.context.Executor.ExecuteCommandWithTimeout(c.timeout, command, args...)
+		output, err = c.executeCommand(c.combinedOutput, command, args)
 	}
 
 	return []byte(output), err
 }
 
+// executeCommand runs the command in the operator. When the context of the cluster info has a deadline and the
+// executor supports it, the command is killed at the deadline so that it does not outlive a timed out call.
+func (c *CephToolCommand) executeCommand(combinedOutput bool, command string, args []string) (string, error) {
+	if _, hasDeadline := c.clusterInfo.Context.Deadline(); hasDeadline {
+		if executor, ok := c.context.Executor.(exec.ContextExecutor); ok {
+			ctx := c.clusterInfo.Context
+			if c.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
+			if combinedOutput {
+				return executor.ExecuteCommandWithCombinedOutputContext(ctx, command, args...)
+			}
+			return executor.ExecuteCommandWithOutputContext(ctx, command, args...)
+		}
+	}
+
+	if c.timeout != 0 {
+		return c.context.Executor.ExecuteCommandWithTimeout(c.timeout, command, args...)
+	}
+	if combinedOutput {
+		return c.context.Executor.ExecuteCommandWithCombinedOutput(command, args...)
+	}
+	return c.context.Executor.ExecuteCommandWithOutput(command, args...)
+}
+
 func (c *CephToolCommand) Run() ([]byte, error) {
 	c.timeout = 0
 	return c.run()
@@ -244,6 +264,27 @@ func (c *CephToolCommand) RunWithTimeout(timeout time.Duration) ([]byte, error)
 	return c.run()
 }
 
+// CallWithTimeout runs fn with a copy of the cluster info whose context expires after the timeout, so that the
+// ceph commands of fn are not started after the deadline and the running ones are killed at the deadline. If fn
+// does not return before the deadline, the error of the context is returned without waiting for fn.
+func CallWithTimeout(ctx context.Context, clusterInfo *ClusterInfo, timeout time.Duration, fn func(clusterInfo *ClusterInfo) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	callClusterInfo := *clusterInfo
+	callClusterInfo.Context = callCtx
+
+	result := make(chan error, 1)
+	go func() {
+		result <- fn(&callClusterInfo)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-callCtx.Done():
+		return errors.Wrapf(callCtx.Err(), "ceph call did not complete within %s", timeout.String())
+	}
+}
+
 // ExecuteRBDCommandWithTimeout executes the 'rbd' command with a timeout of 1
 // minute. This method is left as a special case in which the caller has fully
 // configured its arguments. It is future work to integrate this case into the
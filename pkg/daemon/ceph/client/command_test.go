@@ -226,3 +226,70 @@ func TestNewGaneshaRadosGraceCommand(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestCallWithTimeout(t *testing.T) {
+	ctx := context.TODO()
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	t.Run("the result of the call is returned", func(t *testing.T) {
+		err := CallWithTimeout(ctx, clusterInfo, time.Second, func(callClusterInfo *ClusterInfo) error {
+			_, hasDeadline := callClusterInfo.Context.Deadline()
+			assert.True(t, hasDeadline)
+			assert.Equal(t, clusterInfo.Namespace, callClusterInfo.Namespace)
+			return errors.New("failed")
+		})
+		assert.EqualError(t, err, "failed")
+		_, hasDeadline := clusterInfo.Context.Deadline()
+		assert.False(t, hasDeadline)
+	})
+
+	t.Run("a blocked command times out", func(t *testing.T) {
+		unblock := make(chan struct{})
+		defer close(unblock)
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				<-unblock
+				return "", nil
+			},
+		}
+		c := &clusterd.Context{Executor: executor}
+
+		start := time.Now()
+		err := CallWithTimeout(ctx, clusterInfo, 50*time.Millisecond, func(callClusterInfo *ClusterInfo) error {
+			return CreateRadosNamespace(c, callClusterInfo, "replicapool", "namespace-a")
+		})
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("the commands of the call are killed at the deadline", func(t *testing.T) {
+		executor := &contextMockExecutor{killed: make(chan struct{}, 1)}
+		c := &clusterd.Context{Executor: executor}
+
+		err := CallWithTimeout(ctx, clusterInfo, 50*time.Millisecond, func(callClusterInfo *ClusterInfo) error {
+			return CreateRadosNamespace(c, callClusterInfo, "replicapool", "namespace-a")
+		})
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		select {
+		case <-executor.killed:
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "the command was not killed at the deadline")
+		}
+	})
+}
+
+// contextMockExecutor runs the commands until their context is done, as the exec.CommandContext does
+type contextMockExecutor struct {
+	exectest.MockExecutor
+	killed chan struct{}
+}
+
+func (e *contextMockExecutor) ExecuteCommandWithOutputContext(ctx context.Context, command string, arg ...string) (string, error) {
+	<-ctx.Done()
+	e.killed <- struct{}{}
+	return "", ctx.Err()
+}
+
+func (e *contextMockExecutor) ExecuteCommandWithCombinedOutputContext(ctx context.Context, command string, arg ...string) (string, error) {
+	return e.ExecuteCommandWithOutputContext(ctx, command, arg...)
+}
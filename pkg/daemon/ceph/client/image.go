@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -73,6 +74,33 @@ func ListImagesInRadosNamespace(context *clusterd.Context, clusterInfo *ClusterI
 	return images, nil
 }
 
+// ListImageNamesInRadosNamespace returns the names of the images of a cephblockpool rados namespace, without
+// opening each image like ListImagesInRadosNamespace
+func ListImageNamesInRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespace string) ([]string, error) {
+	args := []string{"ls", poolName}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list image names for pool %s", poolName)
+	}
+
+	// the json result is at the end of the output when the log level is DEBUG, see ListImagesInRadosNamespace
+	res := regexp.MustCompile(`(?m)^\[(.*)\]`).FindStringSubmatch(string(buf))
+	if len(res) == 0 {
+		return []string{}, nil
+	}
+
+	var names []string
+	if err = json.Unmarshal([]byte(res[0]), &names); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal failed, raw buffer response: %s", string(buf))
+	}
+	return names, nil
+}
+
 // ListSnapshotsInRadosNamespace lists all the snapshots created for an image in a cephblockpool in a given rados namespace
 func ListSnapshotsInRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName, namespace string) ([]CephBlockImageSnapshot, error) {
 	snapshots := []CephBlockImageSnapshot{}
@@ -132,6 +160,23 @@ func DeleteImageFromTrashInRadosNamespace(context *clusterd.Context, clusterInfo
 	return nil
 }
 
+// CreateImageInRadosNamespace creates an image of the given size in MB in the rados namespace of a cephblockpool,
+// with the given image features or the default features if none
+func CreateImageInRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName, namespace string, sizeMB uint64, features []string) error {
+	args := []string{"create", getImageSpec(imageName, poolName), "--size", strconv.FormatUint(sizeMB, 10)}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	if len(features) > 0 {
+		args = append(args, "--image-feature", strings.Join(features, ","))
+	}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create image %q in cephblockpool %q, output: %s", imageName, poolName, string(buf))
+	}
+	return nil
+}
+
 func DeleteImageInPool(context *clusterd.Context, clusterInfo *ClusterInfo, name, poolName string) error {
 	return DeleteImageInRadosNamespace(context, clusterInfo, name, poolName, "")
 }
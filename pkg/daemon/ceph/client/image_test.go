@@ -146,3 +146,51 @@ func TestGetWatcherIPs(t *testing.T) {
 	assert.Equal(t, "192.168.39.137", res[0])
 	assert.Equal(t, "192.168.39.136", res[1])
 }
+
+func TestCreateImageInRadosNamespace(t *testing.T) {
+	var createArgs []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "create" {
+				createArgs = args
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := CreateImageInRadosNamespace(context, AdminTestClusterInfo("mycluster"), "replicapool", "marker", "namespace-a", 1, []string{"layering", "exclusive-lock"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create", "replicapool/marker", "--size", "1", "--namespace", "namespace-a", "--image-feature", "layering,exclusive-lock"}, createArgs[:8])
+
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "", errors.New("failed")
+	}
+	err = CreateImageInRadosNamespace(context, AdminTestClusterInfo("mycluster"), "replicapool", "marker", "", 1, nil)
+	assert.Error(t, err)
+}
+
+func TestListImageNamesInRadosNamespace(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "ls" {
+				assert.Equal(t, []string{"ls", "replicapool", "--namespace", "namespace-a"}, args[:4])
+				return "debug output\n[\"csi-vol-1\",\"csi-vol-2\",\"csi-vol-3\"]", nil
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	names, err := ListImageNamesInRadosNamespace(context, AdminTestClusterInfo("mycluster"), "replicapool", "namespace-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"csi-vol-1", "csi-vol-2", "csi-vol-3"}, names)
+
+	// an empty rados namespace
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		return "", nil
+	}
+	names, err = ListImageNamesInRadosNamespace(context, AdminTestClusterInfo("mycluster"), "replicapool", "namespace-a")
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
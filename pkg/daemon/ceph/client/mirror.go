@@ -348,6 +348,45 @@ func removeSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo
 	return nil
 }
 
+// ReconcileSnapshotSchedules converges the snapshot schedules of the pool or pool/radosNamespace to the
+// desired schedules. Only the existing schedules that are not desired are removed and only the desired
+// schedules that do not exist yet are added.
+func ReconcileSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, snapshotSchedules []cephv1.SnapshotScheduleSpec) error {
+	existingSnapshotSchedules, err := listSnapshotSchedules(context, clusterInfo, poolName)
+	if err != nil {
+		return errors.Wrap(err, "failed to list snapshot schedule(s)")
+	}
+
+	desired := map[cephv1.SnapshotSchedule]bool{}
+	for _, snapSchedule := range snapshotSchedules {
+		desired[cephv1.SnapshotSchedule{Interval: snapSchedule.Interval, StartTime: snapSchedule.StartTime}] = true
+	}
+
+	existing := map[cephv1.SnapshotSchedule]bool{}
+	for _, existingSnapshotSchedule := range existingSnapshotSchedules {
+		existing[existingSnapshotSchedule] = true
+		if desired[existingSnapshotSchedule] {
+			continue
+		}
+		err := removeSnapshotSchedule(context, clusterInfo, existingSnapshotSchedule, poolName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove snapshot schedule %v", existingSnapshotSchedule)
+		}
+	}
+
+	for _, snapSchedule := range snapshotSchedules {
+		if existing[cephv1.SnapshotSchedule{Interval: snapSchedule.Interval, StartTime: snapSchedule.StartTime}] {
+			continue
+		}
+		err := enableSnapshotSchedule(context, clusterInfo, snapSchedule, poolName)
+		if err != nil {
+			return errors.Wrap(err, "failed to enable snapshot schedule")
+		}
+	}
+
+	return nil
+}
+
 // listSnapshotSchedules configures the snapshots schedule on a mirrored pool
 func listSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) ([]cephv1.SnapshotSchedule, error) {
 	// Build command
@@ -394,7 +433,71 @@ func ListSnapshotSchedulesRecursively(context *clusterd.Context, clusterInfo *Cl
 	return snapshotSchedulesRecursive, nil
 }
 
-// EnableRBDRadosNamespaceMirroring enables rbd mirroring on a rados namespace.
+// EnableImageMirroring enables the snapshot-based mirroring of an image of a pool or rados namespace
+func EnableImageMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName, imageName string) error {
+	imageSpec := fmt.Sprintf("%s/%s", poolAndRadosNamespaceName, imageName)
+	args := []string{"mirror", "image", "enable", imageSpec, "snapshot"}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable mirroring of image %q. %s", imageSpec, output)
+	}
+
+	logger.Infof("successfully enabled mirroring of image %q", imageSpec)
+	return nil
+}
+
+// DisableImageMirroring disables the mirroring of an image of the pool or pool/radosNamespace
+func DisableImageMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName, imageName string) error {
+	imageSpec := fmt.Sprintf("%s/%s", poolAndRadosNamespaceName, imageName)
+	args := []string{"mirror", "image", "disable", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable mirroring of image %q. %s", imageSpec, output)
+	}
+
+	logger.Infof("successfully disabled mirroring of image %q", imageSpec)
+	return nil
+}
+
+// PromoteImage promotes an image of the pool or pool/radosNamespace to primary. The promotion is forced if the
+// peer is not reachable to demote its image first.
+func PromoteImage(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName, imageName string, force bool) error {
+	imageSpec := fmt.Sprintf("%s/%s", poolAndRadosNamespaceName, imageName)
+	args := []string{"mirror", "image", "promote", imageSpec}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to promote image %q. %s", imageSpec, output)
+	}
+
+	logger.Infof("successfully promoted image %q", imageSpec)
+	return nil
+}
+
+// DemoteImage demotes a primary image of the pool or pool/radosNamespace to non-primary
+func DemoteImage(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName, imageName string) error {
+	imageSpec := fmt.Sprintf("%s/%s", poolAndRadosNamespaceName, imageName)
+	args := []string{"mirror", "image", "demote", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to demote image %q. %s", imageSpec, output)
+	}
+
+	logger.Infof("successfully demoted image %q", imageSpec)
+	return nil
+}
+
+// EnableRBDRadosNamespaceMirroring enables rbd mirroring on a rados namespace
 func EnableRBDRadosNamespaceMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName string, remoteNamespace *string, mode string) error {
 	logger.Infof("enable mirroring in rados namespace %s in k8s namespace %q", poolAndRadosNamespaceName, clusterInfo.Namespace)
 
@@ -423,6 +526,19 @@ func EnableRBDRadosNamespaceMirroring(context *clusterd.Context, clusterInfo *Cl
 	return nil
 }
 
+// SetRBDMirrorPeerDirection sets the mirroring direction of a peer of the pool
+func SetRBDMirrorPeerDirection(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, peerUUID, direction string) error {
+	logger.Infof("setting mirroring peer %q direction to %q for pool %q", peerUUID, direction, poolName)
+	args := []string{"mirror", "pool", "peer", "set", poolName, peerUUID, "direction", direction}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set direction %q on mirroring peer %q for pool %q. %s", direction, peerUUID, poolName, output)
+	}
+
+	return nil
+}
+
 func DisableRBDRadosNamespaceMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName string) error {
 	logger.Infof("disable mirroring in rados namespace %s in k8s namespace %q", poolAndRadosNamespaceName, clusterInfo.Namespace)
 	args := []string{"mirror", "pool", "disable", poolAndRadosNamespaceName}
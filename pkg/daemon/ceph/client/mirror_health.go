@@ -18,18 +18,34 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var defaultHealthCheckInterval = 1 * time.Minute
 
+// MirroringRecoveredEventReason is the reason of the event recorded when the mirroring of a rados namespace
+// recovers from an error
+const MirroringRecoveredEventReason = "MirroringRecovered"
+
+const (
+	// MirroringEnabledAtInfoKey is the status info key of the time mirroring was last enabled on a rados namespace
+	MirroringEnabledAtInfoKey = "mirroringEnabledAt"
+	// MirroringInitializingHealth is the mirroring health reported instead of the errors during the grace period
+	// after mirroring was enabled on a rados namespace
+	MirroringInitializingHealth = "INITIALIZING"
+)
+
 type mirrorChecker struct {
 	context        *clusterd.Context
 	interval       *time.Duration
@@ -38,6 +54,11 @@ type mirrorChecker struct {
 	namespacedName types.NamespacedName
 	monitoringSpec *cephv1.NamedPoolSpec
 	objectType     client.Object
+	checkTimeout   time.Duration
+	heartbeat      func()
+	recorder       record.EventRecorder
+	enabledAt      time.Time
+	gracePeriod    time.Duration
 }
 
 // newMirrorChecker creates a new HealthChecker object
@@ -62,10 +83,49 @@ func NewMirrorChecker(context *clusterd.Context, client client.Client, clusterIn
 	return c
 }
 
+// SetCheckTimeout bounds the duration of each mirroring health check, the checks are not bounded by default
+func (c *mirrorChecker) SetCheckTimeout(timeout time.Duration) {
+	c.checkTimeout = timeout
+}
+
+// SetHeartbeat sets a function called before each mirroring health check, so that the caller can detect a
+// checker that stopped
+func (c *mirrorChecker) SetHeartbeat(heartbeat func()) {
+	c.heartbeat = heartbeat
+}
+
+// SetEventRecorder sets the recorder of the event reporting that the mirroring recovered from an error, no
+// event is recorded by default
+func (c *mirrorChecker) SetEventRecorder(recorder record.EventRecorder) {
+	c.recorder = recorder
+}
+
+// SetGracePeriod sets the period after mirroring was enabled during which the errors of a rados namespace are
+// reported as the INITIALIZING health, since the mirroring status often reports transient errors until the
+// mirroring daemons pick up the rados namespace. The time mirroring was last enabled is also read from the status
+// of the rados namespace, so that the grace period applies when a running checker sees mirroring enabled again.
+// There is no grace period by default.
+func (c *mirrorChecker) SetGracePeriod(enabledAt time.Time, gracePeriod time.Duration) {
+	c.enabledAt = enabledAt
+	c.gracePeriod = gracePeriod
+}
+
+// Interval returns the interval between two mirroring health checks
+func (c *mirrorChecker) Interval() time.Duration {
+	return *c.interval
+}
+
+func (c *mirrorChecker) beat() {
+	if c.heartbeat != nil {
+		c.heartbeat()
+	}
+}
+
 // checkMirroring periodically checks the health of the cluster
 func (c *mirrorChecker) CheckMirroring(context context.Context) {
 	// check the mirroring health immediately before starting the loop
-	err := c.CheckMirroringHealth()
+	c.beat()
+	err := c.checkMirroringHealthWithTimeout(context)
 	if err != nil {
 		c.UpdateStatusMirroring(nil, nil, nil, err.Error())
 		logger.Debugf("failed to check mirroring status for %q. %v", c.namespacedName.Name, err)
@@ -79,7 +139,8 @@ func (c *mirrorChecker) CheckMirroring(context context.Context) {
 
 		case <-time.After(*c.interval):
 			logger.Debugf("checking mirroring status for %q", c.namespacedName.Name)
-			err := c.CheckMirroringHealth()
+			c.beat()
+			err := c.checkMirroringHealthWithTimeout(context)
 			if err != nil {
 				c.UpdateStatusMirroring(nil, nil, nil, err.Error())
 				logger.Debugf("failed to check mirroring status for %q. %v", c.namespacedName.Name, err)
@@ -88,17 +149,34 @@ func (c *mirrorChecker) CheckMirroring(context context.Context) {
 	}
 }
 
+// checkMirroringHealthWithTimeout checks the mirroring health, giving up after the check timeout if one is set
+// so that a hung ceph command does not stall the checker
+func (c *mirrorChecker) checkMirroringHealthWithTimeout(ctx context.Context) error {
+	if c.checkTimeout <= 0 {
+		return c.CheckMirroringHealth()
+	}
+	return CallWithTimeout(ctx, c.clusterInfo, c.checkTimeout, func(clusterInfo *ClusterInfo) error {
+		checker := *c
+		checker.clusterInfo = clusterInfo
+		return checker.CheckMirroringHealth()
+	})
+}
+
 func (c *mirrorChecker) CheckMirroringHealth() error {
+	// The errors are reported in the details of a single status update, so that a failed step is not reported as
+	// a recovery by the update of the successful steps
+	var failures []string
+
 	// Check mirroring status
 	mirrorStatus, err := GetPoolMirroringStatus(c.context, c.clusterInfo, c.monitoringSpec.Name)
 	if err != nil {
-		c.UpdateStatusMirroring(nil, nil, nil, err.Error())
+		failures = append(failures, err.Error())
 	}
 
 	// Check mirroring info
 	mirrorInfo, err := GetPoolMirroringInfo(c.context, c.clusterInfo, c.monitoringSpec.Name)
 	if err != nil {
-		c.UpdateStatusMirroring(nil, nil, nil, err.Error())
+		failures = append(failures, err.Error())
 	}
 
 	// If snapshot scheduling is enabled let's add it to the status
@@ -107,25 +185,97 @@ func (c *mirrorChecker) CheckMirroringHealth() error {
 	if c.monitoringSpec.Mirroring.SnapshotSchedulesEnabled() {
 		snapSchedStatus, err = ListSnapshotSchedulesRecursively(c.context, c.clusterInfo, c.monitoringSpec.Name)
 		if err != nil {
-			c.UpdateStatusMirroring(nil, nil, nil, err.Error())
+			failures = append(failures, err.Error())
 		}
 	}
 
-	// On success
+	// The mirroring status of each peer is only reported for a rados namespace mirrored toward several peers
+	var peerStatuses []cephv1.MirroringPeerStatus
+	if _, ok := c.objectType.(*cephv1.CephBlockPoolRadosNamespace); ok && mirrorInfo != nil && len(mirrorInfo.Peers) > 1 {
+		images, err := GetMirroredImageStatuses(c.context, c.clusterInfo, c.monitoringSpec.Name, nil)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else {
+			peerStatuses = MirroringPeerStatuses(mirrorInfo.Peers, images)
+		}
+	}
+
+	var summary *cephv1.MirroringStatusSummarySpec
 	if mirrorStatus != nil {
-		c.UpdateStatusMirroring(mirrorStatus.Summary, mirrorInfo, snapSchedStatus, "")
+		summary = mirrorStatus.Summary
+	}
+	if mirrorStatus != nil || len(failures) > 0 {
+		c.updateStatusMirroring(summary, mirrorInfo, snapSchedStatus, peerStatuses, strings.Join(failures, "; "))
 	}
 	return nil
 }
 
+// MirroringPeerStatuses returns the mirroring status of the images on each peer. An image that is not reported
+// on a peer, or whose mirroring daemon is down on the peer, is counted in the unknown state.
+func MirroringPeerStatuses(peers []cephv1.PeersSpec, images []MirrorImageStatus) []cephv1.MirroringPeerStatus {
+	statuses := make([]cephv1.MirroringPeerStatus, 0, len(peers))
+	for _, peer := range peers {
+		status := cephv1.MirroringPeerStatus{SiteName: peer.SiteName}
+		for _, image := range images {
+			state := "unknown"
+			for _, site := range image.PeerSites {
+				if site.SiteName != peer.SiteName {
+					continue
+				}
+				if daemonState, imageState, found := strings.Cut(site.State, "+"); found && daemonState == "up" {
+					state = imageState
+				}
+			}
+			addMirroringState(&status.States, state)
+		}
+
+		switch {
+		case status.States.Error > 0:
+			status.Health = "ERROR"
+			status.Details = fmt.Sprintf("%d of %d images are in error on the peer", status.States.Error, len(images))
+		case status.States.Unknown > 0:
+			status.Health = "WARNING"
+			status.Details = fmt.Sprintf("%d of %d images are not replicated by a running mirroring daemon on the peer", status.States.Unknown, len(images))
+		default:
+			status.Health = "OK"
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// addMirroringState counts an image in its mirroring state
+func addMirroringState(states *cephv1.StatesSpec, state string) {
+	switch state {
+	case "starting_replay":
+		states.StartingReplay++
+	case "replaying":
+		states.Replaying++
+	case "syncing":
+		states.Syncing++
+	case "stopping_replay":
+		states.StopReplaying++
+	case "stopped":
+		states.Stopped++
+	case "error":
+		states.Error++
+	default:
+		states.Unknown++
+	}
+}
+
 // updateStatusBucket updates an object with a given status
 func (c *mirrorChecker) UpdateStatusMirroring(mirrorStatus *cephv1.MirroringStatusSummarySpec, mirrorInfo *cephv1.MirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, details string) {
+	c.updateStatusMirroring(mirrorStatus, mirrorInfo, snapSchedStatus, nil, details)
+}
+
+func (c *mirrorChecker) updateStatusMirroring(mirrorStatus *cephv1.MirroringStatusSummarySpec, mirrorInfo *cephv1.MirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, peerStatuses []cephv1.MirroringPeerStatus, details string) {
 	switch c.objectType.(type) {
 	case *cephv1.CephBlockPool:
 		updatePoolStatusMirroring(c, mirrorStatus, mirrorInfo, snapSchedStatus, details)
 
 	case *cephv1.CephBlockPoolRadosNamespace:
-		updateRadosNamespaceStatusMirroring(c, mirrorStatus, mirrorInfo, snapSchedStatus, details)
+		updateRadosNamespaceStatusMirroring(c, mirrorStatus, mirrorInfo, snapSchedStatus, peerStatuses, details)
 	}
 }
 
@@ -153,7 +303,7 @@ func updatePoolStatusMirroring(c *mirrorChecker, mirrorStatus *cephv1.MirroringS
 	logger.Debugf("ceph block pool %q mirroring status updated", c.namespacedName.Name)
 }
 
-func updateRadosNamespaceStatusMirroring(c *mirrorChecker, mirrorStatus *cephv1.MirroringStatusSummarySpec, mirrorInfo *cephv1.MirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, details string) {
+func updateRadosNamespaceStatusMirroring(c *mirrorChecker, mirrorStatus *cephv1.MirroringStatusSummarySpec, mirrorInfo *cephv1.MirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, peerStatuses []cephv1.MirroringPeerStatus, details string) {
 	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
 	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, radosNamespace); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -183,12 +333,82 @@ func updateRadosNamespaceStatusMirroring(c *mirrorChecker, mirrorStatus *cephv1.
 		return
 	}
 
+	if c.isInitializing(radosNamespace, time.Now()) && (details != "" || (mirrorStatus != nil && mirrorStatus.Health == "ERROR")) {
+		logger.Debugf("mirroring of rados namespace %q is initializing, ignoring the mirroring errors. %s", c.namespacedName.Name, details)
+		mirrorStatus = initializingMirroringStatus(mirrorStatus)
+		details = ""
+	}
+
 	// Update the CephBlockPoolRadosNamespace CR status field
+	previousError := mirroringError(radosNamespace.Status.MirroringStatus)
 	radosNamespace.Status.MirroringStatus, radosNamespace.Status.MirroringInfo, radosNamespace.Status.SnapshotScheduleStatus = toCustomResourceStatus(radosNamespace.Status.MirroringStatus, mirrorStatus, radosNamespace.Status.MirroringInfo, mirrorInfo, radosNamespace.Status.SnapshotScheduleStatus, snapSchedStatus, details)
+	radosNamespace.Status.MirroringStatus.Peers = peerStatuses
+	recovered := previousError != "" && mirroringRecovered(mirrorStatus, details)
+	if recovered {
+		// the health changed, the details of the error are already cleared since the check succeeded
+		radosNamespace.Status.MirroringStatus.LastChanged = time.Now().UTC().Format(time.RFC3339)
+	}
 	if err := reporting.UpdateStatus(c.client, radosNamespace); err != nil {
 		logger.Errorf("failed to set ceph block pool rados namespace %q mirroring status. %v", c.namespacedName.Name, err)
 		return
 	}
+
+	if recovered {
+		message := fmt.Sprintf("mirroring health of rados namespace %q recovered to %q after %s", c.namespacedName.Name, mirrorStatus.Health, previousError)
+		logger.Info(message)
+		if c.recorder != nil {
+			c.recorder.Event(radosNamespace, v1.EventTypeNormal, MirroringRecoveredEventReason, message)
+		}
+	}
+}
+
+// isInitializing returns whether the rados namespace is in the grace period after mirroring was enabled
+func (c *mirrorChecker) isInitializing(radosNamespace *cephv1.CephBlockPoolRadosNamespace, now time.Time) bool {
+	if c.gracePeriod <= 0 {
+		return false
+	}
+	enabledAt := c.enabledAt
+	if radosNamespace.Status != nil {
+		recorded, err := time.Parse(time.RFC3339, radosNamespace.Status.Info[MirroringEnabledAtInfoKey])
+		if err == nil && recorded.After(enabledAt) {
+			enabledAt = recorded
+		}
+	}
+	return !enabledAt.IsZero() && now.Before(enabledAt.Add(c.gracePeriod))
+}
+
+// initializingMirroringStatus returns the mirroring status reported during the grace period, the image states
+// of the mirroring status are kept if it was retrieved
+func initializingMirroringStatus(mirrorStatus *cephv1.MirroringStatusSummarySpec) *cephv1.MirroringStatusSummarySpec {
+	initializing := &cephv1.MirroringStatusSummarySpec{}
+	if mirrorStatus != nil {
+		initializing = mirrorStatus.DeepCopy()
+	}
+	initializing.Health = MirroringInitializingHealth
+	return initializing
+}
+
+// mirroringError returns a description of the error reported by the mirroring status, or an empty string if the
+// mirroring status does not report an error
+func mirroringError(status *cephv1.MirroringStatusSpec) string {
+	if status == nil {
+		return ""
+	}
+	if status.Details != "" {
+		return fmt.Sprintf("the failure to check the mirroring status: %s", status.Details)
+	}
+	if status.Summary != nil && status.Summary.Health == "ERROR" {
+		return "the \"ERROR\" health"
+	}
+	return ""
+}
+
+// mirroringRecovered returns whether the mirroring health check succeeded with a health that is not an error
+func mirroringRecovered(mirrorStatus *cephv1.MirroringStatusSummarySpec, details string) bool {
+	if mirrorStatus == nil || details != "" {
+		return false
+	}
+	return mirrorStatus.Health == "OK" || mirrorStatus.Health == "WARNING"
 }
 
 func toCustomResourceStatus(currentStatus *cephv1.MirroringStatusSpec, mirroringStatus *cephv1.MirroringStatusSummarySpec,
@@ -1,10 +1,20 @@
 package client
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestToCustomResourceStatus(t *testing.T) {
@@ -41,3 +51,280 @@ func TestToCustomResourceStatus(t *testing.T) {
 		assert.NotEmpty(t, newSnapshotScheduleStatus)
 	}
 }
+
+func TestCheckMirroringHealthTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			<-unblock
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{Executor: executor}
+	monitoringSpec := &cephv1.NamedPoolSpec{Name: "replicapool/namespace-a"}
+	checker := NewMirrorChecker(c, nil, AdminTestClusterInfo("mycluster"), types.NamespacedName{Name: "namespace-a", Namespace: "mycluster"}, monitoringSpec, nil)
+
+	checker.SetCheckTimeout(50 * time.Millisecond)
+	start := time.Now()
+	err := checker.checkMirroringHealthWithTimeout(context.TODO())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestMirroringPeerStatuses(t *testing.T) {
+	peers := []cephv1.PeersSpec{{SiteName: "site-b"}, {SiteName: "site-c"}}
+	images := []MirrorImageStatus{
+		{Name: "csi-vol-1", PeerSites: []MirrorImagePeerSiteStatus{{SiteName: "site-b", State: "up+replaying"}, {SiteName: "site-c", State: "up+error"}}},
+		{Name: "csi-vol-2", PeerSites: []MirrorImagePeerSiteStatus{{SiteName: "site-b", State: "up+syncing"}, {SiteName: "site-c", State: "up+replaying"}}},
+	}
+
+	statuses := MirroringPeerStatuses(peers, images)
+	assert.Equal(t, []cephv1.MirroringPeerStatus{
+		{SiteName: "site-b", Health: "OK", States: cephv1.StatesSpec{Replaying: 1, Syncing: 1}},
+		{SiteName: "site-c", Health: "ERROR", States: cephv1.StatesSpec{Replaying: 1, Error: 1}, Details: "1 of 2 images are in error on the peer"},
+	}, statuses)
+
+	// the images not reported on a peer or with a mirroring daemon down are unknown
+	images[0].PeerSites = []MirrorImagePeerSiteStatus{{SiteName: "site-c", State: "down+replaying"}}
+	statuses = MirroringPeerStatuses(peers, images)
+	assert.Equal(t, "WARNING", statuses[0].Health)
+	assert.Equal(t, cephv1.StatesSpec{Syncing: 1, Unknown: 1}, statuses[0].States)
+	assert.Equal(t, "WARNING", statuses[1].Health)
+	assert.Equal(t, cephv1.StatesSpec{Replaying: 1, Unknown: 1}, statuses[1].States)
+}
+
+func TestCheckMirroringHealthPeerStatuses(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "mycluster"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+
+	tests := []struct {
+		name        string
+		peers       string
+		expectPeers []string
+	}{
+		{name: "single peer", peers: `{"uuid":"1","site_name":"site-b"}`},
+		{name: "two peers", peers: `{"uuid":"1","site_name":"site-b"},{"uuid":"2","site_name":"site-c"}`, expectPeers: []string{"site-b", "site-c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" {
+						switch {
+						case args[2] == "info":
+							return `{"mode":"image","peers":[` + tt.peers + `]}`, nil
+						case args[2] == "status" && args[3] == "--verbose":
+							return `{"images":[{"name":"csi-vol-1","peer_sites":[{"site_name":"site-b","state":"up+replaying"},{"site_name":"site-c","state":"up+error"}]}]}`, nil
+						case args[2] == "status":
+							return `{"summary":{"health":"ERROR"}}`, nil
+						}
+					}
+					return "", errors.New("unknown command")
+				},
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace.DeepCopy(), blockPool).Build()
+			clusterInfo := AdminTestClusterInfo("mycluster")
+			monitoringSpec := &cephv1.NamedPoolSpec{Name: "replicapool/namespace-a"}
+			checker := NewMirrorChecker(&clusterd.Context{Executor: executor}, cl, clusterInfo, name, monitoringSpec, radosNamespace)
+
+			assert.NoError(t, checker.CheckMirroringHealth())
+			current := &cephv1.CephBlockPoolRadosNamespace{}
+			assert.NoError(t, cl.Get(clusterInfo.Context, name, current))
+			assert.Equal(t, "ERROR", current.Status.MirroringStatus.Summary.Health)
+			var siteNames []string
+			for _, peer := range current.Status.MirroringStatus.Peers {
+				siteNames = append(siteNames, peer.SiteName)
+			}
+			assert.Equal(t, tt.expectPeers, siteNames)
+			if len(tt.expectPeers) > 0 {
+				assert.Equal(t, "OK", current.Status.MirroringStatus.Peers[0].Health)
+				assert.Equal(t, "ERROR", current.Status.MirroringStatus.Peers[1].Health)
+			}
+		})
+	}
+}
+
+func TestUpdateStatusMirroringRecovery(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "mycluster"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{
+			MirroringStatus: &cephv1.MirroringStatusSpec{
+				MirroringStatus: cephv1.MirroringStatus{Summary: &cephv1.MirroringStatusSummarySpec{Health: "ERROR"}},
+			},
+		},
+	}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace, blockPool).Build()
+	clusterInfo := AdminTestClusterInfo("mycluster")
+	monitoringSpec := &cephv1.NamedPoolSpec{Name: "replicapool/namespace-a"}
+	checker := NewMirrorChecker(&clusterd.Context{}, cl, clusterInfo, name, monitoringSpec, radosNamespace)
+	recorder := record.NewFakeRecorder(5)
+	checker.SetEventRecorder(recorder)
+	current := func() *cephv1.MirroringStatusSpec {
+		rns := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(clusterInfo.Context, name, rns))
+		return rns.Status.MirroringStatus
+	}
+
+	t.Run("error health recovers to ok", func(t *testing.T) {
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "OK"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		status := current()
+		assert.Equal(t, "OK", status.Summary.Health)
+		assert.Empty(t, status.Details)
+		assert.NotEmpty(t, status.LastChanged)
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Normal "+MirroringRecoveredEventReason)
+	})
+
+	t.Run("healthy mirroring records no event", func(t *testing.T) {
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "WARNING"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		assert.Equal(t, "WARNING", current().Summary.Health)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("failed check recovers to warning", func(t *testing.T) {
+		checker.UpdateStatusMirroring(nil, nil, nil, "failed to retrieve mirroring pool status")
+		status := current()
+		assert.Nil(t, status.Summary)
+		assert.Equal(t, "failed to retrieve mirroring pool status", status.Details)
+		assert.Empty(t, recorder.Events)
+
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "WARNING"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		status = current()
+		assert.Equal(t, "WARNING", status.Summary.Health)
+		assert.Empty(t, status.Details)
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Normal "+MirroringRecoveredEventReason)
+	})
+
+	t.Run("error health is not a recovery", func(t *testing.T) {
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "ERROR"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "ERROR"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		assert.Equal(t, "ERROR", current().Summary.Health)
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestCheckMirroringHealthPartialFailure(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "mycluster"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	infoFails := true
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" {
+				switch args[2] {
+				case "info":
+					if infoFails {
+						return "", errors.New("timed out")
+					}
+					return `{"mode":"image"}`, nil
+				case "status":
+					return `{"summary":{"health":"OK"}}`, nil
+				}
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace, blockPool).Build()
+	clusterInfo := AdminTestClusterInfo("mycluster")
+	monitoringSpec := &cephv1.NamedPoolSpec{Name: "replicapool/namespace-a"}
+	checker := NewMirrorChecker(&clusterd.Context{Executor: executor}, cl, clusterInfo, name, monitoringSpec, radosNamespace)
+	recorder := record.NewFakeRecorder(5)
+	checker.SetEventRecorder(recorder)
+	current := func() *cephv1.MirroringStatusSpec {
+		rns := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(clusterInfo.Context, name, rns))
+		return rns.Status.MirroringStatus
+	}
+
+	// the failure of a step is kept in the details instead of being cleared by the successful steps
+	assert.NoError(t, checker.CheckMirroringHealth())
+	assert.Equal(t, "OK", current().Summary.Health)
+	assert.NotEmpty(t, current().Details)
+	assert.NoError(t, checker.CheckMirroringHealth())
+	assert.Empty(t, recorder.Events)
+
+	infoFails = false
+	assert.NoError(t, checker.CheckMirroringHealth())
+	assert.Empty(t, current().Details)
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestUpdateStatusMirroringGracePeriod(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "mycluster"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	blockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace, blockPool).Build()
+	clusterInfo := AdminTestClusterInfo("mycluster")
+	monitoringSpec := &cephv1.NamedPoolSpec{Name: "replicapool/namespace-a"}
+	checker := NewMirrorChecker(&clusterd.Context{}, cl, clusterInfo, name, monitoringSpec, radosNamespace)
+	recorder := record.NewFakeRecorder(5)
+	checker.SetEventRecorder(recorder)
+	current := func() *cephv1.MirroringStatusSpec {
+		rns := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(clusterInfo.Context, name, rns))
+		return rns.Status.MirroringStatus
+	}
+
+	t.Run("errors during the grace period are reported as initializing", func(t *testing.T) {
+		checker.SetGracePeriod(time.Now(), time.Minute)
+		checker.UpdateStatusMirroring(nil, nil, nil, "failed to retrieve mirroring pool status")
+		status := current()
+		assert.Equal(t, MirroringInitializingHealth, status.Summary.Health)
+		assert.Empty(t, status.Details)
+
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "ERROR", States: cephv1.StatesSpec{StartingReplay: 2}}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		status = current()
+		assert.Equal(t, MirroringInitializingHealth, status.Summary.Health)
+		assert.Equal(t, 2, status.Summary.States.StartingReplay)
+		assert.Empty(t, status.Details)
+	})
+
+	t.Run("healthy mirroring is reported during the grace period", func(t *testing.T) {
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "OK"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		assert.Equal(t, "OK", current().Summary.Health)
+		// initializing is not an error, its end is not a recovery
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("errors are reported after the grace period", func(t *testing.T) {
+		checker.SetGracePeriod(time.Now().Add(-2*time.Minute), time.Minute)
+		checker.UpdateStatusMirroring(&cephv1.MirroringStatusSummarySpec{Health: "ERROR"}, &cephv1.MirroringInfo{Mode: "image"}, nil, "")
+		assert.Equal(t, "ERROR", current().Summary.Health)
+
+		checker.UpdateStatusMirroring(nil, nil, nil, "failed to retrieve mirroring pool status")
+		status := current()
+		assert.Nil(t, status.Summary)
+		assert.Equal(t, "failed to retrieve mirroring pool status", status.Details)
+	})
+
+	t.Run("mirroring enabled again in the status restarts the grace period", func(t *testing.T) {
+		rns := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(clusterInfo.Context, name, rns))
+		rns.Status.Info = map[string]string{MirroringEnabledAtInfoKey: time.Now().UTC().Format(time.RFC3339)}
+		assert.NoError(t, cl.Update(clusterInfo.Context, rns))
+
+		checker.UpdateStatusMirroring(nil, nil, nil, "failed to retrieve mirroring pool status")
+		assert.Equal(t, MirroringInitializingHealth, current().Summary.Health)
+	})
+
+	t.Run("no grace period", func(t *testing.T) {
+		checker.SetGracePeriod(time.Now(), 0)
+		checker.UpdateStatusMirroring(nil, nil, nil, "failed to retrieve mirroring pool status")
+		assert.Equal(t, "failed to retrieve mirroring pool status", current().Details)
+	})
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// DefaultMirrorImageStatusCacheSize is the default maximum number of parsed image statuses kept in a
+// MirrorImageStatusCache
+const DefaultMirrorImageStatusCacheSize = 10000
+
+// MirrorImageStatus is the mirroring status of an image in the verbose mirror pool status
+type MirrorImageStatus struct {
+	Name        string                      `json:"name"`
+	GlobalID    string                      `json:"global_id"`
+	State       string                      `json:"state"`
+	Description string                      `json:"description"`
+	LastUpdate  string                      `json:"last_update"`
+	PeerSites   []MirrorImagePeerSiteStatus `json:"peer_sites,omitempty"`
+}
+
+// MirrorImagePeerSiteStatus is the mirroring status of an image on a peer site
+type MirrorImagePeerSiteStatus struct {
+	SiteName    string `json:"site_name"`
+	MirrorUUIDs string `json:"mirror_uuids"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	LastUpdate  string `json:"last_update"`
+}
+
+// mirroredImageStatuses is the verbose mirror pool status, with the statuses of the images left unparsed
+type mirroredImageStatuses struct {
+	Images []json.RawMessage `json:"images"`
+}
+
+// MirrorImageStatusCache is a LRU cache of the parsed statuses of the mirrored images. Ceph always returns the
+// status of all the images, so the cache is keyed by the raw status of each image: the images whose status did
+// not change since the previous check are not parsed again. The number of cached statuses is bounded, the least
+// recently used statuses are evicted first, which includes the previous statuses of the changed images.
+type MirrorImageStatusCache struct {
+	mutex    sync.Mutex
+	maxSize  int
+	entries  map[uint64]*list.Element
+	lruOrder *list.List
+}
+
+type mirrorImageStatusCacheEntry struct {
+	key    uint64
+	raw    []byte
+	status MirrorImageStatus
+}
+
+// NewMirrorImageStatusCache returns a cache of at most maxSize parsed image statuses
+func NewMirrorImageStatusCache(maxSize int) *MirrorImageStatusCache {
+	if maxSize <= 0 {
+		maxSize = DefaultMirrorImageStatusCacheSize
+	}
+	return &MirrorImageStatusCache{
+		maxSize:  maxSize,
+		entries:  map[uint64]*list.Element{},
+		lruOrder: list.New(),
+	}
+}
+
+// Len returns the number of cached image statuses
+func (c *MirrorImageStatusCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lruOrder.Len()
+}
+
+// parse returns the parsed status of the raw image status, only parsing it if it is not cached
+func (c *MirrorImageStatusCache) parse(raw []byte) (MirrorImageStatus, error) {
+	hash := fnv.New64a()
+	_, _ = hash.Write(raw)
+	key := hash.Sum64()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*mirrorImageStatusCacheEntry)
+		// the raw status is compared in case of a hash collision
+		if bytes.Equal(entry.raw, raw) {
+			c.lruOrder.MoveToFront(element)
+			return entry.status, nil
+		}
+		c.lruOrder.Remove(element)
+		delete(c.entries, key)
+	}
+
+	var status MirrorImageStatus
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return MirrorImageStatus{}, err
+	}
+	entry := &mirrorImageStatusCacheEntry{key: key, raw: append([]byte(nil), raw...), status: status}
+	c.entries[key] = c.lruOrder.PushFront(entry)
+	for c.lruOrder.Len() > c.maxSize {
+		oldest := c.lruOrder.Back()
+		c.lruOrder.Remove(oldest)
+		delete(c.entries, oldest.Value.(*mirrorImageStatusCacheEntry).key)
+	}
+	return status, nil
+}
+
+// ParseMirroredImageStatuses parses the image statuses of the verbose mirror pool status. The statuses of the
+// images are taken from the cache when unchanged, the cache may be nil to parse all of them.
+func ParseMirroredImageStatuses(buf []byte, cache *MirrorImageStatusCache) ([]MirrorImageStatus, error) {
+	var statuses mirroredImageStatuses
+	if err := json.Unmarshal(buf, &statuses); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal mirror pool status response")
+	}
+
+	images := make([]MirrorImageStatus, 0, len(statuses.Images))
+	for _, raw := range statuses.Images {
+		var status MirrorImageStatus
+		var err error
+		if cache != nil {
+			status, err = cache.parse(raw)
+		} else {
+			err = json.Unmarshal(raw, &status)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal mirrored image status")
+		}
+		images = append(images, status)
+	}
+	return images, nil
+}
+
+// GetMirroredImageStatuses returns the mirroring statuses of the mirrored images of a pool or a
+// pool/radosNamespace, reusing the parsed statuses of the cache for the unchanged images
+func GetMirroredImageStatuses(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, cache *MirrorImageStatusCache) ([]MirrorImageStatus, error) {
+	logger.Debugf("retrieving mirrored image statuses for pool %q", poolName)
+
+	args := []string{"mirror", "pool", "status", "--verbose", poolName}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve mirroring pool %q status", poolName)
+	}
+
+	return ParseMirroredImageStatuses(buf, cache)
+}
+
+// GetMirrorImageStatus returns the mirroring status of an image of a pool or a pool/radosNamespace
+func GetMirrorImageStatus(context *clusterd.Context, clusterInfo *ClusterInfo, poolAndRadosNamespaceName, imageName string) (*MirrorImageStatus, error) {
+	imageSpec := fmt.Sprintf("%s/%s", poolAndRadosNamespaceName, imageName)
+	args := []string{"mirror", "image", "status", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve the mirroring status of image %q", imageSpec)
+	}
+
+	var status MirrorImageStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the mirroring status of image %q. %s", imageSpec, string(buf))
+	}
+	return &status, nil
+}
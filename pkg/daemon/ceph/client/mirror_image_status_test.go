@@ -0,0 +1,173 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// mirrorPoolStatusVerbose returns a verbose mirror pool status with the given number of images, the images
+// whose index is in changed report a different last update
+func mirrorPoolStatusVerbose(imageCount int, changed map[int]bool) string {
+	images := make([]string, 0, imageCount)
+	for i := 0; i < imageCount; i++ {
+		lastUpdate := "2025-01-01 10:00:00"
+		if changed[i] {
+			lastUpdate = "2025-01-01 10:00:30"
+		}
+		images = append(images, fmt.Sprintf(`{"name":"csi-vol-%d","global_id":"b7a3c5e6-%08d","state":"up+stopped",`+
+			`"description":"local image is primary","daemon_service":{"service_id":"4353","instance_id":"4355",`+
+			`"daemon_id":"a","hostname":"node-a"},"last_update":%q,"peer_sites":[{"site_name":"site-b",`+
+			`"mirror_uuids":"6a8d8c6e-e0e2-4c2a-9a3b-3b9a5f2e2f3c","state":"up+replaying","description":"replaying, `+
+			`{\"bytes_per_second\":0.0,\"bytes_per_snapshot\":0.0,\"local_snapshot_timestamp\":1735725600,`+
+			`\"remote_snapshot_timestamp\":1735725600,\"replay_state\":\"idle\"}","last_update":%q}]}`, i, i, lastUpdate, lastUpdate))
+	}
+	return `{"summary":{"health":"OK","daemon_health":"OK","image_health":"OK","states":{"replaying":` +
+		fmt.Sprint(imageCount) + `}},"daemons":[],"images":[` + strings.Join(images, ",") + `]}`
+}
+
+func TestParseMirroredImageStatuses(t *testing.T) {
+	t.Run("without cache", func(t *testing.T) {
+		statuses, err := ParseMirroredImageStatuses([]byte(mirrorPoolStatusVerbose(2, nil)), nil)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 2)
+		assert.Equal(t, "csi-vol-1", statuses[1].Name)
+		assert.Equal(t, "up+stopped", statuses[1].State)
+		assert.Equal(t, "up+replaying", statuses[1].PeerSites[0].State)
+	})
+
+	t.Run("only the changed images are parsed again", func(t *testing.T) {
+		cache := NewMirrorImageStatusCache(10)
+		statuses, err := ParseMirroredImageStatuses([]byte(mirrorPoolStatusVerbose(3, nil)), cache)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 3)
+		assert.Equal(t, 3, cache.Len())
+
+		// the unchanged statuses are reused
+		statuses, err = ParseMirroredImageStatuses([]byte(mirrorPoolStatusVerbose(3, nil)), cache)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 3)
+		assert.Equal(t, 3, cache.Len())
+
+		// the status of the changed image is parsed and cached
+		statuses, err = ParseMirroredImageStatuses([]byte(mirrorPoolStatusVerbose(3, map[int]bool{1: true})), cache)
+		assert.NoError(t, err)
+		assert.Equal(t, "2025-01-01 10:00:30", statuses[1].LastUpdate)
+		assert.Equal(t, "2025-01-01 10:00:00", statuses[0].LastUpdate)
+		assert.Equal(t, 4, cache.Len())
+	})
+
+	t.Run("the cache is bounded", func(t *testing.T) {
+		cache := NewMirrorImageStatusCache(2)
+		statuses, err := ParseMirroredImageStatuses([]byte(mirrorPoolStatusVerbose(5, nil)), cache)
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 5)
+		assert.Equal(t, 2, cache.Len())
+		assert.Equal(t, DefaultMirrorImageStatusCacheSize, NewMirrorImageStatusCache(0).maxSize)
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		statuses, err := ParseMirroredImageStatuses([]byte(`{"summary":{"health":"OK"}}`), NewMirrorImageStatusCache(2))
+		assert.NoError(t, err)
+		assert.Empty(t, statuses)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		_, err := ParseMirroredImageStatuses([]byte(`{"images":[{"name":1}]}`), NewMirrorImageStatusCache(2))
+		assert.Error(t, err)
+		_, err = ParseMirroredImageStatuses([]byte(`not json`), nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetMirroredImageStatuses(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+				assert.Equal(t, "--verbose", args[3])
+				assert.Equal(t, "replicapool/namespace-a", args[4])
+				return mirrorPoolStatusVerbose(2, nil), nil
+			}
+			return "", nil
+		},
+	}
+	statuses, err := GetMirroredImageStatuses(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"), "replicapool/namespace-a", NewMirrorImageStatusCache(10))
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+}
+
+func TestGetMirrorImageStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" && args[1] == "image" && args[2] == "status" {
+				assert.Equal(t, "replicapool/namespace-a/marker", args[3])
+				return `{"name":"marker","global_id":"b7a3c5e6","state":"up+stopped","description":"local image is primary",` +
+					`"last_update":"2025-01-01 10:00:00","peer_sites":[{"site_name":"site-b","mirror_uuids":"5a1d",` +
+					`"state":"up+replaying","description":"replaying","last_update":"2025-01-01 10:00:00"}]}`, nil
+			}
+			return "", nil
+		},
+	}
+	status, err := GetMirrorImageStatus(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"), "replicapool/namespace-a", "marker")
+	assert.NoError(t, err)
+	assert.Equal(t, "marker", status.Name)
+	assert.Equal(t, "local image is primary", status.Description)
+	assert.Len(t, status.PeerSites, 1)
+	assert.Equal(t, "up+replaying", status.PeerSites[0].State)
+}
+
+func BenchmarkParseMirroredImageStatuses(b *testing.B) {
+	const imageCount = 5000
+	// one percent of the images report a new status on each check
+	changed := map[int]bool{}
+	for i := 0; i < imageCount; i += 100 {
+		changed[i] = true
+	}
+	payloads := [][]byte{
+		[]byte(mirrorPoolStatusVerbose(imageCount, nil)),
+		[]byte(mirrorPoolStatusVerbose(imageCount, changed)),
+	}
+
+	b.Run("full", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseMirroredImageStatuses(payloads[i%2], nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("incremental", func(b *testing.B) {
+		cache := NewMirrorImageStatusCache(DefaultMirrorImageStatusCacheSize)
+		if _, err := ParseMirroredImageStatuses(payloads[0], cache); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseMirroredImageStatuses(payloads[i%2], cache); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
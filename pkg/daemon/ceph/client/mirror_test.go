@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -345,6 +346,49 @@ func TestRemoveSnapshotSchedules(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestReconcileSnapshotSchedules(t *testing.T) {
+	pool := "pool-test"
+	removed := []cephv1.SnapshotSchedule{}
+	added := []string{}
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %v %v", command, args)
+		if args[0] == "mirror" {
+			switch args[3] {
+			case "ls":
+				return snapshotScheduleList, nil
+			case "remove":
+				schedule := cephv1.SnapshotSchedule{Interval: args[6]}
+				if len(args) > 7 && !strings.HasPrefix(args[7], "--") {
+					schedule.StartTime = args[7]
+				}
+				removed = append(removed, schedule)
+				return "success", nil
+			case "add":
+				added = append(added, args[6])
+				return "success", nil
+			}
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// the 3d schedule is removed while the 1d schedule is kept
+	desired := []cephv1.SnapshotScheduleSpec{{Interval: "1d", StartTime: "14:00:00-05:00"}}
+	err := ReconcileSnapshotSchedules(context, AdminTestClusterInfo("mycluster"), pool, desired)
+	assert.NoError(t, err)
+	assert.Equal(t, []cephv1.SnapshotSchedule{{Interval: "3d"}}, removed)
+	assert.Empty(t, added)
+
+	// a new schedule is added without touching the existing ones
+	removed = []cephv1.SnapshotSchedule{}
+	desired = []cephv1.SnapshotScheduleSpec{{Interval: "3d"}, {Interval: "1d", StartTime: "14:00:00-05:00"}, {Interval: "4h"}}
+	err = ReconcileSnapshotSchedules(context, AdminTestClusterInfo("mycluster"), pool, desired)
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.Equal(t, []string{"4h"}, added)
+}
+
 func TestDisableMirroring(t *testing.T) {
 	pool := "pool-test"
 	executor := &exectest.MockExecutor{}
@@ -363,6 +407,82 @@ func TestDisableMirroring(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestEnableImageMirroring(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "image", args[1])
+			assert.Equal(t, "enable", args[2])
+			assert.Equal(t, "pool-test/namespace-a/image-a", args[3])
+			assert.Equal(t, "snapshot", args[4])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := EnableImageMirroring(context, AdminTestClusterInfo("mycluster"), "pool-test/namespace-a", "image-a")
+	assert.NoError(t, err)
+}
+
+func TestDisableImageMirroring(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "image", args[1])
+			assert.Equal(t, "disable", args[2])
+			assert.Equal(t, "pool-test/namespace-a/image-a", args[3])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := DisableImageMirroring(context, AdminTestClusterInfo("mycluster"), "pool-test/namespace-a", "image-a")
+	assert.NoError(t, err)
+}
+
+func TestPromoteImage(t *testing.T) {
+	var promoteArgs []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "image", args[1])
+			assert.Equal(t, "promote", args[2])
+			assert.Equal(t, "pool-test/namespace-a/image-a", args[3])
+			promoteArgs = args
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := PromoteImage(context, AdminTestClusterInfo("mycluster"), "pool-test/namespace-a", "image-a", false)
+	assert.NoError(t, err)
+	assert.NotContains(t, promoteArgs, "--force")
+
+	err = PromoteImage(context, AdminTestClusterInfo("mycluster"), "pool-test/namespace-a", "image-a", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "--force", promoteArgs[4])
+}
+
+func TestDemoteImage(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, "image", args[1])
+			assert.Equal(t, "demote", args[2])
+			assert.Equal(t, "pool-test/namespace-a/image-a", args[3])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := DemoteImage(context, AdminTestClusterInfo("mycluster"), "pool-test/namespace-a", "image-a")
+	assert.NoError(t, err)
+}
+
 func TestRemoveClusterPeer(t *testing.T) {
 	pool := "pool-test"
 	peerUUID := "39ae33fb-1dd6-4f9b-8ed7-0e4517068900"
@@ -383,3 +503,23 @@ func TestRemoveClusterPeer(t *testing.T) {
 	err := RemoveClusterPeer(context, AdminTestClusterInfo("mycluster"), pool, peerUUID)
 	assert.NoError(t, err)
 }
+
+func TestSetRBDMirrorPeerDirection(t *testing.T) {
+	pool := "pool-test"
+	peerUUID := "4a6983c0-3c9d-40f5-b2a9-2334a4659827"
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "set" {
+			assert.Equal(t, pool, args[4])
+			assert.Equal(t, peerUUID, args[5])
+			assert.Equal(t, "direction", args[6])
+			assert.Equal(t, "rx-only", args[7])
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := SetRBDMirrorPeerDirection(context, AdminTestClusterInfo("mycluster"), pool, peerUUID, "rx-only")
+	assert.NoError(t, err)
+}
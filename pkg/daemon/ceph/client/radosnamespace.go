@@ -17,7 +17,11 @@ limitations under the License.
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 	"syscall"
 
 	"github.com/pkg/errors"
@@ -35,12 +39,12 @@ func CreateRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, p
 	cmd.JsonOutput = false
 	output, err := cmd.Run()
 	if err != nil {
-		code, ok := exec.ExitStatus(err)
-		if ok && code == int(syscall.EEXIST) {
-			logger.Debugf("rados namespace %s/%s in k8s namespace %q already exists", poolName, namespaceName, clusterInfo.Namespace)
+		if isRadosNamespaceExistsError(err, string(output)) {
+			// the rados namespace was created out of band or by a previous reconcile, creating it is idempotent
+			logger.Infof("rados namespace %s/%s in k8s namespace %q already exists", poolName, namespaceName, clusterInfo.Namespace)
 			return nil
 		}
-		return errors.Wrapf(err, "failed to create rados namespace %s/%s. %s", poolName, namespaceName, output)
+		return errors.Wrapf(detectCephConfigNotInitialized(err, string(output)), "failed to create rados namespace %s/%s. %s", poolName, namespaceName, output)
 
 	}
 
@@ -48,6 +52,42 @@ func CreateRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, p
 	return nil
 }
 
+// isRadosNamespaceExistsError returns whether the rbd namespace creation failed because the rados namespace
+// already exists. The exit code is lost when the command runs remotely, the rbd message is checked then.
+func isRadosNamespaceExistsError(err error, output string) bool {
+	if code, ok := exec.ExitStatus(err); ok {
+		return code == int(syscall.EEXIST)
+	}
+	return strings.Contains(err.Error(), "File exists") || strings.Contains(output, "File exists")
+}
+
+// cephConfigNotInitializedMessage is the message of the rbd commands failing because the operator has not yet
+// written the ceph config, the same message as the UninitializedCephConfigError of the operator controllers
+const cephConfigNotInitializedMessage = "error calling conf_read_file"
+
+// CephConfigNotInitializedError is returned by the rados namespace commands when they fail because the operator
+// has not yet written the ceph config
+type CephConfigNotInitializedError struct {
+	err error
+}
+
+func (e *CephConfigNotInitializedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *CephConfigNotInitializedError) Unwrap() error {
+	return e.err
+}
+
+// detectCephConfigNotInitialized returns a CephConfigNotInitializedError if the rbd command failed because the
+// ceph config is not initialized, so that the callers do not have to check the error message
+func detectCephConfigNotInitialized(err error, output string) error {
+	if strings.Contains(err.Error(), cephConfigNotInitializedMessage) || strings.Contains(output, cephConfigNotInitializedMessage) {
+		return &CephConfigNotInitializedError{err: err}
+	}
+	return err
+}
+
 func getRadosNamespaceStatistics(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string) (*PoolStatistics, error) {
 	var poolStats PoolStatistics
 
@@ -60,7 +100,7 @@ func getRadosNamespaceStatistics(context *clusterd.Context, clusterInfo *Cluster
 		if ok && code == int(syscall.ENOENT) {
 			return &poolStats, nil
 		}
-		return nil, errors.Wrapf(err, "failed to get pool stats. %s", string(output))
+		return nil, errors.Wrapf(detectCephConfigNotInitialized(err, string(output)), "failed to get pool stats. %s", string(output))
 	}
 	if err := json.Unmarshal(output, &poolStats); err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal pool stats response")
@@ -85,11 +125,31 @@ func checkForImagesInRadosNamespace(context *clusterd.Context, clusterInfo *Clus
 	return true, errors.Errorf("pool %s/%s contains %d images and %d snapshots", poolName, namespaceName, stats.Images.Count, stats.Images.SnapCount)
 }
 
+// IsRadosNamespaceEmpty returns whether the rados namespace contains no images nor snapshots and can be
+// deleted. It runs the same check as DeleteRadosNamespace without deleting anything, and the ceph call is
+// canceled when ctx is done.
+func IsRadosNamespaceEmpty(ctx context.Context, context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string) (bool, error) {
+	callClusterInfo := *clusterInfo
+	callClusterInfo.Context = ctx
+	containsImages, err := checkForImagesInRadosNamespace(context, &callClusterInfo, poolName, namespaceName)
+	if containsImages {
+		logger.Infof("rados namespace %s/%s is not empty. %v", poolName, namespaceName, err)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // DeleteRadosNamespace delete a rados namespace.
 func DeleteRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string) (bool, error) {
-	containsImages, err := checkForImagesInRadosNamespace(context, clusterInfo, poolName, namespaceName)
+	empty, err := IsRadosNamespaceEmpty(clusterInfo.Context, context, clusterInfo, poolName, namespaceName)
 	if err != nil {
-		return containsImages, errors.Wrapf(err, "failed to check if pool %s/%s has rbd images", poolName, namespaceName)
+		return false, errors.Wrapf(err, "failed to check if pool %s/%s has rbd images", poolName, namespaceName)
+	}
+	if !empty {
+		return true, errors.Errorf("failed to delete rados namespace %s/%s, it contains images or snapshots", poolName, namespaceName)
 	}
 	logger.Infof("deleting rados namespace %s/%s in k8s namespace %q", poolName, namespaceName, clusterInfo.Namespace)
 	args := []string{"namespace", "remove", "--pool", poolName, "--namespace", namespaceName}
@@ -99,7 +159,7 @@ func DeleteRadosNamespace(context *clusterd.Context, clusterInfo *ClusterInfo, p
 	if err != nil {
 		code, ok := exec.ExitStatus(err)
 		if !ok || code != int(syscall.ENOENT) {
-			return false, errors.Wrapf(err, "failed to delete rados namespace %s/%s. %s", poolName, namespaceName, output)
+			return false, errors.Wrapf(detectCephConfigNotInitialized(err, string(output)), "failed to delete rados namespace %s/%s. %s", poolName, namespaceName, output)
 		}
 	}
 
@@ -140,3 +200,187 @@ func ListRadosNamespacesInPool(context *clusterd.Context, clusterInfo *ClusterIn
 	}
 	return namespacesList, nil
 }
+
+const radosNamespaceApplication = "rbd"
+
+// radosNamespaceMetadataKeyPrefix returns the prefix of the rbd application metadata keys of the pool
+// that belong to the given rados namespace
+func radosNamespaceMetadataKeyPrefix(namespaceName string) string {
+	return fmt.Sprintf("rados_namespace.%s.", namespaceName)
+}
+
+// GetRadosNamespaceApplicationMetadata returns the application metadata of a rados namespace. The metadata is
+// stored in the rbd application metadata of the pool with the keys scoped to the rados namespace.
+func GetRadosNamespaceApplicationMetadata(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string) (map[string]string, error) {
+	poolMetadata, err := getPoolApplicationMetadata(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := radosNamespaceMetadataKeyPrefix(namespaceName)
+	metadata := map[string]string{}
+	for key, value := range poolMetadata {
+		if strings.HasPrefix(key, prefix) {
+			metadata[strings.TrimPrefix(key, prefix)] = value
+		}
+	}
+	return metadata, nil
+}
+
+// SetRadosNamespaceApplicationMetadata sets the application metadata of a rados namespace. Keys that are
+// added or have a different value are set and keys that are no longer desired are removed.
+func SetRadosNamespaceApplicationMetadata(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string, metadata map[string]string) error {
+	current, err := GetRadosNamespaceApplicationMetadata(context, clusterInfo, poolName, namespaceName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get application metadata of rados namespace %s/%s", poolName, namespaceName)
+	}
+
+	prefix := radosNamespaceMetadataKeyPrefix(namespaceName)
+	for _, key := range sortedKeys(metadata) {
+		if currentValue, ok := current[key]; ok && currentValue == metadata[key] {
+			continue
+		}
+		args := []string{"osd", "pool", "application", "set", poolName, radosNamespaceApplication, prefix + key, metadata[key]}
+		output, err := NewCephCommand(context, clusterInfo, args).Run()
+		if err != nil {
+			return errors.Wrapf(err, "failed to set application metadata %q of rados namespace %s/%s. %s", key, poolName, namespaceName, string(output))
+		}
+		logger.Debugf("set application metadata %q of rados namespace %s/%s", key, poolName, namespaceName)
+	}
+
+	for _, key := range sortedKeys(current) {
+		if _, ok := metadata[key]; ok {
+			continue
+		}
+		args := []string{"osd", "pool", "application", "rm", poolName, radosNamespaceApplication, prefix + key}
+		output, err := NewCephCommand(context, clusterInfo, args).Run()
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove application metadata %q of rados namespace %s/%s. %s", key, poolName, namespaceName, string(output))
+		}
+		logger.Debugf("removed application metadata %q of rados namespace %s/%s", key, poolName, namespaceName)
+	}
+
+	return nil
+}
+
+// getPoolApplicationMetadata returns the rbd application metadata of the pool
+func getPoolApplicationMetadata(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (map[string]string, error) {
+	args := []string{"osd", "pool", "application", "get", poolName, radosNamespaceApplication}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %q application metadata of pool %q. %s", radosNamespaceApplication, poolName, string(output))
+	}
+
+	poolMetadata := map[string]string{}
+	if len(output) > 0 {
+		if err := json.Unmarshal(output, &poolMetadata); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal application metadata response %q", string(output))
+		}
+	}
+	return poolMetadata, nil
+}
+
+// RadosNamespaceCompressionHintKey is the rbd config option hinting whether the writes to the images of a rados
+// namespace are compressible. The compression mode and algorithm of BlueStore are pool settings that rbd cannot
+// set per rados namespace.
+const RadosNamespaceCompressionHintKey = "rbd_compression_hint"
+
+var radosNamespaceCompressionKeys = []string{RadosNamespaceCompressionHintKey}
+
+type radosNamespaceConfigOption struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// GetRadosNamespaceCompression returns the compression hint set on the rados namespace
+func GetRadosNamespaceCompression(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string) (map[string]string, error) {
+	return GetRadosNamespaceConfig(context, clusterInfo, poolName, namespaceName, radosNamespaceCompressionKeys)
+}
+
+// SetRadosNamespaceCompression sets the compression hint of a rados namespace. The hint is set when it is added
+// or has a different value and is removed when it is no longer desired, so that the hint of the pool applies.
+func SetRadosNamespaceCompression(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string, compression map[string]string) error {
+	err := SetRadosNamespaceConfig(context, clusterInfo, poolName, namespaceName, radosNamespaceCompressionKeys, compression)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set compression settings of rados namespace %s/%s", poolName, namespaceName)
+	}
+	return nil
+}
+
+// GetRadosNamespaceConfig returns the values of the config keys set on the rados namespace itself
+func GetRadosNamespaceConfig(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string, keys []string) (map[string]string, error) {
+	// sample output: [{"name":"rbd_compression_hint","value":"compressible","source":"namespace"}]
+	args := []string{"config", "namespace", "list", fmt.Sprintf("%s/%s", poolName, namespaceName)}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	output, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the config of rados namespace %s/%s. %s", poolName, namespaceName, string(output))
+	}
+
+	var options []radosNamespaceConfigOption
+	if err := json.Unmarshal(output, &options); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the config of rados namespace %s/%s. %s", poolName, namespaceName, string(output))
+	}
+
+	config := map[string]string{}
+	for _, option := range options {
+		if option.Source != "namespace" {
+			continue
+		}
+		for _, key := range keys {
+			if option.Name == key {
+				config[key] = option.Value
+			}
+		}
+	}
+	return config, nil
+}
+
+// SetRadosNamespaceConfig sets the config keys of a rados namespace to the desired values. Keys that are added or
+// have a different value are set and keys that are not desired are removed from the rados namespace, so that the
+// values of the pool apply.
+func SetRadosNamespaceConfig(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, namespaceName string, keys []string, config map[string]string) error {
+	current, err := GetRadosNamespaceConfig(context, clusterInfo, poolName, namespaceName, keys)
+	if err != nil {
+		return err
+	}
+
+	spec := fmt.Sprintf("%s/%s", poolName, namespaceName)
+	for _, key := range keys {
+		value, desired := config[key]
+		currentValue, isSet := current[key]
+		switch {
+		case desired && (!isSet || currentValue != value):
+			args := []string{"config", "namespace", "set", spec, key, value}
+			cmd := NewRBDCommand(context, clusterInfo, args)
+			cmd.JsonOutput = false
+			output, err := cmd.Run()
+			if err != nil {
+				return errors.Wrapf(err, "failed to set %q of rados namespace %s. %s", key, spec, string(output))
+			}
+			logger.Debugf("set %q to %q on rados namespace %s", key, value, spec)
+		case !desired && isSet:
+			args := []string{"config", "namespace", "remove", spec, key}
+			cmd := NewRBDCommand(context, clusterInfo, args)
+			cmd.JsonOutput = false
+			output, err := cmd.Run()
+			if err != nil {
+				return errors.Wrapf(err, "failed to remove %q of rados namespace %s. %s", key, spec, string(output))
+			}
+			logger.Debugf("removed %q of rados namespace %s", key, spec)
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	ctx "context"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRadosNamespaceApplicationMetadata(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "pool" && args[2] == "application" && args[3] == "get" {
+				assert.Equal(t, "mypool", args[4])
+				assert.Equal(t, "rbd", args[5])
+				return `{"rados_namespace.ns-a.team":"storage","rados_namespace.ns-b.team":"db","other":"value"}`, nil
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	metadata, err := GetRadosNamespaceApplicationMetadata(context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "storage"}, metadata)
+}
+
+func TestSetRadosNamespaceApplicationMetadata(t *testing.T) {
+	tests := []struct {
+		name            string
+		currentMetadata string
+		desired         map[string]string
+		expectedCmds    []string
+	}{
+		{
+			name:            "keys are added",
+			currentMetadata: `{}`,
+			desired:         map[string]string{"team": "storage", "cost-center": "42"},
+			expectedCmds: []string{
+				"set mypool rbd rados_namespace.ns-a.cost-center 42",
+				"set mypool rbd rados_namespace.ns-a.team storage",
+			},
+		},
+		{
+			name:            "changed key is updated",
+			currentMetadata: `{"rados_namespace.ns-a.team":"storage","rados_namespace.ns-a.cost-center":"42"}`,
+			desired:         map[string]string{"team": "db", "cost-center": "42"},
+			expectedCmds:    []string{"set mypool rbd rados_namespace.ns-a.team db"},
+		},
+		{
+			name:            "removed key is removed",
+			currentMetadata: `{"rados_namespace.ns-a.team":"storage","rados_namespace.ns-b.team":"db"}`,
+			desired:         map[string]string{},
+			expectedCmds:    []string{"rm mypool rbd rados_namespace.ns-a.team"},
+		},
+		{
+			name:            "no change",
+			currentMetadata: `{"rados_namespace.ns-a.team":"storage"}`,
+			desired:         map[string]string{"team": "storage"},
+			expectedCmds:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cmds []string
+			executor := &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "osd" && args[1] == "pool" && args[2] == "application" {
+						switch args[3] {
+						case "get":
+							return tt.currentMetadata, nil
+						case "set":
+							cmds = append(cmds, strings.Join(args[3:8], " "))
+						case "rm":
+							cmds = append(cmds, strings.Join(args[3:7], " "))
+						}
+					}
+					return "", nil
+				},
+			}
+			context := &clusterd.Context{Executor: executor}
+
+			err := SetRadosNamespaceApplicationMetadata(context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a", tt.desired)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCmds, cmds)
+		})
+	}
+}
+
+func TestSetRadosNamespaceCompression(t *testing.T) {
+	tests := []struct {
+		name          string
+		currentConfig string
+		compression   map[string]string
+		expectedCmds  []string
+	}{
+		{
+			name:          "compression hint is applied",
+			currentConfig: `[{"name":"rbd_cache","value":"true","source":"config"}]`,
+			compression:   map[string]string{"rbd_compression_hint": "compressible"},
+			expectedCmds:  []string{"set mypool/ns-a rbd_compression_hint compressible"},
+		},
+		{
+			name:          "compression hint is updated",
+			currentConfig: `[{"name":"rbd_compression_hint","value":"none","source":"namespace"}]`,
+			compression:   map[string]string{"rbd_compression_hint": "incompressible"},
+			expectedCmds:  []string{"set mypool/ns-a rbd_compression_hint incompressible"},
+		},
+		{
+			name:          "compression hint inherited from the pool is overridden",
+			currentConfig: `[{"name":"rbd_compression_hint","value":"compressible","source":"pool"}]`,
+			compression:   map[string]string{"rbd_compression_hint": "compressible"},
+			expectedCmds:  []string{"set mypool/ns-a rbd_compression_hint compressible"},
+		},
+		{
+			name:          "compression hint is removed",
+			currentConfig: `[{"name":"rbd_compression_hint","value":"compressible","source":"namespace"},{"name":"rbd_cache","value":"false","source":"namespace"}]`,
+			compression:   nil,
+			expectedCmds:  []string{"remove mypool/ns-a rbd_compression_hint"},
+		},
+		{
+			name:          "compression hint is already applied",
+			currentConfig: `[{"name":"rbd_compression_hint","value":"incompressible","source":"namespace"}]`,
+			compression:   map[string]string{"rbd_compression_hint": "incompressible"},
+			expectedCmds:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cmds []string
+			executor := &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "config" && args[1] == "namespace" {
+						switch args[2] {
+						case "list":
+							assert.Equal(t, "mypool/ns-a", args[3])
+							return tt.currentConfig, nil
+						case "set":
+							cmds = append(cmds, strings.Join(args[2:6], " "))
+						case "remove":
+							cmds = append(cmds, strings.Join(args[2:5], " "))
+						}
+					}
+					return "", nil
+				},
+			}
+			c := &clusterd.Context{Executor: executor}
+
+			err := SetRadosNamespaceCompression(c, AdminTestClusterInfo("mycluster"), "mypool", "ns-a", tt.compression)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCmds, cmds)
+		})
+	}
+}
+
+func TestIsRadosNamespaceEmpty(t *testing.T) {
+	newContext := func(stats string, commands *[]string) *clusterd.Context {
+		return &clusterd.Context{Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				*commands = append(*commands, strings.Join(args, " "))
+				if args[0] == "pool" && args[1] == "stats" {
+					assert.Equal(t, []string{"--pool", "mypool", "--namespace", "ns-a"}, args[2:6])
+					return stats, nil
+				}
+				return "", errors.New("unexpected command")
+			},
+		}}
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		var commands []string
+		context := newContext(`{"images":{"count":0,"snap_count":0}}`, &commands)
+		empty, err := IsRadosNamespaceEmpty(ctx.TODO(), context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+		assert.NoError(t, err)
+		assert.True(t, empty)
+		assert.Len(t, commands, 1)
+	})
+
+	t.Run("images", func(t *testing.T) {
+		var commands []string
+		context := newContext(`{"images":{"count":2,"snap_count":0}}`, &commands)
+		empty, err := IsRadosNamespaceEmpty(ctx.TODO(), context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+		assert.NoError(t, err)
+		assert.False(t, empty)
+		// nothing is deleted
+		assert.Len(t, commands, 1)
+	})
+
+	t.Run("snapshots", func(t *testing.T) {
+		var commands []string
+		context := newContext(`{"images":{"count":0,"snap_count":1}}`, &commands)
+		empty, err := IsRadosNamespaceEmpty(ctx.TODO(), context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+		assert.NoError(t, err)
+		assert.False(t, empty)
+	})
+
+	t.Run("stats failure", func(t *testing.T) {
+		var commands []string
+		context := newContext(`invalid`, &commands)
+		empty, err := IsRadosNamespaceEmpty(ctx.TODO(), context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+		assert.Error(t, err)
+		assert.False(t, empty)
+	})
+
+	t.Run("delete reuses the check", func(t *testing.T) {
+		var commands []string
+		context := newContext(`{"images":{"count":1,"snap_count":0}}`, &commands)
+		containsImages, err := DeleteRadosNamespace(context, AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+		assert.Error(t, err)
+		assert.True(t, containsImages)
+		assert.Len(t, commands, 1)
+	})
+}
+
+func TestCreateRadosNamespace(t *testing.T) {
+	newContext := func(err error) *clusterd.Context {
+		return &clusterd.Context{Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				assert.Equal(t, []string{"namespace", "create", "--pool", "mypool", "--namespace", "ns-a"}, args[:6])
+				return "", err
+			},
+		}}
+	}
+
+	t.Run("created", func(t *testing.T) {
+		assert.NoError(t, CreateRadosNamespace(newContext(nil), AdminTestClusterInfo("mycluster"), "mypool", "ns-a"))
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		assert.NoError(t, CreateRadosNamespace(newContext(syscall.EEXIST), AdminTestClusterInfo("mycluster"), "mypool", "ns-a"))
+	})
+
+	t.Run("already exists without the exit code", func(t *testing.T) {
+		err := errors.New("err=command terminated with exit code 17: stderr=rbd: failed to created namespace: (17) File exists")
+		assert.NoError(t, CreateRadosNamespace(newContext(err), AdminTestClusterInfo("mycluster"), "mypool", "ns-a"))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		assert.Error(t, CreateRadosNamespace(newContext(syscall.EPERM), AdminTestClusterInfo("mycluster"), "mypool", "ns-a"))
+		assert.Error(t, CreateRadosNamespace(newContext(errors.New("connection refused")), AdminTestClusterInfo("mycluster"), "mypool", "ns-a"))
+	})
+}
+
+func TestCephConfigNotInitialized(t *testing.T) {
+	notInitialized := errors.New("err=command terminated with exit code 1: stderr=rbd: error calling conf_read_file: (2) No such file or directory")
+	newContext := func(err error) *clusterd.Context {
+		return &clusterd.Context{Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				return "", err
+			},
+		}}
+	}
+	var configErr *CephConfigNotInitializedError
+
+	err := CreateRadosNamespace(newContext(notInitialized), AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+	assert.True(t, errors.As(err, &configErr))
+	// the original message is kept for the logs
+	assert.Contains(t, err.Error(), cephConfigNotInitializedMessage)
+
+	_, err = DeleteRadosNamespace(newContext(notInitialized), AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+	assert.True(t, errors.As(err, &configErr))
+
+	err = CreateRadosNamespace(newContext(errors.New("connection refused")), AdminTestClusterInfo("mycluster"), "mypool", "ns-a")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &configErr))
+}
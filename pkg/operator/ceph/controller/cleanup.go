@@ -17,6 +17,7 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -34,6 +35,12 @@ const (
 	dataDirHostPath             = "ROOK_DATA_DIR_HOST_PATH"
 	CleanupAppName              = "resource-cleanup"
 	RESOURCE_CLEANUP_ANNOTATION = "rook.io/force-deletion"
+	// CleanupImageSettingName is the operator setting overriding the image of the clean up jobs. It is not an
+	// annotation of the resource since the privileged job runs with the ceph admin credentials.
+	CleanupImageSettingName = "ROOK_CEPH_CLEANUP_IMAGE"
+	// CleanupResourcesAnnotation overrides the resource requests and limits of the clean up job, in the json
+	// format of the container resources, e.g. {"limits":{"memory":"2Gi"},"requests":{"cpu":"500m","memory":"1Gi"}}
+	CleanupResourcesAnnotation = "rook.io/force-deletion-resources"
 
 	// CephFSSubVolumeGroup env resources
 	CephFSSubVolumeGroupNameEnv = "SUB_VOLUME_GROUP_NAME"
@@ -44,8 +51,20 @@ const (
 	// cephblockpoolradosnamespace env resources
 	CephBlockPoolNameEnv           = "BLOCKPOOL_NAME"
 	CephBlockPoolRadosNamespaceEnv = "RADOS_NAMESPACE"
+	// CephBlockPoolRadosNamespaceCleanupOrderEnv is the comma separated list of the tiers of ceph resources
+	// removed in turn by the rados namespace clean up job
+	CephBlockPoolRadosNamespaceCleanupOrderEnv = "RADOS_NAMESPACE_CLEANUP_ORDER"
+
+	// rados namespace clean up tiers
+	RadosNamespaceCleanupSnapshots = "snapshots"
+	RadosNamespaceCleanupImages    = "images"
+	RadosNamespaceCleanupNamespace = "namespace"
 )
 
+// RadosNamespaceCleanupOrder removes the snapshots before the images since an image cannot be removed while it
+// has snapshots, and the rados namespace once it is empty
+var RadosNamespaceCleanupOrder = []string{RadosNamespaceCleanupSnapshots, RadosNamespaceCleanupImages, RadosNamespaceCleanupNamespace}
+
 // ResourceCleanup defines an rook ceph resource to be cleaned up
 type ResourceCleanup struct {
 	resource  k8sClient.Object
@@ -53,6 +72,10 @@ type ResourceCleanup struct {
 	rookImage string
 	// config defines the attributes of the custom resource to passed in as environment variables in the clean up job
 	config map[string]string
+	// image overrides the rook image when set in the operator settings, and resources override the cleanup
+	// resources of the cluster when set in the annotations of the resource
+	image     string
+	resources *v1.ResourceRequirements
 }
 
 func NewResourceCleanup(obj k8sClient.Object, cluster *cephv1.CephCluster, rookImage string, config map[string]string) *ResourceCleanup {
@@ -66,6 +89,10 @@ func NewResourceCleanup(obj k8sClient.Object, cluster *cephv1.CephCluster, rookI
 
 // Start a new job to perform clean up of the ceph resources. It returns true if the cleanup job has succeeded
 func (c *ResourceCleanup) StartJob(ctx context.Context, clientset kubernetes.Interface, jobName string) error {
+	if err := c.loadOverrides(); err != nil {
+		return errors.Wrapf(err, "invalid clean up job overrides for %q resource named %q in namespace %q",
+			c.resource.GetObjectKind().GroupVersionKind().Kind, c.resource.GetName(), c.resource.GetNamespace())
+	}
 	podSpec := c.jobTemplateSpec()
 	job := &batch.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -85,6 +112,48 @@ func (c *ResourceCleanup) StartJob(ctx context.Context, clientset kubernetes.Int
 	return nil
 }
 
+// loadOverrides sets the image of the clean up job from the operator settings and its resources from the
+// annotations of the resource
+func (c *ResourceCleanup) loadOverrides() error {
+	c.image = k8sutil.GetOperatorSetting(CleanupImageSettingName, "")
+
+	resources, err := GetCleanupResourcesOverride(c.resource.GetAnnotations())
+	if err != nil {
+		return err
+	}
+	c.resources = resources
+	return nil
+}
+
+// GetCleanupResourcesOverride returns the resources of the clean up job set in the annotations, or nil if
+// they are not overridden
+func GetCleanupResourcesOverride(annotations map[string]string) (*v1.ResourceRequirements, error) {
+	value, ok := annotations[CleanupResourcesAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	resources := &v1.ResourceRequirements{}
+	// the quantities are validated when they are parsed
+	if err := json.Unmarshal([]byte(value), resources); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse annotation %q", CleanupResourcesAnnotation)
+	}
+	for name, quantity := range resources.Limits {
+		if quantity.Sign() < 0 {
+			return nil, errors.Errorf("invalid %q limit %q in annotation %q, it must not be negative", name, quantity.String(), CleanupResourcesAnnotation)
+		}
+	}
+	for name, request := range resources.Requests {
+		if request.Sign() < 0 {
+			return nil, errors.Errorf("invalid %q request %q in annotation %q, it must not be negative", name, request.String(), CleanupResourcesAnnotation)
+		}
+		if limit, ok := resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return nil, errors.Errorf("invalid %q request %q in annotation %q, it must not be greater than the limit %q", name, request.String(), CleanupResourcesAnnotation, limit.String())
+		}
+	}
+	return resources, nil
+}
+
 func (c *ResourceCleanup) jobContainer() v1.Container {
 	volumeMounts := []v1.VolumeMount{}
 	envVars := []v1.EnvVar{}
@@ -101,15 +170,23 @@ func (c *ResourceCleanup) jobContainer() v1.Container {
 	for k, v := range c.config {
 		envVars = append(envVars, v1.EnvVar{Name: k, Value: v})
 	}
+	image := c.rookImage
+	if c.image != "" {
+		image = c.image
+	}
+	resources := cephv1.GetCleanupResources(c.cluster.Spec.Resources)
+	if c.resources != nil {
+		resources = *c.resources
+	}
 	securityContext := PrivilegedContext(true)
 	return v1.Container{
 		Name:            "resource-cleanup",
-		Image:           c.rookImage,
+		Image:           image,
 		SecurityContext: securityContext,
 		VolumeMounts:    volumeMounts,
 		Env:             envVars,
 		Args:            []string{"ceph", "clean", c.resource.GetObjectKind().GroupVersionKind().Kind},
-		Resources:       cephv1.GetCleanupResources(c.cluster.Spec.Resources),
+		Resources:       resources,
 	}
 }
 
@@ -21,6 +21,8 @@ import (
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -77,3 +79,61 @@ func TestForceDeleteRequested(t *testing.T) {
 	result = ForceDeleteRequested(svgObj.Annotations)
 	assert.True(t, result)
 }
+
+func TestJobTemplateSpecOverrides(t *testing.T) {
+	cluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-rook-ceph"},
+		Spec: cephv1.ClusterSpec{
+			DataDirHostPath: "var/lib/rook",
+		},
+	}
+	rnsObj := &cephv1.CephBlockPoolRadosNamespace{
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-rns",
+			Namespace:   "test-rook-ceph",
+			Annotations: map[string]string{},
+		},
+	}
+
+	t.Run("operator image and default resources", func(t *testing.T) {
+		cleanup := NewResourceCleanup(rnsObj, cluster, "rook/ceph:test", nil)
+		assert.NoError(t, cleanup.loadOverrides())
+		container := cleanup.jobTemplateSpec().Spec.Containers[0]
+		assert.Equal(t, "rook/ceph:test", container.Image)
+		assert.Equal(t, cephv1.GetCleanupResources(cluster.Spec.Resources), container.Resources)
+	})
+
+	t.Run("image and resources overrides", func(t *testing.T) {
+		t.Setenv(CleanupImageSettingName, "rook/ceph:cleanup")
+		rnsObj.Annotations[CleanupResourcesAnnotation] = `{"limits":{"memory":"2Gi"},"requests":{"cpu":"500m","memory":"1Gi"}}`
+		cleanup := NewResourceCleanup(rnsObj, cluster, "rook/ceph:test", nil)
+		assert.NoError(t, cleanup.loadOverrides())
+		container := cleanup.jobTemplateSpec().Spec.Containers[0]
+		assert.Equal(t, "rook/ceph:cleanup", container.Image)
+		assert.Equal(t, resource.MustParse("2Gi"), container.Resources.Limits[v1.ResourceMemory])
+		assert.Equal(t, resource.MustParse("500m"), container.Resources.Requests[v1.ResourceCPU])
+		assert.Equal(t, resource.MustParse("1Gi"), container.Resources.Requests[v1.ResourceMemory])
+	})
+
+	t.Run("image is not overridden by the annotations", func(t *testing.T) {
+		rnsObj.Annotations["rook.io/force-deletion-image"] = "attacker/image:latest"
+		cleanup := NewResourceCleanup(rnsObj, cluster, "rook/ceph:test", nil)
+		assert.NoError(t, cleanup.loadOverrides())
+		assert.Equal(t, "rook/ceph:test", cleanup.jobTemplateSpec().Spec.Containers[0].Image)
+		delete(rnsObj.Annotations, "rook.io/force-deletion-image")
+	})
+
+	t.Run("invalid resources", func(t *testing.T) {
+		for _, value := range []string{
+			`{"limits":{"memory":"lots"}}`,
+			`{"requests":{"cpu":"-1"}}`,
+			`{"limits":{"memory":"1Gi"},"requests":{"memory":"2Gi"}}`,
+			`not json`,
+		} {
+			rnsObj.Annotations[CleanupResourcesAnnotation] = value
+			cleanup := NewResourceCleanup(rnsObj, cluster, "rook/ceph:test", nil)
+			assert.Error(t, cleanup.loadOverrides(), value)
+		}
+	})
+}
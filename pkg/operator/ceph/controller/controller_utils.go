@@ -211,6 +211,12 @@ func canIgnoreHealthErrStatusInReconcile(cephCluster cephv1.CephCluster, control
 
 // IsReadyToReconcile determines if a controller is ready to reconcile or not
 func IsReadyToReconcile(ctx context.Context, c client.Client, namespacedName types.NamespacedName, controllerName string) (cephv1.CephCluster, bool, bool, reconcile.Result) {
+	return IsReadyToReconcileCluster(ctx, c, namespacedName, "", controllerName)
+}
+
+// IsReadyToReconcileCluster is like IsReadyToReconcile for the CephCluster with the given name, when several
+// CephClusters run in the namespace. The first CephCluster of the namespace is used if the name is empty.
+func IsReadyToReconcileCluster(ctx context.Context, c client.Client, namespacedName types.NamespacedName, cephClusterName, controllerName string) (cephv1.CephCluster, bool, bool, reconcile.Result) {
 	cephClusterExists := false
 
 	// Running ceph commands won't work and the controller will keep re-queuing so I believe it's fine not to check
@@ -227,6 +233,19 @@ func IsReadyToReconcile(ctx context.Context, c client.Client, namespacedName typ
 		return cephCluster, false, cephClusterExists, WaitForRequeueIfCephClusterNotReady
 	}
 	cephCluster = clusterList.Items[0]
+	if cephClusterName != "" {
+		found := false
+		for _, item := range clusterList.Items {
+			if item.Name == cephClusterName {
+				cephCluster, found = item, true
+				break
+			}
+		}
+		if !found {
+			logger.Debugf("%q: CephCluster %q not found in namespace %q", controllerName, cephClusterName, namespacedName.Namespace)
+			return cephv1.CephCluster{}, false, cephClusterExists, WaitForRequeueIfCephClusterNotReady
+		}
+	}
 	// If the cluster has a cleanup policy to destroy the cluster and it has been marked for deletion, treat it as if it does not exist
 	if cephCluster.Spec.CleanupPolicy.HasDataDirCleanPolicy() && !cephCluster.DeletionTimestamp.IsZero() {
 		logger.Infof("%q: CephCluster has a destructive cleanup policy, allowing %q to be deleted", controllerName, namespacedName)
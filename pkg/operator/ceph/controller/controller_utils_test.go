@@ -286,6 +286,46 @@ func TestIsReadyToReconcile(t *testing.T) {
 	})
 }
 
+func TestIsReadyToReconcileCluster(t *testing.T) {
+	scheme := scheme.Scheme
+	scheme.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+
+	controllerName := "testing"
+	namespacedName := types.NamespacedName{Name: "myobject", Namespace: "myns"}
+	newCluster := func(name, health string) *cephv1.CephCluster {
+		return &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespacedName.Namespace},
+			Status:     cephv1.ClusterStatus{CephStatus: &cephv1.CephStatus{Health: health}},
+		}
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(newCluster("cluster-a", "HEALTH_ERR"), newCluster("cluster-b", "HEALTH_OK")).Build()
+
+	t.Run("named cephcluster", func(t *testing.T) {
+		c, ready, clusterExists, _ := IsReadyToReconcileCluster(ctx.TODO(), client, namespacedName, "cluster-b", controllerName)
+		assert.Equal(t, "cluster-b", c.Name)
+		assert.True(t, ready)
+		assert.True(t, clusterExists)
+
+		c, ready, clusterExists, _ = IsReadyToReconcileCluster(ctx.TODO(), client, namespacedName, "cluster-a", controllerName)
+		assert.Equal(t, "cluster-a", c.Name)
+		assert.False(t, ready)
+		assert.True(t, clusterExists)
+	})
+
+	t.Run("missing named cephcluster", func(t *testing.T) {
+		_, ready, clusterExists, reconcileResult := IsReadyToReconcileCluster(ctx.TODO(), client, namespacedName, "cluster-c", controllerName)
+		assert.False(t, ready)
+		assert.False(t, clusterExists)
+		assert.Equal(t, WaitForRequeueIfCephClusterNotReady, reconcileResult)
+	})
+
+	t.Run("first cephcluster when not named", func(t *testing.T) {
+		c, _, clusterExists, _ := IsReadyToReconcileCluster(ctx.TODO(), client, namespacedName, "", controllerName)
+		assert.Equal(t, "cluster-a", c.Name)
+		assert.True(t, clusterExists)
+	})
+}
+
 func TestObcAllowAdditionalConfigFields(t *testing.T) {
 	tests := []struct {
 		name             string
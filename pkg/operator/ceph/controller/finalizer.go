@@ -104,6 +104,67 @@ func RemoveFinalizerWithName(ctx context.Context, client client.Client, obj clie
 	return nil
 }
 
+// ReplaceFinalizer replaces the legacy finalizer of an object with its current finalizer in a single update,
+// so that the object never carries both finalizers. Returns whether the generation of the object was updated.
+func ReplaceFinalizer(ctx context.Context, client client.Client, obj client.Object, legacyFinalizer string) (bool, error) {
+	objectFinalizer := buildFinalizerName(obj.GetObjectKind().GroupVersionKind().Kind)
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get meta information of object")
+	}
+	if legacyFinalizer == objectFinalizer || !contains(accessor.GetFinalizers(), legacyFinalizer) {
+		return false, nil
+	}
+
+	logger.Infof("replacing legacy finalizer %q with %q on %q", legacyFinalizer, objectFinalizer, accessor.GetName())
+	finalizers := remove(accessor.GetFinalizers(), legacyFinalizer)
+	if !contains(finalizers, objectFinalizer) {
+		finalizers = append(finalizers, objectFinalizer)
+	}
+	accessor.SetFinalizers(finalizers)
+	originalGeneration := obj.GetGeneration()
+
+	if err := client.Update(ctx, obj); err != nil {
+		return false, errors.Wrapf(err, "failed to replace legacy finalizer %q on %q", legacyFinalizer, accessor.GetName())
+	}
+	return originalGeneration != obj.GetGeneration(), nil
+}
+
+// RemoveFinalizersWithNames removes all the finalizers passed as arguments from an object in a single update
+func RemoveFinalizersWithNames(ctx context.Context, client client.Client, obj client.Object, finalizerNames ...string) error {
+	err := client.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, obj)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the latest version of the object")
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return errors.Wrap(err, "failed to get meta information of object")
+	}
+
+	finalizers := accessor.GetFinalizers()
+	removed := false
+	for _, finalizerName := range finalizerNames {
+		if finalizerName != "" && contains(finalizers, finalizerName) {
+			logger.Infof("removing finalizer %q on %q", finalizerName, accessor.GetName())
+			finalizers = remove(finalizers, finalizerName)
+			removed = true
+		}
+	}
+	if removed {
+		accessor.SetFinalizers(finalizers)
+		if err := client.Update(ctx, obj); err != nil {
+			return errors.Wrapf(err, "failed to remove finalizers %v on %q", finalizerNames, accessor.GetName())
+		}
+	}
+
+	return nil
+}
+
+// FinalizerName returns the finalizer name of an object
+func FinalizerName(obj client.Object) string {
+	return buildFinalizerName(obj.GetObjectKind().GroupVersionKind().Kind)
+}
+
 // buildFinalizerName returns the finalizer name
 func buildFinalizerName(kind string) string {
 	return fmt.Sprintf("%s.%s", strings.ToLower(kind), cephv1.CustomResourceGroup)
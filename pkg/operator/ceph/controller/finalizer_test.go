@@ -105,3 +105,55 @@ func TestRemoveFinalizerWithName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, fakeObject.Finalizers)
 }
+
+func TestReplaceFinalizer(t *testing.T) {
+	legacyFinalizer := "legacy.ceph.rook.io"
+	newObject := func(finalizers ...string) *cephv1.CephBlockPool {
+		return &cephv1.CephBlockPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "rook-ceph", Finalizers: finalizers},
+			TypeMeta:   metav1.TypeMeta{Kind: "cephblockpool"},
+		}
+	}
+
+	t.Run("legacy finalizer is replaced", func(t *testing.T) {
+		fakeObject := newObject("other", legacyFinalizer)
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(fakeObject).Build()
+
+		_, err := ReplaceFinalizer(context.TODO(), cl, fakeObject, legacyFinalizer)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"other", "cephblockpool.ceph.rook.io"}, fakeObject.Finalizers)
+	})
+
+	t.Run("current finalizer is not added twice", func(t *testing.T) {
+		fakeObject := newObject("cephblockpool.ceph.rook.io", legacyFinalizer)
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(fakeObject).Build()
+
+		_, err := ReplaceFinalizer(context.TODO(), cl, fakeObject, legacyFinalizer)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cephblockpool.ceph.rook.io"}, fakeObject.Finalizers)
+	})
+
+	t.Run("object without the legacy finalizer is not updated", func(t *testing.T) {
+		fakeObject := newObject("cephblockpool.ceph.rook.io")
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(fakeObject).Build()
+
+		_, err := ReplaceFinalizer(context.TODO(), cl, fakeObject, legacyFinalizer)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cephblockpool.ceph.rook.io"}, fakeObject.Finalizers)
+	})
+}
+
+func TestRemoveFinalizersWithNames(t *testing.T) {
+	fakeObject := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "rook-ceph",
+			Finalizers: []string{"cephblockpool.ceph.rook.io", "legacy.ceph.rook.io", "other"},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(fakeObject).Build()
+
+	err := RemoveFinalizersWithNames(context.TODO(), cl, fakeObject, "cephblockpool.ceph.rook.io", "legacy.ceph.rook.io", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other"}, fakeObject.Finalizers)
+}
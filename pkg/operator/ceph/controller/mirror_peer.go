@@ -42,6 +42,8 @@ const (
 	//nolint:gosec // // since this is not leaking any hardcoded credentials, it's just the prefix of the secret name
 	clusterMirrorBootstrapPeerSecretName = "cluster-peer-token"
 	//nolint:gosec // since this is not leaking any hardcoded credentials, it's just the prefix of the secret name
+	radosNamespaceMirrorBootstrapPeerSecretName = "rados-namespace-peer-token"
+	//nolint:gosec // since this is not leaking any hardcoded credentials, it's just the prefix of the secret name
 	RBDMirrorBootstrapPeerSecretName = "rbdMirrorBootstrapPeerSecretName"
 	//nolint:gosec // since this is not leaking any hardcoded credentials, it's just the prefix of the secret name
 	FSMirrorBootstrapPeerSecretName = "fsMirrorBootstrapPeerSecretName"
@@ -68,6 +70,22 @@ func CreateBootstrapPeerSecret(ctx *clusterd.Context, clusterInfo *cephclient.Cl
 			return ImmediateRetryResult, errors.Wrap(err, "failed to add extra information to rbd-mirror bootstrap peer")
 		}
 
+	case *cephv1.CephBlockPoolRadosNamespace:
+		ns = objectType.Namespace
+		name = objectType.Spec.BlockPoolName
+		daemonType = "rbd"
+		// Create rbd mirror bootstrap peer token of the pool of the rados namespace
+		bootstrapToken, err = cephclient.CreateRBDMirrorBootstrapPeer(ctx, clusterInfo, name)
+		if err != nil {
+			return ImmediateRetryResult, errors.Wrapf(err, "failed to create %s-mirror bootstrap peer", daemonType)
+		}
+
+		// Add additional information to the peer token
+		bootstrapToken, err = expandBootstrapPeerToken(ctx, clusterInfo, bootstrapToken)
+		if err != nil {
+			return ImmediateRetryResult, errors.Wrap(err, "failed to add extra information to rbd-mirror bootstrap peer")
+		}
+
 	case *cephv1.CephCluster:
 		ns = objectType.Namespace
 		daemonType = "cluster-rbd"
@@ -128,6 +146,10 @@ func GenerateBootstrapPeerSecret(object client.Object, token []byte) *v1.Secret
 		entityType = "pool"
 		entityName = objectType.Name
 		entityNamespace = objectType.Namespace
+	case *cephv1.CephBlockPoolRadosNamespace:
+		entityType = "pool"
+		entityName = objectType.Spec.BlockPoolName
+		entityNamespace = objectType.Namespace
 	case *cephv1.CephCluster:
 		entityType = "cluster"
 		entityName = objectType.Name
@@ -145,6 +167,9 @@ func GenerateBootstrapPeerSecret(object client.Object, token []byte) *v1.Secret
 		},
 		Type: k8sutil.RookType,
 	}
+	if radosNamespace, ok := object.(*cephv1.CephBlockPoolRadosNamespace); ok {
+		s.Data["radosNamespace"] = []byte(cephv1.GetRadosNamespaceName(radosNamespace))
+	}
 
 	return s
 }
@@ -155,6 +180,8 @@ func buildBootstrapPeerSecretName(object client.Object) string {
 		return fmt.Sprintf("%s-%s", fsMirrorBootstrapPeerSecretName, objectType.Name)
 	case *cephv1.CephBlockPool:
 		return fmt.Sprintf("%s-%s", poolMirrorBootstrapPeerSecretName, objectType.Name)
+	case *cephv1.CephBlockPoolRadosNamespace:
+		return fmt.Sprintf("%s-%s", radosNamespaceMirrorBootstrapPeerSecretName, objectType.Name)
 	case *cephv1.CephCluster:
 		return fmt.Sprintf("%s-%s", clusterMirrorBootstrapPeerSecretName, objectType.Name)
 	}
@@ -168,7 +195,7 @@ func GenerateStatusInfo(object client.Object) map[string]string {
 	switch object.(type) {
 	case *cephv1.CephFilesystem:
 		m[FSMirrorBootstrapPeerSecretName] = buildBootstrapPeerSecretName(object)
-	case *cephv1.CephBlockPool:
+	case *cephv1.CephBlockPool, *cephv1.CephBlockPoolRadosNamespace:
 		m[RBDMirrorBootstrapPeerSecretName] = buildBootstrapPeerSecretName(object)
 	}
 
@@ -28,6 +28,7 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -73,7 +74,11 @@ func TestGenerateStatusInfo(t *testing.T) {
 		args args
 		want map[string]string
 	}{
-		// TODO: Add test cases.
+		{
+			name: "rados namespace",
+			args: args{object: &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-a"}}},
+			want: map[string]string{RBDMirrorBootstrapPeerSecretName: "rados-namespace-peer-token-namespace-a"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -45,6 +45,62 @@ var (
 type CSIClusterConfigEntry struct {
 	cephcsi.ClusterInfo
 	Namespace string `json:"namespace"`
+	// RBDMapOptions are written into the rbd section of the entry
+	RBDMapOptions RBDMapOptions `json:"-"`
+	// KeepReadAffinity keeps the read affinity of the entry instead of the read affinity of the cluster, and
+	// writes it even without crush location labels so that a previous read affinity is reverted
+	KeepReadAffinity bool `json:"-"`
+}
+
+// RBDMapOptions are the krbd map and unmap options of the rbd section of a csi config entry, which are not
+// part of the ceph-csi config types
+type RBDMapOptions struct {
+	MapOptions   string `json:"mapOptions,omitempty"`
+	UnmapOptions string `json:"unmapOptions,omitempty"`
+}
+
+// MarshalJSON adds the rbd map options to the rbd section of the entry
+func (e CSIClusterConfigEntry) MarshalJSON() ([]byte, error) {
+	type entry CSIClusterConfigEntry
+	data, err := json.Marshal(entry(e))
+	if err != nil || e.RBDMapOptions == (RBDMapOptions{}) {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	rbd := map[string]interface{}{}
+	if err := json.Unmarshal(fields["rbd"], &rbd); err != nil {
+		return nil, err
+	}
+	if e.RBDMapOptions.MapOptions != "" {
+		rbd["mapOptions"] = e.RBDMapOptions.MapOptions
+	}
+	if e.RBDMapOptions.UnmapOptions != "" {
+		rbd["unmapOptions"] = e.RBDMapOptions.UnmapOptions
+	}
+	if fields["rbd"], err = json.Marshal(rbd); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON reads the rbd map options from the rbd section of the entry
+func (e *CSIClusterConfigEntry) UnmarshalJSON(data []byte) error {
+	type entry CSIClusterConfigEntry
+	if err := json.Unmarshal(data, (*entry)(e)); err != nil {
+		return err
+	}
+	var rbd struct {
+		RBD RBDMapOptions `json:"rbd"`
+	}
+	if err := json.Unmarshal(data, &rbd); err != nil {
+		return err
+	}
+	e.RBDMapOptions = rbd.RBD
+	return nil
 }
 
 type csiClusterConfig []CSIClusterConfigEntry
@@ -209,8 +265,9 @@ func updateCsiClusterConfig(curr, clusterID, clusterNamespace string, newCsiClus
 			// update radosNamespace and rbd netNamespaceFilePath only when either is specified.
 			if newCsiClusterConfigEntry.RBD.RadosNamespace != "" || newCsiClusterConfigEntry.RBD.NetNamespaceFilePath != "" {
 				centry.RBD = newCsiClusterConfigEntry.RBD
+				centry.RBDMapOptions = newCsiClusterConfigEntry.RBDMapOptions
 			}
-			if len(newCsiClusterConfigEntry.ReadAffinity.CrushLocationLabels) != 0 {
+			if newCsiClusterConfigEntry.KeepReadAffinity || len(newCsiClusterConfigEntry.ReadAffinity.CrushLocationLabels) != 0 {
 				centry.ReadAffinity = newCsiClusterConfigEntry.ReadAffinity
 			}
 			found = true
@@ -226,9 +283,10 @@ func updateCsiClusterConfig(curr, clusterID, clusterNamespace string, newCsiClus
 			centry.Namespace = clusterNamespace
 			centry.Monitors = newCsiClusterConfigEntry.Monitors
 			centry.RBD = newCsiClusterConfigEntry.RBD
+			centry.RBDMapOptions = newCsiClusterConfigEntry.RBDMapOptions
 			centry.CephFS = newCsiClusterConfigEntry.CephFS
 			centry.NFS = newCsiClusterConfigEntry.NFS
-			if len(newCsiClusterConfigEntry.ReadAffinity.CrushLocationLabels) != 0 {
+			if newCsiClusterConfigEntry.KeepReadAffinity || len(newCsiClusterConfigEntry.ReadAffinity.CrushLocationLabels) != 0 {
 				centry.ReadAffinity = newCsiClusterConfigEntry.ReadAffinity
 			}
 			cc = append(cc, centry)
@@ -326,15 +384,116 @@ func SaveClusterConfig(clientset kubernetes.Interface, clusterID, clusterNamespa
 	}
 	logger.Debugf("using %q for csi configmap namespace", csiNamespace)
 
+	setCSIDriverOptions(newCsiClusterConfigEntry, clusterInfo)
+
+	return saveClusterConfigUpdates(clientset, csiNamespace, clusterNamespace, clusterInfo, []clusterConfigUpdate{{clusterID: clusterID, entry: newCsiClusterConfigEntry}})
+}
+
+// GetClusterConfigEntry returns the entry of the cluster ID in the csi config map, or nil if the config map or
+// the entry does not exist
+func GetClusterConfigEntry(clientset kubernetes.Interface, clusterID string, clusterInfo *cephclient.ClusterInfo) (*CSIClusterConfigEntry, error) {
+	if EnableCSIOperator() {
+		return nil, nil
+	}
+	csiNamespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	if csiNamespace == "" {
+		return nil, nil
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps(csiNamespace).Get(clusterInfo.Context, ConfigName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to fetch current csi config map")
+	}
+	data := configMap.Data[ConfigKey]
+	if data == "" {
+		return nil, nil
+	}
+	cc, err := parseCsiClusterConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cc {
+		if cc[i].ClusterID == clusterID {
+			return &cc[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ClusterConfigChanged returns whether saving the csi config entry of the cluster ID with SaveClusterConfig would
+// modify the csi config map. The config is considered changed if the config map does not exist yet, so that the
+// save reports the missing config map.
+func ClusterConfigChanged(clientset kubernetes.Interface, clusterID, clusterNamespace string, clusterInfo *cephclient.ClusterInfo, newCsiClusterConfigEntry *CSIClusterConfigEntry) (bool, error) {
+	if EnableCSIOperator() {
+		return false, nil
+	}
+	csiNamespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	if csiNamespace == "" {
+		return false, nil
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps(csiNamespace).Get(clusterInfo.Context, ConfigName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrap(err, "failed to fetch current csi config map")
+	}
+	curr := configMap.Data[ConfigKey]
+	if curr == "" {
+		curr = "[]"
+	}
+
+	var entry *CSIClusterConfigEntry
 	if newCsiClusterConfigEntry != nil {
-		// set CSIDriverOptions
-		newCsiClusterConfigEntry.ReadAffinity.Enabled = clusterInfo.CSIDriverSpec.ReadAffinity.Enabled
-		newCsiClusterConfigEntry.ReadAffinity.CrushLocationLabels = clusterInfo.CSIDriverSpec.ReadAffinity.CrushLocationLabels
+		entryCopy := *newCsiClusterConfigEntry
+		entry = &entryCopy
+		setCSIDriverOptions(entry, clusterInfo)
+	}
+	updated, err := updateCsiClusterConfig(curr, clusterID, clusterNamespace, entry)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to update csi config map data")
+	}
+	// format the current config the same way as the updated config before comparing them
+	cc, err := parseCsiClusterConfig(curr)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse current csi cluster config")
+	}
+	current, err := formatCsiClusterConfig(cc)
+	if err != nil {
+		return false, err
+	}
+	return current != updated, nil
+}
 
-		newCsiClusterConfigEntry.CephFS.KernelMountOptions = clusterInfo.CSIDriverSpec.CephFS.KernelMountOptions
-		newCsiClusterConfigEntry.CephFS.FuseMountOptions = clusterInfo.CSIDriverSpec.CephFS.FuseMountOptions
+// clusterConfigUpdate is the desired csi config entry of a cluster ID, a nil entry removes the cluster ID from
+// the config
+type clusterConfigUpdate struct {
+	clusterID string
+	entry     *CSIClusterConfigEntry
+}
+
+// setCSIDriverOptions sets the CSI driver options of the cluster on the csi config entry, the read affinity is
+// not set if the entry keeps its own
+func setCSIDriverOptions(entry *CSIClusterConfigEntry, clusterInfo *cephclient.ClusterInfo) {
+	if entry == nil {
+		return
+	}
+	if !entry.KeepReadAffinity {
+		entry.ReadAffinity.Enabled = clusterInfo.CSIDriverSpec.ReadAffinity.Enabled
+		entry.ReadAffinity.CrushLocationLabels = clusterInfo.CSIDriverSpec.ReadAffinity.CrushLocationLabels
 	}
 
+	entry.CephFS.KernelMountOptions = clusterInfo.CSIDriverSpec.CephFS.KernelMountOptions
+	entry.CephFS.FuseMountOptions = clusterInfo.CSIDriverSpec.CephFS.FuseMountOptions
+}
+
+// saveClusterConfigUpdates applies the updates of the csi config entries of the cluster namespace with a single
+// update of the csi config map
+func saveClusterConfigUpdates(clientset kubernetes.Interface, csiNamespace, clusterNamespace string, clusterInfo *cephclient.ClusterInfo, updates []clusterConfigUpdate) error {
 	configMutex.Lock()
 	defer configMutex.Unlock()
 
@@ -348,14 +507,16 @@ func SaveClusterConfig(clientset kubernetes.Interface, clusterID, clusterNamespa
 	}
 
 	// update ConfigMap contents for current cluster
-	currData := configMap.Data[ConfigKey]
-	if currData == "" {
-		currData = "[]"
+	newData := configMap.Data[ConfigKey]
+	if newData == "" {
+		newData = "[]"
 	}
 
-	newData, err := updateCsiClusterConfig(currData, clusterID, clusterNamespace, newCsiClusterConfigEntry)
-	if err != nil {
-		return errors.Wrap(err, "failed to update csi config map data")
+	for _, update := range updates {
+		newData, err = updateCsiClusterConfig(newData, update.clusterID, clusterNamespace, update.entry)
+		if err != nil {
+			return errors.Wrap(err, "failed to update csi config map data")
+		}
 	}
 	configMap.Data[ConfigKey] = newData
 
@@ -440,3 +601,82 @@ func SaveCSIDriverOptions(clientset kubernetes.Interface, clusterNamespace strin
 
 	return nil
 }
+
+// removeOrphanedRadosNamespaceEntries removes the rados namespace entries of the cluster namespace that isOrphaned
+// reports as orphaned. Only the entries with a rados namespace are considered since the cluster and subvolumegroup
+// entries are not owned by a rados namespace.
+func removeOrphanedRadosNamespaceEntries(curr, clusterNamespace string, isOrphaned func(clusterID, radosNamespace string) bool) (string, []string, error) {
+	cc, err := parseCsiClusterConfig(curr)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to parse current csi cluster config")
+	}
+
+	removed := []string{}
+	kept := csiClusterConfig{}
+	for _, centry := range cc {
+		if centry.Namespace == clusterNamespace && centry.ClusterID != clusterNamespace &&
+			centry.RBD.RadosNamespace != "" && isOrphaned(centry.ClusterID, centry.RBD.RadosNamespace) {
+			removed = append(removed, centry.ClusterID)
+			continue
+		}
+		kept = append(kept, centry)
+	}
+	if len(removed) == 0 {
+		return curr, removed, nil
+	}
+
+	newData, err := formatCsiClusterConfig(kept)
+	if err != nil {
+		return "", nil, err
+	}
+	return newData, removed, nil
+}
+
+// RemoveOrphanedRadosNamespaceConfigs removes the csi config entries of the rados namespaces of the cluster
+// namespace that are left behind after their CephBlockPoolRadosNamespace CR was deleted, for example if the
+// operator restarted before cleaning them up. isOrphaned returns whether the entry with the cluster ID and rados
+// namespace is orphaned, so that the entries written by other controllers are kept.
+// Returns the cluster IDs of the removed entries.
+func RemoveOrphanedRadosNamespaceConfigs(clientset kubernetes.Interface, clusterNamespace string, clusterInfo *cephclient.ClusterInfo, isOrphaned func(clusterID, radosNamespace string) bool) ([]string, error) {
+	if EnableCSIOperator() {
+		logger.Debugf("csi-operator is enabled no need to clean up csi config in configmap %q", ConfigName)
+		return nil, nil
+	}
+	// csi is deployed into the same namespace as the operator
+	csiNamespace := os.Getenv(k8sutil.PodNamespaceEnvVar)
+	if csiNamespace == "" {
+		logger.Warningf("cannot clean up csi config due to missing env var %q", k8sutil.PodNamespaceEnvVar)
+		return nil, nil
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+
+	configMap, err := clientset.CoreV1().ConfigMaps(csiNamespace).Get(clusterInfo.Context, ConfigName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to fetch current csi config map")
+	}
+
+	currData := configMap.Data[ConfigKey]
+	if currData == "" {
+		return nil, nil
+	}
+
+	newData, removed, err := removeOrphanedRadosNamespaceEntries(currData, clusterNamespace, isOrphaned)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to remove orphaned rados namespace entries from csi config map data")
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+
+	configMap.Data[ConfigKey] = newData
+	if _, err := clientset.CoreV1().ConfigMaps(csiNamespace).Update(clusterInfo.Context, configMap, metav1.UpdateOptions{}); err != nil {
+		return nil, errors.Wrap(err, "failed to update csi config map")
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterConfigBatcher coalesces the csi config updates requested within a short window into a single update
+// of the csi config map per cluster namespace, so that the reconciles of many resources sharing the config map
+// do not rewrite it once each. The batches are flushed in the background when their window expires.
+type ClusterConfigBatcher struct {
+	window  time.Duration
+	mutex   sync.Mutex
+	pending map[string]*clusterConfigBatch
+}
+
+// clusterConfigBatch holds the updates of a cluster namespace waiting to be flushed. Only the last update of
+// each cluster ID is applied, in the order the cluster IDs were first requested.
+type clusterConfigBatch struct {
+	clientset   kubernetes.Interface
+	clusterInfo *cephclient.ClusterInfo
+	clusterIDs  []string
+	entries     map[string]*CSIClusterConfigEntry
+	done        chan struct{}
+	err         error
+}
+
+// NewClusterConfigBatcher returns a batcher flushing the updates requested within the window
+func NewClusterConfigBatcher(window time.Duration) *ClusterConfigBatcher {
+	return &ClusterConfigBatcher{
+		window:  window,
+		pending: map[string]*clusterConfigBatch{},
+	}
+}
+
+// SaveClusterConfig is the batched equivalent of SaveClusterConfig. The csi config entry of the cluster ID is
+// added to the pending batch of the cluster namespace, a nil entry removes the cluster ID from the config.
+// It returns once the batch is flushed, with the result of the config map update.
+func (b *ClusterConfigBatcher) SaveClusterConfig(clientset kubernetes.Interface, clusterID, clusterNamespace string, clusterInfo *cephclient.ClusterInfo, newCsiClusterConfigEntry *CSIClusterConfigEntry) error {
+	if EnableCSIOperator() {
+		logger.Debugf("csi-operator is enabled no need to save/update csi config in configmap %q", configName)
+		return nil
+	}
+	if os.Getenv(k8sutil.PodNamespaceEnvVar) == "" {
+		logger.Warningf("cannot save csi config due to missing env var %q", k8sutil.PodNamespaceEnvVar)
+		return nil
+	}
+	setCSIDriverOptions(newCsiClusterConfigEntry, clusterInfo)
+
+	b.mutex.Lock()
+	batch, ok := b.pending[clusterNamespace]
+	if !ok {
+		batch = &clusterConfigBatch{
+			clientset:   clientset,
+			clusterInfo: clusterInfo,
+			entries:     map[string]*CSIClusterConfigEntry{},
+			done:        make(chan struct{}),
+		}
+		b.pending[clusterNamespace] = batch
+		time.AfterFunc(b.window, func() { b.flush(clusterNamespace) })
+	}
+	if _, ok := batch.entries[clusterID]; !ok {
+		batch.clusterIDs = append(batch.clusterIDs, clusterID)
+	}
+	batch.entries[clusterID] = newCsiClusterConfigEntry
+	b.mutex.Unlock()
+
+	<-batch.done
+	return batch.err
+}
+
+// flush applies the pending batch of the cluster namespace with a single config map update
+func (b *ClusterConfigBatcher) flush(clusterNamespace string) {
+	b.mutex.Lock()
+	batch := b.pending[clusterNamespace]
+	delete(b.pending, clusterNamespace)
+	b.mutex.Unlock()
+	if batch == nil {
+		return
+	}
+
+	updates := make([]clusterConfigUpdate, 0, len(batch.clusterIDs))
+	for _, clusterID := range batch.clusterIDs {
+		updates = append(updates, clusterConfigUpdate{clusterID: clusterID, entry: batch.entries[clusterID]})
+	}
+	logger.Debugf("saving %d csi config updates of cluster namespace %q", len(updates), clusterNamespace)
+	batch.err = saveClusterConfigUpdates(batch.clientset, os.Getenv(k8sutil.PodNamespaceEnvVar), clusterNamespace, batch.clusterInfo, updates)
+	close(batch.done)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cephcsi "github.com/ceph/ceph-csi/api/deploy/kubernetes"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestClusterConfigBatcher(t *testing.T) {
+	ctx := context.TODO()
+	ns := "rook-ceph"
+	t.Setenv(k8sutil.PodNamespaceEnvVar, "rook-ceph-operator")
+
+	clientset := test.New(t, 1)
+	_, err := clientset.CoreV1().ConfigMaps("rook-ceph-operator").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName, Namespace: "rook-ceph-operator"},
+		Data:       map[string]string{ConfigKey: "[]"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	var writes int32
+	clientset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		atomic.AddInt32(&writes, 1)
+		return false, nil, nil
+	})
+	clusterInfo := &cephclient.ClusterInfo{Namespace: ns, Context: ctx}
+	clusterIDs := func() []string {
+		cm, err := clientset.CoreV1().ConfigMaps("rook-ceph-operator").Get(ctx, ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		entries, err := parseCsiClusterConfig(cm.Data[ConfigKey])
+		assert.NoError(t, err)
+		ids := []string{}
+		for _, entry := range entries {
+			ids = append(ids, entry.ClusterID)
+		}
+		return ids
+	}
+	entry := func(radosNamespace string) *CSIClusterConfigEntry {
+		return &CSIClusterConfigEntry{
+			Namespace:   ns,
+			ClusterInfo: cephcsi.ClusterInfo{RBD: cephcsi.RBD{RadosNamespace: radosNamespace}},
+		}
+	}
+	batcher := NewClusterConfigBatcher(100 * time.Millisecond)
+
+	t.Run("concurrent updates are coalesced", func(t *testing.T) {
+		const updates = 10
+		var wg sync.WaitGroup
+		for i := 0; i < updates; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				name := fmt.Sprintf("namespace-%d", i)
+				assert.NoError(t, batcher.SaveClusterConfig(clientset, "id-"+name, ns, clusterInfo, entry(name)))
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Less(t, int(atomic.LoadInt32(&writes)), updates)
+		assert.Len(t, clusterIDs(), updates)
+	})
+
+	t.Run("deletions are batched", func(t *testing.T) {
+		atomic.StoreInt32(&writes, 0)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, batcher.SaveClusterConfig(clientset, fmt.Sprintf("id-namespace-%d", i), ns, clusterInfo, nil))
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Less(t, int(atomic.LoadInt32(&writes)), 5)
+		assert.ElementsMatch(t, []string{"id-namespace-5", "id-namespace-6", "id-namespace-7", "id-namespace-8", "id-namespace-9"}, clusterIDs())
+	})
+
+	t.Run("the last update of a cluster ID wins", func(t *testing.T) {
+		done := make(chan error)
+		go func() {
+			done <- batcher.SaveClusterConfig(clientset, "id-namespace-a", ns, clusterInfo, entry("namespace-a"))
+		}()
+		// wait for the update to be pending before removing it in the same batch
+		assert.Eventually(t, func() bool {
+			batcher.mutex.Lock()
+			defer batcher.mutex.Unlock()
+			return batcher.pending[ns] != nil
+		}, time.Second, time.Millisecond)
+		assert.NoError(t, batcher.SaveClusterConfig(clientset, "id-namespace-a", ns, clusterInfo, nil))
+		assert.NoError(t, <-done)
+		assert.NotContains(t, clusterIDs(), "id-namespace-a")
+	})
+
+	t.Run("the config map is required", func(t *testing.T) {
+		err := clientset.CoreV1().ConfigMaps("rook-ceph-operator").Delete(ctx, ConfigName, metav1.DeleteOptions{})
+		assert.NoError(t, err)
+		err = batcher.SaveClusterConfig(clientset, "id-namespace-b", ns, clusterInfo, entry("namespace-b"))
+		assert.ErrorContains(t, err, "waiting for CSI config map to be created")
+	})
+}
@@ -1062,3 +1062,108 @@ func Test_updateCsiConfigMapOwnerRefs(t *testing.T) {
 		assertOwner(t, clientset)
 	})
 }
+
+func TestRemoveOrphanedRadosNamespaceConfigs(t *testing.T) {
+	ctx := context.TODO()
+	ns := "rook-ceph"
+	t.Setenv(k8sutil.PodNamespaceEnvVar, "rook-ceph-operator")
+
+	cc := csiClusterConfig{
+		{Namespace: ns, ClusterInfo: cephcsi.ClusterInfo{ClusterID: ns}},
+		{Namespace: ns, ClusterInfo: cephcsi.ClusterInfo{ClusterID: "kept", RBD: cephcsi.RBD{RadosNamespace: "namespace-a"}}},
+		{Namespace: ns, ClusterInfo: cephcsi.ClusterInfo{ClusterID: "orphan", RBD: cephcsi.RBD{RadosNamespace: "namespace-b"}}},
+		{Namespace: ns, ClusterInfo: cephcsi.ClusterInfo{ClusterID: "svg", CephFS: cephcsi.CephFS{SubvolumeGroup: "group-a"}}},
+		{Namespace: "other-ns", ClusterInfo: cephcsi.ClusterInfo{ClusterID: "other", RBD: cephcsi.RBD{RadosNamespace: "namespace-c"}}},
+	}
+	data, err := formatCsiClusterConfig(cc)
+	assert.NoError(t, err)
+
+	clientset := test.New(t, 1)
+	_, err = clientset.CoreV1().ConfigMaps("rook-ceph-operator").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigName, Namespace: "rook-ceph-operator"},
+		Data:       map[string]string{ConfigKey: data},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: ns, Context: ctx}
+	var checked []string
+	isOrphaned := func(clusterID, radosNamespace string) bool {
+		checked = append(checked, radosNamespace)
+		return clusterID == "orphan"
+	}
+
+	removed, err := RemoveOrphanedRadosNamespaceConfigs(clientset, ns, clusterInfo, isOrphaned)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orphan"}, removed)
+	// only the rados namespace entries of the cluster namespace are checked
+	assert.Equal(t, []string{"namespace-a", "namespace-b"}, checked)
+
+	cm, err := clientset.CoreV1().ConfigMaps("rook-ceph-operator").Get(ctx, ConfigName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	entries, err := parseCsiClusterConfig(cm.Data[ConfigKey])
+	assert.NoError(t, err)
+	clusterIDs := []string{}
+	for _, entry := range entries {
+		clusterIDs = append(clusterIDs, entry.ClusterID)
+	}
+	assert.Equal(t, []string{ns, "kept", "svg", "other"}, clusterIDs)
+
+	// nothing is removed on the next sweep
+	removed, err = RemoveOrphanedRadosNamespaceConfigs(clientset, ns, clusterInfo, isOrphaned)
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestCSIClusterConfigEntryRBDMapOptions(t *testing.T) {
+	entry := CSIClusterConfigEntry{
+		Namespace:     "rook-ceph",
+		ClusterInfo:   cephcsi.ClusterInfo{ClusterID: "cluster-id", RBD: cephcsi.RBD{RadosNamespace: "namespace-a"}},
+		RBDMapOptions: RBDMapOptions{MapOptions: "lock_on_read", UnmapOptions: "force"},
+	}
+
+	data, err := formatCsiClusterConfig(csiClusterConfig{entry})
+	assert.NoError(t, err)
+	assert.Contains(t, data, `"mapOptions":"lock_on_read"`)
+	assert.Contains(t, data, `"unmapOptions":"force"`)
+	cc, err := parseCsiClusterConfig(data)
+	assert.NoError(t, err)
+	assert.Equal(t, csiClusterConfig{entry}, cc)
+
+	// the options are removed from the existing entry when cleared
+	entry.RBDMapOptions = RBDMapOptions{}
+	data, err = updateCsiClusterConfig(data, "cluster-id", "rook-ceph", &entry)
+	assert.NoError(t, err)
+	assert.NotContains(t, data, "mapOptions")
+	cc, err = parseCsiClusterConfig(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "namespace-a", cc[0].RBD.RadosNamespace)
+	assert.Equal(t, RBDMapOptions{}, cc[0].RBDMapOptions)
+}
+
+func TestCSIClusterConfigEntryKeepReadAffinity(t *testing.T) {
+	clusterInfo := &cephclient.ClusterInfo{CSIDriverSpec: cephv1.CSIDriverSpec{
+		ReadAffinity: cephv1.ReadAffinitySpec{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/zone"}},
+	}}
+	override := cephcsi.ReadAffinity{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/rack"}}
+
+	// the read affinity of the cluster is set unless the entry keeps its own
+	entry := &CSIClusterConfigEntry{Namespace: "rook-ceph", ClusterInfo: cephcsi.ClusterInfo{ReadAffinity: override}}
+	setCSIDriverOptions(entry, clusterInfo)
+	assert.Equal(t, []string{"topology.kubernetes.io/zone"}, entry.ReadAffinity.CrushLocationLabels)
+	entry = &CSIClusterConfigEntry{Namespace: "rook-ceph", ClusterInfo: cephcsi.ClusterInfo{ReadAffinity: override}, KeepReadAffinity: true}
+	setCSIDriverOptions(entry, clusterInfo)
+	assert.Equal(t, override, entry.ReadAffinity)
+
+	data, err := updateCsiClusterConfig("[]", "cluster-id", "rook-ceph", entry)
+	assert.NoError(t, err)
+	cc, err := parseCsiClusterConfig(data)
+	assert.NoError(t, err)
+	assert.Equal(t, override, cc[0].ReadAffinity)
+
+	// an empty read affinity is written when kept, so that the override is reverted
+	entry = &CSIClusterConfigEntry{Namespace: "rook-ceph", KeepReadAffinity: true}
+	data, err = updateCsiClusterConfig(data, "cluster-id", "rook-ceph", entry)
+	assert.NoError(t, err)
+	cc, err = parseCsiClusterConfig(data)
+	assert.NoError(t, err)
+	assert.Equal(t, cephcsi.ReadAffinity{}, cc[0].ReadAffinity)
+}
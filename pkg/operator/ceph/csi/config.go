@@ -35,12 +35,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateUpdateClientProfileRadosNamespace(ctx context.Context, c client.Client, clusterInfo *cephclient.ClusterInfo, cephBlockPoolRadosNamespaceName, clusterID, clusterName string) error {
+// ClientProfileMetadataPrefix is the default prefix of the labels and annotations of a CR that are copied onto
+// its ceph-csi clientProfile CR
+const ClientProfileMetadataPrefix = "csi.ceph.rook.io/"
+
+// CreateUpdateClientProfileRadosNamespace creates or updates the ceph-csi clientProfile CR of a rados namespace.
+// The labels and annotations having one of the metadataPrefixes are copied onto the clientProfile CR.
+func CreateUpdateClientProfileRadosNamespace(ctx context.Context, c client.Client, clusterInfo *cephclient.ClusterInfo, cephBlockPoolRadosNamespaceName, clusterID, clusterName string, metadataPrefixes []string, labels, annotations map[string]string) error {
 	logger.Info("creating ceph-csi clientProfile CR for rados namespace")
 
 	csiOpClientProfile := &csiopv1a1.ClientProfile{}
 	csiOpClientProfile.Name = clusterID
 	csiOpClientProfile.Namespace = os.Getenv(k8sutil.PodNamespaceEnvVar)
+	csiOpClientProfile.Labels = syncPrefixedMetadata(nil, labels, metadataPrefixes)
+	csiOpClientProfile.Annotations = syncPrefixedMetadata(nil, annotations, metadataPrefixes)
 	csiOpClientProfile.Spec = csiopv1a1.ClientProfileSpec{
 		CephConnectionRef: v1.LocalObjectReference{
 			Name: clusterName,
@@ -63,6 +71,10 @@ func CreateUpdateClientProfileRadosNamespace(ctx context.Context, c client.Clien
 		return err
 	}
 
+	// propagate the additions and removals of the prefixed labels and annotations
+	csiOpClientProfile.Labels = syncPrefixedMetadata(csiOpClientProfile.Labels, labels, metadataPrefixes)
+	csiOpClientProfile.Annotations = syncPrefixedMetadata(csiOpClientProfile.Annotations, annotations, metadataPrefixes)
+
 	err = c.Update(ctx, csiOpClientProfile)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create ceph-csi clientProfile cr for RBD %q", csiOpClientProfile.Name)
@@ -72,6 +84,36 @@ func CreateUpdateClientProfileRadosNamespace(ctx context.Context, c client.Clien
 	return nil
 }
 
+// HasMetadataPrefix returns whether the label or annotation key has one of the prefixes
+func HasMetadataPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncPrefixedMetadata returns the existing metadata with the keys having one of the prefixes replaced by the
+// prefixed keys of the desired metadata
+func syncPrefixedMetadata(existing, desired map[string]string, prefixes []string) map[string]string {
+	synced := map[string]string{}
+	for key, value := range existing {
+		if !HasMetadataPrefix(key, prefixes) {
+			synced[key] = value
+		}
+	}
+	for key, value := range desired {
+		if HasMetadataPrefix(key, prefixes) {
+			synced[key] = value
+		}
+	}
+	if len(synced) == 0 {
+		return nil
+	}
+	return synced
+}
+
 func CreateUpdateClientProfileSubVolumeGroup(ctx context.Context, c client.Client, clusterInfo *cephclient.ClusterInfo, cephFilesystemSubVolumeGroupName, clusterID, clusterName string) error {
 	logger.Info("Creating ceph-csi clientProfile CR for subvolume group")
 
@@ -66,7 +66,7 @@ func TestCreateUpdateClientProfile(t *testing.T) {
 
 	// Create a fake client to mock API calls.
 	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(object...).Build()
-	err := CreateUpdateClientProfileRadosNamespace(context.TODO(), cl, c, cephBlockPoolRadosNamespacedName.Name, cephBlockPoolRadosNamespacedName.Name, clusterName)
+	err := CreateUpdateClientProfileRadosNamespace(context.TODO(), cl, c, cephBlockPoolRadosNamespacedName.Name, cephBlockPoolRadosNamespacedName.Name, clusterName, nil, nil, nil)
 	assert.NoError(t, err)
 
 	err = CreateUpdateClientProfileSubVolumeGroup(context.TODO(), cl, c, cephSubVolGrpNamespacedName.Name, cephSubVolGrpNamespacedName.Name, clusterName)
@@ -81,3 +81,56 @@ func TestCreateUpdateClientProfile(t *testing.T) {
 	assert.Equal(t, csiOpClientProfile.Spec.CephFs.SubVolumeGroup, cephSubVolGrpNamespacedName.Name)
 	assert.Equal(t, csiOpClientProfile.Spec.CephFs.KernelMountOptions["ms_mode"], kernelMountKeyVal[1])
 }
+
+func TestCreateUpdateClientProfileRadosNamespaceMetadata(t *testing.T) {
+	c := clienttest.CreateTestClusterInfo(3)
+	ns := "test"
+	c.Namespace = ns
+	t.Setenv(k8sutil.PodNamespaceEnvVar, ns)
+
+	clusterName := "testClusterName"
+	profileName := types.NamespacedName{Namespace: ns, Name: "cephBlockPoolRadosNames"}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &csiopv1a1.ClientProfile{})
+	cl := fake.NewClientBuilder().WithScheme(s).Build()
+
+	prefixes := []string{ClientProfileMetadataPrefix, "example.com/"}
+	labels := map[string]string{
+		ClientProfileMetadataPrefix + "team": "storage",
+		"example.com/cost-center":            "1234",
+		"app":                                "myapp",
+	}
+	annotations := map[string]string{
+		ClientProfileMetadataPrefix + "owner": "alice",
+	}
+
+	t.Run("prefixed labels and annotations are copied", func(t *testing.T) {
+		err := CreateUpdateClientProfileRadosNamespace(context.TODO(), cl, c, "rns", profileName.Name, clusterName, prefixes, labels, annotations)
+		assert.NoError(t, err)
+
+		csiOpClientProfile := &csiopv1a1.ClientProfile{}
+		err = cl.Get(context.TODO(), profileName, csiOpClientProfile)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{ClientProfileMetadataPrefix + "team": "storage", "example.com/cost-center": "1234"}, csiOpClientProfile.Labels)
+		assert.Equal(t, map[string]string{ClientProfileMetadataPrefix + "owner": "alice"}, csiOpClientProfile.Annotations)
+
+		// a label set by someone else is preserved across updates
+		csiOpClientProfile.Labels["other"] = "value"
+		err = cl.Update(context.TODO(), csiOpClientProfile)
+		assert.NoError(t, err)
+	})
+
+	t.Run("removed labels and annotations are removed", func(t *testing.T) {
+		delete(labels, ClientProfileMetadataPrefix+"team")
+		delete(labels, "example.com/cost-center")
+		labels[ClientProfileMetadataPrefix+"env"] = "prod"
+		err := CreateUpdateClientProfileRadosNamespace(context.TODO(), cl, c, "rns", profileName.Name, clusterName, prefixes, labels, nil)
+		assert.NoError(t, err)
+
+		csiOpClientProfile := &csiopv1a1.ClientProfile{}
+		err = cl.Get(context.TODO(), profileName, csiOpClientProfile)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{ClientProfileMetadataPrefix + "env": "prod", "other": "value"}, csiOpClientProfile.Labels)
+		assert.Empty(t, csiOpClientProfile.Annotations)
+	})
+}
@@ -845,3 +845,9 @@ func getPrefixFromArg(arg string) (string, bool) {
 func EnableCSIOperator() bool {
 	return enableCSIOperator
 }
+
+// SetEnableCSIOperator sets whether the csi operator is enabled, which is otherwise loaded from the operator
+// settings by the csi controller. It lets the tests of the other controllers create the csi operator resources.
+func SetEnableCSIOperator(enable bool) {
+	enableCSIOperator = enable
+}
@@ -33,6 +33,12 @@ func (r *ReconcileCephBlockPool) reconcileAddBootstrapPeer(pool *cephv1.CephBloc
 		return reconcile.Result{}, nil
 	}
 
+	// The peers belong to the pool, the rados namespaces of the pool may request the direction of the peers
+	direction, siteDirections, err := r.radosNamespacesPeerDirections(pool)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, err
+	}
+
 	// List all the peers secret, we can have more than one peer we might want to configure
 	// For each, get the Kubernetes Secret and import the "peer token" so that we can configure the mirroring
 	for _, peerSecret := range pool.Spec.Mirroring.Peers.SecretNames {
@@ -49,8 +55,13 @@ func (r *ReconcileCephBlockPool) reconcileAddBootstrapPeer(pool *cephv1.CephBloc
 			return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to validate rbd-mirror bootstrap peer secret %q data", peerSecret)
 		}
 
-		// Import bootstrap peer
-		err = client.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, pool.Name, string(s.Data["direction"]), s.Data["token"])
+		// The direction of the secret takes precedence over the direction requested by the rados namespaces
+		peerDirection := string(s.Data["direction"])
+		if peerDirection != "" {
+			err = client.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, pool.Name, peerDirection, s.Data["token"])
+		} else {
+			err = r.importBootstrapPeerWithDirections(pool, s.Data["token"], direction, siteDirections)
+		}
 		if err != nil {
 			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to import bootstrap peer token")
 		}
@@ -58,3 +69,70 @@ func (r *ReconcileCephBlockPool) reconcileAddBootstrapPeer(pool *cephv1.CephBloc
 
 	return reconcile.Result{}, nil
 }
+
+// radosNamespacesPeerDirections returns the peer directions requested by the rados namespaces of the pool
+func (r *ReconcileCephBlockPool) radosNamespacesPeerDirections(pool *cephv1.CephBlockPool) (cephv1.RadosNamespaceMirroringDirection, map[string]cephv1.RadosNamespaceMirroringDirection, error) {
+	radosNamespaces, err := r.context.RookClientset.CephV1().CephBlockPoolRadosNamespaces("").List(r.opManagerContext, metav1.ListOptions{})
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to list the rados namespaces of pool %q", pool.Name)
+	}
+
+	poolRadosNamespaces := []cephv1.CephBlockPoolRadosNamespace{}
+	for _, radosNamespace := range radosNamespaces.Items {
+		blockPoolNamespace := radosNamespace.Spec.BlockPoolNamespace
+		if blockPoolNamespace == "" {
+			blockPoolNamespace = radosNamespace.Namespace
+		}
+		if radosNamespace.Spec.BlockPoolName == pool.Name && blockPoolNamespace == pool.Namespace {
+			poolRadosNamespaces = append(poolRadosNamespaces, radosNamespace)
+		}
+	}
+
+	direction, siteDirections, err := cephv1.GetMirroringPeerDirections(poolRadosNamespaces)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "invalid mirroring peer directions of the rados namespaces of pool %q", pool.Name)
+	}
+	return direction, siteDirections, nil
+}
+
+// importBootstrapPeerWithDirections imports a bootstrap peer with the direction requested for all the peers. The
+// direction requested for the site of the peer is then set on the peer if it was added by the import, the
+// direction of the peers that are already configured is never changed.
+func (r *ReconcileCephBlockPool) importBootstrapPeerWithDirections(pool *cephv1.CephBlockPool, token []byte,
+	direction cephv1.RadosNamespaceMirroringDirection, siteDirections map[string]cephv1.RadosNamespaceMirroringDirection,
+) error {
+	if len(siteDirections) == 0 {
+		return client.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, pool.Name, string(direction), token)
+	}
+
+	mirrorInfo, err := client.GetPoolMirroringInfo(r.context, r.clusterInfo, pool.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get mirroring info of pool %q", pool.Name)
+	}
+	configuredPeers := map[string]bool{}
+	for _, peer := range mirrorInfo.Peers {
+		configuredPeers[peer.UUID] = true
+	}
+
+	err = client.ImportRBDMirrorBootstrapPeer(r.context, r.clusterInfo, pool.Name, string(direction), token)
+	if err != nil {
+		return err
+	}
+
+	mirrorInfo, err = client.GetPoolMirroringInfo(r.context, r.clusterInfo, pool.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get mirroring info of pool %q", pool.Name)
+	}
+	for _, peer := range mirrorInfo.Peers {
+		siteDirection, ok := siteDirections[peer.SiteName]
+		if configuredPeers[peer.UUID] || !ok || peer.Direction == string(siteDirection) {
+			continue
+		}
+		err = client.SetRBDMirrorPeerDirection(r.context, r.clusterInfo, pool.Name, peer.UUID, string(siteDirection))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRadosNamespacesPeerDirections(t *testing.T) {
+	ns := "rook-ceph"
+	pool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: ns}}
+	newRadosNamespace := func(namespace, name, poolName string, direction cephv1.RadosNamespaceMirroringDirection) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: poolName,
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image", Direction: direction},
+			},
+		}
+	}
+
+	t.Run("directions of the rados namespaces of the pool", func(t *testing.T) {
+		r := &ReconcileCephBlockPool{
+			context: &clusterd.Context{RookClientset: rookclient.NewSimpleClientset(
+				newRadosNamespace(ns, "namespace-a", "replicapool", cephv1.RadosNamespaceMirroringDirectionRxOnly),
+				// the rados namespaces of other pools are ignored
+				newRadosNamespace(ns, "namespace-b", "otherpool", cephv1.RadosNamespaceMirroringDirectionRxTx),
+				newRadosNamespace("other-ns", "namespace-c", "replicapool", cephv1.RadosNamespaceMirroringDirectionRxTx),
+			)},
+			opManagerContext: context.TODO(),
+		}
+		direction, siteDirections, err := r.radosNamespacesPeerDirections(pool)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.RadosNamespaceMirroringDirectionRxOnly, direction)
+		assert.Empty(t, siteDirections)
+	})
+
+	t.Run("conflicting directions", func(t *testing.T) {
+		r := &ReconcileCephBlockPool{
+			context: &clusterd.Context{RookClientset: rookclient.NewSimpleClientset(
+				newRadosNamespace(ns, "namespace-a", "replicapool", cephv1.RadosNamespaceMirroringDirectionRxOnly),
+				newRadosNamespace(ns, "namespace-b", "replicapool", cephv1.RadosNamespaceMirroringDirectionRxTx),
+			)},
+			opManagerContext: context.TODO(),
+		}
+		_, _, err := r.radosNamespacesPeerDirections(pool)
+		assert.ErrorContains(t, err, "conflicting mirroring peer directions")
+	})
+}
+
+func TestImportBootstrapPeerWithDirections(t *testing.T) {
+	pool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"}}
+	existingPeer := `{"uuid":"4a6983c0-3c9d-40f5-b2a9-2334a4659827","direction":"rx-tx","site_name":"site-b"}`
+	importedPeer := `{"uuid":"8a0c5ed3-5884-4ef3-8a4d-390745765884","direction":"rx-tx","site_name":"site-c"}`
+
+	newReconciler := func(importDirection *string, peerSet map[string]string) *ReconcileCephBlockPool {
+		imported := false
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "mirror" && args[1] == "pool" {
+					switch args[2] {
+					case "info":
+						if imported {
+							return `{"mode":"image","peers":[` + existingPeer + `,` + importedPeer + `]}`, nil
+						}
+						return `{"mode":"image","peers":[` + existingPeer + `]}`, nil
+					case "peer":
+						if args[3] == "bootstrap" && args[4] == "import" {
+							imported = true
+							*importDirection = ""
+							if len(args) > 7 && args[7] == "--direction" {
+								*importDirection = args[8]
+							}
+						}
+						if args[3] == "set" {
+							peerSet[args[5]] = args[7]
+						}
+					}
+				}
+				return "", nil
+			},
+		}
+		return &ReconcileCephBlockPool{
+			context:          &clusterd.Context{Executor: executor},
+			clusterInfo:      client.AdminTestClusterInfo("rook-ceph"),
+			opManagerContext: context.TODO(),
+		}
+	}
+
+	t.Run("direction of all the peers", func(t *testing.T) {
+		importDirection := ""
+		peerSet := map[string]string{}
+		r := newReconciler(&importDirection, peerSet)
+		err := r.importBootstrapPeerWithDirections(pool, []byte("token"), cephv1.RadosNamespaceMirroringDirectionRxOnly, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "rx-only", importDirection)
+		assert.Empty(t, peerSet)
+	})
+
+	t.Run("direction of a site is only set on the imported peer", func(t *testing.T) {
+		importDirection := ""
+		peerSet := map[string]string{}
+		r := newReconciler(&importDirection, peerSet)
+		err := r.importBootstrapPeerWithDirections(pool, []byte("token"), "", map[string]cephv1.RadosNamespaceMirroringDirection{
+			"site-b": cephv1.RadosNamespaceMirroringDirectionRxOnly,
+			"site-c": cephv1.RadosNamespaceMirroringDirectionTxOnly,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "", importDirection)
+		assert.Equal(t, map[string]string{"8a0c5ed3-5884-4ef3-8a4d-390745765884": "tx-only"}, peerSet)
+	})
+}
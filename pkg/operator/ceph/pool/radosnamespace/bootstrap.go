@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// bootstrapPeerTokenAnnotation requests a new mirroring bootstrap peer token for the rados namespace each
+	// time its value is changed
+	bootstrapPeerTokenAnnotation = "ceph.rook.io/create-bootstrap-peer-token"
+	// bootstrapPeerTokenRequestInfoKey is the status info key of the last bootstrap peer token request handled
+	bootstrapPeerTokenRequestInfoKey = "bootstrapPeerTokenRequest"
+)
+
+// ExportBootstrapPeerToken creates the mirroring bootstrap peer token of the rados namespace and stores it
+// in a Kubernetes Secret, returns the name of the secret. The token grants access to the pool of the cluster, so
+// it is only stored in the namespace of the CephCluster.
+func ExportBootstrapPeerToken(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, radosNamespace *cephv1.CephBlockPoolRadosNamespace, ownerInfo *k8sutil.OwnerInfo) (string, error) {
+	if radosNamespace.Spec.Mirroring == nil {
+		return "", errors.Errorf("mirroring is not enabled on rados namespace %q", radosNamespace.Name)
+	}
+	if radosNamespace.Namespace != clusterInfo.Namespace {
+		return "", errors.Errorf("the bootstrap peer token of rados namespace %q is only exported in the namespace %q of its CephCluster", radosNamespace.Name, clusterInfo.Namespace)
+	}
+	_, err := opcontroller.CreateBootstrapPeerSecret(context, clusterInfo, radosNamespace, ownerInfo)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create rbd-mirror bootstrap peer for rados namespace %q", radosNamespace.Name)
+	}
+	return opcontroller.GenerateStatusInfo(radosNamespace)[opcontroller.RBDMirrorBootstrapPeerSecretName], nil
+}
+
+// reconcileBootstrapPeerToken exports the bootstrap peer token of the rados namespace when requested by the
+// annotation. The token is only created again when the value of the annotation changes.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileBootstrapPeerToken(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) error {
+	request := radosNamespace.GetAnnotations()[bootstrapPeerTokenAnnotation]
+	if request == "" {
+		return nil
+	}
+	if radosNamespace.Status != nil && radosNamespace.Status.Info[bootstrapPeerTokenRequestInfoKey] == request {
+		return nil
+	}
+	if radosNamespace.Spec.Mirroring == nil {
+		log.Warningf("cannot create the bootstrap peer token of rados namespace %q until mirroring is enabled", name)
+		return nil
+	}
+	if poolNamespace := blockPoolNamespace(radosNamespace); poolNamespace != radosNamespace.Namespace {
+		log.Warningf("cannot create the bootstrap peer token of rados namespace %q outside of the namespace %q of its CephBlockPool, request it on the CephBlockPool instead", name, poolNamespace)
+		return nil
+	}
+
+	secretName, err := ExportBootstrapPeerToken(r.context, r.clusterInfo, radosNamespace, k8sutil.NewOwnerInfo(radosNamespace, r.scheme))
+	if err != nil {
+		return err
+	}
+	log.Infof("bootstrap peer token of rados namespace %q stored in secret %q", name, secretName)
+
+	err = r.mutateStatus(name, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		if current.Status.Info == nil {
+			current.Status.Info = map[string]string{}
+		}
+		current.Status.Info[opcontroller.RBDMirrorBootstrapPeerSecretName] = secretName
+		current.Status.Info[bootstrapPeerTokenRequestInfoKey] = request
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to report the bootstrap peer secret of rados namespace %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testBootstrapPeerToken = `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==`
+
+func TestReconcileBootstrapPeerToken(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace, tokenCalls *int) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client: cl,
+			scheme: scheme.Scheme,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "bootstrap" {
+							*tokenCalls++
+							return testBootstrapPeerToken, nil
+						}
+						return "", nil
+					},
+				},
+				Clientset: testop.New(t, 1),
+			},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+			opManagerContext: ctx,
+		}
+	}
+	newRadosNamespace := func(mirroring *cephv1.RadosNamespaceMirroring) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name.Name,
+				Namespace:   name.Namespace,
+				UID:         "c47cac40-9bee-4d52-823b-ccd803ba5bfe",
+				Annotations: map[string]string{bootstrapPeerTokenAnnotation: "1"},
+			},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     mirroring,
+			},
+			Status: &cephv1.CephBlockPoolRadosNamespaceStatus{},
+		}
+	}
+
+	t.Run("token is exported to a secret once per request", func(t *testing.T) {
+		tokenCalls := 0
+		radosNamespace := newRadosNamespace(&cephv1.RadosNamespaceMirroring{Mode: "image"})
+		r := newReconciler(radosNamespace, &tokenCalls)
+
+		err := r.reconcileBootstrapPeerToken(radosNamespace, name, log)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, tokenCalls)
+
+		secret, err := r.context.Clientset.CoreV1().Secrets(name.Namespace).Get(ctx, "rados-namespace-peer-token-namespace-a", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, secret.Data["token"])
+		assert.Equal(t, "replicapool", string(secret.Data["pool"]))
+		assert.Equal(t, "namespace-a", string(secret.Data["radosNamespace"]))
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, secret.Name, current.Status.Info[opcontroller.RBDMirrorBootstrapPeerSecretName])
+		assert.Equal(t, "1", current.Status.Info[bootstrapPeerTokenRequestInfoKey])
+
+		// the token is not created again for the same request
+		err = r.reconcileBootstrapPeerToken(current, name, log)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, tokenCalls)
+
+		// a new request creates the token again
+		current.Annotations[bootstrapPeerTokenAnnotation] = "2"
+		err = r.reconcileBootstrapPeerToken(current, name, log)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tokenCalls)
+	})
+
+	t.Run("token is not exported without mirroring", func(t *testing.T) {
+		tokenCalls := 0
+		radosNamespace := newRadosNamespace(nil)
+		r := newReconciler(radosNamespace, &tokenCalls)
+
+		err := r.reconcileBootstrapPeerToken(radosNamespace, name, log)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, tokenCalls)
+
+		_, err = ExportBootstrapPeerToken(r.context, r.clusterInfo, radosNamespace, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestBootstrapPeerTokenOfPoolInAnotherNamespace(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "tenant-a"}
+	tokenCalls := 0
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name.Name,
+			Namespace:   name.Namespace,
+			Annotations: map[string]string{bootstrapPeerTokenAnnotation: "1"},
+		},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:      "replicapool",
+			BlockPoolNamespace: "rook-ceph",
+			Mirroring:          &cephv1.RadosNamespaceMirroring{Mode: "image"},
+		},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{},
+	}
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+		scheme: scheme.Scheme,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					tokenCalls++
+					return testBootstrapPeerToken, nil
+				},
+			},
+			Clientset: testop.New(t, 1),
+		},
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: "rook-ceph", Context: ctx},
+		opManagerContext: ctx,
+	}
+
+	// the token of the pool is never written to the namespace of the CR
+	err := r.reconcileBootstrapPeerToken(radosNamespace, name, newReconcileLogger(name))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tokenCalls)
+	secrets, err := r.context.Clientset.CoreV1().Secrets(name.Namespace).List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, secrets.Items)
+
+	_, err = ExportBootstrapPeerToken(r.context, r.clusterInfo, radosNamespace, nil)
+	assert.ErrorContains(t, err, `only exported in the namespace "rook-ceph" of its CephCluster`)
+	assert.Equal(t, 0, tokenCalls)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// cephSettingsChecksumAnnotation is the checksum of the ceph settings of the spec last applied to ceph
+const cephSettingsChecksumAnnotation = "ceph.rook.io/ceph-settings-checksum"
+
+// cephSettings are the fields of the spec applied to ceph. The other fields, such as the map options, the csi
+// settings, the cluster ID, the external monitors and the deletion settings, only change the csi config or the
+// status. A rados namespace of an external cluster is not created by the operator, so switching to or from an
+// external cluster changes the ceph settings.
+type cephSettings struct {
+	Name                string                             `json:"name"`
+	BlockPoolName       string                             `json:"blockPoolName"`
+	BlockPoolNamespace  string                             `json:"blockPoolNamespace"`
+	CephClusterName     string                             `json:"cephClusterName"`
+	Mirroring           *cephv1.RadosNamespaceMirroring    `json:"mirroring"`
+	ApplicationMetadata map[string]string                  `json:"applicationMetadata"`
+	Description         string                             `json:"description"`
+	SetAsPoolDefault    bool                               `json:"setAsPoolDefault"`
+	Compression         *cephv1.RadosNamespaceCompression  `json:"compression"`
+	PostCreateConfig    []cephv1.RadosNamespaceConfigEntry `json:"postCreateConfig"`
+	External            bool                               `json:"external"`
+}
+
+// cephSettingsChecksum returns the checksum of the ceph settings of the spec
+func cephSettingsChecksum(radosNamespace *cephv1.CephBlockPoolRadosNamespace) (string, error) {
+	spec := radosNamespace.Spec
+	settings, err := json.Marshal(cephSettings{
+		Name:                cephv1.GetRadosNamespaceName(radosNamespace),
+		BlockPoolName:       spec.BlockPoolName,
+		BlockPoolNamespace:  blockPoolNamespace(radosNamespace),
+		CephClusterName:     spec.CephClusterName,
+		Mirroring:           spec.Mirroring,
+		ApplicationMetadata: spec.ApplicationMetadata,
+		Description:         spec.Description,
+		SetAsPoolDefault:    spec.SetAsPoolDefault,
+		Compression:         spec.Compression,
+		PostCreateConfig:    spec.PostCreateConfig,
+		External:            spec.External != nil,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal the ceph settings of rados namespace %q", radosNamespace.Name)
+	}
+	return k8sutil.Hash(string(settings)), nil
+}
+
+// isCSIOnlyChange returns whether the rados namespace was fully reconciled and only the fields of the spec that do
+// not apply to ceph or the metadata copied onto the csi operator client profile changed since, so that the
+// reconcile only updates the csi config and the status
+func (r *ReconcileCephBlockPoolRadosNamespace) isCSIOnlyChange(name types.NamespacedName, radosNamespace *cephv1.CephBlockPoolRadosNamespace, fingerprint reconcileFingerprint) bool {
+	if radosNamespace.Status == nil || radosNamespace.Status.Phase != cephv1.ConditionReady {
+		return false
+	}
+	// the csi config of a rados namespace waiting for healthy mirroring depends on the mirroring reconcile
+	if waitsForMirrorHealth(radosNamespace) {
+		return false
+	}
+	if !r.fingerprints.isGenerationOrMetadataOnlyChange(name, fingerprint) {
+		return false
+	}
+	applied := radosNamespace.GetAnnotations()[cephSettingsChecksumAnnotation]
+	checksum, err := cephSettingsChecksum(radosNamespace)
+	return err == nil && applied != "" && applied == checksum
+}
+
+// recordCephSettingsChecksum sets the checksum of the ceph settings applied by a full reconcile in the annotation of
+// the rados namespace. The annotation does not trigger a reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) recordCephSettingsChecksum(name types.NamespacedName, radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	checksum, err := cephSettingsChecksum(radosNamespace)
+	if err != nil {
+		return err
+	}
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, current); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get rados namespace %q", name)
+	}
+	if current.GetAnnotations()[cephSettingsChecksumAnnotation] == checksum {
+		return nil
+	}
+	patch := client.MergeFrom(current.DeepCopy())
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	current.Annotations[cephSettingsChecksumAnnotation] = checksum
+	if err := r.client.Patch(r.opManagerContext, current, patch); err != nil {
+		return errors.Wrapf(err, "failed to set the %q annotation of rados namespace %q", cephSettingsChecksumAnnotation, name)
+	}
+	return nil
+}
+
+// reconcileCSIOnly updates the csi config and the status of a rados namespace whose ceph settings are already
+// applied, without running any ceph command
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileCSIOnly(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster, name types.NamespacedName, fingerprint reconcileFingerprint, log *reconcileLogger) (reconcile.Result, error) {
+	log.Infof("only the csi settings of generation %d of rados namespace %q changed, skipping the ceph commands", radosNamespace.Generation, name)
+	_, err := r.updateClusterConfig(radosNamespace, *cephCluster, log)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to save cluster config")
+	}
+	r.recordMilestoneEvent(radosNamespace, csiConfigUpdatedEventReason, csiConfigState(radosNamespace), fmt.Sprintf("updated the csi config of cluster ID %q", buildClusterID(radosNamespace)))
+	r.updateStatus(radosNamespace.Generation, name, cephv1.ConditionReady, log)
+
+	if csi.EnableCSIOperator() {
+		// the fingerprint is not updated so that the client profile is created once the csi operator is ready
+		if res, err := r.reconcileClientProfile(radosNamespace, cephCluster, name, log); err != nil || !res.IsZero() {
+			return res, err
+		}
+	}
+	r.fingerprints.update(name, fingerprint)
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCephSettingsChecksum(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	checksum, err := cephSettingsChecksum(radosNamespace)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, checksum)
+
+	t.Run("csi settings do not change the checksum", func(t *testing.T) {
+		changed := radosNamespace.DeepCopy()
+		changed.Spec.MapOptions = "lock_on_read"
+		changed.Spec.UnmapOptions = "force"
+		changed.Spec.ClusterID = "cluster-a"
+		changed.Spec.CSI = &cephv1.RadosNamespaceCSISpec{ReadAffinity: &cephv1.ReadAffinitySpec{Enabled: true}}
+		current, err := cephSettingsChecksum(changed)
+		assert.NoError(t, err)
+		assert.Equal(t, checksum, current)
+	})
+
+	t.Run("ceph settings change the checksum", func(t *testing.T) {
+		for name, change := range map[string]func(*cephv1.CephBlockPoolRadosNamespace){
+			"name":        func(rns *cephv1.CephBlockPoolRadosNamespace) { rns.Spec.Name = "other" },
+			"pool":        func(rns *cephv1.CephBlockPoolRadosNamespace) { rns.Spec.BlockPoolName = "otherpool" },
+			"description": func(rns *cephv1.CephBlockPoolRadosNamespace) { rns.Spec.Description = "volumes" },
+			"metadata": func(rns *cephv1.CephBlockPoolRadosNamespace) {
+				rns.Spec.ApplicationMetadata = map[string]string{"a": "b"}
+			},
+			"mirroring": func(rns *cephv1.CephBlockPoolRadosNamespace) {
+				rns.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{Mode: "image"}
+			},
+			"default": func(rns *cephv1.CephBlockPoolRadosNamespace) { rns.Spec.SetAsPoolDefault = true },
+		} {
+			changed := radosNamespace.DeepCopy()
+			change(changed)
+			current, err := cephSettingsChecksum(changed)
+			assert.NoError(t, err)
+			assert.NotEqual(t, checksum, current, name)
+		}
+	})
+}
+
+func TestReconcileCSIOnlyChange(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	var commands [][]string
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		commands = append(commands, args)
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	r.context.Executor.(*exectest.MockExecutor).MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		commands = append(commands, args)
+		return "", nil
+	}
+	createTestCSIConfigMap(t, r, namespace)
+	cl := r.client
+	c := r.context
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+	generation := int64(1)
+	updateSpec := func(change func(*cephv1.CephBlockPoolRadosNamespace)) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		change(current)
+		generation++
+		current.Generation = generation
+		assert.NoError(t, cl.Update(ctx, current))
+		return current
+	}
+	mapOptions := func() string {
+		cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		var entries []csi.CSIClusterConfigEntry
+		assert.NoError(t, json.Unmarshal([]byte(cm.Data[csi.ConfigKey]), &entries))
+		assert.Len(t, entries, 1)
+		return entries[0].RBD.RBDMapOptions.MapOptions
+	}
+
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, commands)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+	checksum, err := cephSettingsChecksum(current)
+	assert.NoError(t, err)
+	assert.Equal(t, checksum, current.Annotations[cephSettingsChecksumAnnotation])
+
+	t.Run("csi settings change runs no ceph command", func(t *testing.T) {
+		updateSpec(func(rns *cephv1.CephBlockPoolRadosNamespace) { rns.Spec.MapOptions = "krbd:rxbounce" })
+		commands = nil
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+		assert.Equal(t, "krbd:rxbounce", mapOptions())
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+		assert.Equal(t, generation, current.Status.ObservedGeneration)
+	})
+
+	t.Run("ceph settings change runs the ceph commands", func(t *testing.T) {
+		updateSpec(func(rns *cephv1.CephBlockPoolRadosNamespace) { rns.Spec.Description = "database volumes" })
+		commands = nil
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, commands)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		checksum, err := cephSettingsChecksum(current)
+		assert.NoError(t, err)
+		assert.Equal(t, checksum, current.Annotations[cephSettingsChecksumAnnotation])
+	})
+
+	t.Run("missing checksum runs the ceph commands", func(t *testing.T) {
+		updateSpec(func(rns *cephv1.CephBlockPoolRadosNamespace) {
+			rns.Spec.MapOptions = "krbd:force"
+			delete(rns.Annotations, cephSettingsChecksumAnnotation)
+		})
+		commands = nil
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, commands)
+		assert.Equal(t, "krbd:force", mapOptions())
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mirrorCheckerStaleIntervals is the number of check intervals without a heartbeat after which a mirroring
+// checker is considered dead
+const mirrorCheckerStaleIntervals = 3
+
+// mirrorCheckerStaleAfter returns how long a mirroring checker running checks at the given interval can go
+// without a heartbeat before it is considered dead, each check being bounded by the ceph call timeout
+func mirrorCheckerStaleAfter(interval time.Duration) time.Duration {
+	return mirrorCheckerStaleIntervals*interval + cephCallTimeout()
+}
+
+// mirrorMonitoringHeartbeat returns the function called by the mirroring checker of the radosNamespace before
+// each check. The heartbeats are recorded on the context tracked when the function is created, so that a
+// checker that was replaced does not keep its replacement alive.
+func (r *ReconcileCephBlockPoolRadosNamespace) mirrorMonitoringHeartbeat(channelKey string) func() {
+	r.radosNamespaceContextsLock.Lock()
+	health := r.radosNamespaceContexts[channelKey]
+	r.radosNamespaceContextsLock.Unlock()
+
+	return func() {
+		if health == nil {
+			return
+		}
+		r.radosNamespaceContextsLock.Lock()
+		defer r.radosNamespaceContextsLock.Unlock()
+		health.lastHeartbeat = time.Now()
+	}
+}
+
+// restartDeadMirrorMonitoring cancels the mirroring checker of the radosNamespace if its go routine exited
+// while its context was not cancelled, e.g. after a panic, or if it did not heartbeat for longer than
+// staleAfter. Returns whether the checker was dead, it is then started again by the reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) restartDeadMirrorMonitoring(channelKey string, staleAfter time.Duration, log *reconcileLogger) bool {
+	r.radosNamespaceContextsLock.Lock()
+	defer r.radosNamespaceContextsLock.Unlock()
+
+	health, ok := r.radosNamespaceContexts[channelKey]
+	if !ok || !health.started {
+		return false
+	}
+
+	select {
+	case <-health.done:
+		if health.internalCtx.Err() != nil {
+			return false
+		}
+		log.Warningf("mirroring status checker of radosnamespace %q exited unexpectedly, restarting it", channelKey)
+	default:
+		if time.Since(health.lastHeartbeat) <= staleAfter {
+			return false
+		}
+		log.Warningf("mirroring status checker of radosnamespace %q did not check the mirroring status since %s, restarting it",
+			channelKey, health.lastHeartbeat.Format(time.RFC3339))
+	}
+
+	r.cancelMirrorMonitoringLocked(channelKey)
+	mirrorCheckerRestartsCounter.Inc()
+	return true
+}
+
+// mirrorMonitoringStaleAfter returns how long the running mirroring checker of the radosNamespace can go without a
+// heartbeat before it is considered dead, or 0 if no checker is running
+func (r *ReconcileCephBlockPoolRadosNamespace) mirrorMonitoringStaleAfter(channelKey string) time.Duration {
+	r.radosNamespaceContextsLock.Lock()
+	defer r.radosNamespaceContextsLock.Unlock()
+
+	health, ok := r.radosNamespaceContexts[channelKey]
+	if !ok || !health.started || health.interval == 0 {
+		return 0
+	}
+	return mirrorCheckerStaleAfter(health.interval)
+}
+
+// checkMirrorMonitoringLiveness cancels the mirroring checker of the radosNamespace if it is dead. It runs before
+// the reconcile of an unchanged CR is skipped, since the checker is only started again by a full reconcile.
+// Returns whether the checker was dead.
+func (r *ReconcileCephBlockPoolRadosNamespace) checkMirrorMonitoringLiveness(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) bool {
+	channelKey := mirrorMonitoringChannelKey(radosNamespace)
+	staleAfter := r.mirrorMonitoringStaleAfter(channelKey)
+	if staleAfter <= 0 {
+		return false
+	}
+	return r.restartDeadMirrorMonitoring(channelKey, staleAfter, log)
+}
+
+// mirrorMonitoringResync returns the interval of the next reconcile of the radosNamespace, so that the liveness
+// of its running mirroring checker is checked again once the checker could be stale, even if the resync is not set
+func (r *ReconcileCephBlockPoolRadosNamespace) mirrorMonitoringResync(radosNamespace *cephv1.CephBlockPoolRadosNamespace, resync time.Duration) time.Duration {
+	staleAfter := r.mirrorMonitoringStaleAfter(mirrorMonitoringChannelKey(radosNamespace))
+	if staleAfter > 0 && (resync <= 0 || staleAfter < resync) {
+		return staleAfter
+	}
+	return resync
+}
+
+// mirrorHealthCheckInterval returns the mirroring health check interval set on the rados namespace, if any
+func mirrorHealthCheckInterval(radosNamespace *cephv1.CephBlockPoolRadosNamespace) *metav1.Duration {
+	mirroring := radosNamespace.Spec.Mirroring
+	if mirroring == nil || mirroring.HealthCheck == nil {
+		return nil
+	}
+	return mirroring.HealthCheck.Interval
+}
+
+// restartMirrorMonitoringOnIntervalChange records the check interval of the mirroring checker of the
+// radosNamespace, and restarts the checker if it is running with a different interval. Returns whether the
+// checker was cancelled, it is then started again by the reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) restartMirrorMonitoringOnIntervalChange(channelKey string, interval time.Duration, log *reconcileLogger) bool {
+	r.radosNamespaceContextsLock.Lock()
+	defer r.radosNamespaceContextsLock.Unlock()
+
+	health, ok := r.radosNamespaceContexts[channelKey]
+	if !ok {
+		return false
+	}
+	if !health.started || health.interval == 0 || health.interval == interval {
+		health.interval = interval
+		return false
+	}
+
+	log.Infof("mirroring status check interval of radosnamespace %q changed from %s to %s, restarting the checker",
+		channelKey, health.interval.String(), interval.String())
+	r.cancelMirrorMonitoringLocked(channelKey)
+	r.initMirrorMonitoringLocked(channelKey).interval = interval
+	return true
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestartDeadMirrorMonitoring(t *testing.T) {
+	const channelKey = "rook-ceph/replicapool/namespace-a"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	blockUntilCancelled := func(ctx context.Context) { <-ctx.Done() }
+	newReconciler := func() *ReconcileCephBlockPoolRadosNamespace {
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			opManagerContext:       context.TODO(),
+			radosNamespaceContexts: map[string]*mirrorHealth{},
+		}
+		r.initMirrorMonitoring(channelKey)
+		return r
+	}
+	waitForExit := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) {
+		select {
+		case <-r.radosNamespaceContexts[channelKey].done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the mirroring checker did not exit")
+		}
+	}
+
+	t.Run("checker not started", func(t *testing.T) {
+		r := newReconciler()
+		assert.False(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+		assert.False(t, r.restartDeadMirrorMonitoring("rook-ceph/replicapool/unknown", time.Minute, log))
+	})
+
+	t.Run("healthy checker", func(t *testing.T) {
+		r := newReconciler()
+		defer r.stopAllMirrorMonitoring(time.Second)
+		heartbeat := r.mirrorMonitoringHeartbeat(channelKey)
+		assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+
+		r.radosNamespaceContexts[channelKey].lastHeartbeat = time.Now().Add(-time.Hour)
+		heartbeat()
+		assert.False(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+		assert.True(t, r.radosNamespaceContexts[channelKey].started)
+	})
+
+	t.Run("stale heartbeat", func(t *testing.T) {
+		r := newReconciler()
+		defer r.stopAllMirrorMonitoring(time.Second)
+		assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+		stale := r.radosNamespaceContexts[channelKey]
+		stale.lastHeartbeat = time.Now().Add(-time.Hour)
+
+		assert.True(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+		assert.NotContains(t, r.radosNamespaceContexts, channelKey)
+		assert.Error(t, stale.internalCtx.Err())
+
+		// the next reconcile starts a new checker
+		r.initMirrorMonitoring(channelKey)
+		assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+		assert.False(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+	})
+
+	t.Run("checker exited", func(t *testing.T) {
+		r := newReconciler()
+		defer r.stopAllMirrorMonitoring(time.Second)
+		assert.True(t, r.startMirrorMonitoring(channelKey, func(ctx context.Context) {}))
+		waitForExit(t, r)
+
+		assert.True(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+		r.initMirrorMonitoring(channelKey)
+		assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+	})
+
+	t.Run("checker panicked", func(t *testing.T) {
+		r := newReconciler()
+		defer r.stopAllMirrorMonitoring(time.Second)
+		assert.True(t, r.startMirrorMonitoring(channelKey, func(ctx context.Context) { panic("checker failure") }))
+		waitForExit(t, r)
+
+		assert.True(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+		r.initMirrorMonitoring(channelKey)
+		assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+	})
+
+	t.Run("cancelled checker is not restarted", func(t *testing.T) {
+		r := newReconciler()
+		assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+		health := r.radosNamespaceContexts[channelKey]
+		health.internalCancel()
+		waitForExit(t, r)
+
+		assert.False(t, r.restartDeadMirrorMonitoring(channelKey, time.Minute, log))
+		r.stopAllMirrorMonitoring(time.Second)
+	})
+}
+
+func TestMirrorCheckerStaleAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Minute+cephCallTimeout(), mirrorCheckerStaleAfter(time.Minute))
+}
+
+func TestRestartMirrorMonitoringOnIntervalChange(t *testing.T) {
+	const channelKey = "rook-ceph/replicapool/namespace-a"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		opManagerContext:       context.TODO(),
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+	defer r.stopAllMirrorMonitoring(time.Second)
+	blockUntilCancelled := func(ctx context.Context) { <-ctx.Done() }
+
+	assert.False(t, r.restartMirrorMonitoringOnIntervalChange(channelKey, time.Minute, log))
+	r.initMirrorMonitoring(channelKey)
+	assert.False(t, r.restartMirrorMonitoringOnIntervalChange(channelKey, time.Minute, log))
+	assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+
+	// the same interval keeps the checker running
+	running := r.radosNamespaceContexts[channelKey]
+	assert.False(t, r.restartMirrorMonitoringOnIntervalChange(channelKey, time.Minute, log))
+	assert.Same(t, running, r.radosNamespaceContexts[channelKey])
+
+	// a new interval cancels the checker, and the next start runs it with the new interval
+	assert.True(t, r.restartMirrorMonitoringOnIntervalChange(channelKey, 10*time.Second, log))
+	assert.Error(t, running.internalCtx.Err())
+	assert.Equal(t, 10*time.Second, r.radosNamespaceContexts[channelKey].interval)
+	assert.True(t, r.startMirrorMonitoring(channelKey, blockUntilCancelled))
+}
+
+func TestMirrorHealthCheckIntervalOverride(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:        "image",
+				HealthCheck: &cephv1.RadosNamespaceMirroringHealthCheck{Interval: &metav1.Duration{Duration: 10 * time.Second}},
+			},
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Interval = &metav1.Duration{Duration: time.Minute}
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"image"}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+						return "[]", nil
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+	defer r.stopAllMirrorMonitoring(time.Second)
+	channelKey := mirrorMonitoringChannelKey(radosNamespace)
+
+	// the interval of the rados namespace overrides the interval of the pool
+	assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+	running := r.radosNamespaceContexts[channelKey]
+	assert.True(t, running.started)
+	assert.Equal(t, 10*time.Second, running.interval)
+
+	// the checker keeps running while the interval is unchanged
+	assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+	assert.Same(t, running, r.radosNamespaceContexts[channelKey])
+
+	// the checker is restarted with the new interval
+	radosNamespace.Spec.Mirroring.HealthCheck.Interval = &metav1.Duration{Duration: 20 * time.Second}
+	assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+	assert.Error(t, running.internalCtx.Err())
+	running = r.radosNamespaceContexts[channelKey]
+	assert.True(t, running.started)
+	assert.Equal(t, 20*time.Second, running.interval)
+
+	// the interval of the pool is used again once the override is removed
+	radosNamespace.Spec.Mirroring.HealthCheck = nil
+	assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+	assert.Equal(t, time.Minute, r.radosNamespaceContexts[channelKey].interval)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	batch "k8s.io/api/batch/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const cleanupJobNamePrefix = "cleanup-radosnamespace-"
+
+// waitForRequeueIfCleanupJobRunning requeues the deletion while the clean up job is running, in case the
+// completion of the job is missed by the job watch
+var waitForRequeueIfCleanupJobRunning = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+
+// cleanupJobName returns the name of the job cleaning up the images of the rados namespace
+func cleanupJobName(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	return k8sutil.TruncateNodeNameForJob(cleanupJobNamePrefix+"%s", fmt.Sprintf("%s-%s", radosNamespace.Spec.BlockPoolName, radosNamespace.Name))
+}
+
+// isCleanupJobRunning returns whether the clean up job started by a previous reconcile is still running. The
+// state of the job is updated in the status info once it is done so that the images are checked again.
+func (r *ReconcileCephBlockPoolRadosNamespace) isCleanupJobRunning(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) (bool, error) {
+	if radosNamespace.Status == nil || radosNamespace.Status.Info[cleanupJobInfoKey] != cleanupJobRunning {
+		return false, nil
+	}
+
+	nsName := types.NamespacedName{Namespace: radosNamespace.Namespace, Name: radosNamespace.Name}
+	jobName := cleanupJobName(radosNamespace)
+	job, err := r.context.Clientset.BatchV1().Jobs(radosNamespace.Namespace).Get(r.opManagerContext, jobName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Infof("clean up job %q for radosNamespace %q no longer exists", jobName, radosNamespace.Name)
+			r.updateCleanupJobStatus(nsName, "", "", log)
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get clean up job %q for radosNamespace %q", jobName, radosNamespace.Name)
+	}
+
+	state := cleanupJobState(job)
+	switch state {
+	case cleanupJobRunning:
+		log.Infof("waiting for clean up job %q for radosNamespace %q to complete", jobName, radosNamespace.Name)
+		return true, nil
+	case cleanupJobFailed:
+		log.Warningf("clean up job %q for radosNamespace %q failed", jobName, radosNamespace.Name)
+	default:
+		log.Infof("clean up job %q for radosNamespace %q completed", jobName, radosNamespace.Name)
+	}
+	r.updateCleanupJobStatus(nsName, "", state, log)
+	return false, nil
+}
+
+// cleanupJobBlocksFinalizerRemoval returns whether the finalizer must be kept since the clean up job launched for
+// the rados namespace has not completed yet. The name of the job is recorded in the status info when the job is
+// started, so the job is checked even if a later reconcile lost track of its state. A failed job blocks the
+// removal with an error until it is recreated or deleted.
+func (r *ReconcileCephBlockPoolRadosNamespace) cleanupJobBlocksFinalizerRemoval(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) (bool, error) {
+	if radosNamespace.Status == nil || radosNamespace.Status.Info[cleanupJobNameInfoKey] == "" {
+		return false, nil
+	}
+
+	jobName := radosNamespace.Status.Info[cleanupJobNameInfoKey]
+	job, err := r.context.Clientset.BatchV1().Jobs(radosNamespace.Namespace).Get(r.opManagerContext, jobName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			log.Infof("clean up job %q for radosNamespace %q no longer exists, removing the finalizer", jobName, radosNamespace.Name)
+			return false, nil
+		}
+		return true, errors.Wrapf(err, "failed to get clean up job %q for radosNamespace %q", jobName, radosNamespace.Name)
+	}
+
+	switch cleanupJobState(job) {
+	case cleanupJobRunning:
+		log.Infof("keeping the finalizer of radosNamespace %q until clean up job %q completes", radosNamespace.Name, jobName)
+		return true, nil
+	case cleanupJobFailed:
+		return true, errors.Errorf("clean up job %q for radosNamespace %q failed, delete the job to remove the finalizer", jobName, radosNamespace.Name)
+	}
+	return false, nil
+}
+
+// radosNamespacesForCleanupJob maps a clean up job to the request of the deleted rados namespace it cleans up
+func radosNamespacesForCleanupJob(ctx context.Context, c client.Client, job *batch.Job) []reconcile.Request {
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	err := c.List(ctx, radosNamespaces, client.InNamespace(job.Namespace))
+	if err != nil {
+		logger.Errorf("failed to list CephBlockPoolRadosNamespace(s) while handling event for clean up job %q in namespace %q. %v", job.Name, job.Namespace, err)
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for i := range radosNamespaces.Items {
+		item := &radosNamespaces.Items[i]
+		if item.DeletionTimestamp.IsZero() || cleanupJobName(item) != job.Name {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace},
+		})
+	}
+	return requests
+}
+
+// cleanupJobFinishedPredicate triggers a reconcile when a rados namespace clean up job completes, fails or
+// is deleted
+func cleanupJobFinishedPredicate() predicate.TypedFuncs[*batch.Job] {
+	return predicate.TypedFuncs[*batch.Job]{
+		CreateFunc: func(e event.TypedCreateEvent[*batch.Job]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*batch.Job]) bool {
+			return strings.HasPrefix(e.ObjectNew.Name, cleanupJobNamePrefix) &&
+				cleanupJobState(e.ObjectOld) != cleanupJobState(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*batch.Job]) bool {
+			return strings.HasPrefix(e.Object.Name, cleanupJobNamePrefix)
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*batch.Job]) bool {
+			return false
+		},
+	}
+}
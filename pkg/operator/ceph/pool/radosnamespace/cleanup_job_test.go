@@ -0,0 +1,240 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/stretchr/testify/assert"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIsCleanupJobRunning(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	newRadosNamespace := func(cleanupJob string) *cephv1.CephBlockPoolRadosNamespace {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+		if cleanupJob != "" {
+			radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{cleanupJobInfoKey: cleanupJob}}
+		}
+		return radosNamespace
+	}
+	newJob := func(conditions ...batch.JobCondition) *batch.Job {
+		return &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: cleanupJobName(newRadosNamespace("")), Namespace: name.Namespace},
+			Status:     batch.JobStatus{Conditions: conditions},
+		}
+	}
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace, objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client:           cl,
+			context:          &clusterd.Context{Clientset: k8sfake.NewSimpleClientset(objects...)},
+			opManagerContext: ctx,
+		}
+	}
+	cleanupJobStatus := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) (string, bool) {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		state, ok := current.Status.Info[cleanupJobInfoKey]
+		return state, ok
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("no clean up job was started", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("")
+		// the job is not even looked up
+		r := &ReconcileCephBlockPoolRadosNamespace{opManagerContext: ctx}
+		running, err := r.isCleanupJobRunning(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.False(t, running)
+	})
+
+	t.Run("clean up job in progress", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(cleanupJobRunning)
+		r := newReconciler(radosNamespace, newJob())
+		running, err := r.isCleanupJobRunning(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.True(t, running)
+		state, _ := cleanupJobStatus(t, r)
+		assert.Equal(t, cleanupJobRunning, state)
+	})
+
+	t.Run("clean up job completed", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(cleanupJobRunning)
+		r := newReconciler(radosNamespace, newJob(batch.JobCondition{Type: batch.JobComplete, Status: v1.ConditionTrue}))
+		running, err := r.isCleanupJobRunning(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.False(t, running)
+		state, _ := cleanupJobStatus(t, r)
+		assert.Equal(t, cleanupJobSucceeded, state)
+	})
+
+	t.Run("clean up job failed", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(cleanupJobRunning)
+		r := newReconciler(radosNamespace, newJob(batch.JobCondition{Type: batch.JobFailed, Status: v1.ConditionTrue}))
+		running, err := r.isCleanupJobRunning(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.False(t, running)
+		state, _ := cleanupJobStatus(t, r)
+		assert.Equal(t, cleanupJobFailed, state)
+	})
+
+	t.Run("clean up job was deleted", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(cleanupJobRunning)
+		r := newReconciler(radosNamespace)
+		running, err := r.isCleanupJobRunning(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.False(t, running)
+		_, ok := cleanupJobStatus(t, r)
+		assert.False(t, ok)
+	})
+}
+
+func TestCleanupJobBlocksFinalizerRemoval(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	jobName := cleanupJobNamePrefix + "replicapool-namespace-a"
+	newRadosNamespace := func(info map[string]string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+			Status:     &cephv1.CephBlockPoolRadosNamespaceStatus{Info: info},
+		}
+	}
+	newJob := func(conditions ...batch.JobCondition) *batch.Job {
+		return &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: name.Namespace},
+			Status:     batch.JobStatus{Conditions: conditions},
+		}
+	}
+	newReconciler := func(objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+		return &ReconcileCephBlockPoolRadosNamespace{
+			context:          &clusterd.Context{Clientset: k8sfake.NewSimpleClientset(objects...)},
+			opManagerContext: ctx,
+		}
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("no clean up job was launched", func(t *testing.T) {
+		// the job is not even looked up
+		r := &ReconcileCephBlockPoolRadosNamespace{opManagerContext: ctx}
+		blocked, err := r.cleanupJobBlocksFinalizerRemoval(newRadosNamespace(nil), log)
+		assert.NoError(t, err)
+		assert.False(t, blocked)
+	})
+
+	t.Run("clean up job still running", func(t *testing.T) {
+		r := newReconciler(newJob())
+		// the job blocks the removal even if its state was lost
+		for _, info := range []map[string]string{
+			{cleanupJobNameInfoKey: jobName, cleanupJobInfoKey: cleanupJobRunning},
+			{cleanupJobNameInfoKey: jobName, cleanupJobInfoKey: cleanupJobSucceeded},
+			{cleanupJobNameInfoKey: jobName},
+		} {
+			blocked, err := r.cleanupJobBlocksFinalizerRemoval(newRadosNamespace(info), log)
+			assert.NoError(t, err)
+			assert.True(t, blocked)
+		}
+	})
+
+	t.Run("clean up job completed", func(t *testing.T) {
+		r := newReconciler(newJob(batch.JobCondition{Type: batch.JobComplete, Status: v1.ConditionTrue}))
+		blocked, err := r.cleanupJobBlocksFinalizerRemoval(newRadosNamespace(map[string]string{cleanupJobNameInfoKey: jobName}), log)
+		assert.NoError(t, err)
+		assert.False(t, blocked)
+	})
+
+	t.Run("clean up job failed", func(t *testing.T) {
+		r := newReconciler(newJob(batch.JobCondition{Type: batch.JobFailed, Status: v1.ConditionTrue}))
+		blocked, err := r.cleanupJobBlocksFinalizerRemoval(newRadosNamespace(map[string]string{cleanupJobNameInfoKey: jobName}), log)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed")
+		assert.True(t, blocked)
+	})
+
+	t.Run("clean up job was deleted", func(t *testing.T) {
+		r := newReconciler()
+		blocked, err := r.cleanupJobBlocksFinalizerRemoval(newRadosNamespace(map[string]string{cleanupJobNameInfoKey: jobName}), log)
+		assert.NoError(t, err)
+		assert.False(t, blocked)
+	})
+}
+
+func TestRadosNamespacesForCleanupJob(t *testing.T) {
+	ctx := context.TODO()
+	newRadosNamespace := func(name string, deleted bool) *cephv1.CephBlockPoolRadosNamespace {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+		if deleted {
+			radosNamespace.Finalizers = []string{"cephblockpoolradosnamespace.ceph.rook.io"}
+			radosNamespace.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return radosNamespace
+	}
+	deleted := newRadosNamespace("namespace-a", true)
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(
+		deleted,
+		newRadosNamespace("namespace-b", false),
+		newRadosNamespace("namespace-c", true),
+	).Build()
+
+	job := &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: cleanupJobName(deleted), Namespace: "rook-ceph"}}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}}},
+		radosNamespacesForCleanupJob(ctx, cl, job))
+
+	job = &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: cleanupJobName(newRadosNamespace("namespace-b", false)), Namespace: "rook-ceph"}}
+	assert.Empty(t, radosNamespacesForCleanupJob(ctx, cl, job))
+
+	job = &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: cleanupJobName(deleted), Namespace: "other"}}
+	assert.Empty(t, radosNamespacesForCleanupJob(ctx, cl, job))
+}
+
+func TestCleanupJobFinishedPredicate(t *testing.T) {
+	p := cleanupJobFinishedPredicate()
+	running := &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: cleanupJobNamePrefix + "replicapool-namespace-a"}}
+	completed := running.DeepCopy()
+	completed.Status.Conditions = []batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}}
+	other := &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-osd-prepare-node-a"}}
+	otherCompleted := other.DeepCopy()
+	otherCompleted.Status.Conditions = completed.Status.Conditions
+
+	assert.False(t, p.Create(event.TypedCreateEvent[*batch.Job]{Object: running}))
+	assert.True(t, p.Update(event.TypedUpdateEvent[*batch.Job]{ObjectOld: running, ObjectNew: completed}))
+	assert.False(t, p.Update(event.TypedUpdateEvent[*batch.Job]{ObjectOld: running, ObjectNew: running.DeepCopy()}))
+	assert.False(t, p.Update(event.TypedUpdateEvent[*batch.Job]{ObjectOld: other, ObjectNew: otherCompleted}))
+	assert.True(t, p.Delete(event.TypedDeleteEvent[*batch.Job]{Object: running}))
+	assert.False(t, p.Delete(event.TypedDeleteEvent[*batch.Job]{Object: other}))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cloneFromAnnotation copies the settings of another rados namespace of the pool, as "<pool>/<name>", into the
+// status of a new rados namespace before it is created. Only the settings are copied, not the images.
+const cloneFromAnnotation = "ceph.rook.io/clone-from"
+
+// parseCloneFrom returns the pool and the rados namespace name of the clone-from annotation
+func parseCloneFrom(value string) (string, string, error) {
+	pool, name, found := strings.Cut(value, "/")
+	if !found || pool == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", errors.Errorf("invalid %q annotation %q, expected \"<pool>/<name>\"", cloneFromAnnotation, value)
+	}
+	return pool, name, nil
+}
+
+// cloneSettings records in the status the compression, post create config and mirroring settings of the rados
+// namespace referenced by the clone-from annotation, with the image features and QoS limits set on that rados
+// namespace in ceph. The settings are only copied before the rados namespace is reconciled for the first time,
+// the spec is not updated.
+func (r *ReconcileCephBlockPoolRadosNamespace) cloneSettings(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) error {
+	cloneFrom := radosNamespace.GetAnnotations()[cloneFromAnnotation]
+	if cloneFrom == "" || !radosNamespace.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	if radosNamespace.Status != nil && (radosNamespace.Status.ClonedSettings != nil || radosNamespace.Status.ObservedGeneration != 0) {
+		log.Debugf("ignoring %q annotation of radosNamespace %q created before", cloneFromAnnotation, radosNamespace.Name)
+		return nil
+	}
+
+	pool, name, err := parseCloneFrom(cloneFrom)
+	if err != nil {
+		return err
+	}
+	source, err := r.cloneSource(radosNamespace, pool, name)
+	if err != nil {
+		return err
+	}
+	config, err := r.cloneConfig(source, log)
+	if err != nil {
+		return err
+	}
+	cloned := &cephv1.RadosNamespaceClonedSettings{
+		From:             cloneFrom,
+		Compression:      source.Spec.Compression.DeepCopy(),
+		PostCreateConfig: config,
+		Mirroring:        source.Spec.Mirroring.DeepCopy(),
+	}
+
+	nsName := types.NamespacedName{Name: radosNamespace.Name, Namespace: radosNamespace.Namespace}
+	err = r.mutateStatus(nsName, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		current.Status.ClonedSettings = cloned.DeepCopy()
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to record the settings of %q in the status of radosNamespace %q", cloneFrom, radosNamespace.Name)
+	}
+	if radosNamespace.Status == nil {
+		radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+	}
+	radosNamespace.Status.ClonedSettings = cloned
+	log.Infof("copied the settings of rados namespace %q into radosNamespace %q", cloneFrom, radosNamespace.Name)
+	return nil
+}
+
+// cloneConfig returns the rbd config options of the rados namespace to clone from: the options of its
+// spec.postCreateConfig and the allowed options set on the rados namespace in ceph, such as the image features
+// and QoS limits, with the values of the spec taking precedence
+func (r *ReconcileCephBlockPoolRadosNamespace) cloneConfig(source *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) ([]cephv1.RadosNamespaceConfigEntry, error) {
+	var config map[string]string
+	err := log.timeCephCall("get clone source config", func() error {
+		var err error
+		config, err = cephclient.GetRadosNamespaceConfig(r.context, r.clusterInfo, source.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(source), sortedAllowedPostCreateConfigKeys())
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the config of rados namespace %q to clone from", source.Name)
+	}
+	for _, entry := range source.Spec.PostCreateConfig {
+		config[entry.Key] = entry.Value
+	}
+
+	var entries []cephv1.RadosNamespaceConfigEntry
+	for _, key := range sortedConfigKeys(config) {
+		entries = append(entries, cephv1.RadosNamespaceConfigEntry{Key: key, Value: config[key]})
+	}
+	return entries, nil
+}
+
+// applyClonedSettings uses the settings cloned into the status for the settings that are not set in the spec.
+// Only the rados namespace of the reconcile is changed, it must not be written back to the CR.
+func applyClonedSettings(radosNamespace *cephv1.CephBlockPoolRadosNamespace) {
+	if radosNamespace.Status == nil || radosNamespace.Status.ClonedSettings == nil {
+		return
+	}
+	cloned := radosNamespace.Status.ClonedSettings
+	if radosNamespace.Spec.Compression == nil && cloned.Compression != nil {
+		radosNamespace.Spec.Compression = cloned.Compression.DeepCopy()
+	}
+	if len(radosNamespace.Spec.PostCreateConfig) == 0 && len(cloned.PostCreateConfig) != 0 {
+		radosNamespace.Spec.PostCreateConfig = append([]cephv1.RadosNamespaceConfigEntry{}, cloned.PostCreateConfig...)
+	}
+	if radosNamespace.Spec.Mirroring == nil && cloned.Mirroring != nil {
+		radosNamespace.Spec.Mirroring = cloned.Mirroring.DeepCopy()
+	}
+}
+
+// cloneSource returns the rados namespace CR of the pool to copy the settings from, the pool is in the same
+// namespace as the pool of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) cloneSource(radosNamespace *cephv1.CephBlockPoolRadosNamespace, pool, name string) (*cephv1.CephBlockPoolRadosNamespace, error) {
+	cephRNSList := &cephv1.CephBlockPoolRadosNamespaceList{}
+	matchingKey := fmt.Sprintf("%s/%s", blockPoolKey(blockPoolNamespace(radosNamespace), pool), name)
+	if err := r.client.List(r.opManagerContext, cephRNSList, &client.MatchingFields{cephRNSNameIndex: matchingKey}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list the rados namespaces to clone %q from", name)
+	}
+	for i := range cephRNSList.Items {
+		item := &cephRNSList.Items[i]
+		if item.Namespace == radosNamespace.Namespace && item.Name == radosNamespace.Name {
+			continue
+		}
+		return item, nil
+	}
+	return nil, errors.Errorf("rados namespace %q of pool %q to clone the settings from not found", name, pool)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// cloneSourceConfig is the output of the rbd config of the rados namespace to clone from, with an option that
+// is not set on the rados namespace
+const cloneSourceConfig = `[{"name":"rbd_default_features","value":"layering","source":"namespace"},` +
+	`{"name":"rbd_qos_bps_limit","value":"100M","source":"namespace"},` +
+	`{"name":"rbd_qos_iops_limit","value":"0","source":"config"}]`
+
+func TestParseCloneFrom(t *testing.T) {
+	pool, name, err := parseCloneFrom("replicapool/namespace-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "replicapool", pool)
+	assert.Equal(t, "namespace-a", name)
+
+	for _, value := range []string{"replicapool", "/namespace-a", "replicapool/", "replicapool/namespace-a/b"} {
+		_, _, err := parseCloneFrom(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCloneSettings(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	source := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Compression:   &cephv1.RadosNamespaceCompression{Hint: "compressible"},
+			PostCreateConfig: []cephv1.RadosNamespaceConfigEntry{
+				{Key: "rbd_default_features", Value: "layering,exclusive-lock"},
+				{Key: "rbd_qos_iops_limit", Value: "1000"},
+			},
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:              "image",
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+			},
+		},
+	}
+	newRadosNamespace := func(cloneFrom string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "namespace-b",
+				Namespace:   namespace,
+				Annotations: map[string]string{cloneFromAnnotation: cloneFrom},
+			},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+	}
+	newReconciler := func(objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).
+			WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, cephRNSNameIndex, indexRadosNamespaceName).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client: cl,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "config" && args[1] == "namespace" && args[2] == "list" {
+							assert.Equal(t, "replicapool/namespace-a", args[3])
+							return cloneSourceConfig, nil
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+			opManagerContext: ctx,
+		}
+	}
+	name := types.NamespacedName{Name: "namespace-b", Namespace: namespace}
+	get := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		return current
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("settings are cloned into the status", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("replicapool/namespace-a")
+		r := newReconciler(source.DeepCopy(), radosNamespace.DeepCopy())
+		assert.NoError(t, r.client.Get(ctx, name, radosNamespace))
+		assert.NoError(t, r.cloneSettings(radosNamespace, log))
+
+		expected := &cephv1.RadosNamespaceClonedSettings{
+			From:        "replicapool/namespace-a",
+			Compression: source.Spec.Compression,
+			// the image features of the spec take precedence over the ones set in ceph
+			PostCreateConfig: []cephv1.RadosNamespaceConfigEntry{
+				{Key: "rbd_default_features", Value: "layering,exclusive-lock"},
+				{Key: "rbd_qos_bps_limit", Value: "100M"},
+				{Key: "rbd_qos_iops_limit", Value: "1000"},
+			},
+			Mirroring: source.Spec.Mirroring,
+		}
+		current := get(t, r)
+		assert.Equal(t, expected, current.Status.ClonedSettings)
+		assert.Equal(t, expected, radosNamespace.Status.ClonedSettings)
+		// the spec is not updated
+		assert.Nil(t, current.Spec.Compression)
+		assert.Empty(t, current.Spec.PostCreateConfig)
+		assert.Nil(t, current.Spec.Mirroring)
+
+		// the settings are not cloned again
+		current.Status.ClonedSettings.From = "recorded"
+		assert.NoError(t, r.cloneSettings(current, log))
+		assert.Equal(t, "replicapool/namespace-a", get(t, r).Status.ClonedSettings.From)
+	})
+
+	t.Run("rados namespace created before", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("replicapool/namespace-a")
+		radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{ObservedGeneration: 1}
+		r := newReconciler(source.DeepCopy(), radosNamespace.DeepCopy())
+		assert.NoError(t, r.cloneSettings(radosNamespace, log))
+		assert.Nil(t, get(t, r).Status.ClonedSettings)
+	})
+
+	t.Run("no annotation", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("")
+		r := newReconciler(source.DeepCopy(), radosNamespace.DeepCopy())
+		assert.NoError(t, r.cloneSettings(radosNamespace, log))
+		assert.Nil(t, radosNamespace.Status)
+	})
+
+	t.Run("source not found", func(t *testing.T) {
+		for _, cloneFrom := range []string{"replicapool/namespace-c", "otherpool/namespace-a", "replicapool/namespace-b"} {
+			radosNamespace := newRadosNamespace(cloneFrom)
+			r := newReconciler(source.DeepCopy(), radosNamespace.DeepCopy())
+			assert.Error(t, r.cloneSettings(radosNamespace, log), cloneFrom)
+			assert.Nil(t, radosNamespace.Status)
+		}
+	})
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("namespace-a")
+		r := newReconciler(source.DeepCopy(), radosNamespace.DeepCopy())
+		assert.Error(t, r.cloneSettings(radosNamespace, log))
+	})
+}
+
+func TestApplyClonedSettings(t *testing.T) {
+	cloned := &cephv1.RadosNamespaceClonedSettings{
+		From:             "replicapool/namespace-a",
+		Compression:      &cephv1.RadosNamespaceCompression{Hint: "compressible"},
+		PostCreateConfig: []cephv1.RadosNamespaceConfigEntry{{Key: "rbd_default_features", Value: "layering"}},
+		Mirroring:        &cephv1.RadosNamespaceMirroring{Mode: "image"},
+	}
+
+	t.Run("cloned settings apply to the settings not set in the spec", func(t *testing.T) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			Spec:   cephv1.CephBlockPoolRadosNamespaceSpec{Compression: &cephv1.RadosNamespaceCompression{Hint: "none"}},
+			Status: &cephv1.CephBlockPoolRadosNamespaceStatus{ClonedSettings: cloned.DeepCopy()},
+		}
+		applyClonedSettings(radosNamespace)
+		assert.Equal(t, "none", radosNamespace.Spec.Compression.Hint)
+		assert.Equal(t, cloned.PostCreateConfig, radosNamespace.Spec.PostCreateConfig)
+		assert.Equal(t, cloned.Mirroring, radosNamespace.Spec.Mirroring)
+	})
+
+	t.Run("no cloned settings", func(t *testing.T) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+		applyClonedSettings(radosNamespace)
+		assert.Equal(t, cephv1.CephBlockPoolRadosNamespaceSpec{}, radosNamespace.Spec)
+	})
+}
+
+func TestReconcileClonedSettings(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	source := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:    "replicapool",
+			Compression:      &cephv1.RadosNamespaceCompression{Hint: "compressible"},
+			PostCreateConfig: []cephv1.RadosNamespaceConfigEntry{{Key: "rbd_qos_iops_limit", Value: "1000"}},
+		},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "namespace-b",
+			Namespace:   namespace,
+			Generation:  1,
+			Finalizers:  []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+			Annotations: map[string]string{cloneFromAnnotation: "replicapool/namespace-a"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+
+	var configured []string
+	executor := func(command string, args ...string) (string, error) {
+		if len(args) >= 4 && args[0] == "config" && args[1] == "namespace" {
+			switch args[2] {
+			case "list":
+				if args[3] == "replicapool/namespace-a" {
+					return cloneSourceConfig, nil
+				}
+				return "[]", nil
+			case "set":
+				configured = append(configured, strings.Join(args[3:6], " "))
+			}
+		}
+		return "", nil
+	}
+	r := newTestReconciler(t, namespace, executor, source, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	r.context.Executor.(*exectest.MockExecutor).MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		return executor(command, args...)
+	}
+	createTestCSIConfigMap(t, r, namespace)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-b", Namespace: namespace}}
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+
+	// the cloned settings are applied to the new rados namespace
+	assert.Contains(t, configured, "replicapool/namespace-b rbd_compression_hint compressible")
+	assert.Contains(t, configured, "replicapool/namespace-b rbd_default_features layering")
+	assert.Contains(t, configured, "replicapool/namespace-b rbd_qos_bps_limit 100M")
+	assert.Contains(t, configured, "replicapool/namespace-b rbd_qos_iops_limit 1000")
+
+	// the cloned settings are recorded in the status and the spec is left as is
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, r.client.Get(ctx, req.NamespacedName, current))
+	assert.Equal(t, "replicapool/namespace-a", current.Status.ClonedSettings.From)
+	assert.Equal(t, cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"}, current.Spec)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// clusterFullEventReason is the reason of the warning event recorded when the cluster is full
+const clusterFullEventReason = "ClusterFull"
+
+// waitForRequeueIfClusterFull retries the creation of the rados namespace once space may have been freed, since
+// retrying while the cluster is full only adds load to the cluster
+var waitForRequeueIfClusterFull = reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Minute}
+
+// waitForClusterSpace reports that the rados namespace cannot be created because the ceph cluster is full, and
+// retries the reconcile with a long backoff
+func (r *ReconcileCephBlockPoolRadosNamespace) waitForClusterSpace(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, err *ClusterFullError, log *reconcileLogger) reconcile.Result {
+	message := fmt.Sprintf("the ceph cluster is full, rados namespace %q cannot be created in pool %q. Free space by deleting unused images or snapshots, or add capacity to the cluster, the creation is retried every %s",
+		cephv1.GetRadosNamespaceName(radosNamespace), radosNamespace.Spec.BlockPoolName, waitForRequeueIfClusterFull.RequeueAfter)
+	log.Warningf("%s. %v", message, err)
+	r.recorder.Event(radosNamespace, v1.EventTypeWarning, clusterFullEventReason, message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionFailure, log, cephv1.Condition{
+		Type:    cephv1.ConditionFailure,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.ClusterFullReason,
+		Message: message,
+	})
+	return waitForRequeueIfClusterFull
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDetectClusterFull(t *testing.T) {
+	assert.NoError(t, detectClusterFull(nil))
+
+	var fullErr *ClusterFullError
+	err := detectClusterFull(errors.New("failed to create rados namespace replicapool/namespace-a. rbd: failed to create namespace: (1) Operation not permitted"))
+	assert.False(t, errors.As(err, &fullErr))
+
+	cliErr := errors.New("failed to create rados namespace replicapool/namespace-a. rbd: failed to create namespace: (28) No space left on device")
+	err = errors.Wrap(detectClusterFull(cliErr), "failed to create ceph blockpool rados namespace")
+	assert.True(t, errors.As(err, &fullErr))
+	// the original message is kept for the logs
+	assert.Contains(t, err.Error(), "No space left on device")
+	// a full cluster is not resolved by a quick retry
+	assert.False(t, isTransientCephError(err))
+}
+
+func TestReconcileClusterFull(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	full := true
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" && full {
+			return "rbd: failed to create namespace: (28) No space left on device", errors.New("exit status 28")
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	createTestCSIConfigMap(t, r, namespace)
+	cl := r.client
+	recorder := r.recorder.(*record.FakeRecorder)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+	t.Run("full cluster is reported with a long backoff", func(t *testing.T) {
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, res.Requeue)
+		assert.Equal(t, 5*time.Minute, res.RequeueAfter)
+		assert.Contains(t, recordedEventReasons(recorder), clusterFullEventReason)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionFailure, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionFailure)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.ClusterFullReason, condition.Reason)
+		assert.Contains(t, condition.Message, "Free space")
+	})
+
+	t.Run("the rados namespace is created once space is freed", func(t *testing.T) {
+		full = false
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionFailure)
+		if condition != nil {
+			assert.Equal(t, v1.ConditionFalse, condition.Status)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// checkClusterIDConflict returns an error if the cluster ID set in the spec is already the cluster ID of the csi
+// config of another rados namespace. The csi config is shared by all the clusters of the operator, so the rados
+// namespaces of all the namespaces are checked. Several CRs of the same rados namespace share the same csi config
+// and do not conflict.
+func (r *ReconcileCephBlockPoolRadosNamespace) checkClusterIDConflict(radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	if radosNamespace.Spec.ClusterID == "" {
+		return nil
+	}
+
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	err := r.client.List(r.opManagerContext, radosNamespaces, client.MatchingFields{clusterIDIndex: radosNamespace.Spec.ClusterID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the rados namespaces with cluster ID %q", radosNamespace.Spec.ClusterID)
+	}
+	for i := range radosNamespaces.Items {
+		other := &radosNamespaces.Items[i]
+		if blockPoolNamespace(other) == blockPoolNamespace(radosNamespace) && other.Spec.BlockPoolName == radosNamespace.Spec.BlockPoolName &&
+			cephv1.GetRadosNamespaceName(other) == cephv1.GetRadosNamespaceName(radosNamespace) {
+			continue
+		}
+		return errors.Errorf("cluster ID %q of rados namespace %q is already used by rados namespace %s/%s", radosNamespace.Spec.ClusterID, radosNamespace.Name, other.Namespace, other.Name)
+	}
+	return nil
+}
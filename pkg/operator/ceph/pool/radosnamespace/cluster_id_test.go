@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckClusterIDConflict(t *testing.T) {
+	newRadosNamespace := func(namespace, name, pool, clusterID string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: pool, ClusterID: clusterID},
+		}
+	}
+	newReconciler := func(objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).
+			WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, clusterIDIndex, indexClusterID).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{client: cl, opManagerContext: context.TODO()}
+	}
+
+	t.Run("hashed cluster ID is not checked", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("rook-ceph", "namespace-a", "replicapool", "")
+		// the index is not needed without an override
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+			opManagerContext: context.TODO(),
+		}
+		assert.NoError(t, r.checkClusterIDConflict(radosNamespace))
+	})
+
+	t.Run("unique override", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("rook-ceph", "namespace-a", "replicapool", "tenant-a")
+		r := newReconciler(radosNamespace, newRadosNamespace("rook-ceph", "namespace-b", "replicapool", "tenant-b"))
+		assert.NoError(t, r.checkClusterIDConflict(radosNamespace))
+	})
+
+	t.Run("override used by another rados namespace in another namespace", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("rook-ceph", "namespace-a", "replicapool", "tenant-a")
+		r := newReconciler(radosNamespace, newRadosNamespace("other-cluster", "namespace-b", "replicapool", "tenant-a"))
+		assert.ErrorContains(t, r.checkClusterIDConflict(radosNamespace), `cluster ID "tenant-a" of rados namespace "namespace-a" is already used by rados namespace other-cluster/namespace-b`)
+	})
+
+	t.Run("override equal to the hashed cluster ID of another rados namespace", func(t *testing.T) {
+		other := newRadosNamespace("rook-ceph", "namespace-b", "replicapool", "")
+		radosNamespace := newRadosNamespace("rook-ceph", "namespace-a", "replicapool", buildClusterID(other))
+		r := newReconciler(radosNamespace, other)
+		assert.Error(t, r.checkClusterIDConflict(radosNamespace))
+	})
+
+	t.Run("CRs of the same rados namespace share the cluster ID", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("rook-ceph", "namespace-a", "replicapool", "tenant-a")
+		other := newRadosNamespace("rook-ceph", "namespace-a-copy", "replicapool", "tenant-a")
+		other.Spec.Name = "namespace-a"
+		r := newReconciler(radosNamespace, other)
+		assert.NoError(t, r.checkClusterIDConflict(radosNamespace))
+	})
+}
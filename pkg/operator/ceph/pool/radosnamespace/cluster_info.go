@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// waitForRequeueIfClusterInfoIncomplete is the delay before loading the cluster info again when it misses
+// fields required to reconcile the rados namespace
+var waitForRequeueIfClusterInfoIncomplete = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+// validateClusterInfo checks that the cluster info has the fields required to run ceph commands against the
+// cluster. The ceph version is only required for mirroring, it is loaded and checked separately.
+func validateClusterInfo(clusterInfo *cephclient.ClusterInfo, requireCephVersion bool) error {
+	if clusterInfo == nil {
+		return errors.New("cluster info is not loaded")
+	}
+	var missing []string
+	if clusterInfo.Namespace == "" {
+		missing = append(missing, "namespace")
+	}
+	if clusterInfo.FSID == "" {
+		missing = append(missing, "fsid")
+	}
+	if clusterInfo.CephCred.Username == "" || clusterInfo.CephCred.Secret == "" {
+		missing = append(missing, "ceph credentials")
+	}
+	if requireCephVersion && clusterInfo.CephVersion == (cephver.CephVersion{}) {
+		missing = append(missing, "ceph version")
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("cluster info is missing the %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// waitForClusterInfo reports that the reconcile of the rados namespace waits for the cluster info to be
+// complete and returns the result to requeue the reconcile
+func (r *ReconcileCephBlockPoolRadosNamespace) waitForClusterInfo(cephCluster *cephv1.CephCluster, name types.NamespacedName, validationErr error, log *reconcileLogger) reconcile.Result {
+	message := fmt.Sprintf("waiting for the info of cephcluster %q to be complete: %v", cephCluster.Name, validationErr)
+	log.Info(message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.ClusterInfoIncompleteReason,
+		Message: message,
+	})
+	return waitForRequeueIfClusterInfoIncomplete
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestValidateClusterInfo(t *testing.T) {
+	newClusterInfo := func() *cephclient.ClusterInfo {
+		return &cephclient.ClusterInfo{
+			Namespace:   "rook-ceph",
+			FSID:        "c47cac40-9bee-4d52-823b-ccd803ba5bfe",
+			CephCred:    cephclient.CephCred{Username: "client.admin", Secret: "adminsecret"},
+			CephVersion: cephver.Squid,
+		}
+	}
+
+	assert.NoError(t, validateClusterInfo(newClusterInfo(), true))
+	assert.ErrorContains(t, validateClusterInfo(nil, false), "not loaded")
+
+	clusterInfo := newClusterInfo()
+	clusterInfo.FSID = ""
+	assert.ErrorContains(t, validateClusterInfo(clusterInfo, false), "missing the fsid")
+
+	clusterInfo = newClusterInfo()
+	clusterInfo.CephCred.Secret = ""
+	assert.ErrorContains(t, validateClusterInfo(clusterInfo, false), "missing the ceph credentials")
+
+	// the ceph version is only required for mirroring
+	clusterInfo = newClusterInfo()
+	clusterInfo.CephVersion = cephver.CephVersion{}
+	assert.NoError(t, validateClusterInfo(clusterInfo, false))
+	assert.ErrorContains(t, validateClusterInfo(clusterInfo, true), "missing the ceph version")
+
+	clusterInfo = &cephclient.ClusterInfo{}
+	assert.ErrorContains(t, validateClusterInfo(clusterInfo, true), "missing the namespace, fsid, ceph credentials, ceph version")
+}
+
+func TestReconcileWithIncompleteClusterInfo(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	name := types.NamespacedName{Name: "namespace-a", Namespace: namespace}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	var cephCommands int
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		cephCommands++
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace))
+	cl := r.client
+
+	// the mon secret has no fsid
+	secrets := r.context.Clientset.CoreV1().Secrets(namespace)
+	secret, err := secrets.Get(ctx, "rook-ceph-mon", metav1.GetOptions{})
+	assert.NoError(t, err)
+	delete(secret.Data, "fsid")
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+	assert.NoError(t, err)
+	assert.Equal(t, waitForRequeueIfClusterInfoIncomplete, res)
+	// no ceph command runs with the incomplete cluster info
+	assert.Zero(t, cephCommands)
+
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+	condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+	assert.NotNil(t, condition)
+	assert.Equal(t, cephv1.ClusterInfoIncompleteReason, condition.Reason)
+	assert.Contains(t, condition.Message, "missing the fsid")
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// waitForRequeueIfPoolNotReady retries the reconcile shortly since the CephBlockPool CR exists and should be ready
+// in a matter of seconds
+var waitForRequeueIfPoolNotReady = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+func isWaitingForCephCluster(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	if radosNamespace.Status == nil {
+		return false
+	}
+	condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionProgressing)
+	return condition != nil && condition.Status == v1.ConditionTrue && condition.Reason == cephv1.WaitingForCephClusterReason
+}
+
+// waitForCephCluster reports that the reconcile of the rados namespace waits for the CephCluster to be ready.
+// The event is only emitted when the rados namespace starts waiting, not on every requeue.
+func (r *ReconcileCephBlockPoolRadosNamespace) waitForCephCluster(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, cephClusterExists bool, log *reconcileLogger) {
+	if isWaitingForCephCluster(radosNamespace) {
+		return
+	}
+	message := "waiting for the CephCluster to be ready"
+	if !cephClusterExists {
+		message = "waiting for a CephCluster to be created in the namespace"
+	}
+	log.Info(message)
+	r.recorder.Event(radosNamespace, v1.EventTypeNormal, string(cephv1.WaitingForCephClusterReason), message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.WaitingForCephClusterReason,
+		Message: message,
+	})
+}
+
+// clearWaitingForCephCluster resets the condition set while waiting for the CephCluster once it is ready
+func (r *ReconcileCephBlockPoolRadosNamespace) clearWaitingForCephCluster(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) {
+	if !isWaitingForCephCluster(radosNamespace) {
+		return
+	}
+	log.Infof("CephCluster is ready, resuming reconcile of rados namespace %q", name)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.ReconcileStarted,
+		Message: "the CephCluster is ready",
+	})
+}
+
+// waitForBlockPool reports that the reconcile of the rados namespace waits for its CephBlockPool to be ready. The
+// wait is not a failure of the reconcile, so no error is returned and the reconcile is requeued instead. The
+// standard conditions reset the Progressing condition once the rados namespace leaves the Progressing phase.
+func (r *ReconcileCephBlockPoolRadosNamespace) waitForBlockPool(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, err *PoolNotReadyError, log *reconcileLogger) reconcile.Result {
+	log.Infof("waiting for the block pool of rados namespace %q. %v", name, err)
+	if radosNamespace.Status != nil {
+		condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionProgressing)
+		if condition != nil && condition.Status == v1.ConditionTrue && condition.Reason == cephv1.WaitingForBlockPoolReason && condition.Message == err.Error() {
+			return waitForRequeueIfPoolNotReady
+		}
+	}
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.WaitingForBlockPoolReason,
+		Message: err.Error(),
+	})
+	return waitForRequeueIfPoolNotReady
+}
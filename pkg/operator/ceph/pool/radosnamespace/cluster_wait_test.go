@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWaitForCephCluster(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	recorder := record.NewFakeRecorder(5)
+	r := &ReconcileCephBlockPoolRadosNamespace{client: cl, recorder: recorder, opManagerContext: ctx}
+
+	// the cluster is not ready, the condition is set and an event is emitted
+	r.waitForCephCluster(radosNamespace, name, true, log)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+	assert.True(t, isWaitingForCephCluster(current))
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, string(cephv1.WaitingForCephClusterReason))
+	assert.Contains(t, event, "waiting for the CephCluster to be ready")
+
+	// the requeues while waiting do not emit more events
+	r.waitForCephCluster(current, name, true, log)
+	assert.Len(t, recorder.Events, 0)
+
+	// the condition is cleared once the cluster is ready
+	r.clearWaitingForCephCluster(current, name, log)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.False(t, isWaitingForCephCluster(current))
+	condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.ReconcileStarted, condition.Reason)
+}
+
+func TestWaitForMissingCephCluster(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	recorder := record.NewFakeRecorder(5)
+	r := &ReconcileCephBlockPoolRadosNamespace{client: cl, recorder: recorder, opManagerContext: context.TODO()}
+
+	r.waitForCephCluster(radosNamespace, name, false, newReconcileLogger(name))
+	event := <-recorder.Events
+	assert.Contains(t, event, "waiting for a CephCluster to be created")
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// compressionInfoKey is the status info key recording the compression hint applied to the rados namespace, so
+// that the hint is only checked in ceph when it is set and is removed once spec.compression is removed
+const compressionInfoKey = "compression"
+
+// compressionSettings returns the rbd config options of the compression hint of the rados namespace to set in ceph
+func compressionSettings(compression *cephv1.RadosNamespaceCompression) map[string]string {
+	settings := map[string]string{}
+	if compression != nil && compression.Hint != "" {
+		settings[cephclient.RadosNamespaceCompressionHintKey] = compression.Hint
+	}
+	return settings
+}
+
+// compressionInfo returns the compression hint recorded in the status info, e.g. "rbd_compression_hint=compressible"
+func compressionInfo(compression *cephv1.RadosNamespaceCompression) string {
+	settings := compressionSettings(compression)
+	if hint, ok := settings[cephclient.RadosNamespaceCompressionHintKey]; ok {
+		return cephclient.RadosNamespaceCompressionHintKey + "=" + hint
+	}
+	return ""
+}
+
+// reconcileCompression sets the compression hint of the rados namespace that overrides the hint of the pool, and
+// removes the hint once it is no longer desired
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileCompression(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) error {
+	info := compressionInfo(radosNamespace.Spec.Compression)
+	recorded := ""
+	if radosNamespace.Status != nil {
+		recorded = radosNamespace.Status.Info[compressionInfoKey]
+	}
+	if info == "" && recorded == "" {
+		return nil
+	}
+
+	err := log.timeCephCall("set compression hint", func() error {
+		return cephclient.SetRadosNamespaceCompression(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace), compressionSettings(radosNamespace.Spec.Compression))
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the compression hint of rados namespace %q", name)
+	}
+	if info != recorded {
+		log.Infof("compression hint of rados namespace %q set to %q", name, info)
+	}
+	r.recordMirroringInfo(name, compressionInfoKey, info, log)
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCompressionInfo(t *testing.T) {
+	assert.Equal(t, "", compressionInfo(nil))
+	assert.Equal(t, "", compressionInfo(&cephv1.RadosNamespaceCompression{}))
+	assert.Equal(t, "rbd_compression_hint=compressible", compressionInfo(&cephv1.RadosNamespaceCompression{Hint: "compressible"}))
+}
+
+func TestReconcileCompression(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+
+	config := map[string]string{}
+	var cmds []string
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "config" && args[1] == "namespace" {
+						assert.Equal(t, "replicapool/namespace-a", args[3])
+						switch args[2] {
+						case "list":
+							options := []map[string]string{}
+							for key, value := range config {
+								options = append(options, map[string]string{"name": key, "value": value, "source": "namespace"})
+							}
+							output, err := json.Marshal(options)
+							return string(output), err
+						case "set":
+							cmds = append(cmds, strings.Join(args[2:6], " "))
+							config[args[4]] = args[5]
+						case "remove":
+							cmds = append(cmds, strings.Join(args[2:5], " "))
+							delete(config, args[4])
+						}
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		opManagerContext: ctx,
+	}
+	reconcileCompression := func(compression *cephv1.RadosNamespaceCompression) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, current))
+		current.Spec.Compression = compression
+		cmds = nil
+		assert.NoError(t, r.reconcileCompression(current, name, newReconcileLogger(name)))
+		assert.NoError(t, cl.Get(ctx, name, current))
+		return current
+	}
+
+	t.Run("no compression settings", func(t *testing.T) {
+		reconcileCompression(nil)
+		assert.Nil(t, cmds)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		current := reconcileCompression(&cephv1.RadosNamespaceCompression{Hint: "compressible"})
+		assert.Equal(t, []string{"set replicapool/namespace-a rbd_compression_hint compressible"}, cmds)
+		assert.Equal(t, "rbd_compression_hint=compressible", current.Status.Info[compressionInfoKey])
+	})
+
+	t.Run("update", func(t *testing.T) {
+		current := reconcileCompression(&cephv1.RadosNamespaceCompression{Hint: "incompressible"})
+		assert.Equal(t, []string{"set replicapool/namespace-a rbd_compression_hint incompressible"}, cmds)
+		assert.Equal(t, "rbd_compression_hint=incompressible", current.Status.Info[compressionInfoKey])
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		current := reconcileCompression(nil)
+		assert.Equal(t, []string{"remove replicapool/namespace-a rbd_compression_hint"}, cmds)
+		assert.NotContains(t, current.Status.Info, compressionInfoKey)
+		assert.Empty(t, config)
+
+		reconcileCompression(nil)
+		assert.Nil(t, cmds)
+	})
+}
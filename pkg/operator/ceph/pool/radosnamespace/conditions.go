@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// phaseReason returns the reason of the standard conditions when the rados namespace reaches the phase
+func phaseReason(phase cephv1.ConditionType) cephv1.ConditionReason {
+	switch phase {
+	case cephv1.ConditionReady:
+		return cephv1.ReconcileSucceeded
+	case cephv1.ConditionFailure:
+		return cephv1.ReconcileFailed
+	case cephv1.ConditionDeleting:
+		return cephv1.DeletingReason
+	default:
+		return cephv1.ReconcileStarted
+	}
+}
+
+// standardConditions returns the Ready, Progressing and Mirroring conditions of the rados namespace reaching
+// the phase. They are set before the conditions passed to updateStatus, so that the specific reasons of the
+// callers take precedence. The Progressing condition is only set on a transition, so that the reason of an
+// ongoing wait is kept while the rados namespace stays in the Progressing phase.
+func standardConditions(radosNamespace *cephv1.CephBlockPoolRadosNamespace, phase cephv1.ConditionType) []cephv1.Condition {
+	reason := phaseReason(phase)
+	ready := cephv1.Condition{
+		Type:    cephv1.ConditionReady,
+		Status:  v1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf("rados namespace is in the %q phase", phase),
+	}
+	if phase == cephv1.ConditionReady {
+		ready.Status = v1.ConditionTrue
+		ready.Message = "rados namespace is ready"
+	}
+	conditions := []cephv1.Condition{ready}
+
+	var progressing *cephv1.Condition
+	if radosNamespace.Status != nil {
+		progressing = cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionProgressing)
+	}
+	progressingNow := progressing != nil && progressing.Status == v1.ConditionTrue
+	if phase == cephv1.ConditionProgressing && !progressingNow {
+		conditions = append(conditions, cephv1.Condition{
+			Type:    cephv1.ConditionProgressing,
+			Status:  v1.ConditionTrue,
+			Reason:  reason,
+			Message: "reconcile is in progress",
+		})
+	} else if phase != cephv1.ConditionProgressing && progressingNow {
+		conditions = append(conditions, cephv1.Condition{
+			Type:    cephv1.ConditionProgressing,
+			Status:  v1.ConditionFalse,
+			Reason:  reason,
+			Message: fmt.Sprintf("rados namespace is in the %q phase", phase),
+		})
+	}
+
+	return append(conditions, mirroringCondition(radosNamespace))
+}
+
+// mirroringCondition returns whether the mirroring of the spec is enabled on the rados namespace
+func mirroringCondition(radosNamespace *cephv1.CephBlockPoolRadosNamespace) cephv1.Condition {
+	mirroring := radosNamespace.Spec.Mirroring
+	if mirroring == nil {
+		return cephv1.Condition{
+			Type:    cephv1.ConditionMirroring,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.MirroringDisabledReason,
+			Message: "mirroring is not configured",
+		}
+	}
+	if !isMirroringRecorded(radosNamespace) {
+		return cephv1.Condition{
+			Type:    cephv1.ConditionMirroring,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.MirroringDisabledReason,
+			Message: fmt.Sprintf("mirroring in %q mode is not enabled yet", mirroring.Mode),
+		}
+	}
+	return cephv1.Condition{
+		Type:    cephv1.ConditionMirroring,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.MirroringEnabledReason,
+		Message: fmt.Sprintf("mirroring is enabled in %q mode", mirroring.Mode),
+	}
+}
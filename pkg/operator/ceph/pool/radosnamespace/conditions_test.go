@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpdateStatusConditions(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+		opManagerContext: ctx,
+	}
+	getCurrent := func(t *testing.T) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		return current
+	}
+	// backdate the transition times, so that a new transition is detected despite the second precision of
+	// the serialized times
+	past := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	backdate := func(t *testing.T) {
+		current := getCurrent(t)
+		for i := range current.Status.Conditions {
+			current.Status.Conditions[i].LastTransitionTime = past
+		}
+		assert.NoError(t, r.client.Update(ctx, current))
+	}
+	find := func(t *testing.T, conditionType cephv1.ConditionType) *cephv1.Condition {
+		condition := cephv1.FindStatusCondition(getCurrent(t).Status.Conditions, conditionType)
+		assert.NotNil(t, condition)
+		return condition
+	}
+
+	t.Run("progressing", func(t *testing.T) {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log)
+		current := getCurrent(t)
+		assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+		assert.Len(t, current.Status.Conditions, 3)
+
+		ready := find(t, cephv1.ConditionReady)
+		assert.Equal(t, v1.ConditionFalse, ready.Status)
+		assert.Equal(t, cephv1.ReconcileStarted, ready.Reason)
+		progressing := find(t, cephv1.ConditionProgressing)
+		assert.Equal(t, v1.ConditionTrue, progressing.Status)
+		mirroring := find(t, cephv1.ConditionMirroring)
+		assert.Equal(t, v1.ConditionFalse, mirroring.Status)
+		assert.Equal(t, cephv1.MirroringDisabledReason, mirroring.Reason)
+	})
+
+	t.Run("the reason of an ongoing wait is kept", func(t *testing.T) {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+			Type:   cephv1.ConditionProgressing,
+			Status: v1.ConditionTrue,
+			Reason: cephv1.WaitingForCephClusterReason,
+		})
+		backdate(t)
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log)
+		progressing := find(t, cephv1.ConditionProgressing)
+		assert.Equal(t, cephv1.WaitingForCephClusterReason, progressing.Reason)
+		assert.True(t, progressing.LastTransitionTime.Equal(&past))
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		r.updateStatus(1, name, cephv1.ConditionReady, log)
+		current := getCurrent(t)
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+
+		// the conditions transitioned and their times are updated
+		ready := find(t, cephv1.ConditionReady)
+		assert.Equal(t, v1.ConditionTrue, ready.Status)
+		assert.Equal(t, cephv1.ReconcileSucceeded, ready.Reason)
+		assert.True(t, ready.LastTransitionTime.After(past.Time))
+		progressing := find(t, cephv1.ConditionProgressing)
+		assert.Equal(t, v1.ConditionFalse, progressing.Status)
+		assert.Equal(t, cephv1.ReconcileSucceeded, progressing.Reason)
+		assert.True(t, progressing.LastTransitionTime.After(past.Time))
+
+		// the mirroring condition did not transition and its time is kept
+		mirroring := find(t, cephv1.ConditionMirroring)
+		assert.True(t, mirroring.LastTransitionTime.Equal(&past))
+	})
+
+	t.Run("conditions accumulate with the caller conditions", func(t *testing.T) {
+		backdate(t)
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:   cephv1.ConditionFailure,
+			Status: v1.ConditionTrue,
+			Reason: cephv1.PoolMirroringDisabledReason,
+		})
+		current := getCurrent(t)
+		assert.Len(t, current.Status.Conditions, 4)
+		ready := find(t, cephv1.ConditionReady)
+		assert.Equal(t, v1.ConditionFalse, ready.Status)
+		assert.Equal(t, cephv1.ReconcileFailed, ready.Reason)
+		assert.True(t, ready.LastTransitionTime.After(past.Time))
+		// progressing was already false
+		progressing := find(t, cephv1.ConditionProgressing)
+		assert.Equal(t, cephv1.ReconcileSucceeded, progressing.Reason)
+		assert.True(t, progressing.LastTransitionTime.Equal(&past))
+		failure := find(t, cephv1.ConditionFailure)
+		assert.Equal(t, cephv1.PoolMirroringDisabledReason, failure.Reason)
+	})
+}
+
+func TestMirroringCondition(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	condition := mirroringCondition(radosNamespace)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, "mirroring is not configured", condition.Message)
+
+	radosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage}
+	condition = mirroringCondition(radosNamespace)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, cephv1.MirroringDisabledReason, condition.Reason)
+	assert.Contains(t, condition.Message, "not enabled yet")
+
+	radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{mirroringEnabledInfoKey: "1"}}
+	condition = mirroringCondition(radosNamespace)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.MirroringEnabledReason, condition.Reason)
+	assert.Equal(t, `mirroring is enabled in "image" mode`, condition.Message)
+}
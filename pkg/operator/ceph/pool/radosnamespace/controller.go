@@ -21,7 +21,10 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	csiopv1a1 "github.com/ceph/ceph-csi-operator/api/v1alpha1"
@@ -36,28 +39,66 @@ import (
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
-
-	cephcsi "github.com/ceph/ceph-csi/api/deploy/kubernetes"
 )
 
 const (
-	controllerName   = "blockpool-rados-namespace-controller"
-	cephRNSNameIndex = "blockPoolName/radosNamespaceName"
+	controllerName     = "blockpool-rados-namespace-controller"
+	cephRNSNameIndex   = "blockPoolNamespace/blockPoolName/radosNamespaceName"
+	blockPoolNameIndex = "blockPoolNamespace/blockPoolName"
+	clusterIDIndex     = "clusterID"
 )
 
 var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
 
+// States of the clean up job reported in the status info
+const (
+	cleanupJobInfoKey   = "cleanupJob"
+	cleanupJobRunning   = "Running"
+	cleanupJobSucceeded = "Succeeded"
+	cleanupJobFailed    = "Failed"
+)
+
+// cleanupJobNameInfoKey records in the status info the name of the clean up job launched for the rados namespace,
+// which must complete before the finalizer is removed
+const cleanupJobNameInfoKey = "cleanupJobName"
+
+// waitForRequeueIfPoolMirroringDisabled waits for mirroring to be enabled on the parent CephBlockPool
+var waitForRequeueIfPoolMirroringDisabled = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+
+// waitForRequeueIfImageMirroringInProgress continues to enable or disable the mirroring of the images of the
+// rados namespace in batches
+var waitForRequeueIfImageMirroringInProgress = reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second}
+
+// csiConfigRetry is the backoff to update the csi config map on conflicts, with a large jitter so that
+// concurrent reconciles do not retry at the same time
+var csiConfigRetry = wait.Backoff{
+	Steps:    5,
+	Duration: 50 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   1.0,
+}
+
+// csiConfigBatchWindow is the window in which the csi config updates of the reconciles are coalesced into a
+// single update of the csi config map
+var csiConfigBatchWindow = 50 * time.Millisecond
+
 var poolNamespace = reflect.TypeOf(cephv1.CephBlockPoolRadosNamespace{}).Name()
 
 // Sets the type meta for the controller main object
@@ -76,33 +117,71 @@ type ReconcileCephBlockPoolRadosNamespace struct {
 	opManagerContext       context.Context
 	recorder               record.EventRecorder
 	opConfig               opcontroller.OperatorConfig
+	cephVersions           cephVersionTracker
+	mirroringTargets       mirroringTargetTracker
+	reconcileRates         reconcileRateLimiter
+	mirroringInfo          mirroringInfoCache
+	statusBatcher          statusBatcher
+	fingerprints           reconcileFingerprintTracker
+	milestones             milestoneTracker
+	clock                  clock.PassiveClock
+	// mirrorImageStatuses caches the parsed statuses of the mirrored images, all the images are parsed if not set
+	mirrorImageStatuses *cephclient.MirrorImageStatusCache
+	// csiConfigBatcher coalesces the csi config updates, the updates are saved directly if not set
+	csiConfigBatcher *csi.ClusterConfigBatcher
+	// summaries are the entries last written to the summary config map
+	summaries map[string]string
+	// lastMirrorCheckersLeakCheck is the last time the mirroring checkers were checked for leaks
+	lastMirrorCheckersLeakCheck time.Time
+	// radosNamespaceContextsLock guards radosNamespaceContexts, which is shared by the reconciles of all the
+	// rados namespaces and the operator shutdown
+	radosNamespaceContextsLock sync.Mutex
 }
 
 type mirrorHealth struct {
 	internalCtx    context.Context
 	internalCancel context.CancelFunc
 	started        bool
+	// done is closed when the mirroring checker go routine exits
+	done chan struct{}
+	// lastHeartbeat is the time of the last check of the mirroring checker
+	lastHeartbeat time.Time
+	// interval is the interval between two checks of the mirroring checker
+	interval time.Duration
 }
 
 // Add creates a new CephBlockPoolRadosNamespace Controller and adds it to the
 // Manager. The Manager will set fields on the Controller and Start it when the
 // Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	if err := mgr.GetFieldIndexer().IndexField(opManagerContext, &cephv1.CephBlockPoolRadosNamespace{}, cephRNSNameIndex, func(obj client.Object) []string {
-		rns, ok := obj.(*cephv1.CephBlockPoolRadosNamespace)
-		if !ok {
-			return nil
-		}
-
-		return []string{fmt.Sprintf("%s/%s", rns.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(rns))}
-	}); err != nil {
+	// The CRs are left untouched, they are reconciled again once the controller is enabled
+	if isControllerDisabled() {
+		logger.Infof("the rados namespace controller is disabled by %q", controllerDisabledSettingName)
+		return nil
+	}
+	if err := mgr.GetFieldIndexer().IndexField(opManagerContext, &cephv1.CephBlockPoolRadosNamespace{}, cephRNSNameIndex, indexRadosNamespaceName); err != nil {
 		return fmt.Errorf("failed to index CephRadosNamespaceName by %s: %v", cephRNSNameIndex, err)
 	}
-	return add(mgr, newReconciler(mgr, context, opManagerContext, opConfig))
+	if err := mgr.GetFieldIndexer().IndexField(opManagerContext, &cephv1.CephBlockPoolRadosNamespace{}, blockPoolNameIndex, indexBlockPoolName); err != nil {
+		return fmt.Errorf("failed to index CephBlockPoolRadosNamespace by %s: %v", blockPoolNameIndex, err)
+	}
+	if err := mgr.GetFieldIndexer().IndexField(opManagerContext, &cephv1.CephBlockPoolRadosNamespace{}, clusterIDIndex, indexClusterID); err != nil {
+		return fmt.Errorf("failed to index CephBlockPoolRadosNamespace by %s: %v", clusterIDIndex, err)
+	}
+	r := newReconciler(mgr, context, opManagerContext, opConfig)
+	// Stop the mirroring checkers when the manager stops
+	if err := mgr.Add(manager.RunnableFunc(r.stopMirrorMonitoringOnShutdown)); err != nil {
+		return fmt.Errorf("failed to add the mirroring checkers shutdown to the manager: %v", err)
+	}
+	// Sweep the csi config entries of the deleted rados namespaces at startup and then periodically
+	if err := mgr.Add(manager.RunnableFunc(r.sweepOrphanedClusterConfigs)); err != nil {
+		return fmt.Errorf("failed to add the sweep of the orphaned csi config entries to the manager: %v", err)
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) *ReconcileCephBlockPoolRadosNamespace {
 	return &ReconcileCephBlockPoolRadosNamespace{
 		client:                 mgr.GetClient(),
 		scheme:                 mgr.GetScheme(),
@@ -111,9 +190,43 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		opManagerContext:       opManagerContext,
 		recorder:               mgr.GetEventRecorderFor("rook-" + controllerName),
 		opConfig:               opConfig,
+		clock:                  clock.RealClock{},
+		csiConfigBatcher:       csi.NewClusterConfigBatcher(csiConfigBatchWindow),
+		mirrorImageStatuses:    cephclient.NewMirrorImageStatusCache(cephclient.DefaultMirrorImageStatusCacheSize),
 	}
 }
 
+// indexRadosNamespaceName indexes the cephBlockPoolRadosNamespace CRs by the namespace and name of their
+// CephBlockPool and rados namespace name
+func indexRadosNamespaceName(obj client.Object) []string {
+	rns, ok := obj.(*cephv1.CephBlockPoolRadosNamespace)
+	if !ok {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s/%s", blockPoolKey(blockPoolNamespace(rns), rns.Spec.BlockPoolName), cephv1.GetRadosNamespaceName(rns))}
+}
+
+// indexBlockPoolName indexes the cephBlockPoolRadosNamespace CRs by the namespace and name of their CephBlockPool
+func indexBlockPoolName(obj client.Object) []string {
+	rns, ok := obj.(*cephv1.CephBlockPoolRadosNamespace)
+	if !ok {
+		return nil
+	}
+
+	return []string{blockPoolKey(blockPoolNamespace(rns), rns.Spec.BlockPoolName)}
+}
+
+// indexClusterID indexes the cephBlockPoolRadosNamespace CRs by the cluster ID of their csi config
+func indexClusterID(obj client.Object) []string {
+	rns, ok := obj.(*cephv1.CephBlockPoolRadosNamespace)
+	if !ok {
+		return nil
+	}
+
+	return []string{buildClusterID(rns)}
+}
+
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
 	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
@@ -128,7 +241,68 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 			mgr.GetCache(),
 			&cephv1.CephBlockPoolRadosNamespace{TypeMeta: controllerTypeMeta},
 			&handler.TypedEnqueueRequestForObject[*cephv1.CephBlockPoolRadosNamespace]{},
-			opcontroller.WatchControllerPredicate[*cephv1.CephBlockPoolRadosNamespace](mgr.GetScheme()),
+			predicate.Or(
+				opcontroller.WatchControllerPredicate[*cephv1.CephBlockPoolRadosNamespace](mgr.GetScheme()),
+				pausedAnnotationChangedPredicate(),
+				annotationsChangedPredicate(fingerprintAnnotations...),
+				clientProfileMetadataChangedPredicate(),
+			),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes on the parent CephBlockPool to reconcile the rados namespaces created in it, so that
+	// the rados namespaces waiting for the pool to be ready or for its mirroring to be enabled are not delayed
+	err = c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephBlockPool{},
+			handler.TypedEnqueueRequestsFromMapFunc(
+				func(ctx context.Context, cephBlockPool *cephv1.CephBlockPool) []reconcile.Request {
+					return radosNamespacesForPool(ctx, mgr.GetClient(), cephBlockPool)
+				},
+			),
+			predicate.Or(
+				opcontroller.WatchControllerPredicate[*cephv1.CephBlockPool](mgr.GetScheme()),
+				poolPhaseChangedPredicate(),
+			),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for msgr2 requirement changes on the CephCluster to refresh the mon endpoints in the csi config
+	// entries of the rados namespaces
+	err = c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&cephv1.CephCluster{},
+			handler.TypedEnqueueRequestsFromMapFunc(
+				func(ctx context.Context, cephCluster *cephv1.CephCluster) []reconcile.Request {
+					return radosNamespacesForCluster(ctx, mgr.GetClient(), cephCluster)
+				},
+			),
+			msgr2RequirementChangedPredicate(),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for the completion of the clean up jobs to resume the deletion of the rados namespaces
+	err = c.Watch(
+		source.Kind(
+			mgr.GetCache(),
+			&batch.Job{},
+			handler.TypedEnqueueRequestsFromMapFunc(
+				func(ctx context.Context, job *batch.Job) []reconcile.Request {
+					return radosNamespacesForCleanupJob(ctx, mgr.GetClient(), job)
+				},
+			),
+			cleanupJobFinishedPredicate(),
 		),
 	)
 	if err != nil {
@@ -150,50 +324,113 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 // otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCephBlockPoolRadosNamespace) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, radosNamespace, err := r.reconcile(request)
+	log := newReconcileLogger(request.NamespacedName)
+	// the status changes of the reconcile are written with a single update
+	r.statusBatcher.begin(request.NamespacedName)
+	reconcileResponse, radosNamespace, err := r.reconcile(request, log)
+	r.flushStatus(request.NamespacedName, log)
+	log.logCephCallsDuration()
+	if isTransientCephError(err) {
+		// do not flood the logs and the events while the error resolves by itself
+		log.Debugf("transient ceph error, retrying the reconcile of %q. %v", request.NamespacedName, err)
+		reconcileResponse, err = waitForRequeueIfTransientError, nil
+	}
 	if err != nil {
-		logger.Errorf("failed to reconcile %q. %v", request.NamespacedName, err)
+		log.Errorf("failed to reconcile %q. %v", request.NamespacedName, err)
+		if dumpErr := r.dumpDiagnostics(request.NamespacedName, err, log); dumpErr != nil {
+			log.Warningf("failed to dump the diagnostics of %q. %v", request.NamespacedName, dumpErr)
+		}
 	}
+	r.checkMirrorCheckersLeak()
+	r.updateSummary(request.NamespacedName, log)
 
-	return reporting.ReportReconcileResult(logger, r.recorder, request, radosNamespace, reconcileResponse, err)
+	return reporting.ReportReconcileResult(log, r.recorder, request, radosNamespace, reconcileResponse, err)
 }
 
-func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Request) (reconcile.Result, *cephv1.CephBlockPoolRadosNamespace, error) {
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Request, log *reconcileLogger) (reconcile.Result, *cephv1.CephBlockPoolRadosNamespace, error) {
 	namespacedName := request.NamespacedName
 	// Fetch the CephBlockPoolRadosNamespace instance
 	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
 	err := r.client.Get(r.opManagerContext, request.NamespacedName, radosNamespace)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			logger.Debugf("cephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", namespacedName)
+			log.Debugf("cephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", namespacedName)
+			forgetDeletionBlocked(namespacedName)
 			return reconcile.Result{}, radosNamespace, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to get cephBlockPoolRadosNamespace")
 	}
+	// update observedGeneration local variable with current generation value,
+	// because generation can be changed before reconcile got completed
+	// CR status will be updated at end of reconcile, so to reflect the reconcile has finished
+	observedGeneration := radosNamespace.ObjectMeta.Generation
+
+	// Do not touch ceph, the csi config or the mirroring while the reconcile is paused
+	if isReconcilePaused(radosNamespace) {
+		r.pauseReconcile(radosNamespace, namespacedName, log)
+		return reconcile.Result{}, radosNamespace, nil
+	}
+	if isPausedCondition(radosNamespace) {
+		log.Infof("resuming reconcile of rados namespace %q", namespacedName)
+		r.clearPausedCondition(namespacedName, log)
+	}
+
+	// Do nothing for the implicit rados namespace CRs if the operator is configured to ignore them
+	if isReconcileIgnored(radosNamespace, log) {
+		result, err := r.ignoreReconcile(radosNamespace, namespacedName, log)
+		return result, radosNamespace, err
+	}
+	if isIgnoredCondition(radosNamespace) {
+		log.Infof("reconciling implicit rados namespace %q that was ignored", namespacedName)
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionProgressing, log, cephv1.Condition{
+			Type:    cephv1.ConditionIgnored,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.ReconcileStarted,
+			Message: "the implicit rados namespace is reconciled",
+		})
+	}
+
+	r.checkStaleness(radosNamespace, namespacedName, log)
+
+	// Never use the CephCluster of another namespace that the rados namespace is not allowed to reference
+	if err := checkBlockPoolNamespace(radosNamespace); err != nil {
+		if !radosNamespace.GetDeletionTimestamp().IsZero() {
+			log.Warningf("removing the finalizer of rados namespace %q without deleting it from ceph. %v", namespacedName, err)
+			if err := r.removeFinalizer(radosNamespace); err != nil {
+				return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to remove finalizer")
+			}
+			return reconcile.Result{}, radosNamespace, nil
+		}
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "invalid rados namespace CR %q spec", radosNamespace.Name)
+	}
 
 	// Set a finalizer so we can do cleanup before the object goes away
-	generationUpdated, err := opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, radosNamespace)
+	generationUpdated, err := r.addFinalizer(radosNamespace)
 	if err != nil {
 		return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to add finalizer")
 	}
 	if generationUpdated {
-		logger.Infof("reconciling the rados namespace %q after adding finalizer", radosNamespace.Name)
+		log.Infof("reconciling the rados namespace %q after adding finalizer", radosNamespace.Name)
 		return reconcile.Result{}, radosNamespace, nil
 	}
 
 	// The CR was just created, initializing status fields
 	if radosNamespace.Status == nil {
-		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionProgressing)
-	}
-
-	poolAndRadosNamespaceName := radosNamespace.Spec.BlockPoolName
-	if rns := cephv1.GetRadosNamespaceName(radosNamespace); rns != "" {
-		poolAndRadosNamespaceName = fmt.Sprintf("%s/%s", radosNamespace.Spec.BlockPoolName, rns)
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionProgressing, log)
 	}
 
-	// Make sure a CephCluster is present otherwise do nothing
-	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, request.NamespacedName, controllerName)
+	// Make sure a CephCluster is present otherwise do nothing. The rados namespace may target one of several
+	// CephClusters of the namespace.
+	// the CephCluster is the one of the namespace of the CephBlockPool
+	clusterNamespacedName := types.NamespacedName{Name: request.Name, Namespace: blockPoolNamespace(radosNamespace)}
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcileCluster(r.opManagerContext, r.client, clusterNamespacedName, radosNamespace.Spec.CephClusterName, controllerName)
 	if !isReadyToReconcile {
 		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
 		// We skip the deleteRadosNamespace() function since everything is gone already
@@ -203,108 +440,210 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
 		if !radosNamespace.GetDeletionTimestamp().IsZero() && !cephClusterExists {
 			// don't leak the health checker routine if we are force-deleting
-			r.cancelMirrorMonitoring(radosNamespaceChannelKeyName(radosNamespace.Namespace, poolAndRadosNamespaceName))
+			r.cancelMirrorMonitoring(mirrorMonitoringChannelKey(radosNamespace))
 			// Remove finalizer
-			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, radosNamespace)
+			err = r.removeFinalizer(radosNamespace)
 			if err != nil {
 				return opcontroller.ImmediateRetryResult, radosNamespace, errors.Wrap(err, "failed to remove finalizer")
 			}
+			forgetDeletionBlocked(namespacedName)
 
 			// Return and do not requeue. Successful deletion.
 			return reconcile.Result{}, radosNamespace, nil
 		}
+		r.waitForCephCluster(radosNamespace, request.NamespacedName, cephClusterExists, log)
 		return reconcileResponse, radosNamespace, nil
 	}
+	r.clearWaitingForCephCluster(radosNamespace, request.NamespacedName, log)
 
 	// Populate clusterInfo during each reconcile
-	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, cephCluster.Namespace, &cephCluster.Spec)
 	if err != nil {
 		return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to populate cluster info")
 	}
 	r.clusterInfo.Context = r.opManagerContext
+	r.clusterInfo.SetName(cephCluster.Name)
+	// A partially loaded cluster info would fail the ceph commands in unexpected ways
+	if err := validateClusterInfo(r.clusterInfo, false); err != nil {
+		return r.waitForClusterInfo(&cephCluster, namespacedName, err, log), radosNamespace, nil
+	}
 
 	// DELETE: the CR was deleted
 	if !radosNamespace.GetDeletionTimestamp().IsZero() {
 		cephRNSList := &cephv1.CephBlockPoolRadosNamespaceList{}
-		namespaceListOpts := client.InNamespace(cephCluster.Namespace)
-		// List cephBlockPoolRadosNamespace CR based on spec.blockPoolName and spec.name
-		matchingKey := fmt.Sprintf("%s/%s", radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
-		err = r.client.List(r.opManagerContext, cephRNSList, &client.MatchingFields{cephRNSNameIndex: matchingKey}, namespaceListOpts)
+		// List cephBlockPoolRadosNamespace CR based on the CephBlockPool and spec.name, in all the namespaces since
+		// the CRs of the pool may be in other namespaces than the pool
+		matchingKey := fmt.Sprintf("%s/%s", blockPoolKey(blockPoolNamespace(radosNamespace), radosNamespace.Spec.BlockPoolName), cephv1.GetRadosNamespaceName(radosNamespace))
+		err = r.client.List(r.opManagerContext, cephRNSList, &client.MatchingFields{cephRNSNameIndex: matchingKey})
 		if err != nil {
 			return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to list cephBlockPoolRadosNamespace")
 		}
 
-		logger.Debugf("delete cephBlockPoolRadosNamespace %q", namespacedName)
-		// On external cluster, we don't delete the rados namespace, it has to be deleted manually
-		if cephCluster.Spec.External.Enable {
-			logger.Warning("external rados namespace %q deletion is not supported, delete it manually", namespacedName)
+		log.Debugf("delete cephBlockPoolRadosNamespace %q", namespacedName)
+		// On external cluster, the rados namespace is neither checked for data nor deleted from ceph unless
+		// the deletion is allowed in the spec, it has to be deleted manually. Only the csi config is cleaned
+		// up before removing the finalizer.
+		if cephCluster.Spec.External.Enable && !radosNamespace.Spec.ExternalAllowDelete {
+			log.Infof("skipping deletion of external rados namespace %q from the ceph cluster, delete it manually if needed", namespacedName)
+		} else if isRetainedOnDeletion(radosNamespace) {
+			r.retainRadosNamespace(radosNamespace, log)
 		} else if len(cephRNSList.Items) <= 1 {
 			// If we have more than one cephBlockPoolRadosNamespace CR with same spec.blockPoolName and same spec.name,
 			// skip the call to deleteRadosNamespace(). This allows the finalizer to be removed without
 			// checking if the radosnamespaceName contains any data. Thus, any extra CRs referencing the same
 			// spec.name and spec.blockPoolName can be easily deleted. Only the last radosNamespace CR referencing the same
 			// blockPoolName would actually check if there is data in the radosNamespace.
-			if containsImages, err := r.deleteRadosNamespace(radosNamespace, &cephCluster); err != nil {
-				if containsImages {
+			// Do not check the images of the rados namespace again until the clean up job completes, the
+			// completion of the job triggers a reconcile
+			running, err := r.isCleanupJobRunning(radosNamespace, log)
+			if err != nil {
+				return reconcile.Result{}, radosNamespace, err
+			}
+			if running {
+				return waitForRequeueIfCleanupJobRunning, radosNamespace, nil
+			}
+			if blocked, err := r.deleteRadosNamespace(radosNamespace, &cephCluster, log); err != nil {
+				if blocked {
 					return opcontroller.WaitForRequeueIfFinalizerBlocked, radosNamespace, err
 				}
-				if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
-					logger.Info(opcontroller.OperatorNotInitializedMessage)
+				var configErr *cephclient.CephConfigNotInitializedError
+				if errors.As(err, &configErr) {
+					log.Info(opcontroller.OperatorNotInitializedMessage)
 					return opcontroller.WaitForRequeueIfOperatorNotInitialized, radosNamespace, nil
 				}
 				return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "failed to delete ceph blockpool rados namespace %q", radosNamespace.Name)
 			}
 			// If the ceph block pool is still in the map, we must remove it during CR deletion
 			// We must remove it first otherwise the checker will panic since the status/info will be nil
-			r.cancelMirrorMonitoring(radosNamespaceChannelKeyName(radosNamespace.Namespace, poolAndRadosNamespaceName))
+			r.cancelMirrorMonitoring(mirrorMonitoringChannelKey(radosNamespace))
 		} else {
-			logger.Infof("Removing finalizer from RNS CR %s without checking if the radosnamespaceName contains any data since more than one RNS(count %d) contains the same blockPool and rados name", radosNamespace.Name, len(cephRNSList.Items))
+			log.Infof("Removing finalizer from RNS CR %s without checking if the radosnamespaceName contains any data since more than one RNS(count %d) contains the same blockPool and rados name", radosNamespace.Name, len(cephRNSList.Items))
+		}
+
+		// Never remove the finalizer while a clean up job launched for the CR has not completed, otherwise the
+		// job would be orphaned
+		blocked, err := r.cleanupJobBlocksFinalizerRemoval(radosNamespace, log)
+		if err != nil {
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, radosNamespace, err
+		}
+		if blocked {
+			return waitForRequeueIfCleanupJobRunning, radosNamespace, nil
 		}
 
-		if len(cephRNSList.Items) <= 1 {
-			err = csi.SaveClusterConfig(r.context.Clientset, buildClusterID(radosNamespace), cephCluster.Namespace, r.clusterInfo, nil)
+		if removesCSIConfigOnDeletion(radosNamespace, len(cephRNSList.Items), log) {
+			err = r.saveClusterConfig(buildClusterID(radosNamespace), cephCluster.Namespace, nil)
 			if err != nil {
 				return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to save cluster config")
 			}
+		} else {
+			log.Infof("keeping the csi config of cluster ID %q of rados namespace %q", buildClusterID(radosNamespace), namespacedName)
 		}
 
 		// Remove finalizer
-		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, radosNamespace)
+		err = r.removeFinalizer(radosNamespace)
 		if err != nil {
 			return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to remove finalizer")
 		}
 
+		r.fingerprints.forget(namespacedName)
+		r.milestones.forget(namespacedName)
+		forgetDeletionBlocked(namespacedName)
+
 		// Return and do not requeue. Successful deletion.
 		return reconcile.Result{}, radosNamespace, nil
 	}
 
+	// Copy the settings of the rados namespace to clone from before the rados namespace is created, and use them
+	// for the settings that are not set in the spec. The CR is not updated past this point, so the cloned
+	// settings are never written to the spec.
+	if err := r.cloneSettings(radosNamespace, log); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: fmt.Sprintf("failed to clone the rados namespace settings: %v", err),
+		})
+		return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to clone the rados namespace settings")
+	}
+	applyClonedSettings(radosNamespace)
+
 	radosNamespaceName := cephv1.GetRadosNamespaceName(radosNamespace)
 
+	// validate the rados namespace settings
+	if err := validateRadosNamespace(radosNamespace); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: fmt.Sprintf("invalid rados namespace spec: %v", err),
+		})
+		return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "invalid rados namespace CR %q spec", radosNamespace.Name)
+	}
+
+	if err := r.checkSnapshotScheduleMinInterval(radosNamespace, log); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: fmt.Sprintf("invalid rados namespace spec: %v", err),
+		})
+		return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "invalid rados namespace CR %q spec", radosNamespace.Name)
+	}
+
+	if err := r.checkMirroringPeerDirections(radosNamespace); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, radosNamespace, err
+	}
+
+	if err := r.checkClusterIDConflict(radosNamespace); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, radosNamespace, err
+	}
+
+	if err := validateExternalMonitorsOverride(radosNamespace, &cephCluster); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.ReconcileFailed,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, radosNamespace, err
+	}
+
 	if cephCluster.Spec.External.Enable {
-		logger.Debug("skip creating external radosnamespace in external mode, create it manually, the controller will assume it's there")
-		err = r.updateClusterConfig(radosNamespace, cephCluster)
+		log.Debug("skip creating external radosnamespace in external mode, create it manually, the controller will assume it's there")
+		_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
 		if err != nil {
 			return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to save cluster config")
 		}
-		r.updateStatus(r.client, namespacedName, cephv1.ConditionReady)
+		r.recordMilestoneEvent(radosNamespace, csiConfigUpdatedEventReason, csiConfigState(radosNamespace), fmt.Sprintf("updated the csi config of cluster ID %q", buildClusterID(radosNamespace)))
+		r.updateStatus(observedGeneration, namespacedName, cephv1.ConditionReady, log)
 		if csi.EnableCSIOperator() {
-			err = csi.CreateUpdateClientProfileRadosNamespace(r.clusterInfo.Context, r.client, r.clusterInfo, radosNamespaceName, buildClusterID(radosNamespace), cephCluster.Name)
-			if err != nil {
-				return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to create ceph csi-op config CR for RadosNamespace")
+			if res, err := r.reconcileClientProfile(radosNamespace, &cephCluster, namespacedName, log); err != nil || !res.IsZero() {
+				return res, radosNamespace, err
 			}
 		}
-		return reconcile.Result{}, radosNamespace, nil
+		return resyncResult(resyncInterval(log)), radosNamespace, nil
 	}
 
 	// cephversion check is only required for enabling mirroring
 	if radosNamespace.Spec.Mirroring != nil {
-		// Get CephCluster version
-		cephVersion, err := opcontroller.GetImageVersion(cephCluster)
+		res, err := r.loadCephVersion(&cephCluster, namespacedName, log)
 		if err != nil {
-			return opcontroller.ImmediateRetryResult, radosNamespace, errors.Wrapf(err, "failed to fetch ceph version from cephcluster %q running in namespace %q", cephCluster.Name, cephCluster.Namespace)
+			return res, radosNamespace, err
 		}
-		if cephVersion != nil {
-			r.clusterInfo.CephVersion = *cephVersion
+		if err := validateClusterInfo(r.clusterInfo, true); err != nil {
+			return r.waitForClusterInfo(&cephCluster, namespacedName, err, log), radosNamespace, nil
 		}
 	}
 
@@ -312,7 +651,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 	// create the rados namespace
 	cephBlockPool := &cephv1.CephBlockPool{}
 	pool := radosNamespace.Spec.BlockPoolName
-	cephBlockPoolNamespacedName := types.NamespacedName{Name: pool, Namespace: request.Namespace}
+	cephBlockPoolNamespacedName := types.NamespacedName{Name: pool, Namespace: blockPoolNamespace(radosNamespace)}
 
 	err = r.client.Get(r.opManagerContext, cephBlockPoolNamespacedName, cephBlockPool)
 	if err != nil {
@@ -324,109 +663,341 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 	}
 
 	// If the cephBlockPool is not ready to accept commands, we should wait for it to be ready
-	if cephBlockPool.Status.Phase != cephv1.ConditionReady {
-		// We know the CR is present so it should a matter of second for it to become ready
-		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, radosNamespace, errors.Wrapf(err, "failed to fetch ceph blockpool %q, cannot create rados namespace %q", pool, radosNamespace.Name)
+	if poolPhase(cephBlockPool) != cephv1.ConditionReady {
+		return r.waitForBlockPool(radosNamespace, namespacedName, &PoolNotReadyError{PoolName: pool, Phase: poolPhase(cephBlockPool)}, log), radosNamespace, nil
+	}
+	r.updatePoolStatusInfo(namespacedName, cephBlockPool, log)
+
+	if err := validatePoolMirroringSupport(radosNamespace.Spec.Mirroring, cephBlockPool); err != nil {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+			Type:    cephv1.ConditionFailure,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.PoolMirroringUnsupportedReason,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "cannot mirror rados namespace %q", radosNamespace.Name)
+	}
+
+	// the image count is refreshed on its own schedule, even when the rest of the reconcile is skipped
+	r.reconcileImageCount(radosNamespace, namespacedName, log)
+
+	// Skip the ceph commands, the csi config and the mirroring if nothing changed since the last
+	// successful reconcile, unless the periodic resync is due
+	resync := resyncInterval(log)
+	fingerprint := newReconcileFingerprint(radosNamespace, &cephCluster, cephBlockPool)
+
+	// the settings changed out-of-band are set again by a full reconcile
+	var drifted []string
+	if isDriftCorrectionEnabled(log) {
+		drifted, err = r.detectDrift(radosNamespace, log)
+		if err != nil {
+			return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "failed to check the drift of rados namespace %q", radosNamespace.Name)
+		}
+		if len(drifted) > 0 {
+			log.Warningf("the %v of rados namespace %q were changed outside of the operator, correcting them", drifted, namespacedName)
+		}
+	}
+	// a dead mirroring checker is only started again by a full reconcile
+	if r.checkMirrorMonitoringLiveness(radosNamespace, log) {
+		r.fingerprints.forget(namespacedName)
+	}
+	if len(drifted) == 0 && r.fingerprints.isUnchanged(namespacedName, radosNamespace, fingerprint) && !r.fingerprints.isOlderThan(namespacedName, resync) {
+		log.Debugf("generation %d of rados namespace %q is already reconciled, skipping", observedGeneration, namespacedName)
+		if staleAfter(log) > 0 {
+			// refresh the time of the last successful reconcile so that the rados namespace is not seen as stale
+			r.updateStatus(observedGeneration, namespacedName, cephv1.ConditionReady, log)
+		}
+		return resyncResult(r.mirrorMonitoringResync(radosNamespace, imageCountResync(resync, imageCountInterval(log)))), radosNamespace, nil
 	}
+	// the ceph commands are skipped when the spec changes do not apply to ceph
+	if len(drifted) == 0 && !r.fingerprints.isOlderThan(namespacedName, resync) && r.isCSIOnlyChange(namespacedName, radosNamespace, fingerprint) {
+		res, err := r.reconcileCSIOnly(radosNamespace, &cephCluster, namespacedName, fingerprint, log)
+		if err != nil || !res.IsZero() {
+			return res, radosNamespace, err
+		}
+		return resyncResult(r.mirrorMonitoringResync(radosNamespace, imageCountResync(resync, imageCountInterval(log)))), radosNamespace, nil
+	}
+	r.fingerprints.forget(namespacedName)
+
+	// a rados namespace whose spec keeps changing would run the ceph commands on each change
+	if limit := maxReconcilesPerMinute(log); limit > 0 {
+		if delay := r.reconcileRates.take(namespacedName, limit, r.now()); delay > 0 {
+			log.Infof("rados namespace %q reached the limit of %d reconciles per minute, delaying the reconcile by %s", namespacedName, limit, delay.String())
+			return reconcile.Result{Requeue: true, RequeueAfter: delay}, radosNamespace, nil
+		}
+	}
+
 	// Create or Update rados namespace
-	err = r.createOrUpdateRadosNamespace(radosNamespace)
+	err = r.createOrUpdateRadosNamespace(radosNamespace, log)
 	if err != nil {
-		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
-			logger.Info(opcontroller.OperatorNotInitializedMessage)
+		var configErr *cephclient.CephConfigNotInitializedError
+		if errors.As(err, &configErr) {
+			log.Info(opcontroller.OperatorNotInitializedMessage)
 			return opcontroller.WaitForRequeueIfOperatorNotInitialized, radosNamespace, nil
 		}
-		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure)
+		var fullErr *ClusterFullError
+		if errors.As(err, &fullErr) {
+			return r.waitForClusterSpace(radosNamespace, namespacedName, fullErr, log), radosNamespace, nil
+		}
+		if !isTransientCephError(err) {
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionFailure, log)
+		}
 		return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "failed to create or update ceph pool rados namespace %q", radosNamespace.Name)
 	}
+	if radosNamespaceName != cephv1.ImplicitNamespaceVal {
+		r.recordMilestoneEvent(radosNamespace, radosNamespaceCreatedEventReason, radosNamespaceName, fmt.Sprintf("created rados namespace %q", getPoolAndRadosNamespaceName(radosNamespace)))
+	}
+
+	if radosNamespaceName != cephv1.ImplicitNamespaceVal {
+		err = log.timeCephCall("set application metadata", func() error {
+			return cephclient.SetRadosNamespaceApplicationMetadata(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, radosNamespaceName, cephApplicationMetadata(radosNamespace))
+		})
+		if err != nil {
+			return reconcile.Result{}, radosNamespace, errors.Wrapf(err, "failed to set application metadata of ceph pool rados namespace %q", radosNamespace.Name)
+		}
+	}
+
+	if radosNamespaceName != cephv1.ImplicitNamespaceVal {
+		err = r.reconcileCompression(radosNamespace, namespacedName, log)
+		if err != nil {
+			return reconcile.Result{}, radosNamespace, err
+		}
+
+		err = r.reconcilePostCreateConfig(radosNamespace, namespacedName, log)
+		if err != nil {
+			return reconcile.Result{}, radosNamespace, err
+		}
+	}
+
+	// the csi config of a rados namespace waiting for healthy mirroring is written once mirroring is reconciled
+	waitForMirrorHealth := waitsForMirrorHealth(radosNamespace)
+	if !waitForMirrorHealth {
+		_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+		if err != nil {
+			return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to save cluster config")
+		}
+		r.recordMilestoneEvent(radosNamespace, csiConfigUpdatedEventReason, csiConfigState(radosNamespace), fmt.Sprintf("updated the csi config of cluster ID %q", buildClusterID(radosNamespace)))
+	}
 
-	err = r.updateClusterConfig(radosNamespace, cephCluster)
+	err = r.reconcileMirroring(radosNamespace, cephBlockPool, log)
 	if err != nil {
-		return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to save cluster config")
+		var mirroringErr *PoolMirroringDisabledError
+		if errors.As(err, &mirroringErr) {
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+				Type:    cephv1.ConditionFailure,
+				Status:  v1.ConditionTrue,
+				Reason:  cephv1.PoolMirroringDisabledReason,
+				Message: fmt.Sprintf("mirroring is disabled on CephBlockPool %q, enable mirroring on the CephBlockPool first to mirror rados namespace %q", cephBlockPool.Name, radosNamespace.Name),
+			})
+			return waitForRequeueIfPoolMirroringDisabled, radosNamespace, err
+		}
+		var drainErr *MirroringDrainInProgressError
+		if errors.As(err, &drainErr) {
+			log.Info(drainErr.Error())
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionProgressing, log)
+			return waitForRequeueIfImageMirroringInProgress, radosNamespace, nil
+		}
+		var modeSwitchErr *MirroringModeSwitchInProgressError
+		if errors.As(err, &modeSwitchErr) {
+			log.Info(modeSwitchErr.Error())
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionProgressing, log)
+			return waitForRequeueIfImageMirroringInProgress, radosNamespace, nil
+		}
+		var filterErr *ImageFilterInProgressError
+		if errors.As(err, &filterErr) {
+			log.Info(filterErr.Error())
+			return waitForRequeueIfImageMirroringInProgress, radosNamespace, nil
+		}
+		var scheduleErr *SnapshotSchedulesError
+		if errors.As(err, &scheduleErr) && !isTransientCephError(err) {
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, namespacedName, cephv1.ConditionFailure, log, cephv1.Condition{
+				Type:    cephv1.ConditionFailure,
+				Status:  v1.ConditionTrue,
+				Reason:  cephv1.SnapshotScheduleFailedReason,
+				Message: fmt.Sprintf("mirroring is enabled but the snapshot schedules could not be set: %v", err),
+			})
+		}
+		return reconcile.Result{}, radosNamespace, err
 	}
+	r.recordMirroringMilestones(radosNamespace)
 
-	err = r.reconcileMirroring(radosNamespace, cephBlockPool)
+	err = r.reconcileBootstrapPeerToken(radosNamespace, namespacedName, log)
 	if err != nil {
 		return reconcile.Result{}, radosNamespace, err
 	}
 
-	r.updateStatus(r.client, namespacedName, cephv1.ConditionReady)
+	err = r.reconcileExportConfig(radosNamespace, namespacedName, log)
+	if err != nil {
+		return reconcile.Result{}, radosNamespace, err
+	}
 
-	if csi.EnableCSIOperator() {
-		err = csi.CreateUpdateClientProfileRadosNamespace(r.clusterInfo.Context, r.client, r.clusterInfo, radosNamespaceName, buildClusterID(radosNamespace), cephCluster.Name)
+	err = r.reconcileMirrorRole(radosNamespace, namespacedName, log)
+	if err != nil {
+		return reconcile.Result{}, radosNamespace, err
+	}
+
+	verifyingMirroring, err := r.reconcileMirrorVerification(radosNamespace, namespacedName, log)
+	if err != nil {
+		return reconcile.Result{}, radosNamespace, err
+	}
+
+	if waitForMirrorHealth {
+		if mirroringHealth(radosNamespace.Status) != "OK" {
+			r.waitForMirrorHealth(radosNamespace, namespacedName, log)
+			return waitForRequeueIfMirrorUnhealthy, radosNamespace, nil
+		}
+		_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
 		if err != nil {
-			return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to create ceph csi-op config CR for RadosNamespace")
+			return reconcile.Result{}, radosNamespace, errors.Wrap(err, "failed to save cluster config")
 		}
+		r.recordMilestoneEvent(radosNamespace, csiConfigUpdatedEventReason, csiConfigState(radosNamespace), fmt.Sprintf("updated the csi config of cluster ID %q", buildClusterID(radosNamespace)))
 	}
 
-	// Return and do not requeue
-	logger.Debugf("done reconciling cephBlockPoolRadosNamespace %q", namespacedName)
-	return reconcile.Result{}, radosNamespace, nil
-}
+	// the rados namespace is not ready until its mirroring reaches the target health, the fingerprint is not
+	// recorded so that the next reconcile checks the mirroring health again
+	if !mirroringTargetReached(radosNamespace) {
+		return r.waitForMirroringTarget(radosNamespace, namespacedName, log), radosNamespace, nil
+	}
+	r.mirroringTargets.forget(namespacedName)
+
+	conditions := append(resolvedSnapshotScheduleConditions(radosNamespace), resolvedMirrorHealthConditions(radosNamespace)...)
+	conditions = append(conditions, resolvedMirroringTargetConditions(radosNamespace)...)
+	mirrorDaemonConditions, err := r.mirrorDaemonConditions(radosNamespace, log)
+	if err != nil {
+		return reconcile.Result{}, radosNamespace, err
+	}
+	conditions = append(conditions, poolDefaultConditions(radosNamespace, log)...)
+	r.updateStatus(observedGeneration, namespacedName, cephv1.ConditionReady, log, append(conditions, mirrorDaemonConditions...)...)
+
+	if csi.EnableCSIOperator() {
+		// the fingerprint is not recorded so that the client profile is created once the csi operator is ready
+		if res, err := r.reconcileClientProfile(radosNamespace, &cephCluster, namespacedName, log); err != nil || !res.IsZero() {
+			return res, radosNamespace, err
+		}
+	}
+
+	if verifyingMirroring {
+		// do not record the fingerprint so that the next reconcile checks whether the marker image is replicated
+		return waitForRequeueIfMirrorVerificationInProgress, radosNamespace, nil
+	}
 
-func (r *ReconcileCephBlockPoolRadosNamespace) updateClusterConfig(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster cephv1.CephCluster) error {
-	// Update CSI config map
-	// If the mon endpoints change, the mon health check go routine will take care of updating the
-	// config map, so no special care is needed in this controller
-	csiClusterConfigEntry := csi.CSIClusterConfigEntry{
-		Namespace: r.clusterInfo.Namespace,
-		ClusterInfo: cephcsi.ClusterInfo{
-			Monitors: csi.MonEndpoints(r.clusterInfo.AllMonitors(), cephCluster.Spec.RequireMsgr2()),
-			RBD: cephcsi.RBD{
-				RadosNamespace: cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace),
-			},
-			CephFS: cephcsi.CephFS{
-				KernelMountOptions: r.clusterInfo.CSIDriverSpec.CephFS.KernelMountOptions,
-				FuseMountOptions:   r.clusterInfo.CSIDriverSpec.CephFS.FuseMountOptions,
-			},
-			ReadAffinity: cephcsi.ReadAffinity{
-				Enabled:             r.clusterInfo.CSIDriverSpec.ReadAffinity.Enabled,
-				CrushLocationLabels: r.clusterInfo.CSIDriverSpec.ReadAffinity.CrushLocationLabels,
-			},
-		},
+	if len(mirrorDaemonConditions) > 0 {
+		// do not record the fingerprint so the condition is cleared once an rbd-mirror daemon is deployed
+		return waitForRequeueIfMirrorDaemonMissing, radosNamespace, nil
 	}
 
-	csiClusterConfigEntry.RBD.NetNamespaceFilePath = ""
+	r.fingerprints.record(namespacedName, fingerprint)
+	if err := r.recordCephSettingsChecksum(namespacedName, radosNamespace); err != nil {
+		// the next change of the spec runs the ceph commands
+		log.Warningf("failed to record the ceph settings applied to rados namespace %q. %v", namespacedName, err)
+	}
+	if len(drifted) > 0 {
+		r.recordDriftCorrected(radosNamespace, drifted)
+	}
+
+	// Return and only requeue for the periodic resync
+	log.Debugf("done reconciling cephBlockPoolRadosNamespace %q", namespacedName)
+	return resyncResult(r.mirrorMonitoringResync(radosNamespace, imageCountResync(resync, imageCountInterval(log)))), radosNamespace, nil
+}
+
+// updateClusterConfig saves the csi config entry of the rados namespace, and returns whether the csi config map
+// was modified. The config map is not written when the entry is unchanged.
+// The labels and annotations of the rados namespace are not part of the entry, they are copied onto the csi
+// operator client profile by reconcileClientProfile.
+func (r *ReconcileCephBlockPoolRadosNamespace) updateClusterConfig(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster cephv1.CephCluster, log *reconcileLogger) (bool, error) {
+	csiClusterConfigEntry := BuildRadosNamespaceCSIEntry(r.clusterInfo, cephCluster, cephBlockPoolRadosNamespace)
+
+	clusterID := buildClusterID(cephBlockPoolRadosNamespace)
+	changed, err := csi.ClusterConfigChanged(r.context.Clientset, clusterID, cephCluster.Namespace, r.clusterInfo, &csiClusterConfigEntry)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to compare the csi config of cluster ID %q", clusterID)
+	}
+	if !changed {
+		log.Debugf("csi config of cluster ID %q is unchanged", clusterID)
+		return false, nil
+	}
+	r.warnIfNetNamespaceFilePathCleared(clusterID, log)
 
 	// Save cluster config in the csi config map
-	err := csi.SaveClusterConfig(r.context.Clientset, buildClusterID(cephBlockPoolRadosNamespace), cephCluster.Namespace, r.clusterInfo, &csiClusterConfigEntry)
+	err = r.saveClusterConfig(clusterID, cephCluster.Namespace, &csiClusterConfigEntry)
 	if err != nil {
-		return errors.Wrap(err, "failed to save cluster config")
+		return false, errors.Wrap(err, "failed to save cluster config")
 	}
 
-	return nil
+	return true, nil
+}
+
+// warnIfNetNamespaceFilePathCleared logs a warning when the existing csi config entry of the cluster ID has a
+// net namespace file path, since the rados namespace entries are always saved without one
+func (r *ReconcileCephBlockPoolRadosNamespace) warnIfNetNamespaceFilePathCleared(clusterID string, log *reconcileLogger) {
+	existing, err := csi.GetClusterConfigEntry(r.context.Clientset, clusterID, r.clusterInfo)
+	if err != nil {
+		log.Debugf("failed to get the existing csi config entry %q. %v", clusterID, err)
+		return
+	}
+	if existing != nil && existing.RBD.NetNamespaceFilePath != "" {
+		log.Warningf("clearing the rbd net namespace file path %q of the csi config entry %q", existing.RBD.NetNamespaceFilePath, clusterID)
+	}
 }
 
 // Create the ceph blockpool rados namespace
-func (r *ReconcileCephBlockPoolRadosNamespace) createOrUpdateRadosNamespace(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+func (r *ReconcileCephBlockPoolRadosNamespace) createOrUpdateRadosNamespace(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) error {
 	namespacedName := fmt.Sprintf("%s/%s", cephBlockPoolRadosNamespace.Namespace, cephBlockPoolRadosNamespace.Name)
-	logger.Infof("creating ceph blockpool rados namespace %q", namespacedName)
+	log.Infof("creating ceph blockpool rados namespace %q", namespacedName)
 
 	if cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace) == "" {
-		logger.Infof("can't create empty radosnamespace %q in the namespace %q as it is already present", cephBlockPoolRadosNamespace.Name, cephBlockPoolRadosNamespace.Namespace)
+		log.Infof("can't create empty radosnamespace %q in the namespace %q as it is already present", cephBlockPoolRadosNamespace.Name, cephBlockPoolRadosNamespace.Namespace)
 		return nil
 	}
-	err := cephclient.CreateRadosNamespace(r.context, r.clusterInfo, cephBlockPoolRadosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace))
+	err := r.withCephTimeout("create rados namespace", log, func(clusterInfo *cephclient.ClusterInfo) error {
+		return cephclient.CreateRadosNamespace(r.context, clusterInfo, cephBlockPoolRadosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace))
+	})
 	if err != nil {
-		return errors.Wrapf(err, "failed to create ceph blockpool rados namespace %q", cephBlockPoolRadosNamespace.Name)
+		return errors.Wrapf(detectClusterFull(err), "failed to create ceph blockpool rados namespace %q", cephBlockPoolRadosNamespace.Name)
 	}
 
 	return nil
 }
 
-// Delete the ceph blockpool rados namespace
-func (r *ReconcileCephBlockPoolRadosNamespace) deleteRadosNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster) (bool, error) {
+// Delete the ceph blockpool rados namespace, returns whether the deletion is blocked
+func (r *ReconcileCephBlockPoolRadosNamespace) deleteRadosNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster, log *reconcileLogger) (bool, error) {
 	nsName := types.NamespacedName{Namespace: radosNamespace.Namespace, Name: radosNamespace.Name}
-	logger.Infof("deleting rados namespace %q", nsName.String())
+	log.Infof("deleting rados namespace %q", nsName.String())
 
 	name := cephv1.GetRadosNamespaceName(radosNamespace)
 	if name == "" {
-		logger.Info("no need to delete implicit radosnamepace")
+		// the implicit rados namespace is never deleted from ceph, whether its csi config is removed is decided
+		// by removesCSIConfigOnDeletion once the deletion completes
+		log.Info("no need to delete implicit radosnamepace")
 		return false, nil
 	}
 
-	containsImages, deleteErr := cephclient.DeleteRadosNamespace(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, name)
+	if radosNamespace.Spec.Mirroring != nil {
+		blocked, err := r.checkMirrorPrimaryDeletion(radosNamespace, nsName, log)
+		if blocked {
+			return true, err
+		}
+	}
+
+	if needsMirroringTeardown(radosNamespace, cephCluster) {
+		if err := r.teardownMirroring(radosNamespace, nsName, log); err != nil {
+			return false, err
+		}
+	}
+
+	var containsImages bool
+	deleteErr := r.withCephTimeout("delete rados namespace", log, func(clusterInfo *cephclient.ClusterInfo) error {
+		var err error
+		containsImages, err = cephclient.DeleteRadosNamespace(r.context, clusterInfo, radosNamespace.Spec.BlockPoolName, name)
+		return err
+	})
+	if errors.Is(deleteErr, context.DeadlineExceeded) {
+		// the images check may still be running, do not report whether the rados namespace contains images
+		return false, errors.Wrapf(deleteErr, "failed to delete rados namespace %q", radosNamespace.Name)
+	}
 	// If deleteErr is not nil, it means the deletion failed, but we still want to
 	// report a condition whether the rados namespace contains images
+	setDeletionBlocked(nsName, containsImages)
 	var emptyCondition cephv1.Condition
 	if containsImages {
 		emptyCondition = dependents.DeletionBlockedDueToNonEmptyRadosNSCondition(
@@ -437,18 +1008,20 @@ func (r *ReconcileCephBlockPoolRadosNamespace) deleteRadosNamespace(radosNamespa
 			false,
 			fmt.Sprintf("rados namespace %q is empty and can be deleted", radosNamespace.Name))
 	}
-	logger.Info(emptyCondition.Message)
+	log.Info(emptyCondition.Message)
 
-	err := reporting.UpdateStatusConditionsWithRetry(
-		r.opManagerContext, r.client, radosNamespace, nsName, radosNamespace.Kind, emptyCondition)
+	err := r.mutateStatus(nsName, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		cephv1.SetStatusCondition(&current.Status.Conditions, emptyCondition)
+		return true
+	})
 	if err != nil {
-		logger.Warningf("failed to update %q status with deletion blocked conditions: %v", nsName.String(), err)
+		log.Warningf("failed to update %q status with deletion blocked conditions: %v", nsName.String(), err)
 	}
 
 	if containsImages {
 		// Force deletion if desired
 		if opcontroller.ForceDeleteRequested(radosNamespace.GetAnnotations()) {
-			cleanupErr := r.cleanup(radosNamespace, cephCluster)
+			cleanupErr := r.cleanup(radosNamespace, cephCluster, log)
 			if cleanupErr != nil {
 				return containsImages, errors.Wrapf(cleanupErr, "failed to create clean up job for rados namespace %q", radosNamespace.Name)
 			}
@@ -456,142 +1029,387 @@ func (r *ReconcileCephBlockPoolRadosNamespace) deleteRadosNamespace(radosNamespa
 	}
 
 	if deleteErr != nil {
+		if containsImages {
+			deleteErr = &ContainsImagesError{RadosNamespace: radosNamespace.Name, err: deleteErr}
+		}
 		return containsImages, errors.Wrapf(deleteErr, "failed to delete rados namespace %q", radosNamespace.Name)
 	}
 
-	logger.Infof("deleted rados namespace %q", nsName.String())
+	// remove the application metadata of the rados namespace from the pool
+	err = log.timeCephCall("remove application metadata", func() error {
+		return cephclient.SetRadosNamespaceApplicationMetadata(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, name, nil)
+	})
+	if err != nil {
+		log.Warningf("failed to remove application metadata of rados namespace %q. %v", nsName.String(), err)
+	}
+
+	log.Infof("deleted rados namespace %q", nsName.String())
 	return false, nil
 }
 
-// updateStatus updates an object with a given status
-func (r *ReconcileCephBlockPoolRadosNamespace) updateStatus(client client.Client, name types.NamespacedName, status cephv1.ConditionType) {
-	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
-	if err := client.Get(r.opManagerContext, name, cephBlockPoolRadosNamespace); err != nil {
-		if kerrors.IsNotFound(err) {
-			logger.Debugf("CephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", name)
-			return
+// checkMirrorPrimaryDeletion reports whether the deletion of the rados namespace is blocked because it
+// is the mirroring primary of a healthy peer. The deletion is not blocked if force deletion is requested.
+func (r *ReconcileCephBlockPoolRadosNamespace) checkMirrorPrimaryDeletion(radosNamespace *cephv1.CephBlockPoolRadosNamespace, nsName types.NamespacedName, log *reconcileLogger) (bool, error) {
+	blocked := isActiveMirrorPrimary(radosNamespace.Status)
+	var primaryCondition cephv1.Condition
+	if blocked && opcontroller.ForceDeleteRequested(radosNamespace.GetAnnotations()) {
+		log.Warningf("force deleting rados namespace %q although it is the mirroring primary of a healthy peer", nsName.String())
+		blocked = false
+	}
+	if blocked {
+		primaryCondition = dependents.DeletionBlockedDueToMirrorPrimaryCondition(
+			true,
+			fmt.Sprintf("rados namespace %q is the mirroring primary of a healthy peer and cannot be deleted", radosNamespace.Name))
+	} else {
+		primaryCondition = dependents.DeletionBlockedDueToMirrorPrimaryCondition(
+			false,
+			fmt.Sprintf("rados namespace %q is not the mirroring primary of a healthy peer and can be deleted", radosNamespace.Name))
+	}
+	log.Info(primaryCondition.Message)
+
+	err := r.mutateStatus(nsName, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		cephv1.SetStatusCondition(&current.Status.Conditions, primaryCondition)
+		return true
+	})
+	if err != nil {
+		log.Warningf("failed to update %q status with deletion blocked conditions: %v", nsName.String(), err)
+	}
+
+	if blocked {
+		return true, errors.New(primaryCondition.Message)
+	}
+	return false, nil
+}
+
+// isActiveMirrorPrimary returns whether the rados namespace is the mirroring primary of a healthy peer based
+// on the mirroring status gathered by the mirroring checker
+func isActiveMirrorPrimary(status *cephv1.CephBlockPoolRadosNamespaceStatus) bool {
+	if status == nil || status.MirroringInfo == nil || status.MirroringInfo.MirroringInfo == nil ||
+		status.MirroringStatus == nil || status.MirroringStatus.Summary == nil {
+		return false
+	}
+
+	// a peer that this site only receives from does not depend on it
+	hasDependentPeer := false
+	for _, peer := range status.MirroringInfo.Peers {
+		if peer.Direction != string(cephv1.RadosNamespaceMirroringDirectionRxOnly) {
+			hasDependentPeer = true
+			break
 		}
-		logger.Warningf("failed to retrieve ceph blockpool rados namespace %q to update status to %q. %v", name, status, err)
-		return
 	}
-	if cephBlockPoolRadosNamespace.Status == nil {
-		cephBlockPoolRadosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+	if !hasDependentPeer {
+		return false
 	}
 
-	cephBlockPoolRadosNamespace.Status.Phase = status
-	cephBlockPoolRadosNamespace.Status.Info = map[string]string{"clusterID": buildClusterID(cephBlockPoolRadosNamespace)}
-	if err := reporting.UpdateStatus(client, cephBlockPoolRadosNamespace); err != nil {
-		logger.Errorf("failed to set ceph blockpool rados namespace %q status to %q. %v", name, status, err)
+	summary := status.MirroringStatus.Summary
+	if summary.Health != "OK" {
+		return false
+	}
+
+	// the primary images are reported as stopped while the images replayed from a peer are reported as replaying
+	return summary.States.Stopped > 0 && summary.States.Replaying == 0
+}
+
+// updateStatus updates an object with a given status and sets the given conditions
+func (r *ReconcileCephBlockPoolRadosNamespace) updateStatus(observedGeneration int64, name types.NamespacedName, status cephv1.ConditionType, log *reconcileLogger, conditions ...cephv1.Condition) {
+	err := r.mutateStatus(name, func(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+		cephBlockPoolRadosNamespace.Status.Phase = status
+		if cephBlockPoolRadosNamespace.Status.Info == nil {
+			cephBlockPoolRadosNamespace.Status.Info = map[string]string{}
+		}
+		cephBlockPoolRadosNamespace.Status.Info["clusterID"] = buildClusterID(cephBlockPoolRadosNamespace)
+		for _, condition := range append(standardConditions(cephBlockPoolRadosNamespace, status), conditions...) {
+			cephv1.SetStatusCondition(&cephBlockPoolRadosNamespace.Status.Conditions, condition)
+		}
+		if status == cephv1.ConditionReady {
+			// the ready status is only set at the end of a successful reconcile
+			r.recordReconcileTime(cephBlockPoolRadosNamespace.Status)
+		}
+		if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
+			cephBlockPoolRadosNamespace.Status.ObservedGeneration = observedGeneration
+		}
+		return true
+	})
+	if err != nil {
+		log.Errorf("failed to set ceph blockpool rados namespace %q status to %q. %v", name, status, err)
 		return
 	}
-	logger.Debugf("ceph blockpool rados namespace %q status updated to %q", name, status)
+	log.Debugf("ceph blockpool rados namespace %q status updated to %q", name, status)
+}
+
+// saveClusterConfig saves the csi config of the rados namespace, retrying on conflicts since the reconciles of
+// all the rados namespaces update the same config map. The config map is read again on each attempt. The
+// updates and removals of concurrent reconciles are batched into a single config map update.
+func (r *ReconcileCephBlockPoolRadosNamespace) saveClusterConfig(clusterID, clusterNamespace string, entry *csi.CSIClusterConfigEntry) error {
+	return retry.RetryOnConflict(csiConfigRetry, func() error {
+		if r.csiConfigBatcher != nil {
+			return r.csiConfigBatcher.SaveClusterConfig(r.context.Clientset, clusterID, clusterNamespace, r.clusterInfo, entry)
+		}
+		return csi.SaveClusterConfig(r.context.Clientset, clusterID, clusterNamespace, r.clusterInfo, entry)
+	})
 }
 
+// buildClusterID returns the cluster ID of the rados namespace in the csi config, the override of the spec or
+// the hash of the namespace, pool and rados namespace names. The name of the targeted CephCluster is part of the
+// hash when set, so that the same pool and rados namespace names in two CephClusters get different cluster IDs.
+// Likewise the namespace of the pool is part of the hash when the pool is in another namespace than the CR.
 func buildClusterID(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
-	clusterID := fmt.Sprintf("%s-%s-block-%s", cephBlockPoolRadosNamespace.Namespace, cephBlockPoolRadosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace))
-	return k8sutil.Hash(clusterID)
+	if cephBlockPoolRadosNamespace.Spec.ClusterID != "" {
+		return cephBlockPoolRadosNamespace.Spec.ClusterID
+	}
+	prefix := cephBlockPoolRadosNamespace.Namespace
+	if cephBlockPoolRadosNamespace.Spec.CephClusterName != "" {
+		prefix = fmt.Sprintf("%s-%s", prefix, cephBlockPoolRadosNamespace.Spec.CephClusterName)
+	}
+	if poolNamespace := blockPoolNamespace(cephBlockPoolRadosNamespace); poolNamespace != cephBlockPoolRadosNamespace.Namespace {
+		prefix = fmt.Sprintf("%s-%s", prefix, poolNamespace)
+	}
+	return generatedClusterID(prefix, cephBlockPoolRadosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace))
 }
 
-func (r *ReconcileCephBlockPoolRadosNamespace) cleanup(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster) error {
-	logger.Infof("starting cleanup of the ceph resources for radosNamespace %q in namespace %q", radosNamespace.Name, radosNamespace.Namespace)
+// generatedClusterID returns the cluster ID generated for the rados namespace of the pool with the prefix
+func generatedClusterID(prefix, poolName, radosNamespaceName string) string {
+	return k8sutil.Hash(fmt.Sprintf("%s-%s-block-%s", prefix, poolName, radosNamespaceName))
+}
+
+func (r *ReconcileCephBlockPoolRadosNamespace) cleanup(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster, log *reconcileLogger) error {
+	nsName := types.NamespacedName{Namespace: radosNamespace.Namespace, Name: radosNamespace.Name}
+	jobName := cleanupJobName(radosNamespace)
+
+	// the clean up job runs in the namespace of the CR, where the ceph config of the cluster is only available
+	// if the pool is in the same namespace
+	if poolNamespace := blockPoolNamespace(radosNamespace); poolNamespace != radosNamespace.Namespace {
+		return errors.Errorf("cannot clean up the ceph resources of radosNamespace %q with a clean up job since its CephBlockPool is in namespace %q, remove the images manually", radosNamespace.Name, poolNamespace)
+	}
+
+	// The reconcile may run several times before the clean up job finishes, so do not recreate a job that
+	// is still running
+	existingJob, err := r.context.Clientset.BatchV1().Jobs(radosNamespace.Namespace).Get(r.clusterInfo.Context, jobName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get clean up job %q for radosNamespace %q", jobName, radosNamespace.Name)
+	}
+	if err == nil {
+		state := cleanupJobState(existingJob)
+		if state == cleanupJobRunning {
+			log.Infof("clean up job %q for radosNamespace %q is still running", jobName, radosNamespace.Name)
+			r.updateCleanupJobStatus(nsName, jobName, state, log)
+			return nil
+		}
+		// a failed job is recreated, as well as a completed job since the rados namespace still contains images
+		log.Infof("recreating clean up job %q for radosNamespace %q in state %q", jobName, radosNamespace.Name, state)
+	}
+
+	log.Infof("starting cleanup of the ceph resources for radosNamespace %q in namespace %q", radosNamespace.Name, radosNamespace.Namespace)
 	cleanupConfig := map[string]string{
 		opcontroller.CephBlockPoolNameEnv:           radosNamespace.Spec.BlockPoolName,
 		opcontroller.CephBlockPoolRadosNamespaceEnv: cephv1.GetRadosNamespaceName(radosNamespace),
+		// the snapshots of all the images are removed first, then the images, then the rados namespace
+		opcontroller.CephBlockPoolRadosNamespaceCleanupOrderEnv: strings.Join(opcontroller.RadosNamespaceCleanupOrder, ","),
 	}
 	cleanup := opcontroller.NewResourceCleanup(radosNamespace, cephCluster, r.opConfig.Image, cleanupConfig)
-	jobName := k8sutil.TruncateNodeNameForJob("cleanup-radosnamespace-%s", fmt.Sprintf("%s-%s", radosNamespace.Spec.BlockPoolName, radosNamespace.Name))
-	err := cleanup.StartJob(r.clusterInfo.Context, r.context.Clientset, jobName)
+	err = cleanup.StartJob(r.clusterInfo.Context, r.context.Clientset, jobName)
 	if err != nil {
 		return errors.Wrapf(err, "failed to run clean up job to clean the ceph resources in radosNamespace %q", radosNamespace.Name)
 	}
+	r.updateCleanupJobStatus(nsName, jobName, cleanupJobRunning, log)
 	return nil
 }
 
+// cleanupJobState returns the state of the clean up job
+func cleanupJobState(job *batch.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != v1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batch.JobFailed:
+			return cleanupJobFailed
+		case batch.JobComplete:
+			return cleanupJobSucceeded
+		}
+	}
+	return cleanupJobRunning
+}
+
+// updateCleanupJobStatus reports the state of the clean up job in the status info of the rados namespace, the
+// state is removed if empty. The name of the job is recorded if not empty.
+func (r *ReconcileCephBlockPoolRadosNamespace) updateCleanupJobStatus(name types.NamespacedName, jobName, state string, log *reconcileLogger) {
+	err := r.mutateStatus(name, func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+		if radosNamespace.Status.Info[cleanupJobInfoKey] == state && (jobName == "" || radosNamespace.Status.Info[cleanupJobNameInfoKey] == jobName) {
+			return false
+		}
+		if state == "" {
+			delete(radosNamespace.Status.Info, cleanupJobInfoKey)
+		} else {
+			if radosNamespace.Status.Info == nil {
+				radosNamespace.Status.Info = map[string]string{}
+			}
+			radosNamespace.Status.Info[cleanupJobInfoKey] = state
+		}
+		if jobName != "" {
+			if radosNamespace.Status.Info == nil {
+				radosNamespace.Status.Info = map[string]string{}
+			}
+			radosNamespace.Status.Info[cleanupJobNameInfoKey] = jobName
+		}
+		return true
+	})
+	if err != nil {
+		log.Errorf("failed to update the clean up job state of ceph blockpool rados namespace %q. %v", name, err)
+	}
+}
+
 func checkBlockPoolMirroring(cephBlockPool *cephv1.CephBlockPool) bool {
 	return !(cephBlockPool.Spec.Mirroring.Enabled)
 }
 
-func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirroring(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, cephBlockPool *cephv1.CephBlockPool) error {
-	poolAndRadosNamespaceName := fmt.Sprintf("%s/%s", cephBlockPool.Name, cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace))
-	if cephv1.GetRadosNamespaceName(cephBlockPoolRadosNamespace) == "" {
-		poolAndRadosNamespaceName = cephBlockPool.Name
-	}
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirroring(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, cephBlockPool *cephv1.CephBlockPool, log *reconcileLogger) error {
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(cephBlockPoolRadosNamespace)
 
-	mirrorInfo, err := cephclient.GetPoolMirroringInfo(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+	var mirrorInfo *cephv1.MirroringInfo
+	err := r.withCephTimeout("get mirroring info", log, func(clusterInfo *cephclient.ClusterInfo) error {
+		var err error
+		mirrorInfo, err = r.mirroringInfo.get(r.context, clusterInfo, poolAndRadosNamespaceName)
+		return err
+	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to get mirroring info for the radosnamespace %q", poolAndRadosNamespaceName)
 	}
 
-	// Initialize the channel for radosNamespace
-	// This allows us to track multiple radosNamespace in the same namespace
-	radosNamespaceChannelKey := radosNamespaceChannelKeyName(cephBlockPool.Namespace, poolAndRadosNamespaceName)
-	_, radosNamespaceContextsExists := r.radosNamespaceContexts[radosNamespaceChannelKey]
-	if !radosNamespaceContextsExists {
-		internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
-		r.radosNamespaceContexts[radosNamespaceChannelKey] = &mirrorHealth{
-			internalCtx:    internalCtx,
-			internalCancel: internalCancel,
-		}
-	}
 	monitoringSpec := cephv1.NamedPoolSpec{
 		Name:     poolAndRadosNamespaceName, // use the name of the blockpool/radosNamespace
 		PoolSpec: cephBlockPool.Spec.PoolSpec,
 	}
-	checker := cephclient.NewMirrorChecker(r.context, r.client, r.clusterInfo, types.NamespacedName{Name: cephBlockPoolRadosNamespace.Name, Namespace: cephBlockPoolRadosNamespace.Namespace}, &monitoringSpec, cephBlockPoolRadosNamespace)
+	if interval := mirrorHealthCheckInterval(cephBlockPoolRadosNamespace); interval != nil {
+		// the rados namespace overrides the check interval of the pool
+		monitoringSpec.StatusCheck.Mirror.Interval = interval
+	}
+	nsName := types.NamespacedName{Name: cephBlockPoolRadosNamespace.Name, Namespace: cephBlockPoolRadosNamespace.Namespace}
+	checker := cephclient.NewMirrorChecker(r.context, r.client, r.clusterInfo, nsName, &monitoringSpec, cephBlockPoolRadosNamespace)
+	checker.SetCheckTimeout(cephCallTimeout())
+	checker.SetEventRecorder(r.recorder)
+
+	// Initialize the channel for radosNamespace
+	// This allows us to track multiple radosNamespace in the same namespace
+	radosNamespaceChannelKey := mirrorMonitoringChannelKey(cephBlockPoolRadosNamespace)
+	r.restartDeadMirrorMonitoring(radosNamespaceChannelKey, mirrorCheckerStaleAfter(checker.Interval()), log)
+	r.initMirrorMonitoring(radosNamespaceChannelKey)
+	r.restartMirrorMonitoringOnIntervalChange(radosNamespaceChannelKey, checker.Interval(), log)
+	checker.SetHeartbeat(r.mirrorMonitoringHeartbeat(radosNamespaceChannelKey))
 
 	if cephBlockPoolRadosNamespace.Spec.Mirroring != nil {
+		// an unknown mirroring mode must not be passed to ceph
+		if err := validateMirroringMode(cephBlockPoolRadosNamespace.Spec.Mirroring.Mode); err != nil {
+			return errors.Wrapf(err, "cannot enable mirroring for radosnamespace %q", poolAndRadosNamespaceName)
+		}
+		// the errors reported right after mirroring is enabled are reported as initializing
+		enabledAt := mirroringEnabledAt(cephBlockPoolRadosNamespace)
 		mirroringDisabled := checkBlockPoolMirroring(cephBlockPool)
 		if mirroringDisabled {
-			return errors.Errorf("mirroring is disabled for block pool %q, cannot enable mirroring for radosnamespace %q", cephBlockPool.Name, poolAndRadosNamespaceName)
+			return errors.Wrapf(&PoolMirroringDisabledError{PoolName: cephBlockPool.Name}, "cannot enable mirroring for radosnamespace %q", poolAndRadosNamespaceName)
 		}
 
-		err = cephclient.EnableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName, cephBlockPoolRadosNamespace.Spec.Mirroring.RemoteNamespace, string(cephBlockPoolRadosNamespace.Spec.Mirroring.Mode))
-		if err != nil {
-			return errors.Wrap(err, "failed to enable rbd rados namespace mirroring")
+		// Only retry the remaining steps if mirroring was already enabled for this generation
+		if isMirroringEnabledForGeneration(cephBlockPoolRadosNamespace) && mirrorInfo.Mode == string(cephBlockPoolRadosNamespace.Spec.Mirroring.Mode) {
+			log.Debugf("mirroring already enabled for radosnamespace %q", poolAndRadosNamespaceName)
+		} else if !isMirroringRecorded(cephBlockPoolRadosNamespace) && mirroringMatchesSpec(cephBlockPoolRadosNamespace, mirrorInfo) {
+			// the mirroring was configured before the rados namespace was adopted, only record it
+			log.Infof("adopting the existing %q mirroring of radosnamespace %q", mirrorInfo.Mode, poolAndRadosNamespaceName)
+			r.recordMirroringEnabled(nsName, strconv.FormatInt(cephBlockPoolRadosNamespace.Generation, 10), log)
+		} else {
+			if mirrorInfo.Mode != "" && mirrorInfo.Mode != "disabled" && !isMirroringRecorded(cephBlockPoolRadosNamespace) {
+				log.Infof("existing %q mirroring of radosnamespace %q with remote namespace %q does not match the spec, updating it",
+					mirrorInfo.Mode, poolAndRadosNamespaceName, mirrorInfo.RemoteNamespace)
+			}
+			if isMirroringModeSwitch(cephBlockPoolRadosNamespace, mirrorInfo) {
+				if err := r.prepareMirroringModeSwitch(poolAndRadosNamespaceName, mirrorInfo, cephBlockPoolRadosNamespace.Spec.Mirroring.Mode, log); err != nil {
+					return err
+				}
+			}
+			err = log.timeCephCall("enable mirroring", func() error {
+				return cephclient.EnableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName, cephBlockPoolRadosNamespace.Spec.Mirroring.RemoteNamespace, string(cephBlockPoolRadosNamespace.Spec.Mirroring.Mode))
+			})
+			r.mirroringInfo.invalidate(r.clusterInfo, poolAndRadosNamespaceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to enable rbd rados namespace mirroring")
+			}
+			if err := r.verifyMirroringPeers(cephBlockPoolRadosNamespace, log); err != nil {
+				return errors.Wrap(err, "failed to enable rbd rados namespace mirroring")
+			}
+			r.recordMirroringEnabled(nsName, strconv.FormatInt(cephBlockPoolRadosNamespace.Generation, 10), log)
+			enabledAt = r.now().UTC()
+			r.recordMirroringInfo(nsName, cephclient.MirroringEnabledAtInfoKey, enabledAt.Format(time.RFC3339), log)
 		}
+		r.recordMirroringInfo(nsName, mirroringDrainOnDisableInfoKey, drainOnDisableInfo(cephBlockPoolRadosNamespace.Spec.Mirroring), log)
 
 		// Schedule snapshots
-		err = cephclient.EnableSnapshotSchedules(r.context, r.clusterInfo, poolAndRadosNamespaceName, cephBlockPoolRadosNamespace.Spec.Mirroring.SnapshotSchedules)
+		err = log.timeCephCall("reconcile snapshot schedules", func() error {
+			return cephclient.ReconcileSnapshotSchedules(r.context, r.clusterInfo, poolAndRadosNamespaceName, desiredSnapshotSchedules(cephBlockPoolRadosNamespace.Spec.Mirroring))
+		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to enable snapshot scheduling for rbd rados namespace %q", poolAndRadosNamespaceName)
+			return &SnapshotSchedulesError{err: errors.Wrapf(err, "failed to enable snapshot scheduling for rbd rados namespace %q", poolAndRadosNamespaceName)}
 		}
+		schedulesInfo := snapshotSchedulesInfo(cephBlockPoolRadosNamespace.Spec.Mirroring)
+		if schedulesInfo != "" && (cephBlockPoolRadosNamespace.Status == nil || cephBlockPoolRadosNamespace.Status.Info[snapshotSchedulesInfoKey] != schedulesInfo) {
+			log.Infof("snapshot schedules of rados namespace %q are %s", poolAndRadosNamespaceName, schedulesInfo)
+		}
+		r.recordMirroringInfo(nsName, snapshotSchedulesInfoKey, schedulesInfo, log)
 
 		// Run the goroutine to update the mirroring status
 		// use the monitoring settings from the cephBlockPool CR
 		if !cephBlockPool.Spec.StatusCheck.Mirror.Disabled {
-			logger.Debugf("starting mirror monitoring for radosnamespace %q", poolAndRadosNamespaceName)
+			log.Debugf("starting mirror monitoring for radosnamespace %q", poolAndRadosNamespaceName)
+			checker.SetGracePeriod(enabledAt, mirroringGracePeriod(log))
 			// Start monitoring of the radosNamespace
-			if r.radosNamespaceContexts[radosNamespaceChannelKey].started {
-				logger.Debug("radosnamespace monitoring go routine already running!")
-			} else {
-				r.radosNamespaceContexts[radosNamespaceChannelKey].started = true
-				go checker.CheckMirroring(r.radosNamespaceContexts[radosNamespaceChannelKey].internalCtx)
+			if !r.startMirrorMonitoring(radosNamespaceChannelKey, checker.CheckMirroring) {
+				log.Debug("radosnamespace monitoring go routine already running!")
+			}
+		}
+
+		if cephBlockPoolRadosNamespace.Spec.Mirroring.ImageFilter != nil {
+			if err := r.reconcileImageFilter(cephBlockPoolRadosNamespace, poolAndRadosNamespaceName, log); err != nil {
+				return err
 			}
 		}
 	}
 
 	if cephBlockPoolRadosNamespace.Spec.Mirroring == nil && mirrorInfo.Mode != "disabled" {
 		if mirrorInfo.Mode == "image" {
-			mirroredPools, err := cephclient.GetMirroredPoolImages(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+			var mirroredPools *cephclient.MirroredImages
+			err := log.timeCephCall("list mirrored images", func() error {
+				var err error
+				mirroredPools, err = cephclient.GetMirroredPoolImages(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+				return err
+			})
 			if err != nil {
 				return errors.Wrapf(err, "failed to list mirrored images for radosnamespace %q", poolAndRadosNamespaceName)
 			}
 
 			if len(*mirroredPools.Images) > 0 {
-				return errors.Errorf("there are images in the radosnamespace %q. Please manually disable mirroring for each image", poolAndRadosNamespaceName)
+				if !isDrainOnDisableRecorded(cephBlockPoolRadosNamespace) {
+					return errors.Errorf("there are images in the radosnamespace %q. Please manually disable mirroring for each image or set mirroring.drainOnDisable before disabling mirroring", poolAndRadosNamespaceName)
+				}
+				if err := r.drainMirroredImages(poolAndRadosNamespaceName, *mirroredPools.Images, log); err != nil {
+					return err
+				}
 			}
 		}
 
-		err = cephclient.DisableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+		err = log.timeCephCall("disable mirroring", func() error {
+			return cephclient.DisableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+		})
+		r.mirroringInfo.invalidate(r.clusterInfo, poolAndRadosNamespaceName)
 		if err != nil {
 			return errors.Wrap(err, "failed to disable rbd rados namespace mirroring")
 		}
+		r.recordMirroringEnabled(nsName, "", log)
+		r.recordMirroringInfo(nsName, mirroringDrainOnDisableInfoKey, "", log)
+		r.recordMirroringInfo(nsName, snapshotSchedulesInfoKey, "", log)
 	}
 
 	if cephBlockPool.Spec.StatusCheck.Mirror.Disabled {
 		// Stop monitoring the mirroring status of this radosNamespace
-		if radosNamespaceContextsExists && r.radosNamespaceContexts[radosNamespaceChannelKey].started {
-			r.cancelMirrorMonitoring(radosNamespaceChannelKey)
+		if r.cancelMirrorMonitoring(radosNamespaceChannelKey) {
 			// Reset the MirrorHealthCheckSpec
 			checker.UpdateStatusMirroring(nil, nil, nil, "")
 		}
@@ -600,18 +1418,94 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirroring(cephBlockPoolR
 	return nil
 }
 
-func radosNamespaceChannelKeyName(poolAndRadosNamespaceName, namespace string) string {
-	return types.NamespacedName{Namespace: namespace, Name: poolAndRadosNamespaceName}.String()
+// getPoolAndRadosNamespaceName returns the "<pool>/<rados namespace>" name of the rados namespace used by the
+// rbd commands, or only the pool name for the implicit rados namespace
+func getPoolAndRadosNamespaceName(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	if name := cephv1.GetRadosNamespaceName(radosNamespace); name != cephv1.ImplicitNamespaceVal {
+		return fmt.Sprintf("%s/%s", radosNamespace.Spec.BlockPoolName, name)
+	}
+	return radosNamespace.Spec.BlockPoolName
+}
+
+// mirrorMonitoringChannelKey returns the key of the mirroring status checker of the rados namespace, as
+// "<namespace>/<pool>/<rados namespace>". The key is only derived from the CR so that the checker is always
+// started and cancelled with the same key.
+func mirrorMonitoringChannelKey(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	return types.NamespacedName{Namespace: radosNamespace.Namespace, Name: getPoolAndRadosNamespaceName(radosNamespace)}.String()
+}
+
+// initMirrorMonitoring tracks the context of the mirroring status checker of the radosNamespace if it is not
+// tracked yet
+func (r *ReconcileCephBlockPoolRadosNamespace) initMirrorMonitoring(channelKey string) {
+	r.radosNamespaceContextsLock.Lock()
+	defer r.radosNamespaceContextsLock.Unlock()
+
+	r.initMirrorMonitoringLocked(channelKey)
+}
+
+// initMirrorMonitoringLocked tracks a new context for the mirror monitoring if there is none and returns the
+// tracked one, the caller must hold radosNamespaceContextsLock
+func (r *ReconcileCephBlockPoolRadosNamespace) initMirrorMonitoringLocked(channelKey string) *mirrorHealth {
+	if health, ok := r.radosNamespaceContexts[channelKey]; ok {
+		return health
+	}
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	health := &mirrorHealth{
+		internalCtx:    internalCtx,
+		internalCancel: internalCancel,
+	}
+	r.radosNamespaceContexts[channelKey] = health
+	return health
 }
 
-// cancel mirror monitoring. This is a noop if monitoring is not running.
-func (r *ReconcileCephBlockPoolRadosNamespace) cancelMirrorMonitoring(channelKey string) {
-	_, poolContextExists := r.radosNamespaceContexts[channelKey]
-	if poolContextExists {
-		// Cancel the context to stop the go routine
-		r.radosNamespaceContexts[channelKey].internalCancel()
+// startMirrorMonitoring runs the mirroring status checker of the radosNamespace in a go routine, returns
+// false if the checker is already running or its context is not tracked
+func (r *ReconcileCephBlockPoolRadosNamespace) startMirrorMonitoring(channelKey string, checkMirroring func(context.Context)) bool {
+	r.radosNamespaceContextsLock.Lock()
+	defer r.radosNamespaceContextsLock.Unlock()
 
-		// Remove ceph radosNamespace from the map
-		delete(r.radosNamespaceContexts, channelKey)
+	health, ok := r.radosNamespaceContexts[channelKey]
+	if !ok || health.started {
+		return false
 	}
+	health.started = true
+	health.done = make(chan struct{})
+	health.lastHeartbeat = time.Now()
+	mirrorCheckersGauge.Inc()
+	go func() {
+		defer close(health.done)
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.Errorf("mirroring status checker of radosnamespace %q panicked, it will be restarted on the next reconcile. %v\n%s", channelKey, rec, debug.Stack())
+			}
+		}()
+		checkMirroring(health.internalCtx)
+	}()
+	return true
+}
+
+// cancel mirror monitoring. This is a noop if monitoring is not running. Returns whether a running
+// checker was cancelled.
+func (r *ReconcileCephBlockPoolRadosNamespace) cancelMirrorMonitoring(channelKey string) bool {
+	r.radosNamespaceContextsLock.Lock()
+	defer r.radosNamespaceContextsLock.Unlock()
+
+	return r.cancelMirrorMonitoringLocked(channelKey)
+}
+
+// cancelMirrorMonitoringLocked cancels the mirror monitoring, the caller must hold radosNamespaceContextsLock
+func (r *ReconcileCephBlockPoolRadosNamespace) cancelMirrorMonitoringLocked(channelKey string) bool {
+	health, poolContextExists := r.radosNamespaceContexts[channelKey]
+	if !poolContextExists {
+		return false
+	}
+	// Cancel the context to stop the go routine
+	health.internalCancel()
+	if health.started {
+		mirrorCheckersGauge.Dec()
+	}
+
+	// Remove ceph radosNamespace from the map
+	delete(r.radosNamespaceContexts, channelKey)
+	return health.started
 }
@@ -18,25 +18,36 @@ package radosnamespace
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	csiopv1a1 "github.com/ceph/ceph-csi-operator/api/v1alpha1"
 	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	testop "github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -164,6 +175,9 @@ func TestCephBlockPoolRadosNamespaceController(t *testing.T) {
 		res, err := r.Reconcile(ctx, req)
 		assert.NoError(t, err)
 		assert.True(t, res.Requeue)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.True(t, isWaitingForCephCluster(current))
 
 		cephCluster.Status.Phase = cephv1.ConditionReady
 		cephCluster.Status.CephStatus.Health = "HEALTH_OK"
@@ -359,9 +373,18 @@ func TestCephBlockPoolRadosNamespaceController(t *testing.T) {
 			recorder:               record.NewFakeRecorder(5),
 		}
 
+		// the error is reported as an event and the reconcile waits for mirroring to be enabled on the pool
 		res, err := r.Reconcile(ctx, req)
-		assert.Error(t, err)
-		assert.False(t, res.Requeue)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfPoolMirroringDisabled, res)
+
+		err = r.client.Get(ctx, req.NamespacedName, cephBlockPoolRadosNamespace)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ConditionFailure, cephBlockPoolRadosNamespace.Status.Phase)
+		condition := cephv1.FindStatusCondition(cephBlockPoolRadosNamespace.Status.Conditions, cephv1.ConditionFailure)
+		assert.NotNil(t, condition)
+		assert.Equal(t, cephv1.PoolMirroringDisabledReason, condition.Reason)
+		assert.Contains(t, condition.Message, "enable mirroring on the CephBlockPool")
 	})
 
 	t.Run("test rbd rados namespace mirroring enabled and blockpool mirroring is also enabled but empty rados namespace", func(t *testing.T) {
@@ -571,6 +594,144 @@ func TestCephBlockPoolRadosNamespaceController(t *testing.T) {
 		assert.NotEmpty(t, cephBlockPoolRadosNamespace.Status.Info["clusterID"])
 	})
 
+	t.Run("application metadata is set on the pool", func(t *testing.T) {
+		cephBlockPoolRadosNamespace.Spec.Mirroring = nil
+		cephBlockPoolRadosNamespace.Spec.ApplicationMetadata = map[string]string{"team": "storage"}
+		objects := []runtime.Object{
+			cephBlockPoolRadosNamespace,
+			cephCluster,
+			cephBlockPool,
+		}
+		// Create a fake client to mock API calls.
+		cl = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+		c.Client = cl
+
+		metadataSet := false
+		c.Executor = &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "osd" && args[1] == "pool" && args[2] == "application" {
+					if args[3] == "get" {
+						return `{}`, nil
+					}
+					if args[3] == "set" {
+						assert.Equal(t, []string{cephBlockPool.Name, "rbd", "rados_namespace." + name + ".team", "storage"}, args[4:8])
+						metadataSet = true
+					}
+				}
+				if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+					return `{"mode":"disabled"}`, nil
+				}
+				return "", nil
+			},
+		}
+
+		r = &ReconcileCephBlockPoolRadosNamespace{
+			client:                 cl,
+			scheme:                 s,
+			context:                c,
+			opManagerContext:       context.TODO(),
+			opConfig:               opcontroller.OperatorConfig{Image: "ceph/ceph:v14.2.9"},
+			radosNamespaceContexts: make(map[string]*mirrorHealth),
+			recorder:               record.NewFakeRecorder(5),
+		}
+
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.True(t, metadataSet)
+		cephBlockPoolRadosNamespace.Spec.ApplicationMetadata = nil
+	})
+
+	t.Run("test rbd rados namespace mirroring with peer direction does not change the pool peers", func(t *testing.T) {
+		cephBlockPoolRadosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{
+			Mode:      "image",
+			Direction: cephv1.RadosNamespaceMirroringDirectionRxOnly,
+		}
+		cephBlockPool.Spec.Mirroring.Enabled = true
+		objects := []runtime.Object{
+			cephBlockPoolRadosNamespace,
+			cephCluster,
+			cephBlockPool,
+		}
+		// Create a fake client to mock API calls.
+		cl = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).
+			WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, blockPoolNameIndex, indexBlockPoolName).Build()
+		c.Client = cl
+
+		peerSetCalled := false
+		executor = &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "namespace" && args[1] == "create" {
+					return "", nil
+				}
+				if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+					return `{"mode":"image","peers":[{"uuid":"4a6983c0-3c9d-40f5-b2a9-2334a4659827","direction":"rx-tx","site_name":"site-b"}]}`, nil
+				}
+				if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "set" {
+					peerSetCalled = true
+					return "", nil
+				}
+				return "", nil
+			},
+		}
+		c.Executor = executor
+
+		s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephBlockPoolList{})
+		// Create a ReconcileCephBlockPoolRadosNamespace object with the scheme and fake client.
+		r = &ReconcileCephBlockPoolRadosNamespace{
+			client:                 cl,
+			scheme:                 s,
+			context:                c,
+			opManagerContext:       context.TODO(),
+			opConfig:               opcontroller.OperatorConfig{Image: "ceph/ceph:v14.2.9"},
+			radosNamespaceContexts: make(map[string]*mirrorHealth),
+			recorder:               record.NewFakeRecorder(5),
+		}
+
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		// the peers belong to the pool, their direction is only set when the bootstrap peer is imported
+		assert.False(t, peerSetCalled)
+
+		err = r.client.Get(ctx, req.NamespacedName, cephBlockPoolRadosNamespace)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ConditionReady, cephBlockPoolRadosNamespace.Status.Phase)
+	})
+
+	t.Run("test rbd rados namespace mirroring with invalid peer direction", func(t *testing.T) {
+		cephBlockPoolRadosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{
+			Mode:      "image",
+			Direction: "invalid",
+		}
+		objects := []runtime.Object{
+			cephBlockPoolRadosNamespace,
+			cephCluster,
+			cephBlockPool,
+		}
+		// Create a fake client to mock API calls.
+		cl = fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).Build()
+		c.Client = cl
+		c.Executor = &exectest.MockExecutor{}
+
+		r = &ReconcileCephBlockPoolRadosNamespace{
+			client:                 cl,
+			scheme:                 s,
+			context:                c,
+			opManagerContext:       context.TODO(),
+			opConfig:               opcontroller.OperatorConfig{Image: "ceph/ceph:v14.2.9"},
+			radosNamespaceContexts: make(map[string]*mirrorHealth),
+			recorder:               record.NewFakeRecorder(5),
+		}
+
+		_, err := r.Reconcile(ctx, req)
+		assert.Error(t, err)
+
+		err = r.client.Get(ctx, req.NamespacedName, cephBlockPoolRadosNamespace)
+		assert.NoError(t, err)
+		assert.Equal(t, cephv1.ConditionFailure, cephBlockPoolRadosNamespace.Status.Phase)
+	})
+
 	t.Run("test rbd rados namespace mirroring disabled", func(t *testing.T) {
 		cephBlockPoolRadosNamespace.Spec.Mirroring = nil
 
@@ -628,11 +789,128 @@ func TestCephBlockPoolRadosNamespaceController(t *testing.T) {
 	})
 }
 
+func TestDeleteRadosNamespaceMirrorPrimary(t *testing.T) {
+	ctx := context.TODO()
+	status := func(peers []cephv1.PeersSpec, states cephv1.StatesSpec) *cephv1.CephBlockPoolRadosNamespaceStatus {
+		return &cephv1.CephBlockPoolRadosNamespaceStatus{
+			MirroringInfo: &cephv1.MirroringInfoSpec{
+				MirroringInfo: &cephv1.MirroringInfo{Mode: "image", Peers: peers},
+			},
+			MirroringStatus: &cephv1.MirroringStatusSpec{
+				MirroringStatus: cephv1.MirroringStatus{
+					Summary: &cephv1.MirroringStatusSummarySpec{Health: "OK", States: states},
+				},
+			},
+		}
+	}
+	peers := []cephv1.PeersSpec{{UUID: "4a6983c0-3c9d-40f5-b2a9-2334a4659827", Direction: "rx-tx", SiteName: "site-b"}}
+
+	tests := []struct {
+		name          string
+		status        *cephv1.CephBlockPoolRadosNamespaceStatus
+		annotations   map[string]string
+		expectBlocked bool
+	}{
+		{"primary with peer", status(peers, cephv1.StatesSpec{Stopped: 2}), nil, true},
+		{"primary without peer", status(nil, cephv1.StatesSpec{Stopped: 2}), nil, false},
+		{"secondary", status(peers, cephv1.StatesSpec{Replaying: 2}), nil, false},
+		{"primary with unhealthy peer", func() *cephv1.CephBlockPoolRadosNamespaceStatus {
+			s := status(peers, cephv1.StatesSpec{Stopped: 2})
+			s.MirroringStatus.Summary.Health = "WARNING"
+			return s
+		}(), nil, false},
+		{"primary with peer and force deletion", status(peers, cephv1.StatesSpec{Stopped: 2}), map[string]string{opcontroller.RESOURCE_CLEANUP_ANNOTATION: "true"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph", Annotations: tt.annotations},
+				TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+				Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+					BlockPoolName: "replicapool",
+					Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+				},
+				Status: tt.status,
+			}
+			s := scheme.Scheme
+			cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(radosNamespace).Build()
+			namespaceRemoved := false
+			c := &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "pool" && args[1] == "stats" {
+							return `{"images":{"count":0,"snap_count":0}}`, nil
+						}
+						if args[0] == "mirror" && args[1] == "snapshot" {
+							return "[]", nil
+						}
+						if args[0] == "namespace" && args[1] == "remove" {
+							namespaceRemoved = true
+						}
+						return "", nil
+					},
+				},
+				Clientset: testop.New(t, 1),
+			}
+			r := &ReconcileCephBlockPoolRadosNamespace{
+				client:           cl,
+				scheme:           s,
+				context:          c,
+				clusterInfo:      &cephclient.ClusterInfo{Namespace: "rook-ceph", Context: ctx},
+				opManagerContext: ctx,
+				recorder:         record.NewFakeRecorder(5),
+			}
+
+			blocked, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}))
+			assert.Equal(t, tt.expectBlocked, blocked)
+			assert.Equal(t, !tt.expectBlocked, namespaceRemoved)
+
+			current := &cephv1.CephBlockPoolRadosNamespace{}
+			assert.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}, current))
+			condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionDeletionBlockedMirrorPrimary)
+			assert.NotNil(t, condition)
+			if tt.expectBlocked {
+				assert.Error(t, err)
+				assert.Equal(t, v1.ConditionTrue, condition.Status)
+				assert.Equal(t, cephv1.RadosNamespaceMirrorPrimaryReason, condition.Reason)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, v1.ConditionFalse, condition.Status)
+				assert.Equal(t, cephv1.RadosNamespaceNotMirrorPrimaryReason, condition.Reason)
+			}
+		})
+	}
+}
+
 func Test_buildClusterID(t *testing.T) {
 	longName := "foooooooooooooooooooooooooooooooooooooooooooo"
 	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: longName}, Spec: cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"}}
 	clusterID := buildClusterID(cephBlockPoolRadosNamespace)
 	assert.Equal(t, "2a74e5201e6ff9d15916ce2109c4f868", clusterID)
+
+	// the cluster ID of the spec overrides the hash
+	cephBlockPoolRadosNamespace.Spec.ClusterID = "site-a-tenant-1"
+	assert.Equal(t, "site-a-tenant-1", buildClusterID(cephBlockPoolRadosNamespace))
+	assert.Equal(t, []string{"site-a-tenant-1"}, indexClusterID(cephBlockPoolRadosNamespace))
+
+	// the hash is used again if the override is empty
+	cephBlockPoolRadosNamespace.Spec.ClusterID = ""
+	assert.Equal(t, "2a74e5201e6ff9d15916ce2109c4f868", buildClusterID(cephBlockPoolRadosNamespace))
+
+	// the targeted CephCluster is part of the hash
+	cephBlockPoolRadosNamespace.Spec.CephClusterName = "cluster-a"
+	clusterA := buildClusterID(cephBlockPoolRadosNamespace)
+	assert.Equal(t, k8sutil.Hash("rook-ceph-cluster-a-replicapool-block-"+longName), clusterA)
+	cephBlockPoolRadosNamespace.Spec.CephClusterName = "cluster-b"
+	assert.NotEqual(t, clusterA, buildClusterID(cephBlockPoolRadosNamespace))
+	assert.NotEqual(t, "2a74e5201e6ff9d15916ce2109c4f868", buildClusterID(cephBlockPoolRadosNamespace))
+
+	// the namespace of the pool is part of the hash only when it is not the namespace of the CR
+	cephBlockPoolRadosNamespace.Spec.CephClusterName = ""
+	cephBlockPoolRadosNamespace.Spec.BlockPoolNamespace = "rook-ceph"
+	assert.Equal(t, "2a74e5201e6ff9d15916ce2109c4f868", buildClusterID(cephBlockPoolRadosNamespace))
+	cephBlockPoolRadosNamespace.Spec.BlockPoolNamespace = "storage"
+	assert.Equal(t, k8sutil.Hash("rook-ceph-storage-replicapool-block-"+longName), buildClusterID(cephBlockPoolRadosNamespace))
 }
 
 func TestGetRadosNamespaceName(t *testing.T) {
@@ -675,3 +953,612 @@ func TestGetRadosNamespaceName(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteExternalRadosNamespace(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+
+	tests := []struct {
+		name          string
+		allowDelete   bool
+		imageCount    int
+		expectRemoved bool
+		expectDeleted bool
+	}{
+		{name: "deletion is skipped by default", expectDeleted: true},
+		{name: "empty rados namespace is deleted when allowed", allowDelete: true, expectRemoved: true, expectDeleted: true},
+		{name: "rados namespace with images is not deleted when allowed", allowDelete: true, imageCount: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := metav1.Now()
+			radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "namespace-a",
+					Namespace:         namespace,
+					Finalizers:        []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+					DeletionTimestamp: &now,
+				},
+				TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+				Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+					BlockPoolName:       "replicapool",
+					ExternalAllowDelete: tt.allowDelete,
+				},
+			}
+			cephCluster := newTestCephCluster(namespace)
+			cephCluster.Spec.External.Enable = true
+
+			var cephCommands []string
+			namespaceRemoved := false
+			r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+				cephCommands = append(cephCommands, strings.Join(args, " "))
+				if args[0] == "pool" && args[1] == "stats" {
+					return fmt.Sprintf(`{"images":{"count":%d,"snap_count":0}}`, tt.imageCount), nil
+				}
+				if args[0] == "namespace" && args[1] == "remove" {
+					namespaceRemoved = true
+				}
+				return "", nil
+			}, radosNamespace, cephCluster)
+			cl := r.client
+			c := r.context
+
+			// Create the CSI config map with an entry for the rados namespace
+			createTestCSIConfigMap(t, r, namespace)
+			clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, Context: ctx}
+			err := csi.SaveClusterConfig(c.Clientset, buildClusterID(radosNamespace), namespace, clusterInfo, &csi.CSIClusterConfigEntry{Namespace: namespace})
+			assert.NoError(t, err)
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+			_, err = r.Reconcile(ctx, req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRemoved, namespaceRemoved)
+			if !tt.allowDelete {
+				// no ceph command is issued, in particular the rados namespace is neither checked nor deleted
+				assert.Empty(t, cephCommands)
+			}
+
+			cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			err = cl.Get(ctx, req.NamespacedName, &cephv1.CephBlockPoolRadosNamespace{})
+			if tt.expectDeleted {
+				// the finalizer is removed and the csi config is cleaned up
+				assert.True(t, kerrors.IsNotFound(err))
+				assert.NotContains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace))
+			} else {
+				// the deletion is blocked by the images
+				assert.NoError(t, err)
+				assert.Contains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace))
+			}
+		})
+	}
+}
+
+func TestFinalizerKeptWhileCleanupJobRunning(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	now := metav1.Now()
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "namespace-a",
+			Namespace:         namespace,
+			Finalizers:        []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+			DeletionTimestamp: &now,
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	jobName := cleanupJobName(radosNamespace)
+	// the state of the job was lost, only its name is known
+	radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{cleanupJobNameInfoKey: jobName}}
+	// the rados namespace of the external cluster is not deleted from ceph, so only the job gates the finalizer
+	cephCluster := newTestCephCluster(namespace)
+	cephCluster.Spec.External.Enable = true
+
+	r := newTestReconciler(t, namespace, nil, radosNamespace, cephCluster)
+	cl := r.client
+	c := r.context
+	createTestCSIConfigMap(t, r, namespace)
+	job, err := c.Clientset.BatchV1().Jobs(namespace).Create(ctx, &batch.Job{ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: namespace}}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+	// every attempt to remove the finalizer while the job is running is requeued
+	for i := 0; i < 2; i++ {
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfCleanupJobRunning, res)
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, &cephv1.CephBlockPoolRadosNamespace{}))
+	}
+
+	// a failed job keeps the finalizer too
+	job.Status.Conditions = []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue}}
+	_, err = c.Clientset.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.NoError(t, cl.Get(ctx, req.NamespacedName, &cephv1.CephBlockPoolRadosNamespace{}))
+
+	// the finalizer is removed once the job completed
+	job.Status.Conditions = []batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}}
+	_, err = c.Clientset.BatchV1().Jobs(namespace).UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	err = cl.Get(ctx, req.NamespacedName, &cephv1.CephBlockPoolRadosNamespace{})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestRadosNamespaceCleanupJob(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: name.Namespace}}
+	jobName := k8sutil.TruncateNodeNameForJob("cleanup-radosnamespace-%s", "replicapool-namespace-a")
+
+	newReconciler := func(objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace.DeepCopy()).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client:           cl,
+			context:          &clusterd.Context{Clientset: k8sfake.NewSimpleClientset(objects...)},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+			opManagerContext: ctx,
+			opConfig:         opcontroller.OperatorConfig{Image: "rook/ceph:test"},
+		}
+	}
+	existingJob := func(conditions ...batch.JobCondition) *batch.Job {
+		return &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: name.Namespace, Labels: map[string]string{"previous": "true"}},
+			Status:     batch.JobStatus{Conditions: conditions},
+		}
+	}
+	cleanupJobStatus := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) string {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		return current.Status.Info[cleanupJobInfoKey]
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("job is created", func(t *testing.T) {
+		r := newReconciler()
+		assert.NoError(t, r.cleanup(radosNamespace, cephCluster, log))
+		job, err := r.context.Clientset.BatchV1().Jobs(name.Namespace).Get(ctx, jobName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "rook/ceph:test", job.Spec.Template.Spec.Containers[0].Image)
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, v1.EnvVar{Name: opcontroller.CephBlockPoolRadosNamespaceCleanupOrderEnv, Value: "snapshots,images,namespace"})
+		assert.Equal(t, cleanupJobRunning, cleanupJobStatus(t, r))
+		// the job is tracked so that the finalizer is kept until it completes
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, jobName, current.Status.Info[cleanupJobNameInfoKey])
+	})
+
+	t.Run("running job already exists", func(t *testing.T) {
+		r := newReconciler(existingJob())
+		assert.NoError(t, r.cleanup(radosNamespace, cephCluster, log))
+		job, err := r.context.Clientset.BatchV1().Jobs(name.Namespace).Get(ctx, jobName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		// the job is not recreated
+		assert.Equal(t, "true", job.Labels["previous"])
+		assert.Equal(t, cleanupJobRunning, cleanupJobStatus(t, r))
+	})
+
+	t.Run("failed job is recreated", func(t *testing.T) {
+		r := newReconciler(existingJob(batch.JobCondition{Type: batch.JobFailed, Status: v1.ConditionTrue}))
+		assert.NoError(t, r.cleanup(radosNamespace, cephCluster, log))
+		job, err := r.context.Clientset.BatchV1().Jobs(name.Namespace).Get(ctx, jobName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, job.Labels["previous"])
+		assert.Empty(t, job.Status.Conditions)
+		assert.Equal(t, cleanupJobRunning, cleanupJobStatus(t, r))
+	})
+}
+
+func TestCleanupJobState(t *testing.T) {
+	assert.Equal(t, cleanupJobRunning, cleanupJobState(&batch.Job{}))
+	assert.Equal(t, cleanupJobRunning, cleanupJobState(&batch.Job{Status: batch.JobStatus{Active: 1}}))
+	assert.Equal(t, cleanupJobFailed, cleanupJobState(&batch.Job{Status: batch.JobStatus{Conditions: []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionTrue}}}}))
+	assert.Equal(t, cleanupJobSucceeded, cleanupJobState(&batch.Job{Status: batch.JobStatus{Conditions: []batch.JobCondition{{Type: batch.JobComplete, Status: v1.ConditionTrue}}}}))
+	assert.Equal(t, cleanupJobRunning, cleanupJobState(&batch.Job{Status: batch.JobStatus{Conditions: []batch.JobCondition{{Type: batch.JobFailed, Status: v1.ConditionFalse}}}}))
+}
+
+func TestSaveClusterConfigRetryOnConflict(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+	}
+	entry := &csi.CSIClusterConfigEntry{Namespace: namespace}
+
+	t.Run("conflicts are retried until success", func(t *testing.T) {
+		conflicts, gets := 0, 0
+		clientset.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			gets++
+			return false, nil, nil
+		})
+		clientset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if conflicts < 2 {
+				conflicts++
+				return true, nil, kerrors.NewConflict(v1.Resource("configmaps"), csi.ConfigName, errors.New("the object has been modified"))
+			}
+			return false, nil, nil
+		})
+
+		assert.NoError(t, r.saveClusterConfig("cluster-id", namespace, entry))
+		assert.Equal(t, 2, conflicts)
+		// the config map is read again on each attempt
+		assert.Equal(t, 3, gets)
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Contains(t, cm.Data[csi.ConfigKey], "cluster-id")
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		updates := 0
+		clientset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updates++
+			return true, nil, errors.New("failed to update")
+		})
+
+		assert.Error(t, r.saveClusterConfig("cluster-id", namespace, entry))
+		assert.Equal(t, 1, updates)
+	})
+}
+
+// TestMirrorMonitoringConcurrentKeys is meant to be run with the race detector, the reconciles of different
+// rados namespaces start and cancel their mirroring checkers concurrently
+func TestMirrorMonitoringConcurrentKeys(t *testing.T) {
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		opManagerContext:       context.TODO(),
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+	checkMirroring := func(ctx context.Context) { <-ctx.Done() }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(channelKey string) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				r.initMirrorMonitoring(channelKey)
+				assert.True(t, r.startMirrorMonitoring(channelKey, checkMirroring))
+				assert.False(t, r.startMirrorMonitoring(channelKey, checkMirroring))
+				assert.True(t, r.cancelMirrorMonitoring(channelKey))
+				assert.False(t, r.cancelMirrorMonitoring(channelKey))
+			}
+			r.initMirrorMonitoring(channelKey)
+			r.startMirrorMonitoring(channelKey, checkMirroring)
+		}(fmt.Sprintf("rook-ceph/replicapool/namespace-%d", i))
+	}
+	wg.Wait()
+	assert.Len(t, r.radosNamespaceContexts, 20)
+
+	r.stopAllMirrorMonitoring(5 * time.Second)
+	assert.Empty(t, r.radosNamespaceContexts)
+}
+
+func TestMirrorMonitoringChannelKey(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	assert.Equal(t, "replicapool/namespace-a", getPoolAndRadosNamespaceName(radosNamespace))
+	assert.Equal(t, "rook-ceph/replicapool/namespace-a", mirrorMonitoringChannelKey(radosNamespace))
+
+	named := radosNamespace.DeepCopy()
+	named.Spec.Name = "ns-a"
+	assert.Equal(t, "rook-ceph/replicapool/ns-a", mirrorMonitoringChannelKey(named))
+
+	implicit := radosNamespace.DeepCopy()
+	implicit.Spec.Name = cephv1.ImplicitNamespaceKey
+	assert.Equal(t, "replicapool", getPoolAndRadosNamespaceName(implicit))
+	assert.Equal(t, "rook-ceph/replicapool", mirrorMonitoringChannelKey(implicit))
+
+	t.Run("the checker started by the reconcile is cancelled on deletion", func(t *testing.T) {
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			opManagerContext:       context.TODO(),
+			radosNamespaceContexts: map[string]*mirrorHealth{},
+		}
+		startKey := mirrorMonitoringChannelKey(radosNamespace)
+		r.initMirrorMonitoring(startKey)
+		assert.True(t, r.startMirrorMonitoring(startKey, func(ctx context.Context) { <-ctx.Done() }))
+
+		deleting := radosNamespace.DeepCopy()
+		deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		deleting.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Phase: cephv1.ConditionDeleting}
+		cancelKey := mirrorMonitoringChannelKey(deleting)
+		assert.Equal(t, []byte(startKey), []byte(cancelKey))
+		assert.True(t, r.cancelMirrorMonitoring(cancelKey))
+		assert.Empty(t, r.radosNamespaceContexts)
+	})
+}
+
+func TestReconcileExistingRadosNamespace(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	createCalls := 0
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" {
+			createCalls++
+			return "rbd: failed to created namespace: (17) File exists", syscall.EEXIST
+		}
+		if args[0] == "mirror" && args[1] == "pool" {
+			return `{"mode":"disabled"}`, nil
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	cl := r.client
+	createTestCSIConfigMap(t, r, namespace)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+	res, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.False(t, res.Requeue)
+	assert.Equal(t, 1, createCalls)
+
+	err = cl.Get(ctx, req.NamespacedName, radosNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, cephv1.ConditionReady, radosNamespace.Status.Phase)
+}
+
+func TestReconcileErasureCodedPoolMirroring(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "ecpool",
+			Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModePool},
+		},
+	}
+	cephBlockPool := newTestCephBlockPool(namespace)
+	cephBlockPool.Name = "ecpool"
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.ErasureCoded.DataChunks = 2
+	cephBlockPool.Spec.ErasureCoded.CodingChunks = 1
+
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" {
+			t.Fatal("the rados namespace must not be created")
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), cephBlockPool)
+	cl := r.client
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+	t.Run("journal-based mirroring is rejected on an erasure coded pool", func(t *testing.T) {
+		_, err := r.Reconcile(ctx, req)
+		var unsupportedErr *PoolMirroringUnsupportedError
+		assert.ErrorAs(t, err, &unsupportedErr)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionFailure, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionFailure)
+		assert.NotNil(t, condition)
+		assert.Equal(t, cephv1.PoolMirroringUnsupportedReason, condition.Reason)
+		assert.Contains(t, condition.Message, "replicated pool")
+	})
+}
+
+func TestReconcileTargetCephCluster(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	newRadosNamespace := func(name, cephClusterName string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Generation: 1,
+				Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+			},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", CephClusterName: cephClusterName},
+		}
+	}
+	newCephCluster := func(name, health string) *cephv1.CephCluster {
+		return &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1},
+			Status: cephv1.ClusterStatus{
+				Phase:      cephv1.ConditionReady,
+				CephStatus: &cephv1.CephStatus{Health: health},
+			},
+		}
+	}
+	cephBlockPool := newTestCephBlockPool(namespace)
+	cephBlockPool.Spec.Replicated.Size = 3
+
+	r := newTestReconciler(t, namespace, nil,
+		newRadosNamespace("namespace-a", "cluster-a"),
+		newRadosNamespace("namespace-b", "cluster-b"),
+		newRadosNamespace("namespace-c", "cluster-c"),
+		newCephCluster("cluster-a", "HEALTH_ERR"),
+		newCephCluster("cluster-b", "HEALTH_OK"),
+		cephBlockPool,
+	)
+	cl := r.client
+	createTestCSIConfigMap(t, r, namespace)
+	reconcileRadosNamespace := func(name string) *cephv1.CephBlockPoolRadosNamespace {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		return current
+	}
+
+	t.Run("rados namespace of the healthy cluster is reconciled", func(t *testing.T) {
+		current := reconcileRadosNamespace("namespace-b")
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+		assert.Equal(t, "cluster-b", r.clusterInfo.NamespacedName().Name)
+		assert.Equal(t, buildClusterID(current), current.Status.Info["clusterID"])
+	})
+
+	t.Run("rados namespace of the unhealthy cluster waits", func(t *testing.T) {
+		current := reconcileRadosNamespace("namespace-a")
+		assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.Equal(t, cephv1.WaitingForCephClusterReason, condition.Reason)
+	})
+
+	t.Run("rados namespace of a missing cluster waits", func(t *testing.T) {
+		current := reconcileRadosNamespace("namespace-c")
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.Equal(t, cephv1.WaitingForCephClusterReason, condition.Reason)
+		assert.Contains(t, condition.Message, "waiting for a CephCluster to be created")
+	})
+}
+
+func TestReconcileBlockPoolNamespace(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "tenant-a"
+	poolNamespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", BlockPoolNamespace: poolNamespace},
+	}
+	cephBlockPool := newTestCephBlockPool(poolNamespace)
+	cephBlockPool.Spec.Replicated.Size = 3
+
+	var createdNamespace string
+	r := newTestReconciler(t, poolNamespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" {
+			createdNamespace = args[3] + "/" + args[5]
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(poolNamespace), cephBlockPool)
+	cl := r.client
+	c := r.context
+	createTestCSIConfigMap(t, r, poolNamespace)
+
+	// the pool of another namespace is not used unless the operator setting allows it
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: radosNamespace.Name, Namespace: namespace}}
+	_, err := r.Reconcile(ctx, req)
+	assert.ErrorContains(t, err, "are not allowed to reference the CephBlockPools")
+	assert.Empty(t, createdNamespace)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+	assert.Equal(t, cephv1.ConditionFailure, current.Status.Phase)
+
+	t.Setenv(crossNamespacePoolsSettingName, namespace+":"+poolNamespace)
+	_, err = r.Reconcile(ctx, req)
+
+	assert.NoError(t, err)
+
+	assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+	assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+	// the rados namespace is created in the pool of the CephCluster of the pool namespace
+	assert.Equal(t, "replicapool/namespace-a", createdNamespace)
+	assert.Equal(t, poolNamespace, r.clusterInfo.Namespace)
+
+	// the csi config entry belongs to the cluster of the pool namespace
+	entry, err := csi.GetClusterConfigEntry(c.Clientset, buildClusterID(current), r.clusterInfo)
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+	assert.Equal(t, poolNamespace, entry.Namespace)
+	assert.Equal(t, "namespace-a", entry.RBD.RadosNamespace)
+
+	// the CR is reconciled on the events of the pool
+	requests := radosNamespacesForPool(ctx, cl, cephBlockPool)
+	assert.Equal(t, []reconcile.Request{req}, requests)
+}
+
+// newTestCephCluster returns a ready CephCluster of the namespace running ceph v20
+func newTestCephCluster(namespace string) *cephv1.CephCluster {
+	return &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace, UID: "cluster-uid", Generation: 1},
+		Spec: cephv1.ClusterSpec{
+			CephVersion: cephv1.CephVersionSpec{Image: "ceph/ceph:v20.0.0"},
+		},
+		Status: cephv1.ClusterStatus{
+			Phase:       cephv1.ConditionReady,
+			CephStatus:  &cephv1.CephStatus{Health: "HEALTH_OK"},
+			CephVersion: &cephv1.ClusterVersion{Version: "20.0.0-0", Image: "ceph/ceph:v20.0.0"},
+		},
+	}
+}
+
+// newTestCephBlockPool returns the ready "replicapool" CephBlockPool of the namespace
+func newTestCephBlockPool(namespace string) *cephv1.CephBlockPool {
+	return &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: namespace, UID: "pool-uid", Generation: 1},
+		Status:     &cephv1.CephBlockPoolStatus{Phase: cephv1.ConditionReady},
+	}
+}
+
+// newTestReconciler returns a reconciler with a fake client of the objects, whose ceph commands are run by the
+// executor. The mon secret of the namespace is created so that the cluster info of the namespace can be loaded.
+func newTestReconciler(t *testing.T, namespace string, executor func(command string, args ...string) (string, error), objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+	ctx := context.TODO()
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{}, &cephv1.CephBlockPoolList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objects...).
+		WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, cephRNSNameIndex, indexRadosNamespaceName).
+		WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, blockPoolNameIndex, indexBlockPoolName).
+		WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, clusterIDIndex, indexClusterID).Build()
+
+	c := &clusterd.Context{
+		Executor:      &exectest.MockExecutor{MockExecuteCommandWithOutput: executor},
+		Clientset:     testop.New(t, 1),
+		RookClientset: rookclient.NewSimpleClientset(),
+		Client:        cl,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	return &ReconcileCephBlockPoolRadosNamespace{
+		client:                 cl,
+		scheme:                 s,
+		context:                c,
+		opManagerContext:       ctx,
+		opConfig:               opcontroller.OperatorConfig{Image: "ceph/ceph:v14.2.9"},
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+		recorder:               record.NewFakeRecorder(50),
+	}
+}
+
+// createTestCSIConfigMap creates the csi config map in the namespace, which is also the namespace of the operator
+func createTestCSIConfigMap(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace, namespace string) {
+	t.Setenv("POD_NAMESPACE", namespace)
+	err := csi.CreateCsiConfigMap(context.TODO(), namespace, r.context.Clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+}
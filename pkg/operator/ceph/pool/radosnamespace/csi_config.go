@@ -0,0 +1,220 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	cephcsi "github.com/ceph/ceph-csi/api/deploy/kubernetes"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// BuildRadosNamespaceCSIEntry returns the csi config entry of the rados namespace, without reading or writing
+// the csi config map. The mon endpoints are sorted so that an unchanged entry is detected regardless of the order
+// of the mons. If the mon endpoints change, the mon health check go routine takes care of updating the config
+// map, so no special care is needed in this controller. The mon endpoints of the rados namespace of an external
+// cluster override the mons of the cluster when set.
+func BuildRadosNamespaceCSIEntry(clusterInfo *cephclient.ClusterInfo, cephCluster cephv1.CephCluster, radosNamespace *cephv1.CephBlockPoolRadosNamespace) csi.CSIClusterConfigEntry {
+	var monitors []string
+	if external := radosNamespace.Spec.External; cephCluster.Spec.External.Enable && external != nil && len(external.Monitors) > 0 {
+		monitors = append(monitors, external.Monitors...)
+	} else {
+		monitors = csi.MonEndpoints(clusterInfo.AllMonitors(), cephCluster.Spec.RequireMsgr2())
+	}
+	sort.Strings(monitors)
+	entry := csi.CSIClusterConfigEntry{
+		Namespace: clusterInfo.Namespace,
+		ClusterInfo: cephcsi.ClusterInfo{
+			Monitors: monitors,
+			RBD: cephcsi.RBD{
+				RadosNamespace: cephv1.GetRadosNamespaceName(radosNamespace),
+			},
+			CephFS: cephcsi.CephFS{
+				KernelMountOptions: clusterInfo.CSIDriverSpec.CephFS.KernelMountOptions,
+				FuseMountOptions:   clusterInfo.CSIDriverSpec.CephFS.FuseMountOptions,
+			},
+			ReadAffinity: cephcsi.ReadAffinity{
+				Enabled:             clusterInfo.CSIDriverSpec.ReadAffinity.Enabled,
+				CrushLocationLabels: clusterInfo.CSIDriverSpec.ReadAffinity.CrushLocationLabels,
+			},
+		},
+		RBDMapOptions: csi.RBDMapOptions{
+			MapOptions:   radosNamespace.Spec.MapOptions,
+			UnmapOptions: radosNamespace.Spec.UnmapOptions,
+		},
+	}
+
+	// the read affinity of the rados namespace overrides the read affinity of the cluster, and is always written
+	// so that the entry reverts to the cluster settings once the override is removed
+	if csiSpec := radosNamespace.Spec.CSI; csiSpec != nil && csiSpec.ReadAffinity != nil {
+		entry.ReadAffinity = cephcsi.ReadAffinity{
+			Enabled:             csiSpec.ReadAffinity.Enabled,
+			CrushLocationLabels: csiSpec.ReadAffinity.CrushLocationLabels,
+		}
+	}
+	entry.KeepReadAffinity = true
+
+	return entry
+}
+
+// radosNamespacesOfCluster returns the rados namespaces of the pools of the cluster namespace, including the CRs
+// of other namespaces referencing a pool of the cluster namespace
+func radosNamespacesOfCluster(ctx context.Context, c client.Client, clusterNamespace string) ([]cephv1.CephBlockPoolRadosNamespace, error) {
+	cephRNSList := &cephv1.CephBlockPoolRadosNamespaceList{}
+	if err := c.List(ctx, cephRNSList); err != nil {
+		return nil, err
+	}
+	var radosNamespaces []cephv1.CephBlockPoolRadosNamespace
+	for i := range cephRNSList.Items {
+		if blockPoolNamespace(&cephRNSList.Items[i]) == clusterNamespace {
+			radosNamespaces = append(radosNamespaces, cephRNSList.Items[i])
+		}
+	}
+	return radosNamespaces, nil
+}
+
+// orphanedCSIConfigSweepInterval is how often the csi config entries of the deleted rados namespaces are swept
+var orphanedCSIConfigSweepInterval = time.Hour
+
+// sweepOrphanedClusterConfigs is run by the manager once the caches are synced. It removes the orphaned csi config
+// entries of all the clusters at startup and then periodically, until the manager stops.
+func (r *ReconcileCephBlockPoolRadosNamespace) sweepOrphanedClusterConfigs(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.removeAllOrphanedClusterConfigs, orphanedCSIConfigSweepInterval)
+	return nil
+}
+
+// removeAllOrphanedClusterConfigs removes the orphaned csi config entries of the namespaces of all the CephClusters
+func (r *ReconcileCephBlockPoolRadosNamespace) removeAllOrphanedClusterConfigs(ctx context.Context) {
+	cephClusters := &cephv1.CephClusterList{}
+	if err := r.client.List(ctx, cephClusters); err != nil {
+		logger.Warningf("failed to list the CephClusters to clean up the csi config of deleted rados namespaces. %v", err)
+		return
+	}
+	cephClusterNames := map[string][]string{}
+	for _, cephCluster := range cephClusters.Items {
+		cephClusterNames[cephCluster.Namespace] = append(cephClusterNames[cephCluster.Namespace], cephCluster.Name)
+	}
+	for clusterNamespace, names := range cephClusterNames {
+		if err := r.removeOrphanedClusterConfigs(ctx, clusterNamespace, names); err != nil {
+			logger.Warningf("failed to clean up the csi config of deleted rados namespaces in namespace %q. %v", clusterNamespace, err)
+		}
+	}
+}
+
+// removeOrphanedClusterConfigs removes the csi config entries of the rados namespaces that no longer have a
+// CephBlockPoolRadosNamespace CR in the cluster namespace. The entries are normally removed when the CR is
+// deleted, but they are left behind if the operator stops between the deletion and the csi config cleanup.
+// Only the entries with a cluster ID this controller generates for a pool of the cluster namespace are removed,
+// so the entries of other controllers, the cluster IDs set in the spec and the cluster IDs of the CRs in another
+// namespace than their pool are never matched.
+func (r *ReconcileCephBlockPoolRadosNamespace) removeOrphanedClusterConfigs(ctx context.Context, clusterNamespace string, cephClusterNames []string) error {
+	radosNamespaces, err := radosNamespacesOfCluster(ctx, r.client, clusterNamespace)
+	if err != nil {
+		return errors.Wrap(err, "failed to list cephBlockPoolRadosNamespace")
+	}
+	existing := make(map[string]bool, len(radosNamespaces))
+	for i := range radosNamespaces {
+		existing[buildClusterID(&radosNamespaces[i])] = true
+	}
+	cephBlockPools := &cephv1.CephBlockPoolList{}
+	if err := r.client.List(ctx, cephBlockPools, client.InNamespace(clusterNamespace)); err != nil {
+		return errors.Wrap(err, "failed to list cephBlockPools")
+	}
+
+	// the cluster IDs of the CRs in the cluster namespace are generated with and without the targeted CephCluster
+	prefixes := []string{clusterNamespace}
+	for _, name := range cephClusterNames {
+		prefixes = append(prefixes, fmt.Sprintf("%s-%s", clusterNamespace, name))
+	}
+	isOrphaned := func(clusterID, radosNamespaceName string) bool {
+		if existing[clusterID] {
+			return false
+		}
+		for _, cephBlockPool := range cephBlockPools.Items {
+			for _, prefix := range prefixes {
+				if generatedClusterID(prefix, cephBlockPool.Name, radosNamespaceName) == clusterID {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// the sweep runs concurrently with the reconciles, so it does not share the cluster info of the reconciler
+	clusterInfo := &cephclient.ClusterInfo{Namespace: clusterNamespace, Context: ctx}
+	var removed []string
+	err = retry.RetryOnConflict(csiConfigRetry, func() error {
+		removed, err = csi.RemoveOrphanedRadosNamespaceConfigs(r.context.Clientset, clusterNamespace, clusterInfo, isOrphaned)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to remove orphaned csi config entries")
+	}
+	if len(removed) > 0 {
+		logger.Infof("removed orphaned csi config entries of deleted rados namespaces in namespace %q with cluster IDs %v", clusterNamespace, removed)
+	}
+	return nil
+}
+
+// radosNamespacesForCluster returns the requests to reconcile all the rados namespaces of the CephCluster
+func radosNamespacesForCluster(ctx context.Context, c client.Client, cephCluster *cephv1.CephCluster) []reconcile.Request {
+	radosNamespaces, err := radosNamespacesOfCluster(ctx, c, cephCluster.Namespace)
+	if err != nil {
+		logger.Errorf("failed to list CephBlockPoolRadosNamespace(s) while handling event for CephCluster %q in namespace %q. %v", cephCluster.Name, cephCluster.Namespace, err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(radosNamespaces))
+	for i, item := range radosNamespaces {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace},
+		}
+	}
+	return requests
+}
+
+// msgr2RequirementChangedPredicate triggers a reconcile of the rados namespaces when the CephCluster starts or
+// stops requiring msgr2, so that the mon endpoints of their csi config entries are rendered with the new port
+func msgr2RequirementChangedPredicate() predicate.TypedFuncs[*cephv1.CephCluster] {
+	return predicate.TypedFuncs[*cephv1.CephCluster]{
+		CreateFunc: func(e event.TypedCreateEvent[*cephv1.CephCluster]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*cephv1.CephCluster]) bool {
+			return e.ObjectOld.Spec.RequireMsgr2() != e.ObjectNew.Spec.RequireMsgr2()
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*cephv1.CephCluster]) bool {
+			return false
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*cephv1.CephCluster]) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,521 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	cephcsi "github.com/ceph/ceph-csi/api/deploy/kubernetes"
+	"github.com/coreos/pkg/capnslog"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRemoveOrphanedClusterConfigs(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	newRadosNamespace := func(name string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+	}
+	existing := newRadosNamespace("namespace-a")
+	deleted := newRadosNamespace("namespace-b")
+	deletedOfCluster := newRadosNamespace("namespace-c")
+	deletedOfCluster.Spec.CephClusterName = "my-cluster"
+	// the cluster IDs that are not generated for a pool of the cluster namespace are never swept
+	overridden := newRadosNamespace("namespace-d")
+	overridden.Spec.ClusterID = "my-cluster-id"
+	otherPoolNamespace := newRadosNamespace("namespace-e")
+	otherPoolNamespace.Spec.BlockPoolNamespace = "other-namespace"
+	unknownPool := newRadosNamespace("namespace-f")
+	unknownPool.Spec.BlockPoolName = "unknown-pool"
+
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: namespace}}
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(existing, cephBlockPool).Build(),
+		context:          &clusterd.Context{Clientset: clientset},
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+		opManagerContext: ctx,
+	}
+
+	// the csi config of all the rados namespaces is saved, but only one CR still exists
+	all := []*cephv1.CephBlockPoolRadosNamespace{existing, deleted, deletedOfCluster, overridden, otherPoolNamespace, unknownPool}
+	for _, radosNamespace := range all {
+		entry := &csi.CSIClusterConfigEntry{Namespace: namespace}
+		entry.RBD.RadosNamespace = radosNamespace.Name
+		assert.NoError(t, r.saveClusterConfig(buildClusterID(radosNamespace), namespace, entry))
+	}
+
+	assert.NoError(t, r.removeOrphanedClusterConfigs(ctx, namespace, []string{"my-cluster"}))
+
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	for _, radosNamespace := range []*cephv1.CephBlockPoolRadosNamespace{existing, overridden, otherPoolNamespace, unknownPool} {
+		assert.Contains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace), radosNamespace.Name)
+	}
+	assert.NotContains(t, cm.Data[csi.ConfigKey], buildClusterID(deleted))
+	assert.NotContains(t, cm.Data[csi.ConfigKey], buildClusterID(deletedOfCluster))
+}
+
+func TestUpdateClusterConfigMapOptions(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: namespace})
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			MapOptions:    "lock_on_read,queue_depth=1024",
+			UnmapOptions:  "force",
+		},
+	}
+	cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+
+	getRBDSection := func() map[string]interface{} {
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		var entries []map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(cm.Data[csi.ConfigKey]), &entries))
+		assert.Len(t, entries, 1)
+		return entries[0]["rbd"].(map[string]interface{})
+	}
+
+	// the options are written into the rbd section of the entry
+	_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	rbd := getRBDSection()
+	assert.Equal(t, "namespace-a", rbd["radosNamespace"])
+	assert.Equal(t, "lock_on_read,queue_depth=1024", rbd["mapOptions"])
+	assert.Equal(t, "force", rbd["unmapOptions"])
+
+	// the options are removed when cleared
+	radosNamespace.Spec.MapOptions = ""
+	radosNamespace.Spec.UnmapOptions = ""
+	_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	rbd = getRBDSection()
+	assert.Equal(t, "namespace-a", rbd["radosNamespace"])
+	assert.NotContains(t, rbd, "mapOptions")
+	assert.NotContains(t, rbd, "unmapOptions")
+}
+
+func TestUpdateClusterConfigReadAffinity(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: namespace})
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, Context: ctx}
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: clusterInfo,
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+	getReadAffinity := func() cephcsi.ReadAffinity {
+		entry, err := csi.GetClusterConfigEntry(clientset, buildClusterID(radosNamespace), clusterInfo)
+		assert.NoError(t, err)
+		assert.NotNil(t, entry)
+		return entry.ReadAffinity
+	}
+
+	t.Run("cluster defaults", func(t *testing.T) {
+		clusterInfo.CSIDriverSpec.ReadAffinity = cephv1.ReadAffinitySpec{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/zone"}}
+		_, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.Equal(t, cephcsi.ReadAffinity{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/zone"}}, getReadAffinity())
+	})
+
+	t.Run("override takes precedence", func(t *testing.T) {
+		radosNamespace.Spec.CSI = &cephv1.RadosNamespaceCSISpec{
+			ReadAffinity: &cephv1.ReadAffinitySpec{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/rack"}},
+		}
+		changed, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, cephcsi.ReadAffinity{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/rack"}}, getReadAffinity())
+
+		// the override is kept when the entry is saved again
+		changed, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("override disables read affinity", func(t *testing.T) {
+		radosNamespace.Spec.CSI.ReadAffinity = &cephv1.ReadAffinitySpec{Enabled: false}
+		_, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.False(t, getReadAffinity().Enabled)
+		assert.Empty(t, getReadAffinity().CrushLocationLabels)
+	})
+
+	t.Run("removed override reverts to cluster defaults", func(t *testing.T) {
+		radosNamespace.Spec.CSI = nil
+		_, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.Equal(t, cephcsi.ReadAffinity{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/zone"}}, getReadAffinity())
+	})
+
+	t.Run("removed override reverts to disabled cluster read affinity", func(t *testing.T) {
+		radosNamespace.Spec.CSI = &cephv1.RadosNamespaceCSISpec{
+			ReadAffinity: &cephv1.ReadAffinitySpec{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/rack"}},
+		}
+		clusterInfo.CSIDriverSpec.ReadAffinity = cephv1.ReadAffinitySpec{}
+		_, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.True(t, getReadAffinity().Enabled)
+
+		radosNamespace.Spec.CSI = nil
+		_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.False(t, getReadAffinity().Enabled)
+		assert.Empty(t, getReadAffinity().CrushLocationLabels)
+	})
+}
+
+func TestUpdateClusterConfigClusterID(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: namespace})
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", ClusterID: "tenant-a"},
+	}
+	cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+
+	// the entry is written with the cluster ID of the spec
+	_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	var entries []map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(cm.Data[csi.ConfigKey]), &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "tenant-a", entries[0]["clusterID"])
+}
+
+func TestUpdateClusterConfigMsgr2(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: namespace})
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context: &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{
+			Namespace:        namespace,
+			Context:          ctx,
+			InternalMonitors: map[string]*cephclient.MonInfo{"a": {Name: "a", Endpoint: "10.0.0.1:6789"}},
+		},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+
+	getMonitors := func() []interface{} {
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		var entries []map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(cm.Data[csi.ConfigKey]), &entries))
+		assert.Len(t, entries, 1)
+		return entries[0]["monitors"].([]interface{})
+	}
+
+	_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"10.0.0.1:6789"}, getMonitors())
+
+	// requiring msgr2 renders the endpoints with the msgr2 port
+	cephCluster.Spec.Network.Connections = &cephv1.ConnectionsSpec{RequireMsgr2: true}
+	_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"10.0.0.1:3300"}, getMonitors())
+
+	// and back to the msgr1 port when msgr2 is no longer required
+	cephCluster.Spec.Network.Connections.RequireMsgr2 = false
+	_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"10.0.0.1:6789"}, getMonitors())
+}
+
+func TestMsgr2RequirementChangedPredicate(t *testing.T) {
+	namespace := "rook-ceph"
+	msgr1Cluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+	msgr2Cluster := msgr1Cluster.DeepCopy()
+	msgr2Cluster.Spec.Network.Connections = &cephv1.ConnectionsSpec{RequireMsgr2: true}
+	otherChange := msgr1Cluster.DeepCopy()
+	otherChange.Spec.DataDirHostPath = "/var/lib/other"
+
+	p := msgr2RequirementChangedPredicate()
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephCluster]{ObjectOld: msgr1Cluster, ObjectNew: msgr2Cluster}))
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephCluster]{ObjectOld: msgr2Cluster, ObjectNew: msgr1Cluster}))
+	assert.False(t, p.Update(event.TypedUpdateEvent[*cephv1.CephCluster]{ObjectOld: msgr1Cluster, ObjectNew: otherChange}))
+	assert.False(t, p.Create(event.TypedCreateEvent[*cephv1.CephCluster]{Object: msgr2Cluster}))
+
+	// all the rados namespaces of the cluster are reconciled
+	objects := []runtime.Object{
+		&cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace}},
+		&cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-b", Namespace: namespace}},
+		&cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-c", Namespace: "other-cluster"}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).Build()
+	requests := radosNamespacesForCluster(context.TODO(), cl, msgr2Cluster)
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}},
+		{NamespacedName: types.NamespacedName{Name: "namespace-b", Namespace: namespace}},
+	}, requests)
+}
+
+func TestUpdateClusterConfigNetNamespaceFilePathWarning(t *testing.T) {
+	logBuf := &bytes.Buffer{}
+	capnslog.SetFormatter(capnslog.NewLogFormatter(logBuf, "", 0))
+	defer capnslog.SetFormatter(capnslog.NewPrettyFormatter(os.Stderr, false))
+
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: namespace})
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context:     &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", ClusterID: "tenant-a"},
+	}
+	cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+	const warning = "clearing the rbd net namespace file path"
+
+	t.Run("no previous entry", func(t *testing.T) {
+		_, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.NotContains(t, logBuf.String(), warning)
+	})
+
+	t.Run("previous entry without a path", func(t *testing.T) {
+		_, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.NotContains(t, logBuf.String(), warning)
+	})
+
+	t.Run("previous entry with a path", func(t *testing.T) {
+		cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		cm.Data[csi.ConfigKey] = `[{"clusterID":"tenant-a","monitors":[],"rbd":{"netNamespaceFilePath":"/var/run/netns/tenant-a","radosNamespace":"namespace-a"},"namespace":"rook-ceph"}]`
+		_, err = clientset.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+
+		_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.Contains(t, logBuf.String(), `clearing the rbd net namespace file path "/var/run/netns/tenant-a" of the csi config entry "tenant-a"`)
+
+		// the path is cleared, so the next update does not warn again
+		logBuf.Reset()
+		_, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.NotContains(t, logBuf.String(), warning)
+	})
+}
+
+func TestUpdateClusterConfigChanged(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: namespace})
+	t.Setenv("POD_NAMESPACE", namespace)
+	clientset := k8sfake.NewSimpleClientset()
+	err := csi.CreateCsiConfigMap(ctx, namespace, clientset, k8sutil.NewOwnerInfoWithOwnerRef(&metav1.OwnerReference{}, ""))
+	assert.NoError(t, err)
+	writes := 0
+	clientset.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		writes++
+		return false, nil, nil
+	})
+
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context: &clusterd.Context{Clientset: clientset},
+		clusterInfo: &cephclient.ClusterInfo{
+			Namespace:        namespace,
+			Context:          ctx,
+			InternalMonitors: map[string]*cephclient.MonInfo{"a": {Name: "a", Endpoint: "10.0.0.1:6789"}},
+		},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: namespace}}
+
+	changed, err := r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, writes)
+
+	// a no-op reconcile does not write the config map
+	changed, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, 1, writes)
+
+	radosNamespace.Spec.MapOptions = "lock_on_read"
+	changed, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, writes)
+
+	r.clusterInfo.InternalMonitors["b"] = &cephclient.MonInfo{Name: "b", Endpoint: "10.0.0.2:6789"}
+	changed, err = r.updateClusterConfig(radosNamespace, cephCluster, log)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 3, writes)
+}
+
+func TestBuildRadosNamespaceCSIEntry(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			MapOptions:    "krbd:queue_depth=1024",
+			UnmapOptions:  "krbd:force",
+		},
+	}
+	newClusterInfo := func() *cephclient.ClusterInfo {
+		clusterInfo := &cephclient.ClusterInfo{
+			Namespace: "rook-ceph",
+			InternalMonitors: map[string]*cephclient.MonInfo{
+				"b": {Name: "b", Endpoint: "10.0.0.2:6789"},
+				"a": {Name: "a", Endpoint: "10.0.0.1:6789"},
+			},
+		}
+		clusterInfo.CSIDriverSpec.CephFS.KernelMountOptions = "ms_mode=secure"
+		clusterInfo.CSIDriverSpec.ReadAffinity = cephv1.ReadAffinitySpec{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/zone"}}
+		return clusterInfo
+	}
+
+	t.Run("internal cluster", func(t *testing.T) {
+		clusterInfo := newClusterInfo()
+		clusterInfo.ExternalMons = map[string]*cephclient.MonInfo{"c": {Name: "c", Endpoint: "10.0.1.1:6789"}}
+		cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"}}
+		cephCluster.Spec.Network.Connections = &cephv1.ConnectionsSpec{RequireMsgr2: true}
+
+		entry := BuildRadosNamespaceCSIEntry(clusterInfo, cephCluster, radosNamespace)
+		assert.Equal(t, "rook-ceph", entry.Namespace)
+		assert.Equal(t, []string{"10.0.0.1:3300", "10.0.0.2:3300", "10.0.1.1:3300"}, entry.Monitors)
+		assert.Equal(t, "namespace-a", entry.RBD.RadosNamespace)
+		assert.Equal(t, "ms_mode=secure", entry.CephFS.KernelMountOptions)
+		assert.Equal(t, csi.RBDMapOptions{MapOptions: "krbd:queue_depth=1024", UnmapOptions: "krbd:force"}, entry.RBDMapOptions)
+		assert.Equal(t, cephcsi.ReadAffinity{Enabled: true, CrushLocationLabels: []string{"topology.kubernetes.io/zone"}}, entry.ReadAffinity)
+		assert.True(t, entry.KeepReadAffinity)
+	})
+
+	t.Run("external cluster", func(t *testing.T) {
+		cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"}}
+		cephCluster.Spec.External.Enable = true
+		external := radosNamespace.DeepCopy()
+		external.Spec.Name = "tenant-a"
+		external.Spec.CSI = &cephv1.RadosNamespaceCSISpec{ReadAffinity: &cephv1.ReadAffinitySpec{Enabled: false}}
+
+		entry := BuildRadosNamespaceCSIEntry(newClusterInfo(), cephCluster, external)
+		assert.Equal(t, []string{"10.0.0.1:6789", "10.0.0.2:6789"}, entry.Monitors)
+		assert.Equal(t, "tenant-a", entry.RBD.RadosNamespace)
+		assert.Equal(t, cephcsi.ReadAffinity{}, entry.ReadAffinity)
+		assert.True(t, entry.KeepReadAffinity)
+		assert.Empty(t, entry.RBD.NetNamespaceFilePath)
+	})
+
+	t.Run("external monitors override", func(t *testing.T) {
+		cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"}}
+		cephCluster.Spec.External.Enable = true
+		external := radosNamespace.DeepCopy()
+		external.Spec.External = &cephv1.RadosNamespaceExternalSpec{Monitors: []string{"192.168.10.2:3300", "192.168.10.1:3300"}}
+
+		entry := BuildRadosNamespaceCSIEntry(newClusterInfo(), cephCluster, external)
+		assert.Equal(t, []string{"192.168.10.1:3300", "192.168.10.2:3300"}, entry.Monitors)
+		// the spec is not reordered
+		assert.Equal(t, "192.168.10.2:3300", external.Spec.External.Monitors[0])
+
+		// the mons of the cluster are used when the override is empty
+		external.Spec.External.Monitors = nil
+		entry = BuildRadosNamespaceCSIEntry(newClusterInfo(), cephCluster, external)
+		assert.Equal(t, []string{"10.0.0.1:6789", "10.0.0.2:6789"}, entry.Monitors)
+	})
+
+	t.Run("external monitors of an internal cluster", func(t *testing.T) {
+		cephCluster := cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph", Namespace: "rook-ceph"}}
+		internal := radosNamespace.DeepCopy()
+		internal.Spec.External = &cephv1.RadosNamespaceExternalSpec{Monitors: []string{"192.168.10.1:3300"}}
+
+		entry := BuildRadosNamespaceCSIEntry(newClusterInfo(), cephCluster, internal)
+		assert.Equal(t, []string{"10.0.0.1:6789", "10.0.0.2:6789"}, entry.Monitors)
+	})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const csiOperatorNotReadyEventReason = "CSIOperatorNotReady"
+
+// clientProfileMetadataPrefixesSettingName is the operator setting with the comma separated prefixes of the labels
+// and annotations of the rados namespace that are copied onto its csi operator client profile
+const clientProfileMetadataPrefixesSettingName = "ROOK_RADOS_NAMESPACE_CLIENT_PROFILE_METADATA_PREFIXES"
+
+// waitForRequeueIfCSIOperatorNotReady waits for the CRDs of the csi operator to be installed
+var waitForRequeueIfCSIOperatorNotReady = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+
+// reconcileClientProfile creates or updates the csi operator client profile of the rados namespace. The rados
+// namespace is reported as progressing instead of failing the reconcile while the CRDs of the csi operator are
+// not installed, and the result requeues the reconcile until they are.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileClientProfile(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster, name types.NamespacedName, log *reconcileLogger) (reconcile.Result, error) {
+	prefixes := clientProfileMetadataPrefixes()
+	labels, annotations := clientProfileMetadata(radosNamespace, prefixes)
+	err := csi.CreateUpdateClientProfileRadosNamespace(r.clusterInfo.Context, r.client, r.clusterInfo, cephv1.GetRadosNamespaceName(radosNamespace), buildClusterID(radosNamespace), cephCluster.Name, prefixes, labels, annotations)
+	if err == nil {
+		return reconcile.Result{}, nil
+	}
+	if !meta.IsNoMatchError(err) {
+		return reconcile.Result{}, errors.Wrap(err, "failed to create ceph csi-op config CR for RadosNamespace")
+	}
+
+	message := fmt.Sprintf("the csi operator CRDs are not installed, the csi operator client profile of the rados namespace is created once they are. %v", err)
+	log.Warningf("%s", message)
+	r.recorder.Event(radosNamespace, v1.EventTypeWarning, csiOperatorNotReadyEventReason, message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.CSIOperatorNotReadyReason,
+		Message: message,
+	})
+	return waitForRequeueIfCSIOperatorNotReady, nil
+}
+
+// clientProfileMetadataPrefixes returns the prefixes of the labels and annotations of the rados namespaces that are
+// copied onto their csi operator client profiles
+func clientProfileMetadataPrefixes() []string {
+	var prefixes []string
+	for _, prefix := range strings.Split(k8sutil.GetOperatorSetting(clientProfileMetadataPrefixesSettingName, csi.ClientProfileMetadataPrefix), ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// clientProfileMetadata returns the labels and annotations of the rados namespace having one of the prefixes
+func clientProfileMetadata(radosNamespace *cephv1.CephBlockPoolRadosNamespace, prefixes []string) (map[string]string, map[string]string) {
+	filter := func(metadata map[string]string) map[string]string {
+		filtered := map[string]string{}
+		for key, value := range metadata {
+			if csi.HasMetadataPrefix(key, prefixes) {
+				filtered[key] = value
+			}
+		}
+		return filtered
+	}
+	return filter(radosNamespace.GetLabels()), filter(radosNamespace.GetAnnotations())
+}
+
+// clientProfileMetadataFingerprint returns the labels and annotations of the rados namespace that are copied onto
+// its csi operator client profile, in a comparable form
+func clientProfileMetadataFingerprint(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	labels, annotations := clientProfileMetadata(radosNamespace, clientProfileMetadataPrefixes())
+	// the maps are printed with sorted keys
+	return fmt.Sprintf("labels:%v annotations:%v", labels, annotations)
+}
+
+// clientProfileMetadataChangedPredicate triggers a reconcile when the labels or annotations copied onto the csi
+// operator client profile are changed, since metadata changes are otherwise ignored by the controller predicate
+func clientProfileMetadataChangedPredicate() predicate.TypedFuncs[*cephv1.CephBlockPoolRadosNamespace] {
+	return predicate.TypedFuncs[*cephv1.CephBlockPoolRadosNamespace]{
+		CreateFunc: func(e event.TypedCreateEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return clientProfileMetadataFingerprint(e.ObjectOld) != clientProfileMetadataFingerprint(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+	}
+}
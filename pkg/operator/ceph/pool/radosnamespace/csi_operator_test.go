@@ -0,0 +1,211 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	csiopv1a1 "github.com/ceph/ceph-csi-operator/api/v1alpha1"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileClientProfile(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: name.Namespace}}
+	t.Setenv("POD_NAMESPACE", name.Namespace)
+
+	// the error returned when getting the client profile, which is created if nil
+	var getErr error
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &csiopv1a1.ClientProfile{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(radosNamespace).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*csiopv1a1.ClientProfile); ok && getErr != nil {
+					return getErr
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+	recorder := record.NewFakeRecorder(5)
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           cl,
+		scheme:           s,
+		opManagerContext: ctx,
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		recorder:         recorder,
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("progressing while the csi operator CRDs are not installed", func(t *testing.T) {
+		getErr = &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "csi.ceph.io", Kind: "ClientProfile"}}
+		res, err := r.reconcileClientProfile(radosNamespace, cephCluster, name, log)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfCSIOperatorNotReady, res)
+		assert.Contains(t, <-recorder.Events, csiOperatorNotReadyEventReason)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, current))
+		assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.CSIOperatorNotReadyReason, condition.Reason)
+	})
+
+	t.Run("other errors fail the reconcile", func(t *testing.T) {
+		getErr = errors.New("connection refused")
+		res, err := r.reconcileClientProfile(radosNamespace, cephCluster, name, log)
+		assert.ErrorContains(t, err, "connection refused")
+		assert.True(t, res.IsZero())
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("client profile is created once the csi operator CRDs are installed", func(t *testing.T) {
+		getErr = nil
+		res, err := r.reconcileClientProfile(radosNamespace, cephCluster, name, log)
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+
+		clientProfile := &csiopv1a1.ClientProfile{}
+		assert.NoError(t, cl.Get(ctx, types.NamespacedName{Name: buildClusterID(radosNamespace), Namespace: name.Namespace}, clientProfile))
+		assert.Equal(t, "namespace-a", clientProfile.Spec.Rbd.RadosNamespace)
+	})
+}
+
+func TestClientProfileMetadata(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "namespace-a",
+			Namespace:   "rook-ceph",
+			Labels:      map[string]string{csi.ClientProfileMetadataPrefix + "team": "storage", "example.com/cost-center": "1234", "app": "db"},
+			Annotations: map[string]string{csi.ClientProfileMetadataPrefix + "owner": "alice", forceReconcileAnnotation: "1"},
+		},
+	}
+
+	labels, annotations := clientProfileMetadata(radosNamespace, clientProfileMetadataPrefixes())
+	assert.Equal(t, map[string]string{csi.ClientProfileMetadataPrefix + "team": "storage"}, labels)
+	assert.Equal(t, map[string]string{csi.ClientProfileMetadataPrefix + "owner": "alice"}, annotations)
+
+	t.Setenv(clientProfileMetadataPrefixesSettingName, "example.com/, ,"+csi.ClientProfileMetadataPrefix)
+	assert.Equal(t, []string{"example.com/", csi.ClientProfileMetadataPrefix}, clientProfileMetadataPrefixes())
+	labels, _ = clientProfileMetadata(radosNamespace, clientProfileMetadataPrefixes())
+	assert.Equal(t, map[string]string{csi.ClientProfileMetadataPrefix + "team": "storage", "example.com/cost-center": "1234"}, labels)
+
+	t.Run("predicate", func(t *testing.T) {
+		p := clientProfileMetadataChangedPredicate()
+		updated := radosNamespace.DeepCopy()
+		updated.Labels["app"] = "web"
+		assert.False(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: radosNamespace, ObjectNew: updated}))
+		delete(updated.Labels, "example.com/cost-center")
+		assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: radosNamespace, ObjectNew: updated}))
+		updated = radosNamespace.DeepCopy()
+		updated.Annotations[csi.ClientProfileMetadataPrefix+"owner"] = "bob"
+		assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: radosNamespace, ObjectNew: updated}))
+	})
+}
+
+func TestReconcileClientProfileMetadata(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	label := csi.ClientProfileMetadataPrefix + "team"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	// the client profiles are created with the csi operator
+	scheme.Scheme.AddKnownTypes(cephv1.SchemeGroupVersion, &csiopv1a1.ClientProfile{})
+	var cephCommands []string
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		cephCommands = append(cephCommands, strings.Join(args, " "))
+		if args[0] == "mirror" && args[1] == "pool" {
+			return `{"mode":"disabled"}`, nil
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	cl := r.client
+	t.Setenv("POD_NAMESPACE", namespace)
+	csi.SetEnableCSIOperator(true)
+	defer csi.SetEnableCSIOperator(false)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+	profileName := types.NamespacedName{Name: buildClusterID(radosNamespace), Namespace: namespace}
+	setLabels := func(labels map[string]string) {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		current.Labels = labels
+		assert.NoError(t, cl.Update(ctx, current))
+	}
+
+	_, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	clientProfile := &csiopv1a1.ClientProfile{}
+	assert.NoError(t, cl.Get(ctx, profileName, clientProfile))
+	assert.Empty(t, clientProfile.Labels)
+
+	t.Run("added label is copied onto the client profile", func(t *testing.T) {
+		setLabels(map[string]string{label: "storage", "app": "db"})
+		cephCommands = nil
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		// only the metadata changed, no ceph command is needed
+		assert.Empty(t, cephCommands)
+
+		clientProfile := &csiopv1a1.ClientProfile{}
+		assert.NoError(t, cl.Get(ctx, profileName, clientProfile))
+		assert.Equal(t, map[string]string{label: "storage"}, clientProfile.Labels)
+	})
+
+	t.Run("removed label is removed from the client profile", func(t *testing.T) {
+		setLabels(map[string]string{"app": "db"})
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+
+		clientProfile := &csiopv1a1.ClientProfile{}
+		assert.NoError(t, cl.Get(ctx, profileName, clientProfile))
+		assert.Empty(t, clientProfile.Labels)
+	})
+}
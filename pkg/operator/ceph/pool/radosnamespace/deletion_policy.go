@@ -0,0 +1,44 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// radosNamespaceRetainedEventReason is the reason of the event recorded when the rados namespace of a deleted CR
+// is retained in ceph
+const radosNamespaceRetainedEventReason = "RadosNamespaceRetained"
+
+// isRetainedOnDeletion returns whether the rados namespace is kept in ceph when the CR is deleted
+func isRetainedOnDeletion(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.Spec.DeletionPolicy == cephv1.RadosNamespaceDeletionPolicyRetain
+}
+
+// retainRadosNamespace releases the rados namespace of a deleted CR without deleting it from ceph, its images
+// are neither checked nor removed. The mirroring of the rados namespace is no longer monitored.
+func (r *ReconcileCephBlockPoolRadosNamespace) retainRadosNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) {
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	log.Infof("retaining rados namespace %q and its data in the ceph cluster since the deletion policy of %q is %q, delete it manually if needed",
+		poolAndRadosNamespaceName, radosNamespace.Name, cephv1.RadosNamespaceDeletionPolicyRetain)
+	r.recorder.Event(radosNamespace, v1.EventTypeNormal, radosNamespaceRetainedEventReason,
+		fmt.Sprintf("rados namespace %q and its data are retained in the ceph cluster", poolAndRadosNamespaceName))
+	r.cancelMirrorMonitoring(mirrorMonitoringChannelKey(radosNamespace))
+}
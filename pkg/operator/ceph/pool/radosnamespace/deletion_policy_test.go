@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDeletionPolicy(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	tests := []struct {
+		name          string
+		policy        cephv1.RadosNamespaceDeletionPolicy
+		imageCount    int
+		expectRemoved bool
+		expectDeleted bool
+	}{
+		{name: "empty rados namespace is deleted by default", expectRemoved: true, expectDeleted: true},
+		{name: "empty rados namespace is deleted", policy: cephv1.RadosNamespaceDeletionPolicyDelete, expectRemoved: true, expectDeleted: true},
+		{name: "rados namespace with images blocks the deletion", policy: cephv1.RadosNamespaceDeletionPolicyDelete, imageCount: 2},
+		{name: "rados namespace with images is retained", policy: cephv1.RadosNamespaceDeletionPolicyRetain, imageCount: 2, expectDeleted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := metav1.Now()
+			radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "namespace-a",
+					Namespace:         namespace,
+					Finalizers:        []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+					DeletionTimestamp: &now,
+				},
+				TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+				Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+					BlockPoolName:  "replicapool",
+					DeletionPolicy: tt.policy,
+				},
+			}
+			var cephCommands []string
+			namespaceRemoved := false
+			r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+				cephCommands = append(cephCommands, strings.Join(args, " "))
+				if args[0] == "pool" && args[1] == "stats" {
+					return fmt.Sprintf(`{"images":{"count":%d,"snap_count":0}}`, tt.imageCount), nil
+				}
+				if args[0] == "namespace" && args[1] == "remove" {
+					namespaceRemoved = true
+				}
+				return "", nil
+			}, radosNamespace, newTestCephCluster(namespace))
+			cl := r.client
+			c := r.context
+			recorder := r.recorder.(*record.FakeRecorder)
+
+			// Create the CSI config map with an entry for the rados namespace
+			createTestCSIConfigMap(t, r, namespace)
+			clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, Context: ctx}
+			err := csi.SaveClusterConfig(c.Clientset, buildClusterID(radosNamespace), namespace, clusterInfo, &csi.CSIClusterConfigEntry{Namespace: namespace})
+			assert.NoError(t, err)
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+			_, err = r.Reconcile(ctx, req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectRemoved, namespaceRemoved)
+
+			cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			err = cl.Get(ctx, req.NamespacedName, &cephv1.CephBlockPoolRadosNamespace{})
+			if tt.expectDeleted {
+				// the finalizer is removed and the csi config is cleaned up
+				assert.True(t, kerrors.IsNotFound(err))
+				assert.NotContains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace))
+			} else {
+				// the deletion is blocked by the images
+				assert.NoError(t, err)
+				assert.Contains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace))
+			}
+
+			if tt.policy == cephv1.RadosNamespaceDeletionPolicyRetain {
+				// the images of the retained rados namespace are neither checked nor removed
+				for _, command := range cephCommands {
+					assert.NotContains(t, command, "pool stats")
+					assert.NotContains(t, command, "namespace remove")
+				}
+				assert.Contains(t, <-recorder.Events, "Normal "+radosNamespaceRetainedEventReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+// cephApplicationMetadata returns the application metadata of the rados namespace to store in ceph, which
+// includes its description. The description is removed from ceph with the other keys once it is cleared.
+func cephApplicationMetadata(radosNamespace *cephv1.CephBlockPoolRadosNamespace) map[string]string {
+	if radosNamespace.Spec.Description == "" {
+		return radosNamespace.Spec.ApplicationMetadata
+	}
+	metadata := make(map[string]string, len(radosNamespace.Spec.ApplicationMetadata)+1)
+	for key, value := range radosNamespace.Spec.ApplicationMetadata {
+		metadata[key] = value
+	}
+	metadata[cephv1.RadosNamespaceDescriptionKey] = radosNamespace.Spec.Description
+	return metadata
+}
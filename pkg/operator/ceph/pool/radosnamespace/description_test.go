@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCephApplicationMetadata(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{ApplicationMetadata: map[string]string{"team": "storage"}},
+	}
+	assert.Equal(t, map[string]string{"team": "storage"}, cephApplicationMetadata(radosNamespace))
+
+	radosNamespace.Spec.Description = "volumes of tenant a"
+	assert.Equal(t, map[string]string{"team": "storage", "description": "volumes of tenant a"}, cephApplicationMetadata(radosNamespace))
+	// the spec is not modified
+	assert.Equal(t, map[string]string{"team": "storage"}, radosNamespace.Spec.ApplicationMetadata)
+
+	radosNamespace.Spec.ApplicationMetadata = nil
+	assert.Equal(t, map[string]string{"description": "volumes of tenant a"}, cephApplicationMetadata(radosNamespace))
+}
+
+func TestDescriptionMetadataCommands(t *testing.T) {
+	// the rbd application metadata of the pool, updated by the set and rm commands
+	poolMetadata := map[string]string{}
+	var commands []string
+	c := &clusterd.Context{
+		Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] != "osd" || args[1] != "pool" || args[2] != "application" {
+					return "", nil
+				}
+				switch args[3] {
+				case "get":
+					output, err := json.Marshal(poolMetadata)
+					return string(output), err
+				case "set":
+					poolMetadata[args[6]] = args[7]
+					commands = append(commands, strings.Join(args[3:8], " "))
+				case "rm":
+					delete(poolMetadata, args[6])
+					commands = append(commands, strings.Join(args[3:7], " "))
+				}
+				return "", nil
+			},
+		},
+	}
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "rook-ceph", Context: context.TODO()}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:       "replicapool",
+			ApplicationMetadata: map[string]string{"team": "storage"},
+		},
+	}
+	setMetadata := func(t *testing.T) {
+		commands = nil
+		err := cephclient.SetRadosNamespaceApplicationMetadata(c, clusterInfo, "replicapool", "namespace-a", cephApplicationMetadata(radosNamespace))
+		assert.NoError(t, err)
+	}
+
+	t.Run("description is set", func(t *testing.T) {
+		radosNamespace.Spec.Description = "volumes of tenant a"
+		setMetadata(t)
+		assert.Contains(t, commands, "set replicapool rbd rados_namespace.namespace-a.description volumes of tenant a")
+		assert.Equal(t, "volumes of tenant a", poolMetadata["rados_namespace.namespace-a.description"])
+		assert.Equal(t, "storage", poolMetadata["rados_namespace.namespace-a.team"])
+	})
+
+	t.Run("unchanged description is not set again", func(t *testing.T) {
+		setMetadata(t)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("description is updated", func(t *testing.T) {
+		radosNamespace.Spec.Description = "volumes of tenant b"
+		setMetadata(t)
+		assert.Equal(t, []string{"set replicapool rbd rados_namespace.namespace-a.description volumes of tenant b"}, commands)
+	})
+
+	t.Run("description is removed when cleared", func(t *testing.T) {
+		radosNamespace.Spec.Description = ""
+		setMetadata(t)
+		assert.Equal(t, []string{"rm replicapool rbd rados_namespace.namespace-a.description"}, commands)
+		assert.Equal(t, map[string]string{"rados_namespace.namespace-a.team": "storage"}, poolMetadata)
+	})
+}
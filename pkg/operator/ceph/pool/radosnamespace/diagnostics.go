@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// diagnosticsAnnotation enables the dump of the diagnostics of the rados namespace when its reconcile fails
+	diagnosticsAnnotation = "ceph.rook.io/diagnostics"
+	// maxDiagnosticsEntrySize is the maximum size of each entry of the diagnostics config map, so that the config
+	// map stays well below the size limit of the objects
+	maxDiagnosticsEntrySize = 64 * 1024
+
+	diagnosticsErrorKey          = "error"
+	diagnosticsCollectedAtKey    = "collectedAt"
+	diagnosticsRadosNamespaceKey = "radosNamespace.yaml"
+	diagnosticsBlockPoolKey      = "blockPoolStatus.yaml"
+	diagnosticsMirroringInfoKey  = "mirroringInfo.yaml"
+	diagnosticsCephCallsKey      = "cephCalls.yaml"
+)
+
+var (
+	// secretPattern matches the values of the settings whose name suggests a secret, e.g. "key: AQD..." or
+	// "--token=abc"
+	secretPattern = regexp.MustCompile(`(?i)([\w./-]*(?:secret|token|password|keyring|key)[\w./-]*"?\s*[:=]\s*"?)[^\s",}]+`)
+	// cephKeyPattern matches the ceph auth keys
+	cephKeyPattern = regexp.MustCompile(`AQ[A-Za-z0-9+/]{38}==`)
+)
+
+const redactedValue = "<redacted>"
+
+func isDiagnosticsEnabled(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.GetAnnotations()[diagnosticsAnnotation] == "true"
+}
+
+// diagnosticsConfigMapName returns the name of the config map with the diagnostics of the rados namespace
+func diagnosticsConfigMapName(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	return fmt.Sprintf("%s-rados-namespace-diagnostics", radosNamespace.Name)
+}
+
+// redactSecrets replaces the secrets in the diagnostics
+func redactSecrets(text string) string {
+	text = cephKeyPattern.ReplaceAllString(text, redactedValue)
+	return secretPattern.ReplaceAllString(text, "${1}"+redactedValue)
+}
+
+// boundDiagnosticsEntry truncates an entry of the diagnostics to the maximum size
+func boundDiagnosticsEntry(text string) string {
+	if len(text) <= maxDiagnosticsEntrySize {
+		return text
+	}
+	return fmt.Sprintf("%s\n... truncated %d bytes", text[:maxDiagnosticsEntrySize], len(text)-maxDiagnosticsEntrySize)
+}
+
+// diagnosticsYAML returns the object as YAML, or the error if it cannot be marshalled
+func diagnosticsYAML(object interface{}) string {
+	out, err := yaml.Marshal(object)
+	if err != nil {
+		return fmt.Sprintf("failed to marshal: %v", err)
+	}
+	return string(out)
+}
+
+// collectDiagnostics returns the state of the rados namespace useful to investigate the failure of its reconcile.
+// The mirroring info is only taken from the cache so that no ceph command is run.
+func (r *ReconcileCephBlockPoolRadosNamespace) collectDiagnostics(radosNamespace *cephv1.CephBlockPoolRadosNamespace, reconcileErr error, log *reconcileLogger) map[string]string {
+	data := map[string]string{
+		diagnosticsErrorKey:       reconcileErr.Error(),
+		diagnosticsCollectedAtKey: r.now().UTC().Format(time.RFC3339),
+		diagnosticsRadosNamespaceKey: diagnosticsYAML(struct {
+			Annotations map[string]string                         `json:"annotations,omitempty"`
+			Generation  int64                                     `json:"generation"`
+			Spec        cephv1.CephBlockPoolRadosNamespaceSpec    `json:"spec"`
+			Status      *cephv1.CephBlockPoolRadosNamespaceStatus `json:"status,omitempty"`
+		}{radosNamespace.Annotations, radosNamespace.Generation, radosNamespace.Spec, radosNamespace.Status}),
+	}
+
+	cephBlockPool := &cephv1.CephBlockPool{}
+	poolName := types.NamespacedName{Name: radosNamespace.Spec.BlockPoolName, Namespace: blockPoolNamespace(radosNamespace)}
+	if err := r.client.Get(r.opManagerContext, poolName, cephBlockPool); err != nil {
+		data[diagnosticsBlockPoolKey] = fmt.Sprintf("failed to get block pool %q: %v", poolName, err)
+	} else {
+		data[diagnosticsBlockPoolKey] = diagnosticsYAML(cephBlockPool.Status)
+	}
+
+	if r.clusterInfo != nil {
+		if mirrorInfo := r.mirroringInfo.peek(r.clusterInfo, getPoolAndRadosNamespaceName(radosNamespace)); mirrorInfo != nil {
+			data[diagnosticsMirroringInfoKey] = diagnosticsYAML(mirrorInfo)
+		}
+	}
+	if len(log.recentCephCalls) > 0 {
+		data[diagnosticsCephCallsKey] = diagnosticsYAML(log.recentCephCalls)
+	}
+
+	for key, value := range data {
+		data[key] = boundDiagnosticsEntry(redactSecrets(value))
+	}
+	return data
+}
+
+// dumpDiagnostics saves the diagnostics of the failed reconcile of the rados namespace in a config map when they
+// are enabled by the annotation. The config map is replaced by each failed reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) dumpDiagnostics(name types.NamespacedName, reconcileErr error, log *reconcileLogger) error {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, radosNamespace); err != nil {
+		// the rados namespace is gone or cannot be read, the failure is already logged
+		return nil
+	}
+	if !isDiagnosticsEnabled(radosNamespace) {
+		return nil
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: diagnosticsConfigMapName(radosNamespace), Namespace: radosNamespace.Namespace},
+		Data:       r.collectDiagnostics(radosNamespace, reconcileErr, log),
+	}
+	err := k8sutil.NewOwnerInfo(radosNamespace, r.scheme).SetControllerReference(cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set owner reference of config map %q", cm.Name)
+	}
+	_, err = k8sutil.CreateOrUpdateConfigMap(r.opManagerContext, r.context.Clientset, cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the diagnostics of rados namespace %q", name)
+	}
+	log.Infof("diagnostics of the failed reconcile of rados namespace %q saved to config map %q", name, cm.Name)
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	cephKey := "AQBsNNNeAAAAABAAiHFt0XXL3BIXsXzX+Vd/OA=="
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"no secret", "failed to create rados namespace replicapool/namespace-a", "failed to create rados namespace replicapool/namespace-a"},
+		{"ceph key", "auth returned " + cephKey, "auth returned <redacted>"},
+		{"key flag", "rbd --id admin --key=abc123 namespace create", "rbd --id admin --key=<redacted> namespace create"},
+		{"yaml setting", "  admin-secret: adminsecret\n  fsid: abc", "  admin-secret: <redacted>\n  fsid: abc"},
+		{"json setting", `{"token":"abc","site":"a"}`, `{"token":"<redacted>","site":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactSecrets(tt.text))
+		})
+	}
+}
+
+func TestBoundDiagnosticsEntry(t *testing.T) {
+	assert.Equal(t, "short", boundDiagnosticsEntry("short"))
+
+	bounded := boundDiagnosticsEntry(strings.Repeat("a", maxDiagnosticsEntrySize+10))
+	assert.True(t, strings.HasPrefix(bounded, strings.Repeat("a", maxDiagnosticsEntrySize)))
+	assert.True(t, strings.HasSuffix(bounded, "... truncated 10 bytes"))
+}
+
+func TestRecentCephCalls(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	for i := 0; i < maxRecentCephCalls+5; i++ {
+		_ = log.timeCephCall("create rados namespace", func() error { return nil })
+	}
+	_ = log.timeCephCall("enable mirroring", func() error { return errors.New("rbd: mirroring not enabled") })
+	assert.Len(t, log.recentCephCalls, maxRecentCephCalls)
+	last := log.recentCephCalls[maxRecentCephCalls-1]
+	assert.Equal(t, "enable mirroring", last.Operation)
+	assert.Equal(t, "rbd: mirroring not enabled", last.Error)
+}
+
+func TestDumpDiagnosticsOnFailure(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	newRadosNamespace := func(name string, annotations map[string]string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Generation:  1,
+				Finalizers:  []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+				Annotations: annotations,
+			},
+			TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+			Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+	}
+	enabled := newRadosNamespace("namespace-a", map[string]string{diagnosticsAnnotation: "true"})
+	disabled := newRadosNamespace("namespace-b", nil)
+
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" {
+			return "rbd: failed to create namespace with --key=AQBsNNNeAAAAABAAiHFt0XXL3BIXsXzX+Vd/OA==: (1) Operation not permitted", errors.New("exit status 1")
+		}
+		return "", nil
+	}, enabled, disabled, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	createTestCSIConfigMap(t, r, namespace)
+	configMaps := r.context.Clientset.CoreV1().ConfigMaps(namespace)
+
+	t.Run("diagnostics are dumped on failure when enabled", func(t *testing.T) {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: enabled.Name, Namespace: namespace}})
+		assert.Error(t, err)
+
+		cm, err := configMaps.Get(ctx, diagnosticsConfigMapName(enabled), metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, cm.OwnerReferences, 1)
+		assert.Contains(t, cm.Data[diagnosticsErrorKey], "Operation not permitted")
+		assert.Contains(t, cm.Data[diagnosticsRadosNamespaceKey], "blockPoolName: replicapool")
+		assert.Contains(t, cm.Data[diagnosticsRadosNamespaceKey], "phase: Failure")
+		assert.Contains(t, cm.Data[diagnosticsBlockPoolKey], "phase: Ready")
+		assert.Contains(t, cm.Data[diagnosticsCephCallsKey], "create rados namespace")
+		assert.NotEmpty(t, cm.Data[diagnosticsCollectedAtKey])
+		for key, value := range cm.Data {
+			assert.NotContains(t, value, "AQBsNNNeAAAAABAAiHFt0XXL3BIXsXzX+Vd/OA==", key)
+			assert.LessOrEqual(t, len(value), maxDiagnosticsEntrySize+len("\n... truncated 0000000 bytes"), key)
+		}
+	})
+
+	t.Run("nothing is dumped without the annotation", func(t *testing.T) {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: disabled.Name, Namespace: namespace}})
+		assert.Error(t, err)
+
+		_, err = configMaps.Get(ctx, diagnosticsConfigMapName(disabled), metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
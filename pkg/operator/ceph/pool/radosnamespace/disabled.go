@@ -0,0 +1,37 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strconv"
+
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+// controllerDisabledSettingName is the operator setting to not register the rados namespace controller, its
+// watches and its indexes, for the deployments that never use rados namespaces
+const controllerDisabledSettingName = "ROOK_RADOS_NAMESPACE_CONTROLLER_DISABLED"
+
+// isControllerDisabled returns whether the operator is configured not to run the rados namespace controller
+func isControllerDisabled() bool {
+	disabled, err := strconv.ParseBool(k8sutil.GetOperatorSetting(controllerDisabledSettingName, "false"))
+	if err != nil {
+		logger.Warningf("failed to parse setting %q, the rados namespace controller is enabled. %v", controllerDisabledSettingName, err)
+		return false
+	}
+	return disabled
+}
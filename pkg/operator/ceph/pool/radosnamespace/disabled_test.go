@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// recordingManager records the indexes registered by the controller, any other use of the manager panics
+type recordingManager struct {
+	manager.Manager
+	indexes []string
+}
+
+func (m *recordingManager) GetFieldIndexer() client.FieldIndexer {
+	return m
+}
+
+// IndexField records the index and fails so that the controller is not registered further
+func (m *recordingManager) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	m.indexes = append(m.indexes, field)
+	return errors.New("index recorded")
+}
+
+func TestAddDisabledController(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("controller is registered by default", func(t *testing.T) {
+		mgr := &recordingManager{}
+		err := Add(mgr, &clusterd.Context{}, ctx, opcontroller.OperatorConfig{})
+		assert.ErrorContains(t, err, "index recorded")
+		assert.Equal(t, []string{cephRNSNameIndex}, mgr.indexes)
+	})
+
+	t.Run("controller and indexes are not registered when disabled", func(t *testing.T) {
+		t.Setenv(controllerDisabledSettingName, "true")
+		mgr := &recordingManager{}
+		assert.NotPanics(t, func() {
+			assert.NoError(t, Add(mgr, &clusterd.Context{}, ctx, opcontroller.OperatorConfig{}))
+		})
+		assert.Empty(t, mgr.indexes)
+	})
+
+	t.Run("invalid setting keeps the controller enabled", func(t *testing.T) {
+		t.Setenv(controllerDisabledSettingName, "sometimes")
+		assert.False(t, isControllerDisabled())
+	})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// driftCorrectionSettingName is the operator setting to check the settings of the reconciled rados namespaces
+	// in ceph on each reconcile, and to set them again when they were changed out-of-band. It is disabled by
+	// default since it runs additional ceph commands.
+	driftCorrectionSettingName = "ROOK_RADOS_NAMESPACE_DRIFT_CORRECTION"
+
+	// driftCorrectedEventReason is the reason of the event recorded when drifted settings are corrected
+	driftCorrectedEventReason = "DriftCorrected"
+)
+
+func isDriftCorrectionEnabled(log *reconcileLogger) bool {
+	enabled, err := strconv.ParseBool(k8sutil.GetOperatorSetting(driftCorrectionSettingName, "false"))
+	if err != nil {
+		log.Warningf("failed to parse setting %q, drift correction is disabled. %v", driftCorrectionSettingName, err)
+		return false
+	}
+	return enabled
+}
+
+// detectDrift returns the settings managed by the operator whose state in ceph differs from the spec of the
+// rados namespace. Only a rados namespace whose current generation was reconciled is checked, the differences
+// with a new generation are not a drift.
+func (r *ReconcileCephBlockPoolRadosNamespace) detectDrift(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) ([]string, error) {
+	if radosNamespace.Status == nil || radosNamespace.Status.Phase != cephv1.ConditionReady || radosNamespace.Status.ObservedGeneration != radosNamespace.Generation {
+		return nil, nil
+	}
+	if cephv1.GetRadosNamespaceName(radosNamespace) == cephv1.ImplicitNamespaceVal {
+		return nil, nil
+	}
+
+	var drifted []string
+	compressionDrifted, err := r.isCompressionDrifted(radosNamespace, log)
+	if err != nil {
+		return nil, err
+	}
+	if compressionDrifted {
+		drifted = append(drifted, "compression")
+	}
+
+	configDrifted, err := r.isPostCreateConfigDrifted(radosNamespace, log)
+	if err != nil {
+		return nil, err
+	}
+	if configDrifted {
+		drifted = append(drifted, "postCreateConfig")
+	}
+
+	modeDrifted, err := r.isMirroringModeDrifted(radosNamespace, log)
+	if err != nil {
+		return nil, err
+	}
+	if modeDrifted {
+		drifted = append(drifted, "mirroring mode")
+	}
+	return drifted, nil
+}
+
+// isCompressionDrifted returns whether the rbd_compression_hint option of the rados namespace differs from the spec,
+// the hint is only managed once the spec or a previous reconcile set it
+func (r *ReconcileCephBlockPoolRadosNamespace) isCompressionDrifted(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) (bool, error) {
+	if compressionInfo(radosNamespace.Spec.Compression) == "" && radosNamespace.Status.Info[compressionInfoKey] == "" {
+		return false, nil
+	}
+	var current map[string]string
+	err := log.timeCephCall("get compression hint", func() error {
+		var err error
+		current, err = cephclient.GetRadosNamespaceCompression(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
+		return err
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the compression hint of rados namespace %q", radosNamespace.Name)
+	}
+	desired := compressionSettings(radosNamespace.Spec.Compression)
+	return !(len(current) == 0 && len(desired) == 0) && !reflect.DeepEqual(current, desired), nil
+}
+
+// isPostCreateConfigDrifted returns whether the rbd config options of spec.postCreateConfig, or the options
+// applied by a previous reconcile, differ in the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) isPostCreateConfigDrifted(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) (bool, error) {
+	desired := map[string]string{}
+	for _, entry := range radosNamespace.Spec.PostCreateConfig {
+		desired[entry.Key] = entry.Value
+	}
+	managed := map[string]string{}
+	for _, key := range postCreateConfigKeys(radosNamespace.Status.Info[postCreateConfigInfoKey]) {
+		managed[key] = ""
+	}
+	for key := range desired {
+		managed[key] = ""
+	}
+	if len(managed) == 0 {
+		return false, nil
+	}
+
+	var current map[string]string
+	err := log.timeCephCall("get post create config", func() error {
+		var err error
+		current, err = cephclient.GetRadosNamespaceConfig(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace), sortedConfigKeys(managed))
+		return err
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the post create config of rados namespace %q", radosNamespace.Name)
+	}
+	return !(len(current) == 0 && len(desired) == 0) && !reflect.DeepEqual(current, desired), nil
+}
+
+// isMirroringModeDrifted returns whether the mirroring mode of the rados namespace differs from the spec. The
+// mirroring info is read from ceph and the cached info is dropped on a drift, so that the reconcile sets the mode
+// again.
+func (r *ReconcileCephBlockPoolRadosNamespace) isMirroringModeDrifted(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) (bool, error) {
+	if radosNamespace.Spec.Mirroring == nil || !isMirroringRecorded(radosNamespace) {
+		return false, nil
+	}
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	var mirrorInfo *cephv1.MirroringInfo
+	err := log.timeCephCall("get mirroring info", func() error {
+		var err error
+		mirrorInfo, err = cephclient.GetPoolMirroringInfo(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+		return err
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get the mirroring info of rados namespace %q", poolAndRadosNamespaceName)
+	}
+	if mirrorInfo.Mode == string(radosNamespace.Spec.Mirroring.Mode) {
+		return false, nil
+	}
+	r.mirroringInfo.invalidate(r.clusterInfo, poolAndRadosNamespaceName)
+	return true, nil
+}
+
+// recordDriftCorrected records a warning event with the drifted settings corrected by the reconcile
+func (r *ReconcileCephBlockPoolRadosNamespace) recordDriftCorrected(radosNamespace *cephv1.CephBlockPoolRadosNamespace, drifted []string) {
+	r.recorder.Event(radosNamespace, v1.EventTypeWarning, driftCorrectedEventReason,
+		fmt.Sprintf("corrected the %s of rados namespace %q that were changed outside of the operator", strings.Join(drifted, ", "), getPoolAndRadosNamespaceName(radosNamespace)))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsDriftCorrectionEnabled(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.False(t, isDriftCorrectionEnabled(log))
+
+	t.Setenv(driftCorrectionSettingName, "true")
+	assert.True(t, isDriftCorrectionEnabled(log))
+
+	t.Setenv(driftCorrectionSettingName, "invalid")
+	assert.False(t, isDriftCorrectionEnabled(log))
+}
+
+func TestDetectDrift(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName:    "replicapool",
+			Compression:      &cephv1.RadosNamespaceCompression{Hint: "compressible"},
+			PostCreateConfig: []cephv1.RadosNamespaceConfigEntry{{Key: "rbd_default_features", Value: "layering"}},
+			Mirroring:        &cephv1.RadosNamespaceMirroring{Mode: "image"},
+		},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{
+			Phase:              cephv1.ConditionReady,
+			ObservedGeneration: 1,
+			Info: map[string]string{
+				compressionInfoKey:      "rbd_compression_hint=compressible",
+				postCreateConfigInfoKey: "rbd_default_features",
+				mirroringEnabledInfoKey: "1",
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+
+	// config and mode are the live state of the rados namespace in ceph
+	var config map[string]string
+	var mode string
+	commands := 0
+	recorder := record.NewFakeRecorder(5)
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:   cl,
+		recorder: recorder,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					commands++
+					if args[0] == "config" && args[1] == "namespace" {
+						assert.Equal(t, "replicapool/namespace-a", args[3])
+						switch args[2] {
+						case "list":
+							options := []map[string]string{}
+							for key, value := range config {
+								options = append(options, map[string]string{"name": key, "value": value, "source": "namespace"})
+							}
+							output, err := json.Marshal(options)
+							return string(output), err
+						case "set":
+							config[args[4]] = args[5]
+						case "remove":
+							delete(config, args[4])
+						}
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						assert.Equal(t, "replicapool/namespace-a", args[3])
+						return `{"mode":"` + mode + `"}`, nil
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		opManagerContext: ctx,
+	}
+	inSync := func() {
+		config = map[string]string{"rbd_compression_hint": "compressible", "rbd_default_features": "layering"}
+		mode = "image"
+	}
+
+	t.Run("in sync", func(t *testing.T) {
+		inSync()
+		drifted, err := r.detectDrift(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.Empty(t, drifted)
+	})
+
+	t.Run("compression changed", func(t *testing.T) {
+		inSync()
+		config["rbd_compression_hint"] = "incompressible"
+		drifted, err := r.detectDrift(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"compression"}, drifted)
+	})
+
+	t.Run("image features and mirroring mode changed", func(t *testing.T) {
+		inSync()
+		config["rbd_default_features"] = "layering,exclusive-lock"
+		mode = "disabled"
+		drifted, err := r.detectDrift(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"postCreateConfig", "mirroring mode"}, drifted)
+	})
+
+	t.Run("config removed out-of-band", func(t *testing.T) {
+		inSync()
+		delete(config, "rbd_default_features")
+		drifted, err := r.detectDrift(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"postCreateConfig"}, drifted)
+	})
+
+	t.Run("drift is corrected", func(t *testing.T) {
+		inSync()
+		config["rbd_compression_hint"] = "incompressible"
+		config["rbd_default_features"] = "layering,exclusive-lock"
+		drifted, err := r.detectDrift(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"compression", "postCreateConfig"}, drifted)
+
+		// the steps of the full reconcile set the settings of the spec again
+		assert.NoError(t, r.reconcileCompression(radosNamespace, name, log))
+		assert.NoError(t, r.reconcilePostCreateConfig(radosNamespace, name, log))
+		assert.Equal(t, map[string]string{"rbd_compression_hint": "compressible", "rbd_default_features": "layering"}, config)
+		r.recordDriftCorrected(radosNamespace, drifted)
+		assert.Contains(t, <-recorder.Events, "Warning "+driftCorrectedEventReason)
+
+		drifted, err = r.detectDrift(radosNamespace, log)
+		assert.NoError(t, err)
+		assert.Empty(t, drifted)
+	})
+
+	t.Run("new generation is not checked", func(t *testing.T) {
+		inSync()
+		config["rbd_compression_hint"] = "incompressible"
+		newGeneration := radosNamespace.DeepCopy()
+		newGeneration.Generation = 2
+		commands = 0
+		drifted, err := r.detectDrift(newGeneration, log)
+		assert.NoError(t, err)
+		assert.Empty(t, drifted)
+		assert.Zero(t, commands)
+	})
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strings"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+// PoolNotReadyError is returned when the parent CephBlockPool is not ready to create the rados namespace
+type PoolNotReadyError struct {
+	PoolName string
+	Phase    cephv1.ConditionType
+}
+
+func (e *PoolNotReadyError) Error() string {
+	return fmt.Sprintf("ceph blockpool %q is not ready, phase is %q", e.PoolName, e.Phase)
+}
+
+// ContainsImagesError is returned when the rados namespace cannot be deleted because it contains images
+// or snapshots
+type ContainsImagesError struct {
+	RadosNamespace string
+	err            error
+}
+
+func (e *ContainsImagesError) Error() string {
+	return fmt.Sprintf("rados namespace %q contains images or snapshots. %v", e.RadosNamespace, e.err)
+}
+
+func (e *ContainsImagesError) Unwrap() error {
+	return e.err
+}
+
+// PoolMirroringDisabledError is returned when mirroring is enabled on the rados namespace but not on the
+// parent CephBlockPool
+type PoolMirroringDisabledError struct {
+	PoolName string
+}
+
+func (e *PoolMirroringDisabledError) Error() string {
+	return fmt.Sprintf("mirroring is disabled for the block pool %q", e.PoolName)
+}
+
+// PoolMirroringUnsupportedError is returned when the mirroring mode of the rados namespace is not supported by
+// the erasure coded parent CephBlockPool
+type PoolMirroringUnsupportedError struct {
+	PoolName string
+	Mode     cephv1.RadosNamespaceMirroringMode
+}
+
+func (e *PoolMirroringUnsupportedError) Error() string {
+	return fmt.Sprintf("%q mirroring mode is not supported on the erasure coded block pool %q, the journal-based mirroring requires a replicated pool. Create the rados namespace in a replicated pool, or use the snapshot-based %q mirroring mode",
+		e.Mode, e.PoolName, cephv1.RadosNamespaceMirroringModeImage)
+}
+
+// clusterFullErrors are the substrings of the ceph errors returned when the cluster is full
+var clusterFullErrors = []string{
+	"No space left on device",
+	"ENOSPC",
+}
+
+// ClusterFullError is returned when the rados namespace cannot be created because the ceph cluster is full
+type ClusterFullError struct {
+	err error
+}
+
+func (e *ClusterFullError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ClusterFullError) Unwrap() error {
+	return e.err
+}
+
+// detectClusterFull returns a ClusterFullError if the ceph command failed because the cluster is full
+func detectClusterFull(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, substring := range clusterFullErrors {
+		if strings.Contains(err.Error(), substring) {
+			return &ClusterFullError{err: err}
+		}
+	}
+	return err
+}
+
+// SnapshotSchedulesError is returned when mirroring is enabled on the rados namespace but its snapshot
+// schedules could not be set
+type SnapshotSchedulesError struct {
+	err error
+}
+
+func (e *SnapshotSchedulesError) Error() string {
+	return e.err.Error()
+}
+
+func (e *SnapshotSchedulesError) Unwrap() error {
+	return e.err
+}
+
+// MirroringDrainInProgressError is returned when mirroring is being disabled on the rados namespace and
+// there are mirrored images left to drain
+type MirroringDrainInProgressError struct {
+	RadosNamespace string
+	Remaining      int
+}
+
+func (e *MirroringDrainInProgressError) Error() string {
+	return fmt.Sprintf("disabling mirroring of rados namespace %q, %d mirrored images left to drain", e.RadosNamespace, e.Remaining)
+}
+
+// ImageFilterInProgressError is returned when mirroring is being enabled or disabled on the images of the rados
+// namespace to match its image filter and there are images left to update
+type ImageFilterInProgressError struct {
+	RadosNamespace string
+	Remaining      int
+}
+
+func (e *ImageFilterInProgressError) Error() string {
+	return fmt.Sprintf("updating the mirrored images of rados namespace %q to match the image filter, %d images left to update", e.RadosNamespace, e.Remaining)
+}
+
+// MirroringModeSwitchInProgressError is returned when the mirroring mode of the rados namespace cannot be
+// switched yet because images are mid-replication
+type MirroringModeSwitchInProgressError struct {
+	RadosNamespace string
+	From           string
+	To             string
+	Replicating    int
+}
+
+func (e *MirroringModeSwitchInProgressError) Error() string {
+	return fmt.Sprintf("waiting to switch the mirroring of rados namespace %q from %q to %q mode, %d images are mid-replication", e.RadosNamespace, e.From, e.To, e.Replicating)
+}
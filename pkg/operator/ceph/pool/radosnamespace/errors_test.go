@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileTypedErrors(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	name := types.NamespacedName{Name: "namespace-a", Namespace: namespace}
+	log := newReconcileLogger(name)
+
+	t.Run("pool not ready", func(t *testing.T) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace, Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"}},
+			TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+		cephBlockPool := newTestCephBlockPool(namespace)
+		cephBlockPool.Status.Phase = cephv1.ConditionProgressing
+		r := newTestReconciler(t, namespace, nil, radosNamespace, newTestCephCluster(namespace), cephBlockPool)
+		cl := r.client
+
+		// waiting for the pool is not a failure, the reconcile is requeued without an error
+		res, _, err := r.reconcile(reconcile.Request{NamespacedName: name}, log)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfPoolNotReady, res)
+
+		updated := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, updated))
+		assert.Equal(t, cephv1.ConditionProgressing, updated.Status.Phase)
+		condition := cephv1.FindStatusCondition(updated.Status.Conditions, cephv1.ConditionProgressing)
+		assert.Equal(t, cephv1.WaitingForBlockPoolReason, condition.Reason)
+		assert.Equal(t, (&PoolNotReadyError{PoolName: "replicapool", Phase: cephv1.ConditionProgressing}).Error(), condition.Message)
+	})
+
+	t.Run("rados namespace contains images", func(t *testing.T) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace},
+			TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			client: cl,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "pool" && args[1] == "stats" {
+							return `{"images":{"count":2,"snap_count":0}}`, nil
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+			opManagerContext: ctx,
+			recorder:         record.NewFakeRecorder(5),
+		}
+
+		blocked, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+		assert.True(t, blocked)
+		var imagesErr *ContainsImagesError
+		assert.True(t, errors.As(err, &imagesErr))
+		assert.Equal(t, name.Name, imagesErr.RadosNamespace)
+	})
+
+	t.Run("pool mirroring disabled", func(t *testing.T) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+			},
+		}
+		cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: namespace}}
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						return `{"mode":"disabled"}`, nil
+					},
+				},
+			},
+			clusterInfo:            &cephclient.ClusterInfo{Namespace: namespace, Context: ctx},
+			opManagerContext:       ctx,
+			radosNamespaceContexts: map[string]*mirrorHealth{},
+		}
+
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		var mirroringErr *PoolMirroringDisabledError
+		assert.True(t, errors.As(err, &mirroringErr))
+		assert.Equal(t, "replicapool", mirroringErr.PoolName)
+	})
+}
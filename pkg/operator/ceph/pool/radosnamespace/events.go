@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The reasons of the events recorded when a rados namespace reaches a milestone
+const (
+	radosNamespaceCreatedEventReason      = "RadosNamespaceCreated"
+	mirroringEnabledEventReason           = "MirroringEnabled"
+	snapshotScheduleConfiguredEventReason = "SnapshotScheduleConfigured"
+	csiConfigUpdatedEventReason           = "CSIConfigUpdated"
+)
+
+// milestoneTracker tracks the state last reached by each milestone of each rados namespace, so that
+// the milestone events are only recorded on transitions. Like the reconcile fingerprints it is only
+// kept in memory.
+type milestoneTracker struct {
+	states map[types.NamespacedName]map[string]string
+}
+
+// changed records the state of the milestone and returns whether it differs from the recorded one
+func (t *milestoneTracker) changed(name types.NamespacedName, reason, state string) bool {
+	if t.states == nil {
+		t.states = map[types.NamespacedName]map[string]string{}
+	}
+	if t.states[name] == nil {
+		t.states[name] = map[string]string{}
+	}
+	previous, ok := t.states[name][reason]
+	t.states[name][reason] = state
+	return !ok || previous != state
+}
+
+func (t *milestoneTracker) forget(name types.NamespacedName) {
+	delete(t.states, name)
+}
+
+// recordMilestoneEvent records a Normal event when the milestone reached a new state. The event is
+// only recorded while a new generation is reconciled, so that an operator restart does not record the
+// milestones of all the rados namespaces again.
+func (r *ReconcileCephBlockPoolRadosNamespace) recordMilestoneEvent(radosNamespace *cephv1.CephBlockPoolRadosNamespace, reason, state, message string) {
+	name := types.NamespacedName{Name: radosNamespace.Name, Namespace: radosNamespace.Namespace}
+	if !r.milestones.changed(name, reason, state) || !isNewGeneration(radosNamespace) {
+		return
+	}
+	r.recorder.Event(radosNamespace, v1.EventTypeNormal, reason, message)
+}
+
+// isNewGeneration returns whether the generation of the rados namespace was not reconciled yet
+func isNewGeneration(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.Status == nil || radosNamespace.Status.ObservedGeneration != radosNamespace.Generation
+}
+
+// recordMirroringMilestones records the events of the mirroring milestones of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) recordMirroringMilestones(radosNamespace *cephv1.CephBlockPoolRadosNamespace) {
+	mirroring := radosNamespace.Spec.Mirroring
+	if mirroring == nil {
+		return
+	}
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	remoteNamespace := ""
+	if mirroring.RemoteNamespace != nil {
+		remoteNamespace = *mirroring.RemoteNamespace
+	}
+	r.recordMilestoneEvent(radosNamespace, mirroringEnabledEventReason,
+		fmt.Sprintf("%s/%s/%s", mirroring.Mode, mirroring.Direction, remoteNamespace),
+		fmt.Sprintf("enabled %q mirroring of rados namespace %q", mirroring.Mode, poolAndRadosNamespaceName))
+	if len(mirroring.SnapshotSchedules) > 0 {
+		r.recordMilestoneEvent(radosNamespace, snapshotScheduleConfiguredEventReason, fmt.Sprintf("%v", mirroring.SnapshotSchedules),
+			fmt.Sprintf("configured %d snapshot schedules of rados namespace %q", len(mirroring.SnapshotSchedules), poolAndRadosNamespaceName))
+	}
+}
+
+// csiConfigState is the part of the rados namespace that is saved in its csi config entry
+func csiConfigState(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	return fmt.Sprintf("%s/%s/%s/%s", buildClusterID(radosNamespace), cephv1.GetRadosNamespaceName(radosNamespace),
+		radosNamespace.Spec.MapOptions, radosNamespace.Spec.UnmapOptions)
+}
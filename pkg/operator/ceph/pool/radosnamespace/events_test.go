@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// recordedEventReasons returns the reasons of the events recorded since the last call
+func recordedEventReasons(recorder *record.FakeRecorder) []string {
+	reasons := []string{}
+	for {
+		select {
+		case e := <-recorder.Events:
+			reasons = append(reasons, strings.Fields(e)[1])
+		default:
+			return reasons
+		}
+	}
+}
+
+func TestMilestoneEventsOnReconcile(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" && args[1] == "pool" {
+			return `{"mode":"disabled"}`, nil
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	cl := r.client
+	recorder := r.recorder.(*record.FakeRecorder)
+	createTestCSIConfigMap(t, r, namespace)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+	t.Run("first successful reconcile records the milestones", func(t *testing.T) {
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{radosNamespaceCreatedEventReason, csiConfigUpdatedEventReason, string(cephv1.ReconcileSucceeded)}, recordedEventReasons(recorder))
+	})
+
+	t.Run("no-op reconcile records no milestone", func(t *testing.T) {
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{string(cephv1.ReconcileSucceeded)}, recordedEventReasons(recorder))
+	})
+
+	t.Run("full reconcile of the same generation records no milestone", func(t *testing.T) {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		current.Annotations = map[string]string{forceReconcileAnnotation: "1"}
+		assert.NoError(t, cl.Update(ctx, current))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{string(cephv1.ReconcileSucceeded)}, recordedEventReasons(recorder))
+	})
+
+	t.Run("csi config change records the csi milestone only", func(t *testing.T) {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		current.Spec.MapOptions = "rxbounce"
+		current.Generation = 2
+		assert.NoError(t, cl.Update(ctx, current))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{csiConfigUpdatedEventReason, string(cephv1.ReconcileSucceeded)}, recordedEventReasons(recorder))
+	})
+}
+
+func TestRecordMirroringMilestones(t *testing.T) {
+	remoteNamespace := "remote"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph", Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:              "image",
+				RemoteNamespace:   &remoteNamespace,
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+			},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileCephBlockPoolRadosNamespace{recorder: recorder}
+
+	t.Run("first enable records each milestone once", func(t *testing.T) {
+		r.recordMirroringMilestones(radosNamespace)
+		assert.Equal(t, []string{mirroringEnabledEventReason, snapshotScheduleConfiguredEventReason}, recordedEventReasons(recorder))
+		r.recordMirroringMilestones(radosNamespace)
+		assert.Empty(t, recordedEventReasons(recorder))
+	})
+
+	t.Run("schedule change records the schedule milestone only", func(t *testing.T) {
+		radosNamespace.Generation = 2
+		radosNamespace.Spec.Mirroring.SnapshotSchedules = []cephv1.SnapshotScheduleSpec{{Interval: "2h"}}
+		r.recordMirroringMilestones(radosNamespace)
+		assert.Equal(t, []string{snapshotScheduleConfiguredEventReason}, recordedEventReasons(recorder))
+	})
+
+	t.Run("operator restart does not record the reconciled milestones again", func(t *testing.T) {
+		radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Phase: cephv1.ConditionReady, ObservedGeneration: 2}
+		r := &ReconcileCephBlockPoolRadosNamespace{recorder: recorder}
+		r.recordMirroringMilestones(radosNamespace)
+		assert.Empty(t, recordedEventReasons(recorder))
+	})
+
+	t.Run("mirroring disabled records nothing", func(t *testing.T) {
+		radosNamespace.Spec.Mirroring = nil
+		radosNamespace.Status = nil
+		r.recordMirroringMilestones(radosNamespace)
+		assert.Empty(t, recordedEventReasons(recorder))
+	})
+}
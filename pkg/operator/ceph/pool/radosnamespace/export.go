@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// exportConfigAnnotation requests the export of the manifest of the rados namespace each time its value is
+	// changed
+	exportConfigAnnotation = "ceph.rook.io/export-config"
+	// exportConfigRequestInfoKey is the status info key of the last export request handled
+	exportConfigRequestInfoKey = "exportConfigRequest"
+	// exportConfigMapInfoKey is the status info key of the config map with the exported manifest
+	exportConfigMapInfoKey = "exportConfigMap"
+	// ExportManifestKey is the key of the exported manifest in the export config map
+	ExportManifestKey = "manifest.yaml"
+)
+
+// operationalAnnotations are the annotations requesting one-off operations on the rados namespace or overriding
+// its pause and clean up on this cluster, which are not part of its exported manifest
+var operationalAnnotations = []string{
+	exportConfigAnnotation,
+	pausedAnnotation,
+	opcontroller.RESOURCE_CLEANUP_ANNOTATION,
+	opcontroller.CleanupResourcesAnnotation,
+	// the clean up job image is no longer read from the annotation, but the override must not reach another cluster
+	"rook.io/force-deletion-image",
+	bootstrapPeerTokenAnnotation,
+	cloneFromAnnotation,
+	forceReconcileAnnotation,
+	mirrorPromoteAnnotation,
+	mirrorDemoteAnnotation,
+	mirrorVerifyAnnotation,
+	cephSettingsChecksumAnnotation,
+	diagnosticsAnnotation,
+	v1.LastAppliedConfigAnnotation,
+}
+
+// exportConfigMapName returns the name of the config map with the exported manifest of the rados namespace
+func exportConfigMapName(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	return fmt.Sprintf("%s-rados-namespace-export", radosNamespace.Name)
+}
+
+// exportManifest returns the manifest of the rados namespace as YAML, so that it can be applied to another
+// cluster. The rados namespace name and the cluster ID resolved by the operator are set in the spec so that the
+// rados namespace is reconciled with the same names wherever it is applied. The namespace, the status and the
+// annotations requesting one-off operations are not exported.
+func exportManifest(radosNamespace *cephv1.CephBlockPoolRadosNamespace) ([]byte, error) {
+	exported := &cephv1.CephBlockPoolRadosNamespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: cephv1.SchemeGroupVersion.String(),
+			Kind:       "CephBlockPoolRadosNamespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   radosNamespace.Name,
+			Labels: radosNamespace.Labels,
+		},
+		Spec: *radosNamespace.Spec.DeepCopy(),
+	}
+	for key, value := range radosNamespace.Annotations {
+		if isOperationalAnnotation(key) {
+			continue
+		}
+		if exported.Annotations == nil {
+			exported.Annotations = map[string]string{}
+		}
+		exported.Annotations[key] = value
+	}
+	if exported.Spec.Name == "" {
+		exported.Spec.Name = cephv1.GetRadosNamespaceName(radosNamespace)
+	}
+	exported.Spec.ClusterID = buildClusterID(radosNamespace)
+
+	manifest, err := yaml.Marshal(exported)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the manifest of rados namespace %q", radosNamespace.Name)
+	}
+	return manifest, nil
+}
+
+func isOperationalAnnotation(key string) bool {
+	for _, annotation := range operationalAnnotations {
+		if key == annotation {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcileExportConfig exports the manifest of the rados namespace to a config map when requested by the
+// annotation. The manifest is only exported again when the value of the annotation changes.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileExportConfig(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) error {
+	request := radosNamespace.GetAnnotations()[exportConfigAnnotation]
+	if request == "" {
+		return nil
+	}
+	if radosNamespace.Status != nil && radosNamespace.Status.Info[exportConfigRequestInfoKey] == request {
+		return nil
+	}
+
+	manifest, err := exportManifest(radosNamespace)
+	if err != nil {
+		return err
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: exportConfigMapName(radosNamespace), Namespace: radosNamespace.Namespace},
+		Data:       map[string]string{ExportManifestKey: string(manifest)},
+	}
+	err = k8sutil.NewOwnerInfo(radosNamespace, r.scheme).SetControllerReference(cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set owner reference of config map %q", cm.Name)
+	}
+	_, err = k8sutil.CreateOrUpdateConfigMap(r.opManagerContext, r.context.Clientset, cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to export the manifest of rados namespace %q", name)
+	}
+	log.Infof("manifest of rados namespace %q exported to config map %q", name, cm.Name)
+
+	err = r.mutateStatus(name, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		if current.Status.Info == nil {
+			current.Status.Info = map[string]string{}
+		}
+		current.Status.Info[exportConfigMapInfoKey] = cm.Name
+		current.Status.Info[exportConfigRequestInfoKey] = request
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to report the export config map of rados namespace %q", name)
+	}
+	return nil
+}
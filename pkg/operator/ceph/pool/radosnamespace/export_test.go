@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	testop "github.com/rook/rook/pkg/operator/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestExportManifest(t *testing.T) {
+	remoteNamespace := "remote"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  "rook-ceph",
+			UID:        "c47cac40-9bee-4d52-823b-ccd803ba5bfe",
+			Generation: 3,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+			Labels:     map[string]string{"app": "db"},
+			Annotations: map[string]string{
+				"team":                                   "storage",
+				exportConfigAnnotation:                   "1",
+				bootstrapPeerTokenAnnotation:             "1",
+				pausedAnnotation:                         "true",
+				opcontroller.RESOURCE_CLEANUP_ANNOTATION: "true",
+				opcontroller.CleanupResourcesAnnotation:  `{"limits":{"memory":"2Gi"}}`,
+				"rook.io/force-deletion-image":           "attacker/image:latest",
+			},
+		},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Description:   "database volumes",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:              "image",
+				RemoteNamespace:   &remoteNamespace,
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+			},
+			ApplicationMetadata: map[string]string{"owner": "db"},
+		},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{
+			Phase: cephv1.ConditionReady,
+			Info:  map[string]string{"clusterID": "abc"},
+		},
+	}
+
+	manifest, err := exportManifest(radosNamespace)
+	assert.NoError(t, err)
+
+	imported := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, yaml.Unmarshal(manifest, imported))
+
+	t.Run("spec round-trips with the resolved names", func(t *testing.T) {
+		expected := radosNamespace.Spec.DeepCopy()
+		expected.Name = "namespace-a"
+		expected.ClusterID = buildClusterID(radosNamespace)
+		assert.Equal(t, *expected, imported.Spec)
+		assert.Equal(t, "CephBlockPoolRadosNamespace", imported.Kind)
+		assert.Equal(t, "ceph.rook.io/v1", imported.APIVersion)
+		assert.Equal(t, "namespace-a", imported.Name)
+	})
+
+	t.Run("imported rados namespace resolves to the same names in another namespace", func(t *testing.T) {
+		imported.Namespace = "other-cluster"
+		assert.Equal(t, cephv1.GetRadosNamespaceName(radosNamespace), cephv1.GetRadosNamespaceName(imported))
+		assert.Equal(t, buildClusterID(radosNamespace), buildClusterID(imported))
+
+		// exporting the imported rados namespace again gives the same manifest
+		exportedAgain, err := exportManifest(imported)
+		assert.NoError(t, err)
+		assert.Equal(t, string(manifest), string(exportedAgain))
+	})
+
+	t.Run("runtime state is not exported", func(t *testing.T) {
+		assert.Nil(t, imported.Status)
+		assert.Empty(t, imported.UID)
+		assert.Empty(t, imported.Finalizers)
+		assert.Zero(t, imported.Generation)
+		assert.Equal(t, map[string]string{"app": "db"}, imported.Labels)
+		assert.Equal(t, map[string]string{"team": "storage"}, imported.Annotations)
+	})
+
+	t.Run("implicit rados namespace keeps its name", func(t *testing.T) {
+		implicit := radosNamespace.DeepCopy()
+		implicit.Spec.Name = cephv1.ImplicitNamespaceKey
+		implicit.Spec.Mirroring = nil
+		manifest, err := exportManifest(implicit)
+		assert.NoError(t, err)
+		imported := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, yaml.Unmarshal(manifest, imported))
+		assert.Equal(t, cephv1.ImplicitNamespaceKey, imported.Spec.Name)
+		assert.Equal(t, buildClusterID(implicit), imported.Spec.ClusterID)
+	})
+}
+
+func TestReconcileExportConfig(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name.Name,
+			Namespace:   name.Namespace,
+			UID:         "c47cac40-9bee-4d52-823b-ccd803ba5bfe",
+			Annotations: map[string]string{exportConfigAnnotation: "1"},
+		},
+		Spec:   cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           cl,
+		scheme:           scheme.Scheme,
+		context:          &clusterd.Context{Clientset: testop.New(t, 1)},
+		opManagerContext: ctx,
+	}
+	configMaps := r.context.Clientset.CoreV1().ConfigMaps(name.Namespace)
+
+	t.Run("manifest is exported to a config map once per request", func(t *testing.T) {
+		err := r.reconcileExportConfig(radosNamespace, name, log)
+		assert.NoError(t, err)
+
+		cm, err := configMaps.Get(ctx, "namespace-a-rados-namespace-export", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, cm.OwnerReferences, 1)
+		imported := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, yaml.Unmarshal([]byte(cm.Data[ExportManifestKey]), imported))
+		assert.Equal(t, "replicapool", imported.Spec.BlockPoolName)
+		assert.Equal(t, buildClusterID(radosNamespace), imported.Spec.ClusterID)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, current))
+		assert.Equal(t, cm.Name, current.Status.Info[exportConfigMapInfoKey])
+		assert.Equal(t, "1", current.Status.Info[exportConfigRequestInfoKey])
+
+		// the manifest is not exported again for the same request
+		assert.NoError(t, configMaps.Delete(ctx, cm.Name, metav1.DeleteOptions{}))
+		err = r.reconcileExportConfig(current, name, log)
+		assert.NoError(t, err)
+		_, err = configMaps.Get(ctx, cm.Name, metav1.GetOptions{})
+		assert.Error(t, err)
+
+		// a new request exports the manifest again
+		current.Annotations[exportConfigAnnotation] = "2"
+		err = r.reconcileExportConfig(current, name, log)
+		assert.NoError(t, err)
+		_, err = configMaps.Get(ctx, cm.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("nothing is exported without the annotation", func(t *testing.T) {
+		other := radosNamespace.DeepCopy()
+		other.Name = "namespace-b"
+		other.Annotations = nil
+		err := r.reconcileExportConfig(other, types.NamespacedName{Name: other.Name, Namespace: other.Namespace}, log)
+		assert.NoError(t, err)
+		_, err = configMaps.Get(ctx, "namespace-b-rados-namespace-export", metav1.GetOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"slices"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+// legacyFinalizerSettingName is the operator setting with the name of a legacy finalizer of the rados
+// namespaces. The legacy finalizer is replaced with the current finalizer on the first reconcile and is
+// removed with the current finalizer when the CR is deleted.
+const legacyFinalizerSettingName = "ROOK_RADOS_NAMESPACE_LEGACY_FINALIZER"
+
+func legacyFinalizer() string {
+	return k8sutil.GetOperatorSetting(legacyFinalizerSettingName, "")
+}
+
+// addFinalizer sets the finalizer of the rados namespace, replacing the legacy finalizer if the CR still
+// carries it. Returns whether the generation of the CR was updated.
+func (r *ReconcileCephBlockPoolRadosNamespace) addFinalizer(radosNamespace *cephv1.CephBlockPoolRadosNamespace) (bool, error) {
+	legacy := legacyFinalizer()
+	if legacy != "" && slices.Contains(radosNamespace.GetFinalizers(), legacy) {
+		// no finalizer can be added to a CR being deleted, both finalizers are removed by the deletion
+		if !radosNamespace.GetDeletionTimestamp().IsZero() {
+			return false, nil
+		}
+		return opcontroller.ReplaceFinalizer(r.opManagerContext, r.client, radosNamespace, legacy)
+	}
+	return opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, radosNamespace)
+}
+
+// removeFinalizer removes the finalizer and the legacy finalizer of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) removeFinalizer(radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	return opcontroller.RemoveFinalizersWithNames(r.opManagerContext, r.client, radosNamespace, opcontroller.FinalizerName(radosNamespace), legacyFinalizer())
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLegacyFinalizer(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	currentFinalizer := "cephblockpoolradosnamespace.ceph.rook.io"
+	legacy := "radosnamespace.ceph.rook.io"
+	t.Setenv(legacyFinalizerSettingName, legacy)
+
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) *ReconcileCephBlockPoolRadosNamespace {
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+			opManagerContext: ctx,
+		}
+	}
+	newRadosNamespace := func(finalizers ...string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Finalizers: finalizers},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		}
+	}
+
+	t.Run("legacy finalizer is migrated on the first reconcile", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(legacy)
+		r := newReconciler(radosNamespace)
+
+		_, err := r.addFinalizer(radosNamespace)
+		assert.NoError(t, err)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, []string{currentFinalizer}, current.Finalizers)
+
+		// the next reconcile keeps a single finalizer
+		_, err = r.addFinalizer(current)
+		assert.NoError(t, err)
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, []string{currentFinalizer}, current.Finalizers)
+	})
+
+	t.Run("both finalizers are removed on deletion", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(currentFinalizer, legacy, "other")
+		r := newReconciler(radosNamespace)
+
+		assert.NoError(t, r.removeFinalizer(radosNamespace))
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, []string{"other"}, current.Finalizers)
+	})
+
+	t.Run("legacy finalizer is not migrated while the CR is deleted", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(legacy, "other")
+		now := metav1.Now()
+		radosNamespace.DeletionTimestamp = &now
+		r := newReconciler(radosNamespace)
+
+		_, err := r.addFinalizer(radosNamespace)
+		assert.NoError(t, err)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, []string{legacy, "other"}, current.Finalizers)
+
+		assert.NoError(t, r.removeFinalizer(radosNamespace))
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, []string{"other"}, current.Finalizers)
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// forceReconcileAnnotation forces a full reconcile of a rados namespace whose generation was already
+// reconciled when its value is changed
+const forceReconcileAnnotation = "ceph.rook.io/force-reconcile"
+
+// fingerprintAnnotations are the annotations requesting an operation of the create, csi config or mirroring
+// steps. Changing one of them triggers a reconcile, and they are part of the fingerprint so that the steps are not
+// skipped for the request.
+var fingerprintAnnotations = []string{
+	forceReconcileAnnotation,
+	bootstrapPeerTokenAnnotation,
+	mirrorPromoteAnnotation,
+	mirrorDemoteAnnotation,
+	mirrorVerifyAnnotation,
+	exportConfigAnnotation,
+}
+
+// reconcileFingerprint is the state of a rados namespace and of its dependencies when it was last
+// reconciled successfully. The create, csi config and mirroring steps are skipped while it is unchanged, so it
+// must cover every input of these steps:
+//   - the generation of the rados namespace, which covers its spec
+//   - the UID and the generation of the CephCluster and of the CephBlockPool, and the cluster settings written
+//     to the csi config
+//   - the fingerprintAnnotations
+//   - the labels and annotations copied onto the csi operator client profile
+//
+// An annotation read by the reconcile must either be added to the fingerprintAnnotations or be read outside of
+// the skipped steps, which is checked by the tests for the annotations of this package.
+type reconcileFingerprint struct {
+	generation        int64
+	clusterUID        types.UID
+	clusterGeneration int64
+	requireMsgr2      bool
+	poolUID           types.UID
+	poolGeneration    int64
+	forceReconcile    string
+	bootstrapRequest  string
+	mirrorPromote     string
+	mirrorDemote      string
+	mirrorVerify      string
+	exportRequest     string
+	// clientProfileMetadata are the labels and annotations copied onto the csi operator client profile
+	clientProfileMetadata string
+}
+
+func newReconcileFingerprint(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster, cephBlockPool *cephv1.CephBlockPool) reconcileFingerprint {
+	return reconcileFingerprint{
+		generation:            radosNamespace.Generation,
+		clusterUID:            cephCluster.UID,
+		clusterGeneration:     cephCluster.Generation,
+		requireMsgr2:          cephCluster.Spec.RequireMsgr2(),
+		poolUID:               cephBlockPool.UID,
+		poolGeneration:        cephBlockPool.Generation,
+		forceReconcile:        radosNamespace.GetAnnotations()[forceReconcileAnnotation],
+		bootstrapRequest:      radosNamespace.GetAnnotations()[bootstrapPeerTokenAnnotation],
+		mirrorPromote:         radosNamespace.GetAnnotations()[mirrorPromoteAnnotation],
+		mirrorDemote:          radosNamespace.GetAnnotations()[mirrorDemoteAnnotation],
+		mirrorVerify:          radosNamespace.GetAnnotations()[mirrorVerifyAnnotation],
+		exportRequest:         radosNamespace.GetAnnotations()[exportConfigAnnotation],
+		clientProfileMetadata: clientProfileMetadataFingerprint(radosNamespace),
+	}
+}
+
+// reconcileFingerprintTracker tracks the fingerprint of the last successful reconcile of each rados
+// namespace. It is only kept in memory so that a full reconcile is always done after an operator restart.
+type reconcileFingerprintTracker struct {
+	fingerprints map[types.NamespacedName]reconcileFingerprint
+	reconciledAt map[types.NamespacedName]time.Time
+}
+
+func (t *reconcileFingerprintTracker) record(name types.NamespacedName, fingerprint reconcileFingerprint) {
+	if t.fingerprints == nil {
+		t.fingerprints = map[types.NamespacedName]reconcileFingerprint{}
+		t.reconciledAt = map[types.NamespacedName]time.Time{}
+	}
+	t.fingerprints[name] = fingerprint
+	t.reconciledAt[name] = time.Now()
+}
+
+func (t *reconcileFingerprintTracker) forget(name types.NamespacedName) {
+	delete(t.fingerprints, name)
+	delete(t.reconciledAt, name)
+}
+
+// isGenerationOrMetadataOnlyChange returns whether the generation of the rados namespace or its metadata copied
+// onto the csi operator client profile are the only changes since its last successful reconcile
+func (t *reconcileFingerprintTracker) isGenerationOrMetadataOnlyChange(name types.NamespacedName, fingerprint reconcileFingerprint) bool {
+	last, ok := t.fingerprints[name]
+	if !ok || last == fingerprint {
+		return false
+	}
+	last.generation = fingerprint.generation
+	last.clientProfileMetadata = fingerprint.clientProfileMetadata
+	return last == fingerprint
+}
+
+// update sets the fingerprint of a rados namespace reconciled without the ceph commands, the time of its last
+// full reconcile is kept for the periodic resync
+func (t *reconcileFingerprintTracker) update(name types.NamespacedName, fingerprint reconcileFingerprint) {
+	if _, ok := t.fingerprints[name]; ok {
+		t.fingerprints[name] = fingerprint
+	}
+}
+
+// isOlderThan returns whether the last successful reconcile of the rados namespace is older than maxAge,
+// so that the periodic resync does a full reconcile. It is never older if maxAge is not set.
+func (t *reconcileFingerprintTracker) isOlderThan(name types.NamespacedName, maxAge time.Duration) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	reconciledAt, ok := t.reconciledAt[name]
+	return !ok || time.Since(reconciledAt) >= maxAge
+}
+
+// isUnchanged returns whether the rados namespace was already reconciled successfully with the same
+// spec, CephCluster and CephBlockPool so that the create, csi config and mirroring steps can be skipped
+func (t *reconcileFingerprintTracker) isUnchanged(name types.NamespacedName, radosNamespace *cephv1.CephBlockPoolRadosNamespace, fingerprint reconcileFingerprint) bool {
+	if radosNamespace.Status == nil || radosNamespace.Status.Phase != cephv1.ConditionReady {
+		return false
+	}
+	if radosNamespace.Status.ObservedGeneration != radosNamespace.Generation {
+		return false
+	}
+	last, ok := t.fingerprints[name]
+	return ok && last == fingerprint
+}
+
+// annotationsChangedPredicate triggers a reconcile when one of the annotations is changed since annotation
+// changes are otherwise ignored by the controller predicate
+func annotationsChangedPredicate(annotations ...string) predicate.TypedFuncs[*cephv1.CephBlockPoolRadosNamespace] {
+	return predicate.TypedFuncs[*cephv1.CephBlockPoolRadosNamespace]{
+		CreateFunc: func(e event.TypedCreateEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			for _, annotation := range annotations {
+				if e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation] {
+					return true
+				}
+			}
+			return false
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+	}
+}
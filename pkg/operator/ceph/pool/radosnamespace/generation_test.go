@@ -0,0 +1,258 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestUnchangedReconcileIsSkipped(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	var cephCommands []string
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		cephCommands = append(cephCommands, strings.Join(args, " "))
+		if args[0] == "mirror" && args[1] == "pool" {
+			return `{"mode":"disabled"}`, nil
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	cl := r.client
+	createTestCSIConfigMap(t, r, namespace)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+	t.Run("first reconcile creates the rados namespace", func(t *testing.T) {
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.NotEmpty(t, cephCommands)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+		assert.Equal(t, int64(1), current.Status.ObservedGeneration)
+	})
+
+	t.Run("unchanged reconcile runs no ceph command", func(t *testing.T) {
+		cephCommands = nil
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Empty(t, cephCommands)
+	})
+
+	t.Run("force reconcile annotation bypasses the skip", func(t *testing.T) {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		current.Annotations = map[string]string{forceReconcileAnnotation: "1"}
+		assert.NoError(t, cl.Update(ctx, current))
+
+		cephCommands = nil
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.NotEmpty(t, cephCommands)
+
+		// the same annotation value does not force another reconcile
+		cephCommands = nil
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, cephCommands)
+	})
+
+	t.Run("pool change bypasses the skip", func(t *testing.T) {
+		pool := &cephv1.CephBlockPool{}
+		assert.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "replicapool", Namespace: namespace}, pool))
+		pool.Spec.FailureDomain = "rack"
+		pool.Generation = 2
+		assert.NoError(t, cl.Update(ctx, pool))
+
+		cephCommands = nil
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, cephCommands)
+	})
+
+	channelKey := mirrorMonitoringChannelKey(radosNamespace)
+	defer r.stopAllMirrorMonitoring(time.Second)
+
+	t.Run("unchanged reconcile requeues to check the mirroring checker", func(t *testing.T) {
+		r.cancelMirrorMonitoring(channelKey)
+		r.initMirrorMonitoring(channelKey)
+		assert.True(t, r.startMirrorMonitoring(channelKey, func(ctx context.Context) { <-ctx.Done() }))
+		r.radosNamespaceContexts[channelKey].interval = time.Minute
+
+		cephCommands = nil
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, cephCommands)
+		staleAfter := mirrorCheckerStaleAfter(time.Minute)
+		assert.GreaterOrEqual(t, res.RequeueAfter, staleAfter)
+		assert.Less(t, res.RequeueAfter, staleAfter+time.Duration(resyncJitterFactor*float64(staleAfter)))
+		assert.True(t, r.radosNamespaceContexts[channelKey].started)
+	})
+
+	t.Run("dead mirroring checker bypasses the skip", func(t *testing.T) {
+		r.cancelMirrorMonitoring(channelKey)
+		r.initMirrorMonitoring(channelKey)
+		assert.True(t, r.startMirrorMonitoring(channelKey, func(ctx context.Context) { <-ctx.Done() }))
+		dead := r.radosNamespaceContexts[channelKey]
+		dead.interval = time.Minute
+		dead.lastHeartbeat = time.Now().Add(-time.Hour)
+
+		// the CR is unchanged since the last reconcile
+		cephCommands = nil
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Error(t, dead.internalCtx.Err())
+		assert.NotEmpty(t, cephCommands)
+	})
+}
+
+func TestReconcileFingerprintTracker(t *testing.T) {
+	name := types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 2},
+		Status:     &cephv1.CephBlockPoolRadosNamespaceStatus{Phase: cephv1.ConditionReady, ObservedGeneration: 2},
+	}
+	fingerprint := newReconcileFingerprint(radosNamespace, &cephv1.CephCluster{}, &cephv1.CephBlockPool{})
+	tracker := &reconcileFingerprintTracker{}
+
+	// nothing is skipped before a successful reconcile
+	assert.False(t, tracker.isUnchanged(name, radosNamespace, fingerprint))
+
+	tracker.record(name, fingerprint)
+	assert.True(t, tracker.isUnchanged(name, radosNamespace, fingerprint))
+
+	// a new generation is not skipped
+	radosNamespace.Generation = 3
+	assert.False(t, tracker.isUnchanged(name, radosNamespace, newReconcileFingerprint(radosNamespace, &cephv1.CephCluster{}, &cephv1.CephBlockPool{})))
+	radosNamespace.Generation = 2
+
+	// a rados namespace that is not ready is not skipped
+	radosNamespace.Status.Phase = cephv1.ConditionFailure
+	assert.False(t, tracker.isUnchanged(name, radosNamespace, fingerprint))
+	radosNamespace.Status.Phase = cephv1.ConditionReady
+
+	// a cluster change is not skipped
+	assert.False(t, tracker.isUnchanged(name, radosNamespace, newReconcileFingerprint(radosNamespace, &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Generation: 5}}, &cephv1.CephBlockPool{})))
+
+	tracker.forget(name)
+	assert.False(t, tracker.isUnchanged(name, radosNamespace, fingerprint))
+}
+
+func TestAnnotationsChangedPredicate(t *testing.T) {
+	p := annotationsChangedPredicate(forceReconcileAnnotation, bootstrapPeerTokenAnnotation)
+	forced := &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{forceReconcileAnnotation: "1"}}}
+	notForced := &cephv1.CephBlockPoolRadosNamespace{}
+
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: notForced, ObjectNew: forced}))
+	assert.False(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: forced, ObjectNew: forced}))
+	bootstrap := &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{bootstrapPeerTokenAnnotation: "1"}}}
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: notForced, ObjectNew: bootstrap}))
+	assert.False(t, p.Create(event.TypedCreateEvent[*cephv1.CephBlockPoolRadosNamespace]{Object: forced}))
+}
+
+// packageAnnotations returns the values of the annotation constants declared in the package
+func packageAnnotations(t *testing.T) map[string]string {
+	files, err := filepath.Glob("*.go")
+	assert.NoError(t, err)
+	annotations := map[string]string{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+		assert.NoError(t, err)
+		for _, decl := range f.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				for i, name := range valueSpec.Names {
+					if !strings.HasSuffix(name.Name, "Annotation") || i >= len(valueSpec.Values) {
+						continue
+					}
+					if lit, ok := valueSpec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						value, err := strconv.Unquote(lit.Value)
+						assert.NoError(t, err)
+						annotations[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+	return annotations
+}
+
+func TestFingerprintAnnotations(t *testing.T) {
+	// the annotations that are not read by the steps skipped for an unchanged rados namespace
+	notFingerprinted := map[string]string{
+		"pausedAnnotation":               "checked before the fingerprint, with its own predicate",
+		"cloneFromAnnotation":            "only read when the rados namespace is created",
+		"diagnosticsAnnotation":          "only read when the reconcile fails",
+		"cephSettingsChecksumAnnotation": "written by the operator, the settings are covered by the generation",
+	}
+	annotations := packageAnnotations(t)
+	assert.Contains(t, annotations, "forceReconcileAnnotation")
+	for name := range notFingerprinted {
+		assert.Contains(t, annotations, name, "stale entry")
+	}
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"}}
+	fingerprint := newReconcileFingerprint(radosNamespace, &cephv1.CephCluster{}, &cephv1.CephBlockPool{})
+	for name, annotation := range annotations {
+		if _, ok := notFingerprinted[name]; ok {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			annotated := radosNamespace.DeepCopy()
+			annotated.Annotations = map[string]string{annotation: "1"}
+			// a new annotation must be part of the fingerprint or be added to notFingerprinted with the reason
+			assert.NotEqual(t, fingerprint, newReconcileFingerprint(annotated, &cephv1.CephCluster{}, &cephv1.CephBlockPool{}))
+			assert.Contains(t, fingerprintAnnotations, annotation)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ignoreImplicitSettingName is the operator setting to ignore the rados namespace CRs of the implicit rados
+// namespace of the pools, which are then only placeholders for which the operator does nothing
+const ignoreImplicitSettingName = "ROOK_RADOS_NAMESPACE_IGNORE_IMPLICIT"
+
+// isReconcileIgnored returns whether the rados namespace is the implicit rados namespace of its pool and the
+// operator is configured to ignore them
+func isReconcileIgnored(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) bool {
+	if cephv1.GetRadosNamespaceName(radosNamespace) != cephv1.ImplicitNamespaceVal {
+		return false
+	}
+	ignore, err := strconv.ParseBool(k8sutil.GetOperatorSetting(ignoreImplicitSettingName, "false"))
+	if err != nil {
+		log.Warningf("failed to parse setting %q, reconciling the implicit rados namespace. %v", ignoreImplicitSettingName, err)
+		return false
+	}
+	return ignore
+}
+
+// ignoreReconcile reports the rados namespace as ignored without running any ceph command or updating the csi
+// config. The mirroring status checker is stopped since mirroring is not reconciled for ignored CRs, and the
+// finalizer is removed when the CR is deleted.
+func (r *ReconcileCephBlockPoolRadosNamespace) ignoreReconcile(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) (reconcile.Result, error) {
+	r.cancelMirrorMonitoring(mirrorMonitoringChannelKey(radosNamespace))
+
+	if !radosNamespace.GetDeletionTimestamp().IsZero() {
+		log.Infof("removing the finalizer of ignored implicit rados namespace %q", name)
+		if err := r.removeFinalizer(radosNamespace); err != nil {
+			return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove finalizer")
+		}
+		r.fingerprints.forget(name)
+		return reconcile.Result{}, nil
+	}
+
+	if isIgnoredCondition(radosNamespace) {
+		log.Debugf("implicit rados namespace %q is ignored", name)
+		return reconcile.Result{}, nil
+	}
+	log.Infof("ignoring implicit rados namespace %q, %q is set", name, ignoreImplicitSettingName)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionIgnored, log, cephv1.Condition{
+		Type:    cephv1.ConditionIgnored,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.ImplicitNamespaceIgnoredReason,
+		Message: fmt.Sprintf("the implicit rados namespace is not reconciled, unset %q in the operator config to reconcile it", ignoreImplicitSettingName),
+	})
+	return reconcile.Result{}, nil
+}
+
+// isIgnoredCondition returns whether the rados namespace was reported as ignored
+func isIgnoredCondition(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	if radosNamespace.Status == nil {
+		return false
+	}
+	condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionIgnored)
+	return condition != nil && condition.Status == v1.ConditionTrue
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIsReconcileIgnored(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"})
+	implicit := &cephv1.CephBlockPoolRadosNamespace{Spec: cephv1.CephBlockPoolRadosNamespaceSpec{Name: cephv1.ImplicitNamespaceKey}}
+	named := &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-a"}}
+
+	assert.False(t, isReconcileIgnored(implicit, log))
+
+	t.Setenv(ignoreImplicitSettingName, "true")
+	assert.True(t, isReconcileIgnored(implicit, log))
+	assert.False(t, isReconcileIgnored(named, log))
+
+	t.Setenv(ignoreImplicitSettingName, "yes please")
+	assert.False(t, isReconcileIgnored(implicit, log))
+}
+
+func TestIgnoredReconcile(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"}
+	newReconciler := func(t *testing.T, radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCommands *[]string) *ReconcileCephBlockPoolRadosNamespace {
+		// the ceph cluster is not ready so that a reconcile that is not ignored stops before running any ceph command
+		cephCluster := &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Namespace, Namespace: name.Namespace},
+		}
+		return newTestReconciler(t, name.Namespace, func(command string, args ...string) (string, error) {
+			*cephCommands = append(*cephCommands, strings.Join(args, " "))
+			return "", nil
+		}, radosNamespace, cephCluster)
+	}
+	newRadosNamespace := func() *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name.Name,
+				Namespace:  name.Namespace,
+				Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+			},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Name:          cephv1.ImplicitNamespaceKey,
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+			},
+		}
+	}
+	channelKey := mirrorMonitoringChannelKey(newRadosNamespace())
+
+	t.Run("ignored implicit rados namespace", func(t *testing.T) {
+		t.Setenv(ignoreImplicitSettingName, "true")
+		var cephCommands []string
+		r := newReconciler(t, newRadosNamespace(), &cephCommands)
+		internalCtx, internalCancel := context.WithCancel(ctx)
+		r.radosNamespaceContexts[channelKey] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel, started: true}
+
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Empty(t, cephCommands)
+		// the mirroring of the implicit rados namespace is not monitored either
+		assert.NotContains(t, r.radosNamespaceContexts, channelKey)
+		assert.Error(t, internalCtx.Err())
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, cephv1.ConditionIgnored, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionIgnored)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.ImplicitNamespaceIgnoredReason, condition.Reason)
+
+		// the status is not updated again by the next reconciles
+		resourceVersion := current.ResourceVersion
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, resourceVersion, current.ResourceVersion)
+	})
+
+	t.Run("ignored implicit rados namespace is reconciled once the setting is unset", func(t *testing.T) {
+		var cephCommands []string
+		radosNamespace := newRadosNamespace()
+		radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{
+			Phase:      cephv1.ConditionIgnored,
+			Conditions: []cephv1.Condition{{Type: cephv1.ConditionIgnored, Status: v1.ConditionTrue, Reason: cephv1.ImplicitNamespaceIgnoredReason}},
+		}
+		r := newReconciler(t, radosNamespace, &cephCommands)
+
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.True(t, res.Requeue)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionIgnored)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.ReconcileStarted, condition.Reason)
+	})
+
+	t.Run("ignored implicit rados namespace is deleted", func(t *testing.T) {
+		t.Setenv(ignoreImplicitSettingName, "true")
+		var cephCommands []string
+		radosNamespace := newRadosNamespace()
+		radosNamespace.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		r := newReconciler(t, radosNamespace, &cephCommands)
+
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Empty(t, cephCommands)
+		err = r.client.Get(ctx, name, &cephv1.CephBlockPoolRadosNamespace{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strconv"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// imageCountIntervalSettingName is the operator setting with the interval of the refresh of the image count of
+	// the rados namespaces, e.g. "1h". The images are listed at most once per interval. An interval of 0 disables
+	// the image count.
+	imageCountIntervalSettingName = "ROOK_RADOS_NAMESPACE_IMAGE_COUNT_INTERVAL"
+	defaultImageCountInterval     = "1h"
+	// imageCountMaxSettingName is the operator setting with the number of images above which the image count of
+	// a rados namespace is no longer refreshed since listing its images is too expensive. A maximum of 0 always
+	// refreshes the count.
+	imageCountMaxSettingName = "ROOK_RADOS_NAMESPACE_IMAGE_COUNT_MAX"
+	defaultImageCountMax     = 10000
+
+	// imageCountInfoKey records in the status info the number of images of the rados namespace, and
+	// imageCountTimeInfoKey the time it was counted
+	imageCountInfoKey     = "imageCount"
+	imageCountTimeInfoKey = "imageCountTime"
+)
+
+// imageCountInterval returns the interval of the refresh of the image count, the image count is disabled if the
+// setting is invalid
+func imageCountInterval(log *reconcileLogger) time.Duration {
+	value := k8sutil.GetOperatorSetting(imageCountIntervalSettingName, defaultImageCountInterval)
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warningf("invalid setting %q value %q, the image count is disabled. %v", imageCountIntervalSettingName, value, err)
+		return 0
+	}
+	return interval
+}
+
+// imageCountMax returns the number of images above which the image count is not refreshed, the default maximum
+// is used if the setting is invalid
+func imageCountMax(log *reconcileLogger) int {
+	value := k8sutil.GetOperatorSetting(imageCountMaxSettingName, strconv.Itoa(defaultImageCountMax))
+	maxImages, err := strconv.Atoi(value)
+	if err != nil || maxImages < 0 {
+		log.Warningf("invalid setting %q value %q, using the default maximum of %d images. %v", imageCountMaxSettingName, value, defaultImageCountMax, err)
+		return defaultImageCountMax
+	}
+	return maxImages
+}
+
+// imageCountResync returns the interval of the periodic reconcile, shortened to the interval of the image count
+// so that the count is refreshed on schedule
+func imageCountResync(resync, imageCount time.Duration) time.Duration {
+	if imageCount > 0 && (resync <= 0 || imageCount < resync) {
+		return imageCount
+	}
+	return resync
+}
+
+// reconcileImageCount records the number of images of the rados namespace in the status info, at most once per
+// interval. The count of a rados namespace with more images than the maximum is not refreshed. Failing to count
+// the images does not fail the reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileImageCount(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) {
+	interval := imageCountInterval(log)
+	if interval <= 0 {
+		return
+	}
+	var info map[string]string
+	if radosNamespace.Status != nil {
+		info = radosNamespace.Status.Info
+	}
+	if counted, err := time.Parse(time.RFC3339, info[imageCountTimeInfoKey]); err == nil && r.now().Sub(counted) < interval {
+		return
+	}
+	maxImages := imageCountMax(log)
+	if count, err := strconv.Atoi(info[imageCountInfoKey]); err == nil && maxImages > 0 && count > maxImages {
+		log.Debugf("not refreshing the image count of rados namespace %q with %d images, more than the maximum of %d", name, count, maxImages)
+		return
+	}
+
+	var images []string
+	err := log.timeCephCall("list image names", func() error {
+		var err error
+		images, err = cephclient.ListImageNamesInRadosNamespace(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
+		return err
+	})
+	if err != nil {
+		log.Warningf("failed to count the images of rados namespace %q. %v", name, err)
+		return
+	}
+
+	count := strconv.Itoa(len(images))
+	counted := r.now().UTC().Format(time.RFC3339)
+	err = r.mutateStatus(name, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		if current.Status.Info == nil {
+			current.Status.Info = map[string]string{}
+		}
+		current.Status.Info[imageCountInfoKey] = count
+		current.Status.Info[imageCountTimeInfoKey] = counted
+		return true
+	})
+	if err != nil {
+		log.Warningf("failed to record the image count of rados namespace %q. %v", name, err)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestImageCountSettings(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.Equal(t, time.Hour, imageCountInterval(log))
+	assert.Equal(t, 10000, imageCountMax(log))
+
+	t.Setenv(imageCountIntervalSettingName, "10m")
+	t.Setenv(imageCountMaxSettingName, "500")
+	assert.Equal(t, 10*time.Minute, imageCountInterval(log))
+	assert.Equal(t, 500, imageCountMax(log))
+
+	t.Setenv(imageCountIntervalSettingName, "invalid")
+	t.Setenv(imageCountMaxSettingName, "invalid")
+	assert.Zero(t, imageCountInterval(log))
+	assert.Equal(t, 10000, imageCountMax(log))
+}
+
+func TestImageCountResync(t *testing.T) {
+	assert.Equal(t, time.Hour, imageCountResync(0, time.Hour))
+	assert.Equal(t, time.Hour, imageCountResync(2*time.Hour, time.Hour))
+	assert.Equal(t, 30*time.Minute, imageCountResync(30*time.Minute, time.Hour))
+	assert.Equal(t, 30*time.Minute, imageCountResync(30*time.Minute, 0))
+	assert.Zero(t, imageCountResync(0, 0))
+}
+
+func TestReconcileImageCount(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	countedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// images are the images of the rados namespace, listed is the number of times they are listed
+	images := []string{"csi-vol-1", "csi-vol-2", "csi-vol-3"}
+	listed := 0
+	fakeClock := clocktesting.NewFakePassiveClock(countedAt)
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		clock:  fakeClock,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "ls" {
+						assert.Equal(t, []string{"ls", "replicapool", "--namespace", "namespace-a"}, args[:4])
+						listed++
+						output, err := json.Marshal(images)
+						return string(output), err
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		opManagerContext: ctx,
+	}
+	getCurrent := func(t *testing.T) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, current))
+		return current
+	}
+
+	t.Run("images are counted", func(t *testing.T) {
+		r.reconcileImageCount(getCurrent(t), name, log)
+		assert.Equal(t, 1, listed)
+		current := getCurrent(t)
+		assert.Equal(t, "3", current.Status.Info[imageCountInfoKey])
+		assert.Equal(t, "2025-01-01T10:00:00Z", current.Status.Info[imageCountTimeInfoKey])
+	})
+
+	t.Run("count is not refreshed before the interval", func(t *testing.T) {
+		images = append(images, "csi-vol-4")
+		fakeClock.SetTime(countedAt.Add(30 * time.Minute))
+		r.reconcileImageCount(getCurrent(t), name, log)
+		assert.Equal(t, 1, listed)
+		assert.Equal(t, "3", getCurrent(t).Status.Info[imageCountInfoKey])
+	})
+
+	t.Run("count is refreshed after the interval", func(t *testing.T) {
+		fakeClock.SetTime(countedAt.Add(time.Hour))
+		r.reconcileImageCount(getCurrent(t), name, log)
+		assert.Equal(t, 2, listed)
+		current := getCurrent(t)
+		assert.Equal(t, "4", current.Status.Info[imageCountInfoKey])
+		assert.Equal(t, "2025-01-01T11:00:00Z", current.Status.Info[imageCountTimeInfoKey])
+	})
+
+	t.Run("large rados namespace is not refreshed", func(t *testing.T) {
+		t.Setenv(imageCountMaxSettingName, "3")
+		for i := 5; i <= 10; i++ {
+			images = append(images, fmt.Sprintf("csi-vol-%d", i))
+		}
+		fakeClock.SetTime(countedAt.Add(2 * time.Hour))
+		r.reconcileImageCount(getCurrent(t), name, log)
+		assert.Equal(t, 2, listed)
+		assert.Equal(t, "4", getCurrent(t).Status.Info[imageCountInfoKey])
+
+		// a maximum of 0 always refreshes the count
+		t.Setenv(imageCountMaxSettingName, "0")
+		r.reconcileImageCount(getCurrent(t), name, log)
+		assert.Equal(t, 3, listed)
+		assert.Equal(t, "10", getCurrent(t).Status.Info[imageCountInfoKey])
+	})
+
+	t.Run("image count disabled", func(t *testing.T) {
+		t.Setenv(imageCountIntervalSettingName, "0")
+		fakeClock.SetTime(countedAt.Add(24 * time.Hour))
+		r.reconcileImageCount(getCurrent(t), name, log)
+		assert.Equal(t, 3, listed)
+	})
+}
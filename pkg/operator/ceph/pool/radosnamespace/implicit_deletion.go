@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+// removeImplicitCSIConfigSettingName is the operator setting deciding whether the csi config entry of the implicit
+// rados namespace of a pool is removed when its CR is deleted. The implicit rados namespace is never deleted from
+// ceph, so its entry can be kept for the volumes still provisioned with its cluster ID. The sweep of the orphaned
+// csi config entries skips the entries of the implicit rados namespaces, so a kept entry is not removed later.
+const removeImplicitCSIConfigSettingName = "ROOK_RADOS_NAMESPACE_REMOVE_IMPLICIT_CSI_CONFIG"
+
+// removesImplicitCSIConfig returns whether the csi config entry of the implicit rados namespace is removed when
+// its CR is deleted, which is the default
+func removesImplicitCSIConfig(log *reconcileLogger) bool {
+	remove, err := strconv.ParseBool(k8sutil.GetOperatorSetting(removeImplicitCSIConfigSettingName, "true"))
+	if err != nil {
+		log.Warningf("failed to parse setting %q, removing the csi config of the implicit rados namespace. %v", removeImplicitCSIConfigSettingName, err)
+		return true
+	}
+	return remove
+}
+
+// removesCSIConfigOnDeletion returns whether the csi config entry of the rados namespace is removed when its CR is
+// deleted. The entry is only removed with the last CR of the rados namespace, and the entry of the implicit rados
+// namespace is removed unless the operator setting keeps it.
+func removesCSIConfigOnDeletion(radosNamespace *cephv1.CephBlockPoolRadosNamespace, radosNamespaceCRs int, log *reconcileLogger) bool {
+	if radosNamespaceCRs > 1 {
+		return false
+	}
+	if cephv1.GetRadosNamespaceName(radosNamespace) == cephv1.ImplicitNamespaceVal {
+		return removesImplicitCSIConfig(log)
+	}
+	return true
+}
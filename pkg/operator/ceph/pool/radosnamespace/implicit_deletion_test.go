@@ -0,0 +1,125 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRemovesCSIConfigOnDeletion(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	named := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	implicit := named.DeepCopy()
+	implicit.Spec.Name = cephv1.ImplicitNamespaceKey
+
+	assert.True(t, removesCSIConfigOnDeletion(named, 1, log))
+	assert.True(t, removesCSIConfigOnDeletion(implicit, 1, log))
+	// the entry is kept for the other CRs of the rados namespace
+	assert.False(t, removesCSIConfigOnDeletion(named, 2, log))
+	assert.False(t, removesCSIConfigOnDeletion(implicit, 2, log))
+
+	t.Setenv(removeImplicitCSIConfigSettingName, "false")
+	assert.False(t, removesCSIConfigOnDeletion(implicit, 1, log))
+	// the setting only applies to the implicit rados namespace
+	assert.True(t, removesCSIConfigOnDeletion(named, 1, log))
+
+	t.Setenv(removeImplicitCSIConfigSettingName, "sometimes")
+	assert.True(t, removesCSIConfigOnDeletion(implicit, 1, log))
+}
+
+func TestImplicitRadosNamespaceDeletion(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	tests := []struct {
+		name            string
+		setting         string
+		expectCSIConfig bool
+	}{
+		{name: "csi config is removed by default", expectCSIConfig: false},
+		{name: "csi config is removed when enabled", setting: "true", expectCSIConfig: false},
+		{name: "csi config is kept when disabled", setting: "false", expectCSIConfig: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setting != "" {
+				t.Setenv(removeImplicitCSIConfigSettingName, tt.setting)
+			}
+			now := metav1.Now()
+			radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "namespace-a",
+					Namespace:         namespace,
+					Finalizers:        []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+					DeletionTimestamp: &now,
+				},
+				TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+				Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+					BlockPoolName: "replicapool",
+					Name:          cephv1.ImplicitNamespaceKey,
+				},
+			}
+			var cephCommands []string
+			r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+				cephCommands = append(cephCommands, strings.Join(args, " "))
+				return "", nil
+			}, radosNamespace, newTestCephCluster(namespace))
+			cl := r.client
+			c := r.context
+
+			// Create the CSI config map with an entry for the implicit rados namespace
+			createTestCSIConfigMap(t, r, namespace)
+			clusterInfo := &cephclient.ClusterInfo{Namespace: namespace, Context: ctx}
+			err := csi.SaveClusterConfig(c.Clientset, buildClusterID(radosNamespace), namespace, clusterInfo, &csi.CSIClusterConfigEntry{Namespace: namespace})
+			assert.NoError(t, err)
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+			_, err = r.Reconcile(ctx, req)
+			assert.NoError(t, err)
+
+			// the implicit rados namespace is never deleted from ceph
+			for _, command := range cephCommands {
+				assert.False(t, strings.HasPrefix(command, "namespace remove"), command)
+			}
+			// the finalizer is removed in both cases
+			err = cl.Get(ctx, req.NamespacedName, &cephv1.CephBlockPoolRadosNamespace{})
+			assert.True(t, kerrors.IsNotFound(err))
+
+			cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+			assert.NoError(t, err)
+			if tt.expectCSIConfig {
+				assert.Contains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace))
+			} else {
+				assert.NotContains(t, cm.Data[csi.ConfigKey], buildClusterID(radosNamespace))
+			}
+		})
+	}
+}
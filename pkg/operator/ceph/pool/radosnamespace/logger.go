@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	correlationIDSuffixLength = 5
+	// maxRecentCephCalls is the number of the last ceph calls of a reconcile kept for the diagnostics
+	maxRecentCephCalls = 20
+)
+
+// reconcileLogger prefixes the messages of the package logger with the correlation ID of a
+// single reconcile so that the logs of concurrent reconciles can be told apart
+type reconcileLogger struct {
+	correlationID string
+	// cephCalls and cephCallsDuration accumulate the ceph calls timed during the reconcile
+	cephCalls         int
+	cephCallsDuration time.Duration
+	// recentCephCalls are the last ceph calls of the reconcile with their result
+	recentCephCalls []cephCallRecord
+}
+
+// cephCallRecord is the result of a ceph call. The error of a failed call includes the output of the ceph command.
+type cephCallRecord struct {
+	Operation string `json:"operation"`
+	Duration  string `json:"duration"`
+	Error     string `json:"error,omitempty"`
+}
+
+func newReconcileLogger(name types.NamespacedName) *reconcileLogger {
+	return &reconcileLogger{correlationID: fmt.Sprintf("%s-%s", name.String(), rand.String(correlationIDSuffixLength))}
+}
+
+func (l *reconcileLogger) prefix(format string) string {
+	return fmt.Sprintf("[%s] %s", l.correlationID, format)
+}
+
+func (l *reconcileLogger) Debug(msg string) {
+	logger.Debug(l.prefix(msg))
+}
+
+func (l *reconcileLogger) Debugf(format string, args ...interface{}) {
+	logger.Debugf(l.prefix(format), args...)
+}
+
+func (l *reconcileLogger) Info(msg string) {
+	logger.Info(l.prefix(msg))
+}
+
+func (l *reconcileLogger) Infof(format string, args ...interface{}) {
+	logger.Infof(l.prefix(format), args...)
+}
+
+func (l *reconcileLogger) Warningf(format string, args ...interface{}) {
+	logger.Warningf(l.prefix(format), args...)
+}
+
+func (l *reconcileLogger) Errorf(format string, args ...interface{}) {
+	logger.Errorf(l.prefix(format), args...)
+}
+
+// timeCephCall runs a ceph call and logs its duration, which is added to the total of the reconcile. The error of
+// the call is returned unchanged.
+func (l *reconcileLogger) timeCephCall(operation string, call func() error) error {
+	start := time.Now()
+	err := call()
+	duration := time.Since(start)
+	l.cephCalls++
+	l.cephCallsDuration += duration
+	l.recordCephCall(operation, duration, err)
+	l.Debugf("ceph call %q took %s", operation, duration.String())
+	return err
+}
+
+func (l *reconcileLogger) recordCephCall(operation string, duration time.Duration, err error) {
+	record := cephCallRecord{Operation: operation, Duration: duration.String()}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	l.recentCephCalls = append(l.recentCephCalls, record)
+	if len(l.recentCephCalls) > maxRecentCephCalls {
+		l.recentCephCalls = l.recentCephCalls[len(l.recentCephCalls)-maxRecentCephCalls:]
+	}
+}
+
+// logCephCallsDuration logs the total duration of the ceph calls of the reconcile
+func (l *reconcileLogger) logCephCallsDuration() {
+	if l.cephCalls == 0 {
+		return
+	}
+	l.Debugf("%d ceph calls took %s in total", l.cephCalls, l.cephCallsDuration.String())
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNewReconcileLogger(t *testing.T) {
+	name := types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"}
+	idRegex := regexp.MustCompile(`^rook-ceph/namespace-a-[a-z0-9]{5}$`)
+
+	l1 := newReconcileLogger(name)
+	l2 := newReconcileLogger(name)
+	assert.Regexp(t, idRegex, l1.correlationID)
+	assert.Regexp(t, idRegex, l2.correlationID)
+	assert.NotEqual(t, l1.correlationID, l2.correlationID)
+	assert.Equal(t, "["+l1.correlationID+"] deleting %q", l1.prefix("deleting %q"))
+}
+
+func TestReconcileLogCorrelationID(t *testing.T) {
+	ctx := context.TODO()
+	logBuf := bytes.NewBuffer([]byte{})
+	capnslog.SetFormatter(capnslog.NewLogFormatter(logBuf, "", 0))
+	defer capnslog.SetFormatter(capnslog.NewPrettyFormatter(os.Stderr, false))
+	capnslog.SetGlobalLogLevel(capnslog.DEBUG)
+	t.Setenv("POD_NAMESPACE", "")
+
+	var (
+		name      = "namespace-a"
+		namespace = "rook-ceph"
+	)
+
+	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  namespace,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+		},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{},
+	}
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" && args[1] == "pool" {
+			return `{"mode":"disabled"}`, nil
+		}
+		return "", nil
+	}, cephBlockPoolRadosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	idRegex := regexp.MustCompile(`\[` + regexp.QuoteMeta(req.NamespacedName.String()) + `-([a-z0-9]{5})\]`)
+
+	// returns the correlation IDs found in the log output of one reconcile
+	reconcileIDs := func() []string {
+		logBuf.Reset()
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		ids := []string{}
+		for _, match := range idRegex.FindAllStringSubmatch(logBuf.String(), -1) {
+			ids = append(ids, match[1])
+		}
+		return ids
+	}
+
+	firstIDs := reconcileIDs()
+	assert.Greater(t, len(firstIDs), 1)
+	for _, id := range firstIDs {
+		assert.Equal(t, firstIDs[0], id)
+	}
+
+	secondIDs := reconcileIDs()
+	assert.Greater(t, len(secondIDs), 1)
+	for _, id := range secondIDs {
+		assert.Equal(t, secondIDs[0], id)
+	}
+	assert.NotEqual(t, firstIDs[0], secondIDs[0])
+}
+
+func TestTimeCephCall(t *testing.T) {
+	logBuf := bytes.NewBuffer([]byte{})
+	capnslog.SetFormatter(capnslog.NewLogFormatter(logBuf, "", 0))
+	defer capnslog.SetFormatter(capnslog.NewPrettyFormatter(os.Stderr, false))
+	capnslog.SetGlobalLogLevel(capnslog.DEBUG)
+
+	log := newReconcileLogger(types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"})
+
+	// no total is logged without ceph calls
+	log.logCephCallsDuration()
+	assert.Empty(t, logBuf.String())
+
+	callErr := errors.New("failed to create rados namespace")
+	err := log.timeCephCall("create rados namespace", func() error {
+		time.Sleep(10 * time.Millisecond)
+		return callErr
+	})
+	assert.Equal(t, callErr, err)
+	err = log.timeCephCall("get mirroring info", func() error { return nil })
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, log.cephCalls)
+	assert.GreaterOrEqual(t, log.cephCallsDuration, 10*time.Millisecond)
+	assert.Regexp(t, regexp.MustCompile(`\[`+regexp.QuoteMeta(log.correlationID)+`\] ceph call "create rados namespace" took [0-9.]+ms`), logBuf.String())
+	assert.Contains(t, logBuf.String(), `ceph call "get mirroring info" took`)
+
+	log.logCephCallsDuration()
+	assert.Contains(t, logBuf.String(), "2 ceph calls took "+log.cephCallsDuration.String()+" in total")
+}
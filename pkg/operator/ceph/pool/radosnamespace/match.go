@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// globMetaCharacters are the characters making a pattern a glob rather than a prefix
+const globMetaCharacters = `*?[\`
+
+// MatchRadosNamespaces returns the rados namespace CRs of a CephBlockPool whose rados namespace name matches the
+// pattern, sorted by the namespace and name of the CRs, so that external controllers can run bulk operations such
+// as enabling mirroring on all the "team-*" rados namespaces. The pattern is a glob as matched by path.Match, or a
+// prefix if it has no glob characters. The CRs are listed with the index of the CephBlockPool of the rados
+// namespaces, the client must be the client of the manager running the rados namespace controller.
+func MatchRadosNamespaces(ctx context.Context, c client.Reader, cephBlockPool types.NamespacedName, pattern string) ([]cephv1.CephBlockPoolRadosNamespace, error) {
+	isGlob := strings.ContainsAny(pattern, globMetaCharacters)
+	if isGlob {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid rados namespace pattern %q", pattern)
+		}
+	}
+
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	err := c.List(ctx, radosNamespaces, client.MatchingFields{blockPoolNameIndex: blockPoolKey(cephBlockPool.Namespace, cephBlockPool.Name)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the rados namespaces of CephBlockPool %q", cephBlockPool)
+	}
+
+	matching := []cephv1.CephBlockPoolRadosNamespace{}
+	for _, radosNamespace := range radosNamespaces.Items {
+		name := cephv1.GetRadosNamespaceName(&radosNamespace)
+		matched := strings.HasPrefix(name, pattern)
+		if isGlob {
+			// the pattern was validated
+			matched, _ = path.Match(pattern, name)
+		}
+		if matched {
+			matching = append(matching, radosNamespace)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		if matching[i].Namespace != matching[j].Namespace {
+			return matching[i].Namespace < matching[j].Namespace
+		}
+		return matching[i].Name < matching[j].Name
+	})
+	return matching, nil
+}
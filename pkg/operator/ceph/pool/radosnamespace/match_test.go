@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMatchRadosNamespaces(t *testing.T) {
+	ctx := context.TODO()
+	newRadosNamespace := func(namespace, name, pool string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: pool},
+		}
+	}
+	// the rados namespace name is matched rather than the name of the CR
+	renamed := newRadosNamespace("rook-ceph", "billing", "replicapool")
+	renamed.Spec.Name = "team-billing"
+	// the rados namespace of another namespace created in the pool
+	remote := newRadosNamespace("tenant", "team-tenant", "replicapool")
+	remote.Spec.BlockPoolNamespace = "rook-ceph"
+
+	objects := []runtime.Object{
+		newRadosNamespace("rook-ceph", "team-a", "replicapool"),
+		newRadosNamespace("rook-ceph", "team-b", "replicapool"),
+		newRadosNamespace("rook-ceph", "teams", "replicapool"),
+		newRadosNamespace("rook-ceph", "db-1", "replicapool"),
+		newRadosNamespace("rook-ceph", "db-2", "replicapool"),
+		newRadosNamespace("rook-ceph", "team-c", "otherpool"),
+		renamed,
+		remote,
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).
+		WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, blockPoolNameIndex, indexBlockPoolName).Build()
+	pool := types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"}
+
+	matchedNames := func(pattern string) []string {
+		matching, err := MatchRadosNamespaces(ctx, cl, pool, pattern)
+		assert.NoError(t, err)
+		names := []string{}
+		for _, radosNamespace := range matching {
+			names = append(names, radosNamespace.Namespace+"/"+radosNamespace.Name)
+		}
+		return names
+	}
+
+	t.Run("glob", func(t *testing.T) {
+		assert.Equal(t, []string{"rook-ceph/billing", "rook-ceph/team-a", "rook-ceph/team-b", "tenant/team-tenant"}, matchedNames("team-*"))
+		assert.Equal(t, []string{"rook-ceph/db-1", "rook-ceph/db-2"}, matchedNames("db-?"))
+		assert.Equal(t, []string{"rook-ceph/team-b"}, matchedNames("team-[b-c]"))
+		assert.Empty(t, matchedNames("*-z"))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		assert.Equal(t, []string{"rook-ceph/billing", "rook-ceph/team-a", "rook-ceph/team-b", "rook-ceph/teams", "tenant/team-tenant"}, matchedNames("team"))
+		assert.Equal(t, []string{"rook-ceph/db-2"}, matchedNames("db-2"))
+		assert.Len(t, matchedNames(""), 7)
+	})
+
+	t.Run("other pool", func(t *testing.T) {
+		matching, err := MatchRadosNamespaces(ctx, cl, types.NamespacedName{Name: "otherpool", Namespace: "rook-ceph"}, "team-*")
+		assert.NoError(t, err)
+		assert.Len(t, matching, 1)
+		assert.Equal(t, "team-c", matching[0].Name)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := MatchRadosNamespaces(ctx, cl, pool, "team-[")
+		assert.Error(t, err)
+	})
+}
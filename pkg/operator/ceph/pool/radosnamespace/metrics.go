@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// mirrorCheckersLeakCheckInterval is the minimum interval between two checks for leaked mirroring checkers
+const mirrorCheckersLeakCheckInterval = 5 * time.Minute
+
+// mirrorCheckersGauge is the number of running mirroring status checker go routines of the rados namespaces
+var mirrorCheckersGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "rook",
+	Subsystem: "ceph_rados_namespace",
+	Name:      "mirror_checkers",
+	Help:      "Number of running mirroring status checkers of the CephBlockPoolRadosNamespaces",
+})
+
+// mirrorCheckerRestartsCounter is the number of mirroring status checkers restarted after they stopped
+var mirrorCheckerRestartsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "rook",
+	Subsystem: "ceph_rados_namespace",
+	Name:      "mirror_checker_restarts_total",
+	Help:      "Number of mirroring status checkers of the CephBlockPoolRadosNamespaces restarted after they stopped",
+})
+
+// deletionBlockedGauge is set to 1 while the deletion of a rados namespace is blocked because it contains images
+// or snapshots, and to 0 once it is empty. The series of a rados namespace is removed when the CR is gone.
+var deletionBlockedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "rook",
+	Subsystem: "ceph_rados_namespace",
+	Name:      "deletion_blocked",
+	Help:      "Whether the deletion of the CephBlockPoolRadosNamespace is blocked because it contains images or snapshots",
+}, []string{"namespace", "name"})
+
+func init() {
+	metrics.Registry.MustRegister(mirrorCheckersGauge, mirrorCheckerRestartsCounter, deletionBlockedGauge)
+}
+
+// setDeletionBlocked reports whether the deletion of the rados namespace is blocked by its images
+func setDeletionBlocked(name types.NamespacedName, blocked bool) {
+	value := 0.0
+	if blocked {
+		value = 1
+	}
+	deletionBlockedGauge.WithLabelValues(name.Namespace, name.Name).Set(value)
+}
+
+// forgetDeletionBlocked removes the deletion blocked series of a rados namespace that is gone
+func forgetDeletionBlocked(name types.NamespacedName) {
+	deletionBlockedGauge.DeleteLabelValues(name.Namespace, name.Name)
+}
+
+// checkMirrorCheckersLeak warns when more mirroring contexts are tracked than there are rados namespace CRs,
+// which means that the contexts of deleted rados namespaces were not cancelled. It is run from the reconcile
+// at most once per mirrorCheckersLeakCheckInterval so that it does not race with the updates of the contexts.
+func (r *ReconcileCephBlockPoolRadosNamespace) checkMirrorCheckersLeak() {
+	if time.Since(r.lastMirrorCheckersLeakCheck) < mirrorCheckersLeakCheckInterval {
+		return
+	}
+	r.lastMirrorCheckersLeakCheck = time.Now()
+
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	if err := r.client.List(r.opManagerContext, radosNamespaces); err != nil {
+		logger.Warningf("failed to list rados namespaces to check for leaked mirroring checkers. %v", err)
+		return
+	}
+	r.radosNamespaceContextsLock.Lock()
+	tracked := len(r.radosNamespaceContexts)
+	r.radosNamespaceContextsLock.Unlock()
+	if tracked > len(radosNamespaces.Items) {
+		logger.Warningf("%d mirroring contexts are tracked for %d rados namespaces, the mirroring checkers of deleted rados namespaces may be leaked",
+			tracked, len(radosNamespaces.Items))
+	}
+}
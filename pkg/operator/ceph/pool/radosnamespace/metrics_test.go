@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func mirrorCheckersGaugeValue(t *testing.T) float64 {
+	m := &dto.Metric{}
+	assert.NoError(t, mirrorCheckersGauge.Write(m))
+	return m.GetGauge().GetValue()
+}
+
+func TestMirrorCheckersGauge(t *testing.T) {
+	r := &ReconcileCephBlockPoolRadosNamespace{radosNamespaceContexts: map[string]*mirrorHealth{}}
+	newContext := func(key string) {
+		internalCtx, internalCancel := context.WithCancel(context.TODO())
+		r.radosNamespaceContexts[key] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel}
+	}
+	initial := mirrorCheckersGaugeValue(t)
+	running := make(chan struct{}, 2)
+	checkMirroring := func(ctx context.Context) {
+		running <- struct{}{}
+		<-ctx.Done()
+	}
+
+	newContext("rook-ceph/replicapool/namespace-a")
+	newContext("rook-ceph/replicapool/namespace-b")
+	r.startMirrorMonitoring("rook-ceph/replicapool/namespace-a", checkMirroring)
+	r.startMirrorMonitoring("rook-ceph/replicapool/namespace-b", checkMirroring)
+	<-running
+	<-running
+	assert.Equal(t, initial+2, mirrorCheckersGaugeValue(t))
+
+	r.cancelMirrorMonitoring("rook-ceph/replicapool/namespace-a")
+	assert.Equal(t, initial+1, mirrorCheckersGaugeValue(t))
+
+	// cancelling an unknown key does not change the gauge
+	r.cancelMirrorMonitoring("rook-ceph/replicapool/namespace-a")
+	assert.Equal(t, initial+1, mirrorCheckersGaugeValue(t))
+
+	// a context without a running checker does not change the gauge
+	newContext("rook-ceph/replicapool/namespace-c")
+	r.cancelMirrorMonitoring("rook-ceph/replicapool/namespace-c")
+	assert.Equal(t, initial+1, mirrorCheckersGaugeValue(t))
+
+	r.cancelMirrorMonitoring("rook-ceph/replicapool/namespace-b")
+	assert.Equal(t, initial, mirrorCheckersGaugeValue(t))
+	assert.Empty(t, r.radosNamespaceContexts)
+}
+
+func TestCheckMirrorCheckersLeak(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(&cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+	}).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           cl,
+		opManagerContext: context.TODO(),
+		radosNamespaceContexts: map[string]*mirrorHealth{
+			"rook-ceph/replicapool/namespace-a": {},
+			"rook-ceph/replicapool/namespace-b": {},
+		},
+	}
+
+	r.checkMirrorCheckersLeak()
+	lastCheck := r.lastMirrorCheckersLeakCheck
+	assert.False(t, lastCheck.IsZero())
+
+	// the check is not repeated before the interval
+	r.checkMirrorCheckersLeak()
+	assert.Equal(t, lastCheck, r.lastMirrorCheckersLeakCheck)
+
+	r.lastMirrorCheckersLeakCheck = time.Now().Add(-mirrorCheckersLeakCheckInterval)
+	r.checkMirrorCheckersLeak()
+	assert.True(t, r.lastMirrorCheckersLeakCheck.After(lastCheck))
+}
+
+// deletionBlockedValue returns the deletion blocked gauge of the rados namespace, and whether its series exists
+func deletionBlockedValue(t *testing.T, name types.NamespacedName) (float64, bool) {
+	ch := make(chan prometheus.Metric, 100)
+	deletionBlockedGauge.Collect(ch)
+	close(ch)
+	for metric := range ch {
+		m := &dto.Metric{}
+		assert.NoError(t, metric.Write(m))
+		labels := map[string]string{}
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["namespace"] == name.Namespace && labels["name"] == name.Name {
+			return m.GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
+
+func TestDeletionBlockedGauge(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	images := 2
+	c := &clusterd.Context{
+		Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "pool" && args[1] == "stats" {
+					return fmt.Sprintf(`{"images":{"count":%d,"snap_count":0}}`, images), nil
+				}
+				return "", nil
+			},
+		},
+	}
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+		context:          c,
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		opManagerContext: ctx,
+		recorder:         record.NewFakeRecorder(5),
+	}
+	log := newReconcileLogger(name)
+
+	_, found := deletionBlockedValue(t, name)
+	assert.False(t, found)
+
+	blocked, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+	assert.Error(t, err)
+	assert.True(t, blocked)
+	value, found := deletionBlockedValue(t, name)
+	assert.True(t, found)
+	assert.Equal(t, 1.0, value)
+
+	images = 0
+	blocked, err = r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+	value, found = deletionBlockedValue(t, name)
+	assert.True(t, found)
+	assert.Equal(t, 0.0, value)
+
+	// the series is removed once the CR is gone
+	assert.NoError(t, r.client.Delete(ctx, radosNamespace))
+	_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+	assert.NoError(t, err)
+	_, found = deletionBlockedValue(t, name)
+	assert.False(t, found)
+}
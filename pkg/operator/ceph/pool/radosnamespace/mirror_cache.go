@@ -0,0 +1,93 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"sync"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// mirroringInfoCacheTTL is how long the mirroring info of a pool/radosNamespace is reused
+const mirroringInfoCacheTTL = 10 * time.Second
+
+// mirroringInfoCache caches the mirroring info of the pools/radosNamespaces for a short time to avoid
+// running the same ceph command repeatedly
+type mirroringInfoCache struct {
+	mutex   sync.Mutex
+	entries map[string]mirroringInfoCacheEntry
+}
+
+type mirroringInfoCacheEntry struct {
+	info    *cephv1.MirroringInfo
+	expires time.Time
+}
+
+func mirroringInfoCacheKey(clusterInfo *cephclient.ClusterInfo, poolAndRadosNamespaceName string) string {
+	return types.NamespacedName{Namespace: clusterInfo.Namespace, Name: poolAndRadosNamespaceName}.String()
+}
+
+// get returns the mirroring info of the pool/radosNamespace, only running the ceph command if the cached
+// info is missing or expired
+func (c *mirroringInfoCache) get(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolAndRadosNamespaceName string) (*cephv1.MirroringInfo, error) {
+	key := mirroringInfoCacheKey(clusterInfo, poolAndRadosNamespaceName)
+
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	c.mutex.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		logger.Debugf("using cached mirroring info of %q", poolAndRadosNamespaceName)
+		return entry.info, nil
+	}
+
+	info, err := cephclient.GetPoolMirroringInfo(context, clusterInfo, poolAndRadosNamespaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]mirroringInfoCacheEntry{}
+	}
+	c.entries[key] = mirroringInfoCacheEntry{info: info, expires: time.Now().Add(mirroringInfoCacheTTL)}
+	return info, nil
+}
+
+// invalidate removes the cached mirroring info of the pool/radosNamespace, it must be called when the
+// mirroring is enabled or disabled
+func (c *mirroringInfoCache) invalidate(clusterInfo *cephclient.ClusterInfo, poolAndRadosNamespaceName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, mirroringInfoCacheKey(clusterInfo, poolAndRadosNamespaceName))
+}
+
+// peek returns the cached mirroring info of the pool/radosNamespace even if it expired, without running the ceph
+// command, or nil if the info is not cached
+func (c *mirroringInfoCache) peek(clusterInfo *cephclient.ClusterInfo, poolAndRadosNamespaceName string) *cephv1.MirroringInfo {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[mirroringInfoCacheKey(clusterInfo, poolAndRadosNamespaceName)]
+	if !ok {
+		return nil
+	}
+	return entry.info
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirroringInfoCache(t *testing.T) {
+	infoCalls := 0
+	c := &clusterd.Context{
+		Executor: &exectest.MockExecutor{
+			MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+				if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+					infoCalls++
+					return `{"mode":"image"}`, nil
+				}
+				return "", nil
+			},
+		},
+	}
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "rook-ceph", Context: context.TODO()}
+	cache := &mirroringInfoCache{}
+
+	t.Run("info is fetched once within the ttl", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			info, err := cache.get(c, clusterInfo, "replicapool/namespace-a")
+			assert.NoError(t, err)
+			assert.Equal(t, "image", info.Mode)
+		}
+		assert.Equal(t, 1, infoCalls)
+
+		// another rados namespace is not served from the cache
+		_, err := cache.get(c, clusterInfo, "replicapool/namespace-b")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, infoCalls)
+	})
+
+	t.Run("info is fetched again after invalidation", func(t *testing.T) {
+		cache.invalidate(clusterInfo, "replicapool/namespace-a")
+		_, err := cache.get(c, clusterInfo, "replicapool/namespace-a")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, infoCalls)
+	})
+
+	t.Run("info is fetched again after expiry", func(t *testing.T) {
+		key := mirroringInfoCacheKey(clusterInfo, "replicapool/namespace-a")
+		entry := cache.entries[key]
+		entry.expires = time.Now().Add(-time.Second)
+		cache.entries[key] = entry
+		_, err := cache.get(c, clusterInfo, "replicapool/namespace-a")
+		assert.NoError(t, err)
+		assert.Equal(t, 4, infoCalls)
+	})
+}
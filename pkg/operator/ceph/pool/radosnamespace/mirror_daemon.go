@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const mirrorDaemonMissingEventReason = "MirrorDaemonMissing"
+
+// waitForRequeueIfMirrorDaemonMissing checks again for an rbd-mirror daemon, since the creation of a
+// CephRBDMirror does not trigger the reconcile of the rados namespaces
+var waitForRequeueIfMirrorDaemonMissing = reconcile.Result{Requeue: true, RequeueAfter: time.Minute}
+
+// mirrorDaemonDeployed returns whether a CephRBDMirror that is not being deleted exists in the namespace of the
+// cluster
+func (r *ReconcileCephBlockPoolRadosNamespace) mirrorDaemonDeployed(clusterNamespace string) (bool, error) {
+	rbdMirrors := &cephv1.CephRBDMirrorList{}
+	if err := r.client.List(r.opManagerContext, rbdMirrors, client.InNamespace(clusterNamespace)); err != nil {
+		return false, errors.Wrapf(err, "failed to list the CephRBDMirrors in namespace %q", clusterNamespace)
+	}
+	for _, rbdMirror := range rbdMirrors.Items {
+		if rbdMirror.DeletionTimestamp.IsZero() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mirrorDaemonConditions returns the condition reporting that the snapshot schedules of the rados namespace are
+// set while no rbd-mirror daemon is deployed to replicate the snapshots. The rados namespace is still reported as
+// ready since its mirroring is configured, and the condition is cleared once a daemon is deployed.
+func (r *ReconcileCephBlockPoolRadosNamespace) mirrorDaemonConditions(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) ([]cephv1.Condition, error) {
+	if len(desiredSnapshotSchedules(radosNamespace.Spec.Mirroring)) == 0 {
+		return nil, nil
+	}
+	deployed, err := r.mirrorDaemonDeployed(radosNamespace.Namespace)
+	if err != nil || deployed {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("the snapshot schedules are set but no CephRBDMirror is deployed in namespace %q, the snapshots are not replicated until an rbd-mirror daemon runs", radosNamespace.Namespace)
+	log.Warningf("%s", message)
+	r.recorder.Event(radosNamespace, v1.EventTypeWarning, mirrorDaemonMissingEventReason, message)
+	return []cephv1.Condition{{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.MirrorDaemonMissingReason,
+		Message: message,
+	}}, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMirrorDaemonConditions(t *testing.T) {
+	namespace := "rook-ceph"
+	name := types.NamespacedName{Name: "namespace-a", Namespace: namespace}
+	log := newReconcileLogger(name)
+	radosNamespace := func(paused bool) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring: &cephv1.RadosNamespaceMirroring{
+					Mode:                    "image",
+					SnapshotSchedules:       []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+					SnapshotSchedulesPaused: paused,
+				},
+			},
+		}
+	}
+	newReconciler := func(objects ...runtime.Object) (*ReconcileCephBlockPoolRadosNamespace, *record.FakeRecorder) {
+		recorder := record.NewFakeRecorder(5)
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{client: cl, opManagerContext: context.TODO(), recorder: recorder}, recorder
+	}
+
+	t.Run("no rbd-mirror daemon", func(t *testing.T) {
+		r, recorder := newReconciler()
+		conditions, err := r.mirrorDaemonConditions(radosNamespace(false), log)
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 1)
+		assert.Equal(t, cephv1.ConditionProgressing, conditions[0].Type)
+		assert.Equal(t, v1.ConditionTrue, conditions[0].Status)
+		assert.Equal(t, cephv1.MirrorDaemonMissingReason, conditions[0].Reason)
+		assert.Equal(t, []string{mirrorDaemonMissingEventReason}, recordedEventReasons(recorder))
+	})
+
+	t.Run("rbd-mirror daemon deployed", func(t *testing.T) {
+		rbdMirror := &cephv1.CephRBDMirror{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-rbd-mirror", Namespace: namespace},
+			Spec:       cephv1.RBDMirroringSpec{Count: 1},
+		}
+		r, recorder := newReconciler(rbdMirror)
+		conditions, err := r.mirrorDaemonConditions(radosNamespace(false), log)
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+		assert.Empty(t, recordedEventReasons(recorder))
+	})
+
+	t.Run("rbd-mirror daemon in another namespace", func(t *testing.T) {
+		rbdMirror := &cephv1.CephRBDMirror{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-rbd-mirror", Namespace: "other-cluster"},
+			Spec:       cephv1.RBDMirroringSpec{Count: 1},
+		}
+		r, _ := newReconciler(rbdMirror)
+		conditions, err := r.mirrorDaemonConditions(radosNamespace(false), log)
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 1)
+	})
+
+	t.Run("rbd-mirror daemon being deleted", func(t *testing.T) {
+		now := metav1.Now()
+		rbdMirror := &cephv1.CephRBDMirror{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "my-rbd-mirror",
+				Namespace:         namespace,
+				DeletionTimestamp: &now,
+				Finalizers:        []string{"cephrbdmirror.ceph.rook.io"},
+			},
+			Spec: cephv1.RBDMirroringSpec{Count: 1},
+		}
+		r, _ := newReconciler(rbdMirror)
+		conditions, err := r.mirrorDaemonConditions(radosNamespace(false), log)
+		assert.NoError(t, err)
+		assert.Len(t, conditions, 1)
+	})
+
+	t.Run("paused snapshot schedules", func(t *testing.T) {
+		r, recorder := newReconciler()
+		conditions, err := r.mirrorDaemonConditions(radosNamespace(true), log)
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+		assert.Empty(t, recordedEventReasons(recorder))
+	})
+
+	t.Run("no snapshot schedules", func(t *testing.T) {
+		r, _ := newReconciler()
+		rns := radosNamespace(false)
+		rns.Spec.Mirroring.SnapshotSchedules = nil
+		conditions, err := r.mirrorDaemonConditions(rns, log)
+		assert.NoError(t, err)
+		assert.Empty(t, conditions)
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// waitForRequeueIfMirrorUnhealthy requeues the reconcile while the csi config is withheld, since the mirroring
+// checker updates the mirroring status without triggering a reconcile
+var waitForRequeueIfMirrorUnhealthy = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+// waitsForMirrorHealth returns whether the csi config of the rados namespace is only written once its mirroring
+// is healthy
+func waitsForMirrorHealth(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.Spec.Mirroring != nil && radosNamespace.Spec.CSI != nil && radosNamespace.Spec.CSI.WaitForMirrorHealthy
+}
+
+// mirroringHealth returns the mirroring health last reported by the mirroring checker
+func mirroringHealth(status *cephv1.CephBlockPoolRadosNamespaceStatus) string {
+	if status == nil || status.MirroringStatus == nil || status.MirroringStatus.Summary == nil {
+		return ""
+	}
+	return status.MirroringStatus.Summary.Health
+}
+
+// waitForMirrorHealth reports that the csi config of the rados namespace is withheld until its mirroring is
+// healthy
+func (r *ReconcileCephBlockPoolRadosNamespace) waitForMirrorHealth(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) {
+	health := mirroringHealth(radosNamespace.Status)
+	if health == "" {
+		health = "unknown"
+	}
+	message := fmt.Sprintf("waiting for healthy mirroring to update the csi config, the mirroring health is %q", health)
+	log.Info(message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.WaitingForMirrorHealthReason,
+		Message: message,
+	})
+}
+
+// resolvedMirrorHealthConditions returns the condition clearing a previous wait for healthy mirroring once the
+// csi config is written
+func resolvedMirrorHealthConditions(radosNamespace *cephv1.CephBlockPoolRadosNamespace) []cephv1.Condition {
+	if radosNamespace.Status == nil {
+		return nil
+	}
+	condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionProgressing)
+	if condition == nil || condition.Reason != cephv1.WaitingForMirrorHealthReason || condition.Status != v1.ConditionTrue {
+		return nil
+	}
+	return []cephv1.Condition{{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.ReconcileSucceeded,
+		Message: "mirroring is healthy, the csi config is updated",
+	}}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestWaitForMirrorHealthy(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+			CSI:           &cephv1.RadosNamespaceCSISpec{WaitForMirrorHealthy: true},
+		},
+	}
+	cephBlockPool := newTestCephBlockPool(namespace)
+	cephBlockPool.Spec.Mirroring = cephv1.MirroringSpec{Enabled: true, Mode: "image"}
+	// the mirroring status reported by the checker is set by the test
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+			return `{"mode":"image"}`, nil
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), cephBlockPool)
+	cl := r.client
+	c := r.context
+	createTestCSIConfigMap(t, r, namespace)
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+	csiConfig := func() string {
+		cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return cm.Data[csi.ConfigKey]
+	}
+	setMirroringHealth := func(health string) {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		current.Status.MirroringStatus = &cephv1.MirroringStatusSpec{
+			MirroringStatus: cephv1.MirroringStatus{Summary: &cephv1.MirroringStatusSummarySpec{Health: health}},
+		}
+		assert.NoError(t, cl.Update(ctx, current))
+	}
+
+	t.Run("csi config is withheld while the mirroring status is unknown", func(t *testing.T) {
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfMirrorUnhealthy, res)
+		assert.NotContains(t, csiConfig(), buildClusterID(radosNamespace))
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.NotNil(t, condition)
+		assert.Equal(t, cephv1.WaitingForMirrorHealthReason, condition.Reason)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+	})
+
+	t.Run("csi config is withheld while the mirroring is unhealthy", func(t *testing.T) {
+		setMirroringHealth("WARNING")
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfMirrorUnhealthy, res)
+		assert.NotContains(t, csiConfig(), buildClusterID(radosNamespace))
+	})
+
+	t.Run("csi config is written once the mirroring is healthy", func(t *testing.T) {
+		setMirroringHealth("OK")
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.NotEqual(t, waitForRequeueIfMirrorUnhealthy, res)
+		assert.Contains(t, csiConfig(), buildClusterID(radosNamespace))
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+	})
+}
+
+func TestWaitsForMirrorHealth(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.False(t, waitsForMirrorHealth(radosNamespace))
+
+	radosNamespace.Spec.CSI = &cephv1.RadosNamespaceCSISpec{WaitForMirrorHealthy: true}
+	// nothing to wait for without mirroring
+	assert.False(t, waitsForMirrorHealth(radosNamespace))
+
+	radosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{Mode: "image"}
+	assert.True(t, waitsForMirrorHealth(radosNamespace))
+
+	radosNamespace.Spec.CSI.WaitForMirrorHealthy = false
+	assert.False(t, waitsForMirrorHealth(radosNamespace))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// mirrorPromoteAnnotation promotes the mirrored images of the rados namespace to primary, the promotion is
+	// forced when the value is "force". The annotation is removed once the images are promoted.
+	mirrorPromoteAnnotation = "ceph.rook.io/mirror-promote"
+	// mirrorDemoteAnnotation demotes the primary mirrored images of the rados namespace. The annotation is
+	// removed once the images are demoted.
+	mirrorDemoteAnnotation = "ceph.rook.io/mirror-demote"
+	mirrorPromoteForce     = "force"
+
+	// mirroringRoleInfoKey records in the status info whether the rados namespace was promoted or demoted
+	mirroringRoleInfoKey   = "mirroringRole"
+	mirroringRolePrimary   = "Primary"
+	mirroringRoleSecondary = "Secondary"
+
+	// primaryImageDescription is the description of the mirroring status of a primary image
+	primaryImageDescription = "local image is primary"
+)
+
+// isPrimaryImage returns whether the mirroring status is the status of a primary image
+func isPrimaryImage(status cephclient.MirrorImageStatus) bool {
+	return status.Description == primaryImageDescription
+}
+
+// reconcileMirrorRole promotes or demotes the mirrored images of the rados namespace when requested by the
+// promote or demote annotation, then records the role in the status info and removes the annotation. The
+// images that already have the requested role are skipped, so a partial promotion or demotion is resumed by
+// the next reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirrorRole(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) error {
+	promote := radosNamespace.GetAnnotations()[mirrorPromoteAnnotation]
+	demote := radosNamespace.GetAnnotations()[mirrorDemoteAnnotation]
+	if promote == "" && demote == "" {
+		return nil
+	}
+	if promote != "" && demote != "" {
+		return errors.Errorf("cannot both promote and demote rados namespace %q, remove the %q or the %q annotation", name, mirrorPromoteAnnotation, mirrorDemoteAnnotation)
+	}
+	if radosNamespace.Spec.Mirroring == nil {
+		log.Warningf("cannot promote or demote rados namespace %q until mirroring is enabled", name)
+		return nil
+	}
+
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	var statuses []cephclient.MirrorImageStatus
+	err := log.timeCephCall("list mirrored images", func() error {
+		var err error
+		statuses, err = cephclient.GetMirroredImageStatuses(r.context, r.clusterInfo, poolAndRadosNamespaceName, r.mirrorImageStatuses)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the mirrored images of rados namespace %q", poolAndRadosNamespaceName)
+	}
+
+	annotation, role := mirrorDemoteAnnotation, mirroringRoleSecondary
+	if promote != "" {
+		annotation, role = mirrorPromoteAnnotation, mirroringRolePrimary
+	}
+	changed := 0
+	for _, status := range statuses {
+		if isPrimaryImage(status) == (role == mirroringRolePrimary) {
+			continue
+		}
+		err := log.timeCephCall("change image mirroring role", func() error {
+			if role == mirroringRolePrimary {
+				return cephclient.PromoteImage(r.context, r.clusterInfo, poolAndRadosNamespaceName, status.Name, promote == mirrorPromoteForce)
+			}
+			return cephclient.DemoteImage(r.context, r.clusterInfo, poolAndRadosNamespaceName, status.Name)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to change the mirroring role of rados namespace %q to %q", poolAndRadosNamespaceName, role)
+		}
+		changed++
+	}
+	if changed == 0 {
+		// do not promote a rados namespace that is already primary, nor demote a secondary
+		log.Warningf("the mirrored images of rados namespace %q already have the %q role, ignoring the %q annotation", poolAndRadosNamespaceName, role, annotation)
+	} else {
+		log.Infof("changed the mirroring role of %d images of rados namespace %q to %q", changed, poolAndRadosNamespaceName, role)
+	}
+
+	r.recordMirroringInfo(name, mirroringRoleInfoKey, role, log)
+	return r.removeAnnotation(name, annotation)
+}
+
+// removeAnnotation removes an annotation of the rados namespace once the request it carries is applied
+func (r *ReconcileCephBlockPoolRadosNamespace) removeAnnotation(name types.NamespacedName, annotation string) error {
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, current); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get rados namespace %q", name)
+	}
+	if _, ok := current.GetAnnotations()[annotation]; !ok {
+		return nil
+	}
+	patch := client.MergeFrom(current.DeepCopy())
+	delete(current.Annotations, annotation)
+	if err := r.client.Patch(r.opManagerContext, current, patch); err != nil {
+		return errors.Wrapf(err, "failed to remove the %q annotation of rados namespace %q", annotation, name)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileMirrorRole(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	newRadosNamespace := func(annotations map[string]string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Annotations: annotations},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+			},
+		}
+	}
+	// commands are the rbd mirror image commands issued, primary is the role of each image
+	type cluster struct {
+		commands []string
+		primary  map[string]bool
+	}
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace, c *cluster) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client: cl,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+							assert.Equal(t, poolAndRadosNamespaceName, args[4])
+							var list []string
+							for image, primary := range c.primary {
+								description := "replaying"
+								if primary {
+									description = primaryImageDescription
+								}
+								list = append(list, `{"name":"`+image+`","description":"`+description+`"}`)
+							}
+							sort.Strings(list)
+							return `{"images":[` + strings.Join(list, ",") + `]}`, nil
+						}
+						if args[0] == "mirror" && args[1] == "image" {
+							image := strings.TrimPrefix(args[3], poolAndRadosNamespaceName+"/")
+							command := args[2] + " " + image
+							if len(args) > 4 && args[4] == "--force" {
+								command += " --force"
+							}
+							c.commands = append(c.commands, command)
+							c.primary[image] = args[2] == "promote"
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+			opManagerContext: ctx,
+		}
+	}
+	current := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) *cephv1.CephBlockPoolRadosNamespace {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, radosNamespace))
+		return radosNamespace
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("promote", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorPromoteAnnotation: "true"})
+		c := &cluster{primary: map[string]bool{"image-a": false, "image-b": false, "image-c": true}}
+		r := newReconciler(radosNamespace, c)
+		assert.NoError(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		sort.Strings(c.commands)
+		assert.Equal(t, []string{"promote image-a", "promote image-b"}, c.commands)
+
+		result := current(t, r)
+		assert.Equal(t, mirroringRolePrimary, result.Status.Info[mirroringRoleInfoKey])
+		assert.NotContains(t, result.Annotations, mirrorPromoteAnnotation)
+	})
+
+	t.Run("forced promote", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorPromoteAnnotation: mirrorPromoteForce})
+		c := &cluster{primary: map[string]bool{"image-a": false}}
+		r := newReconciler(radosNamespace, c)
+		assert.NoError(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		assert.Equal(t, []string{"promote image-a --force"}, c.commands)
+		assert.NotContains(t, current(t, r).Annotations, mirrorPromoteAnnotation)
+	})
+
+	t.Run("already primary", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorPromoteAnnotation: "true", "other": "kept"})
+		c := &cluster{primary: map[string]bool{"image-a": true, "image-b": true}}
+		r := newReconciler(radosNamespace, c)
+		assert.NoError(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		// no image is promoted again
+		assert.Empty(t, c.commands)
+
+		result := current(t, r)
+		assert.Equal(t, mirroringRolePrimary, result.Status.Info[mirroringRoleInfoKey])
+		assert.Equal(t, map[string]string{"other": "kept"}, result.Annotations)
+	})
+
+	t.Run("demote", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorDemoteAnnotation: "true"})
+		c := &cluster{primary: map[string]bool{"image-a": true, "image-b": false}}
+		r := newReconciler(radosNamespace, c)
+		assert.NoError(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		assert.Equal(t, []string{"demote image-a"}, c.commands)
+
+		result := current(t, r)
+		assert.Equal(t, mirroringRoleSecondary, result.Status.Info[mirroringRoleInfoKey])
+		assert.NotContains(t, result.Annotations, mirrorDemoteAnnotation)
+	})
+
+	t.Run("implicit rados namespace", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorPromoteAnnotation: "true"})
+		radosNamespace.Spec.Name = cephv1.ImplicitNamespaceKey
+		c := &cluster{primary: map[string]bool{"image-a": false}}
+		r := newReconciler(radosNamespace, c)
+		assert.NoError(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		assert.Equal(t, []string{"promote image-a"}, c.commands)
+	})
+
+	t.Run("promote and demote", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorPromoteAnnotation: "true", mirrorDemoteAnnotation: "true"})
+		c := &cluster{primary: map[string]bool{"image-a": false}}
+		r := newReconciler(radosNamespace, c)
+		assert.Error(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		assert.Empty(t, c.commands)
+		assert.Contains(t, current(t, r).Annotations, mirrorPromoteAnnotation)
+	})
+
+	t.Run("mirroring not enabled", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(map[string]string{mirrorPromoteAnnotation: "true"})
+		radosNamespace.Spec.Mirroring = nil
+		c := &cluster{primary: map[string]bool{"image-a": false}}
+		r := newReconciler(radosNamespace, c)
+		assert.NoError(t, r.reconcileMirrorRole(radosNamespace, name, log))
+		assert.Empty(t, c.commands)
+		assert.Contains(t, current(t, r).Annotations, mirrorPromoteAnnotation)
+	})
+}
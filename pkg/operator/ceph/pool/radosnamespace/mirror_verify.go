@@ -0,0 +1,269 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// mirrorVerifyAnnotation requests an end-to-end check of the mirroring of the rados namespace: a small
+	// marker image is mirrored to the peers, and the check passes once a peer replays it. The annotation is
+	// removed once the report is recorded in the status info.
+	mirrorVerifyAnnotation = "ceph.rook.io/mirror-verify"
+
+	// mirrorVerifyTimeoutSettingName is the operator setting with the time the marker image has to be replicated
+	// to a peer before the check fails
+	mirrorVerifyTimeoutSettingName = "ROOK_RADOS_NAMESPACE_MIRROR_VERIFY_TIMEOUT"
+	defaultMirrorVerifyTimeout     = "5m"
+
+	// the marker image in progress and the time it was created are recorded in the status info until the check
+	// completes
+	mirrorVerifyMarkerInfoKey  = "mirrorVerifyMarker"
+	mirrorVerifyStartedInfoKey = "mirrorVerifyStarted"
+	// the report of the last check
+	mirrorVerifyResultInfoKey  = "mirrorVerifyResult"
+	mirrorVerifyMessageInfoKey = "mirrorVerifyMessage"
+	mirrorVerifyTimeInfoKey    = "mirrorVerifyTime"
+
+	mirrorVerifyPassed  = "Passed"
+	mirrorVerifyFailed  = "Failed"
+	mirrorVerifySkipped = "Skipped"
+
+	mirrorVerifyEventReason = "MirrorVerify"
+
+	mirrorVerifyMarkerPrefix = "rook-mirror-verify-"
+	mirrorVerifyMarkerSizeMB = 1
+	// replayingPeerState is the state of a peer site replaying the mirrored image
+	replayingPeerState = "up+replaying"
+)
+
+var waitForRequeueIfMirrorVerificationInProgress = reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}
+
+// mirrorVerifyTimeout returns the time the marker image has to be replicated, the default timeout is used if
+// the setting is invalid
+func mirrorVerifyTimeout(log *reconcileLogger) time.Duration {
+	value := k8sutil.GetOperatorSetting(mirrorVerifyTimeoutSettingName, defaultMirrorVerifyTimeout)
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Warningf("invalid setting %q value %q, using the default mirroring check timeout %q. %v", mirrorVerifyTimeoutSettingName, value, defaultMirrorVerifyTimeout, err)
+		timeout, _ = time.ParseDuration(defaultMirrorVerifyTimeout)
+	}
+	return timeout
+}
+
+// replayingPeerSite returns the name of a peer site replaying the image, or empty if no peer replays it
+func replayingPeerSite(status *cephclient.MirrorImageStatus) string {
+	for _, site := range status.PeerSites {
+		if site.State == replayingPeerState {
+			return site.SiteName
+		}
+	}
+	return ""
+}
+
+// reconcileMirrorVerification runs the end-to-end mirroring check requested by the annotation, and returns
+// whether the check is in progress. The check spans several reconciles: the marker image is created by the
+// first one, and the following ones wait until a peer replays it or the timeout expires.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirrorVerification(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) (bool, error) {
+	var marker string
+	if radosNamespace.Status != nil {
+		marker = radosNamespace.Status.Info[mirrorVerifyMarkerInfoKey]
+	}
+	if marker != "" {
+		return r.checkMirrorVerification(radosNamespace, name, marker, log)
+	}
+	if _, ok := radosNamespace.GetAnnotations()[mirrorVerifyAnnotation]; !ok {
+		return false, nil
+	}
+
+	skipReason, err := r.mirrorVerificationSkipReason(radosNamespace, log)
+	if err != nil {
+		return false, err
+	}
+	if skipReason != "" {
+		log.Infof("skipping the mirroring check of rados namespace %q, %s", name, skipReason)
+		return false, r.completeMirrorVerification(radosNamespace, name, "", mirrorVerifySkipped, skipReason, log)
+	}
+	return true, r.startMirrorVerification(radosNamespace, name, log)
+}
+
+// mirrorVerificationSkipReason returns why the mirroring of the rados namespace cannot be checked, the check
+// is only safe on a primary rados namespace whose images are sent to a peer
+func (r *ReconcileCephBlockPoolRadosNamespace) mirrorVerificationSkipReason(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) (string, error) {
+	if radosNamespace.Spec.Mirroring == nil {
+		return "mirroring is not enabled", nil
+	}
+	if radosNamespace.Status != nil && radosNamespace.Status.Info[mirroringRoleInfoKey] == mirroringRoleSecondary {
+		return "the rados namespace is not primary", nil
+	}
+	if radosNamespace.Spec.Mirroring.Direction == cephv1.RadosNamespaceMirroringDirectionRxOnly {
+		return "the images are only received from the peers", nil
+	}
+
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	var mirrorInfo *cephv1.MirroringInfo
+	err := log.timeCephCall("get mirroring info", func() error {
+		var err error
+		mirrorInfo, err = cephclient.GetPoolMirroringInfo(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the mirroring info of rados namespace %q", poolAndRadosNamespaceName)
+	}
+	if len(mirrorInfo.Peers) == 0 {
+		return "no mirroring peer is configured", nil
+	}
+	return "", nil
+}
+
+// startMirrorVerification creates the marker image and mirrors it, then records it in the status info. In pool
+// mode the marker is mirrored with journaling, in image mode with snapshots.
+func (r *ReconcileCephBlockPoolRadosNamespace) startMirrorVerification(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) error {
+	pool := radosNamespace.Spec.BlockPoolName
+	radosNamespaceName := cephv1.GetRadosNamespaceName(radosNamespace)
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	started := r.now()
+	marker := fmt.Sprintf("%s%d", mirrorVerifyMarkerPrefix, started.Unix())
+
+	features := []string{"layering", "exclusive-lock"}
+	if radosNamespace.Spec.Mirroring.Mode == cephv1.RadosNamespaceMirroringModePool {
+		features = append(features, "journaling")
+	}
+	err := log.timeCephCall("create mirroring check marker", func() error {
+		return cephclient.CreateImageInRadosNamespace(r.context, r.clusterInfo, pool, marker, radosNamespaceName, mirrorVerifyMarkerSizeMB, features)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the mirroring check marker of rados namespace %q", poolAndRadosNamespaceName)
+	}
+	if radosNamespace.Spec.Mirroring.Mode == cephv1.RadosNamespaceMirroringModeImage {
+		err = log.timeCephCall("enable mirroring check marker mirroring", func() error {
+			return cephclient.EnableImageMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName, marker)
+		})
+		if err != nil {
+			r.removeMirrorVerificationMarker(radosNamespace, marker, log)
+			return errors.Wrapf(err, "failed to mirror the mirroring check marker of rados namespace %q", poolAndRadosNamespaceName)
+		}
+	}
+
+	log.Infof("checking the mirroring of rados namespace %q with marker image %q", name, marker)
+	err = r.mutateStatus(name, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		if current.Status.Info == nil {
+			current.Status.Info = map[string]string{}
+		}
+		current.Status.Info[mirrorVerifyMarkerInfoKey] = marker
+		current.Status.Info[mirrorVerifyStartedInfoKey] = started.UTC().Format(time.RFC3339)
+		return true
+	})
+	if err != nil {
+		r.removeMirrorVerificationMarker(radosNamespace, marker, log)
+		return errors.Wrapf(err, "failed to record the mirroring check marker of rados namespace %q", name)
+	}
+	return nil
+}
+
+// checkMirrorVerification checks whether a peer replays the marker image, and completes the check once it does
+// or once the timeout expires
+func (r *ReconcileCephBlockPoolRadosNamespace) checkMirrorVerification(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, marker string, log *reconcileLogger) (bool, error) {
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	var status *cephclient.MirrorImageStatus
+	statusErr := log.timeCephCall("get mirroring check marker status", func() error {
+		var err error
+		status, err = cephclient.GetMirrorImageStatus(r.context, r.clusterInfo, poolAndRadosNamespaceName, marker)
+		return err
+	})
+	if statusErr == nil {
+		if site := replayingPeerSite(status); site != "" {
+			return false, r.completeMirrorVerification(radosNamespace, name, marker, mirrorVerifyPassed,
+				fmt.Sprintf("marker image %q is replicated to peer site %q", marker, site), log)
+		}
+	}
+
+	// a check whose start time cannot be parsed is timed out, it would never complete otherwise
+	started, _ := time.Parse(time.RFC3339, radosNamespace.Status.Info[mirrorVerifyStartedInfoKey])
+	if elapsed := r.now().Sub(started); elapsed < mirrorVerifyTimeout(log) {
+		log.Debugf("waiting for the mirroring check marker %q of rados namespace %q to be replicated", marker, name)
+		return true, nil
+	}
+
+	message := fmt.Sprintf("marker image %q is not replicated to a peer within %s", marker, mirrorVerifyTimeout(log))
+	if statusErr != nil {
+		message = fmt.Sprintf("%s: %v", message, statusErr)
+	} else if len(status.PeerSites) > 0 {
+		var states []string
+		for _, site := range status.PeerSites {
+			states = append(states, fmt.Sprintf("%s: %s", site.SiteName, site.State))
+		}
+		message = fmt.Sprintf("%s, peer sites %s", message, strings.Join(states, ", "))
+	}
+	return false, r.completeMirrorVerification(radosNamespace, name, marker, mirrorVerifyFailed, message, log)
+}
+
+// completeMirrorVerification removes the marker image if any, records the report in the status info with an
+// event, and removes the annotation requesting the check
+func (r *ReconcileCephBlockPoolRadosNamespace) completeMirrorVerification(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, marker, result, message string, log *reconcileLogger) error {
+	if marker != "" {
+		err := log.timeCephCall("remove mirroring check marker", func() error {
+			return cephclient.DeleteImageInRadosNamespace(r.context, r.clusterInfo, marker, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove the mirroring check marker %q of rados namespace %q", marker, name)
+		}
+	}
+
+	reportTime := r.now().UTC().Format(time.RFC3339)
+	err := r.mutateStatus(name, func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+		if current.Status.Info == nil {
+			current.Status.Info = map[string]string{}
+		}
+		delete(current.Status.Info, mirrorVerifyMarkerInfoKey)
+		delete(current.Status.Info, mirrorVerifyStartedInfoKey)
+		current.Status.Info[mirrorVerifyResultInfoKey] = result
+		current.Status.Info[mirrorVerifyMessageInfoKey] = message
+		current.Status.Info[mirrorVerifyTimeInfoKey] = reportTime
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to record the mirroring check report of rados namespace %q", name)
+	}
+
+	eventType := v1.EventTypeNormal
+	if result != mirrorVerifyPassed {
+		eventType = v1.EventTypeWarning
+	}
+	r.recorder.Event(radosNamespace, eventType, mirrorVerifyEventReason, fmt.Sprintf("mirroring check %s: %s", strings.ToLower(result), message))
+	log.Infof("mirroring check of rados namespace %q %s: %s", name, strings.ToLower(result), message)
+	return r.removeAnnotation(name, mirrorVerifyAnnotation)
+}
+
+// removeMirrorVerificationMarker removes a marker image that could not be mirrored or recorded
+func (r *ReconcileCephBlockPoolRadosNamespace) removeMirrorVerificationMarker(radosNamespace *cephv1.CephBlockPoolRadosNamespace, marker string, log *reconcileLogger) {
+	err := cephclient.DeleteImageInRadosNamespace(r.context, r.clusterInfo, marker, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
+	if err != nil {
+		log.Warningf("failed to remove the mirroring check marker %q of rados namespace %q, remove it manually. %v", marker, radosNamespace.Name, err)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMirrorVerifyTimeout(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.Equal(t, 5*time.Minute, mirrorVerifyTimeout(log))
+
+	t.Setenv(mirrorVerifyTimeoutSettingName, "30s")
+	assert.Equal(t, 30*time.Second, mirrorVerifyTimeout(log))
+
+	t.Setenv(mirrorVerifyTimeoutSettingName, "invalid")
+	assert.Equal(t, 5*time.Minute, mirrorVerifyTimeout(log))
+}
+
+func TestReconcileMirrorVerification(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	newRadosNamespace := func(mirroring *cephv1.RadosNamespaceMirroring) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Annotations: map[string]string{mirrorVerifyAnnotation: "true"}},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", Mirroring: mirroring},
+		}
+	}
+	// peers is the mirroring info of the rados namespace, peerState the state of the marker image on the peer
+	type cluster struct {
+		peers     string
+		peerState string
+		commands  []string
+	}
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace, c *cluster, fakeClock *clocktesting.FakePassiveClock) (*ReconcileCephBlockPoolRadosNamespace, *record.FakeRecorder) {
+		recorder := record.NewFakeRecorder(5)
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client:   cl,
+			recorder: recorder,
+			clock:    fakeClock,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						switch {
+						case args[0] == "mirror" && args[1] == "pool" && args[2] == "info":
+							return `{"mode":"image","peers":[` + c.peers + `]}`, nil
+						case args[0] == "mirror" && args[1] == "image" && args[2] == "status":
+							return `{"name":"marker","description":"local image is primary","peer_sites":[{"site_name":"site-b","state":"` + c.peerState + `"}]}`, nil
+						case args[0] == "create" || args[0] == "rm" || (args[0] == "mirror" && args[1] == "image"):
+							c.commands = append(c.commands, strings.Join(args[:4], " "))
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+			opManagerContext: ctx,
+		}, recorder
+	}
+	getCurrent := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		return current
+	}
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	marker := "rook-mirror-verify-1735725600"
+
+	t.Run("no annotation", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(&cephv1.RadosNamespaceMirroring{Mode: "image"})
+		radosNamespace.Annotations = nil
+		c := &cluster{peers: `{"uuid":"1"}`}
+		r, _ := newReconciler(radosNamespace, c, clocktesting.NewFakePassiveClock(started))
+		verifying, err := r.reconcileMirrorVerification(radosNamespace, name, log)
+		assert.NoError(t, err)
+		assert.False(t, verifying)
+		assert.Empty(t, c.commands)
+	})
+
+	skipTests := []struct {
+		name         string
+		mirroring    *cephv1.RadosNamespaceMirroring
+		role         string
+		peers        string
+		expectReason string
+	}{
+		{name: "mirroring disabled", expectReason: "mirroring is not enabled"},
+		{name: "secondary", mirroring: &cephv1.RadosNamespaceMirroring{Mode: "image"}, role: mirroringRoleSecondary, peers: `{"uuid":"1"}`, expectReason: "not primary"},
+		{name: "receive only", mirroring: &cephv1.RadosNamespaceMirroring{Mode: "image", Direction: cephv1.RadosNamespaceMirroringDirectionRxOnly}, peers: `{"uuid":"1"}`, expectReason: "only received"},
+		{name: "no peer", mirroring: &cephv1.RadosNamespaceMirroring{Mode: "image"}, expectReason: "no mirroring peer"},
+	}
+	for _, tt := range skipTests {
+		t.Run("skipped when "+tt.name, func(t *testing.T) {
+			radosNamespace := newRadosNamespace(tt.mirroring)
+			if tt.role != "" {
+				radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{mirroringRoleInfoKey: tt.role}}
+			}
+			c := &cluster{peers: tt.peers}
+			r, recorder := newReconciler(radosNamespace, c, clocktesting.NewFakePassiveClock(started))
+			verifying, err := r.reconcileMirrorVerification(radosNamespace, name, log)
+			assert.NoError(t, err)
+			assert.False(t, verifying)
+			// no marker image is created
+			assert.Empty(t, c.commands)
+
+			current := getCurrent(t, r)
+			assert.Equal(t, mirrorVerifySkipped, current.Status.Info[mirrorVerifyResultInfoKey])
+			assert.Contains(t, current.Status.Info[mirrorVerifyMessageInfoKey], tt.expectReason)
+			assert.NotContains(t, current.Annotations, mirrorVerifyAnnotation)
+			assert.Contains(t, <-recorder.Events, "Warning "+mirrorVerifyEventReason)
+		})
+	}
+
+	t.Run("marker replicated", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(&cephv1.RadosNamespaceMirroring{Mode: "image"})
+		c := &cluster{peers: `{"uuid":"1"}`, peerState: "down+unknown"}
+		fakeClock := clocktesting.NewFakePassiveClock(started)
+		r, recorder := newReconciler(radosNamespace, c, fakeClock)
+
+		// the marker image is created and mirrored
+		verifying, err := r.reconcileMirrorVerification(radosNamespace, name, log)
+		assert.NoError(t, err)
+		assert.True(t, verifying)
+		assert.Equal(t, []string{"create replicapool/" + marker + " --size 1", "mirror image enable replicapool/namespace-a/" + marker}, c.commands)
+		current := getCurrent(t, r)
+		assert.Equal(t, marker, current.Status.Info[mirrorVerifyMarkerInfoKey])
+		assert.Equal(t, "2025-01-01T10:00:00Z", current.Status.Info[mirrorVerifyStartedInfoKey])
+
+		// the check waits while the peer does not replay the marker
+		fakeClock.SetTime(started.Add(time.Minute))
+		verifying, err = r.reconcileMirrorVerification(current, name, log)
+		assert.NoError(t, err)
+		assert.True(t, verifying)
+		assert.Len(t, c.commands, 2)
+
+		// the check passes once the peer replays the marker, and the marker is removed
+		c.peerState = replayingPeerState
+		verifying, err = r.reconcileMirrorVerification(current, name, log)
+		assert.NoError(t, err)
+		assert.False(t, verifying)
+		assert.Equal(t, "rm replicapool/"+marker+" --namespace namespace-a", c.commands[2])
+
+		current = getCurrent(t, r)
+		assert.Equal(t, mirrorVerifyPassed, current.Status.Info[mirrorVerifyResultInfoKey])
+		assert.Contains(t, current.Status.Info[mirrorVerifyMessageInfoKey], `peer site "site-b"`)
+		assert.Equal(t, "2025-01-01T10:01:00Z", current.Status.Info[mirrorVerifyTimeInfoKey])
+		assert.NotContains(t, current.Status.Info, mirrorVerifyMarkerInfoKey)
+		assert.NotContains(t, current.Status.Info, mirrorVerifyStartedInfoKey)
+		assert.NotContains(t, current.Annotations, mirrorVerifyAnnotation)
+		assert.Contains(t, <-recorder.Events, "Normal "+mirrorVerifyEventReason)
+	})
+
+	t.Run("marker not replicated in time", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(&cephv1.RadosNamespaceMirroring{Mode: "pool"})
+		c := &cluster{peers: `{"uuid":"1"}`, peerState: "up+starting_replay"}
+		fakeClock := clocktesting.NewFakePassiveClock(started)
+		r, recorder := newReconciler(radosNamespace, c, fakeClock)
+
+		// in pool mode the marker is mirrored with journaling
+		verifying, err := r.reconcileMirrorVerification(radosNamespace, name, log)
+		assert.NoError(t, err)
+		assert.True(t, verifying)
+		assert.Equal(t, []string{"create replicapool/" + marker + " --size 1"}, c.commands)
+
+		fakeClock.SetTime(started.Add(6 * time.Minute))
+		verifying, err = r.reconcileMirrorVerification(getCurrent(t, r), name, log)
+		assert.NoError(t, err)
+		assert.False(t, verifying)
+		assert.Equal(t, "rm replicapool/"+marker+" --namespace namespace-a", c.commands[1])
+
+		current := getCurrent(t, r)
+		assert.Equal(t, mirrorVerifyFailed, current.Status.Info[mirrorVerifyResultInfoKey])
+		assert.Contains(t, current.Status.Info[mirrorVerifyMessageInfoKey], "site-b: up+starting_replay")
+		assert.NotContains(t, current.Status.Info, mirrorVerifyMarkerInfoKey)
+		assert.NotContains(t, current.Annotations, mirrorVerifyAnnotation)
+		assert.Contains(t, <-recorder.Events, "Warning "+mirrorVerifyEventReason)
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MirroredRadosNamespace is the mirroring of a rados namespace as last reported in the status of its CR
+type MirroredRadosNamespace struct {
+	// Name is the name of the CR
+	Name types.NamespacedName
+	// BlockPool is the CephBlockPool of the rados namespace
+	BlockPool types.NamespacedName
+	// RadosNamespace is the name of the rados namespace in the pool
+	RadosNamespace string
+	// Mode is the mirroring mode of the spec
+	Mode cephv1.RadosNamespaceMirroringMode
+	// Peers are the peers of the rados namespace, empty until the mirroring info is reported
+	Peers []cephv1.PeersSpec
+	// Health is the last known mirroring health, empty until the mirroring status is checked
+	Health string
+	// LastChecked is the last time the mirroring status was checked
+	LastChecked string
+	// Details are the errors of the last mirroring status check
+	Details string
+}
+
+// ListMirroredRadosNamespaces returns the rados namespaces with mirroring enabled in all the namespaces, sorted by
+// name, for example for a DR dashboard. The mirroring is read from the status of the CRs, no ceph command is run.
+// The client of the manager should be passed so that the CRs are listed from its cache.
+func ListMirroredRadosNamespaces(ctx context.Context, c client.Reader) ([]MirroredRadosNamespace, error) {
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	if err := c.List(ctx, radosNamespaces); err != nil {
+		return nil, errors.Wrap(err, "failed to list rados namespaces")
+	}
+
+	mirrored := []MirroredRadosNamespace{}
+	for i := range radosNamespaces.Items {
+		radosNamespace := &radosNamespaces.Items[i]
+		if radosNamespace.Spec.Mirroring == nil {
+			continue
+		}
+		mirrored = append(mirrored, newMirroredRadosNamespace(radosNamespace))
+	}
+	sort.Slice(mirrored, func(i, j int) bool {
+		if mirrored[i].Name.Namespace != mirrored[j].Name.Namespace {
+			return mirrored[i].Name.Namespace < mirrored[j].Name.Namespace
+		}
+		return mirrored[i].Name.Name < mirrored[j].Name.Name
+	})
+	return mirrored, nil
+}
+
+func newMirroredRadosNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace) MirroredRadosNamespace {
+	mirrored := MirroredRadosNamespace{
+		Name:           types.NamespacedName{Name: radosNamespace.Name, Namespace: radosNamespace.Namespace},
+		BlockPool:      types.NamespacedName{Name: radosNamespace.Spec.BlockPoolName, Namespace: blockPoolNamespace(radosNamespace)},
+		RadosNamespace: cephv1.GetRadosNamespaceName(radosNamespace),
+		Mode:           radosNamespace.Spec.Mirroring.Mode,
+	}
+	if radosNamespace.Status == nil {
+		return mirrored
+	}
+	if info := radosNamespace.Status.MirroringInfo; info != nil && info.MirroringInfo != nil {
+		mirrored.Peers = info.Peers
+	}
+	if status := radosNamespace.Status.MirroringStatus; status != nil {
+		if status.Summary != nil {
+			mirrored.Health = status.Summary.Health
+		}
+		mirrored.LastChecked = status.LastChecked
+		mirrored.Details = status.Details
+	}
+	return mirrored
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestListMirroredRadosNamespaces(t *testing.T) {
+	ctx := context.TODO()
+	remoteNamespace := "remote"
+	peers := []cephv1.PeersSpec{{UUID: "4a6983c0-3c9d-40f5-b2a9-2334a4659827", SiteName: "site-b"}}
+	newRadosNamespace := func(namespace, name string, mirroring *cephv1.RadosNamespaceMirroring) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", Mirroring: mirroring},
+		}
+	}
+
+	healthy := newRadosNamespace("rook-ceph", "namespace-b", &cephv1.RadosNamespaceMirroring{Mode: "image", RemoteNamespace: &remoteNamespace})
+	healthy.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{
+		MirroringInfo: &cephv1.MirroringInfoSpec{MirroringInfo: &cephv1.MirroringInfo{Mode: "image", Peers: peers}},
+		MirroringStatus: &cephv1.MirroringStatusSpec{
+			MirroringStatus: cephv1.MirroringStatus{Summary: &cephv1.MirroringStatusSummarySpec{Health: "OK"}},
+			LastChecked:     "2025-01-01T00:00:00Z",
+		},
+	}
+	failing := newRadosNamespace("rook-ceph", "namespace-a", &cephv1.RadosNamespaceMirroring{Mode: "pool"})
+	failing.Spec.Name = "ns-a"
+	failing.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{
+		MirroringStatus: &cephv1.MirroringStatusSpec{
+			MirroringStatus: cephv1.MirroringStatus{Summary: &cephv1.MirroringStatusSummarySpec{Health: "ERROR"}},
+			Details:         "failed to get mirroring status",
+		},
+	}
+	// not checked yet
+	pending := newRadosNamespace("other", "namespace-c", &cephv1.RadosNamespaceMirroring{Mode: "image"})
+	pending.Spec.BlockPoolNamespace = "rook-ceph"
+
+	objects := []runtime.Object{
+		healthy,
+		failing,
+		pending,
+		newRadosNamespace("rook-ceph", "namespace-d", nil),
+		newRadosNamespace("other", "namespace-e", nil),
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).Build()
+
+	mirrored, err := ListMirroredRadosNamespaces(ctx, cl)
+	assert.NoError(t, err)
+	assert.Equal(t, []MirroredRadosNamespace{
+		{
+			Name:           types.NamespacedName{Name: "namespace-c", Namespace: "other"},
+			BlockPool:      types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"},
+			RadosNamespace: "namespace-c",
+			Mode:           "image",
+		},
+		{
+			Name:           types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"},
+			BlockPool:      types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"},
+			RadosNamespace: "ns-a",
+			Mode:           "pool",
+			Health:         "ERROR",
+			Details:        "failed to get mirroring status",
+		},
+		{
+			Name:           types.NamespacedName{Name: "namespace-b", Namespace: "rook-ceph"},
+			BlockPool:      types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"},
+			RadosNamespace: "namespace-b",
+			Mode:           "image",
+			Peers:          peers,
+			Health:         "OK",
+			LastChecked:    "2025-01-01T00:00:00Z",
+		},
+	}, mirrored)
+
+	t.Run("no mirrored rados namespace", func(t *testing.T) {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(newRadosNamespace("rook-ceph", "namespace-d", nil)).Build()
+		mirrored, err := ListMirroredRadosNamespaces(ctx, cl)
+		assert.NoError(t, err)
+		assert.Empty(t, mirrored)
+	})
+}
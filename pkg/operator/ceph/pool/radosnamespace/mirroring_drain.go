@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// mirroringDrainOnDisableInfoKey is the status info key recording that the mirrored images must be drained
+// when mirroring is disabled. The spec setting is recorded while mirroring is enabled since the mirroring
+// spec is removed to disable mirroring.
+const mirroringDrainOnDisableInfoKey = "mirroringDrainOnDisable"
+
+// maxImagesDrainedPerReconcile is the maximum number of images for which mirroring is disabled in a single
+// reconcile, the remaining images are drained by the next reconciles
+var maxImagesDrainedPerReconcile = 20
+
+func drainOnDisableInfo(mirroring *cephv1.RadosNamespaceMirroring) string {
+	if mirroring != nil && mirroring.DrainOnDisable {
+		return "true"
+	}
+	return ""
+}
+
+func isDrainOnDisableRecorded(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.Status != nil && radosNamespace.Status.Info[mirroringDrainOnDisableInfoKey] == "true"
+}
+
+// drainMirroredImages disables the mirroring of a batch of the mirrored images, and returns a
+// MirroringDrainInProgressError if images are left for the next reconciles
+func (r *ReconcileCephBlockPoolRadosNamespace) drainMirroredImages(poolAndRadosNamespaceName string, images []cephclient.Images, log *reconcileLogger) error {
+	batch := images
+	if len(batch) > maxImagesDrainedPerReconcile {
+		batch = batch[:maxImagesDrainedPerReconcile]
+	}
+	log.Infof("disabling mirroring of %d of the %d mirrored images of rados namespace %q", len(batch), len(images), poolAndRadosNamespaceName)
+	for _, image := range batch {
+		err := log.timeCephCall("disable image mirroring", func() error {
+			return cephclient.DisableImageMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName, image.Name)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to drain the mirrored images of rados namespace %q", poolAndRadosNamespaceName)
+		}
+	}
+	if remaining := len(images) - len(batch); remaining > 0 {
+		return &MirroringDrainInProgressError{RadosNamespace: poolAndRadosNamespaceName, Remaining: remaining}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// maxImagesFilteredPerReconcile is the maximum number of images for which mirroring is enabled or disabled
+// to match the image filter in a single reconcile, the remaining images are updated by the next reconciles
+var maxImagesFilteredPerReconcile = 20
+
+// imageFilterMatches returns whether the image name matches one of the include patterns, or all the
+// images if there is none, and none of the exclude patterns. The patterns are validated beforehand.
+func imageFilterMatches(filter *cephv1.RadosNamespaceMirroringImageFilter, imageName string) bool {
+	for _, pattern := range filter.Exclude {
+		if matched, _ := path.Match(pattern, imageName); matched {
+			return false
+		}
+	}
+	if len(filter.Include) == 0 {
+		return true
+	}
+	for _, pattern := range filter.Include {
+		if matched, _ := path.Match(pattern, imageName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// imageFilterChanges returns the sorted names of the images on which mirroring must be enabled and disabled to
+// match the image filter
+func imageFilterChanges(filter *cephv1.RadosNamespaceMirroringImageFilter, images []cephclient.CephBlockImage, mirroredImages []cephclient.Images) ([]string, []string) {
+	mirrored := map[string]bool{}
+	for _, image := range mirroredImages {
+		mirrored[image.Name] = true
+	}
+
+	var toEnable, toDisable []string
+	for _, image := range images {
+		matches := imageFilterMatches(filter, image.Name)
+		if matches && !mirrored[image.Name] {
+			toEnable = append(toEnable, image.Name)
+		}
+		if !matches && mirrored[image.Name] {
+			toDisable = append(toDisable, image.Name)
+		}
+	}
+	sort.Strings(toEnable)
+	sort.Strings(toDisable)
+	return toEnable, toDisable
+}
+
+// reconcileImageFilter enables the mirroring of the images matching the image filter and disables it on the
+// others, in batches. A ImageFilterInProgressError is returned if images are left for the next reconciles.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileImageFilter(radosNamespace *cephv1.CephBlockPoolRadosNamespace, poolAndRadosNamespaceName string, log *reconcileLogger) error {
+	var images []cephclient.CephBlockImage
+	err := log.timeCephCall("list images", func() error {
+		var err error
+		images, err = cephclient.ListImagesInRadosNamespace(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the images of rados namespace %q", poolAndRadosNamespaceName)
+	}
+	// the statuses of the images are listed on every reconcile, only the changed statuses are parsed again
+	var mirroredStatuses []cephclient.MirrorImageStatus
+	err = log.timeCephCall("list mirrored images", func() error {
+		var err error
+		mirroredStatuses, err = cephclient.GetMirroredImageStatuses(r.context, r.clusterInfo, poolAndRadosNamespaceName, r.mirrorImageStatuses)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the mirrored images of rados namespace %q", poolAndRadosNamespaceName)
+	}
+	mirrored := make([]cephclient.Images, 0, len(mirroredStatuses))
+	for _, status := range mirroredStatuses {
+		mirrored = append(mirrored, cephclient.Images{Name: status.Name})
+	}
+
+	toEnable, toDisable := imageFilterChanges(radosNamespace.Spec.Mirroring.ImageFilter, images, mirrored)
+	if len(toEnable) == 0 && len(toDisable) == 0 {
+		log.Debugf("the mirrored images of rados namespace %q match the image filter", poolAndRadosNamespaceName)
+		return nil
+	}
+	log.Infof("enabling mirroring of %d images and disabling mirroring of %d images of rados namespace %q to match the image filter", len(toEnable), len(toDisable), poolAndRadosNamespaceName)
+
+	processed := 0
+	for _, image := range toEnable {
+		if processed == maxImagesFilteredPerReconcile {
+			break
+		}
+		err := log.timeCephCall("enable image mirroring", func() error {
+			return cephclient.EnableImageMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName, image)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to enable mirroring of the images matching the image filter of rados namespace %q", poolAndRadosNamespaceName)
+		}
+		processed++
+	}
+	for _, image := range toDisable {
+		if processed == maxImagesFilteredPerReconcile {
+			break
+		}
+		err := log.timeCephCall("disable image mirroring", func() error {
+			return cephclient.DisableImageMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName, image)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to disable mirroring of the images not matching the image filter of rados namespace %q", poolAndRadosNamespaceName)
+		}
+		processed++
+	}
+
+	if remaining := len(toEnable) + len(toDisable) - processed; remaining > 0 {
+		return &ImageFilterInProgressError{RadosNamespace: poolAndRadosNamespaceName, Remaining: remaining}
+	}
+	return nil
+}
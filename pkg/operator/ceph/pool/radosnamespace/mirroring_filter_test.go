@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestImageFilterMatches(t *testing.T) {
+	filter := &cephv1.RadosNamespaceMirroringImageFilter{}
+	assert.True(t, imageFilterMatches(filter, "db-1"))
+
+	filter.Include = []string{"db-*", "web-?"}
+	assert.True(t, imageFilterMatches(filter, "db-1"))
+	assert.True(t, imageFilterMatches(filter, "web-1"))
+	assert.False(t, imageFilterMatches(filter, "web-10"))
+	assert.False(t, imageFilterMatches(filter, "cache"))
+
+	// exclusions take precedence over inclusions
+	filter.Exclude = []string{"*-tmp"}
+	assert.True(t, imageFilterMatches(filter, "db-1"))
+	assert.False(t, imageFilterMatches(filter, "db-tmp"))
+
+	filter.Include = nil
+	assert.True(t, imageFilterMatches(filter, "cache"))
+	assert.False(t, imageFilterMatches(filter, "cache-tmp"))
+}
+
+func TestImageFilterChanges(t *testing.T) {
+	filter := &cephv1.RadosNamespaceMirroringImageFilter{Include: []string{"db-*"}}
+	images := []cephclient.CephBlockImage{{Name: "db-2"}, {Name: "db-1"}, {Name: "web-1"}, {Name: "web-2"}, {Name: "db-3"}}
+	mirrored := []cephclient.Images{{Name: "db-3"}, {Name: "web-2"}}
+
+	toEnable, toDisable := imageFilterChanges(filter, images, mirrored)
+	assert.Equal(t, []string{"db-1", "db-2"}, toEnable)
+	assert.Equal(t, []string{"web-2"}, toDisable)
+}
+
+func TestReconcileImageFilter(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:        "image",
+				ImageFilter: &cephv1.RadosNamespaceMirroringImageFilter{Include: []string{"db-*"}, Exclude: []string{"db-tmp"}},
+			},
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	defer func(max int) { maxImagesFilteredPerReconcile = max }(maxImagesFilteredPerReconcile)
+	maxImagesFilteredPerReconcile = 2
+
+	images := []string{"db-1", "db-2", "db-3", "db-tmp", "web-1", "web-2"}
+	mirrored := map[string]bool{"db-1": true, "db-tmp": true, "web-2": true}
+	var enabled, disabled []string
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"image"}`, nil
+					}
+					if args[0] == "ls" {
+						assert.Equal(t, []string{"-l", "replicapool", "--namespace", "namespace-a"}, args[1:5])
+						var list []string
+						for _, image := range images {
+							list = append(list, `{"image":"`+image+`"}`)
+						}
+						return "[" + strings.Join(list, ",") + "]", nil
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+						var list []string
+						for image := range mirrored {
+							list = append(list, `{"name":"`+image+`"}`)
+						}
+						sort.Strings(list)
+						return `{"images":[` + strings.Join(list, ",") + `]}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "image" {
+						image := strings.TrimPrefix(args[3], "replicapool/namespace-a/")
+						switch args[2] {
+						case "enable":
+							enabled = append(enabled, image)
+							mirrored[image] = true
+						case "disable":
+							disabled = append(disabled, image)
+							delete(mirrored, image)
+						}
+						return "", nil
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+						return "[]", nil
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+
+	// the filter requires enabling db-2 and db-3 and disabling db-tmp and web-2, two images per reconcile
+	err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	var filterErr *ImageFilterInProgressError
+	assert.True(t, errors.As(err, &filterErr))
+	assert.Equal(t, 2, filterErr.Remaining)
+	assert.Equal(t, []string{"db-2", "db-3"}, enabled)
+	assert.Empty(t, disabled)
+
+	err = r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db-tmp", "web-2"}, disabled)
+	assert.Equal(t, map[string]bool{"db-1": true, "db-2": true, "db-3": true}, mirrored)
+
+	// the images already match the filter
+	err = r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	assert.NoError(t, err)
+	assert.Len(t, enabled, 2)
+	assert.Len(t, disabled, 2)
+}
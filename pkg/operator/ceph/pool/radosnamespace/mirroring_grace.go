@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	// mirroringGracePeriodSettingName is the operator setting with the period after mirroring is enabled during
+	// which the mirroring errors are reported as initializing, 0 disables the grace period
+	mirroringGracePeriodSettingName = "ROOK_RADOS_NAMESPACE_MIRRORING_GRACE_PERIOD"
+	defaultMirroringGracePeriod     = 2 * time.Minute
+)
+
+// mirroringGracePeriod returns the period after mirroring is enabled during which the mirroring errors are
+// reported as initializing
+func mirroringGracePeriod(log *reconcileLogger) time.Duration {
+	value := k8sutil.GetOperatorSetting(mirroringGracePeriodSettingName, defaultMirroringGracePeriod.String())
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil || gracePeriod < 0 {
+		log.Warningf("invalid setting %q value %q, using the default %s. %v", mirroringGracePeriodSettingName, value, defaultMirroringGracePeriod, err)
+		return defaultMirroringGracePeriod
+	}
+	return gracePeriod
+}
+
+// mirroringEnabledAt returns the time mirroring was last enabled on the rados namespace, or the zero time if it
+// is not recorded
+func mirroringEnabledAt(radosNamespace *cephv1.CephBlockPoolRadosNamespace) time.Time {
+	if radosNamespace.Status == nil {
+		return time.Time{}
+	}
+	enabledAt, err := time.Parse(time.RFC3339, radosNamespace.Status.Info[cephclient.MirroringEnabledAtInfoKey])
+	if err != nil {
+		return time.Time{}
+	}
+	return enabledAt
+}
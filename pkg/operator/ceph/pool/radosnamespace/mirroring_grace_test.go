@@ -0,0 +1,111 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMirroringGracePeriod(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.Equal(t, defaultMirroringGracePeriod, mirroringGracePeriod(log))
+
+	t.Setenv(mirroringGracePeriodSettingName, "30s")
+	assert.Equal(t, 30*time.Second, mirroringGracePeriod(log))
+
+	t.Setenv(mirroringGracePeriodSettingName, "0")
+	assert.Zero(t, mirroringGracePeriod(log))
+
+	t.Setenv(mirroringGracePeriodSettingName, "-1m")
+	assert.Equal(t, defaultMirroringGracePeriod, mirroringGracePeriod(log))
+
+	t.Setenv(mirroringGracePeriodSettingName, "soon")
+	assert.Equal(t, defaultMirroringGracePeriod, mirroringGracePeriod(log))
+}
+
+func TestRecordMirroringEnabledAt(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+	assert.True(t, mirroringEnabledAt(radosNamespace).IsZero())
+
+	mirroringMode := "disabled"
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"` + mirroringMode + `"}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "enable" {
+						mirroringMode = "image"
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+						return "[]", nil
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+		clock:                  fakeClock,
+	}
+
+	// the time mirroring is enabled starts the grace period of the mirroring checker
+	err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	assert.NoError(t, err)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "2025-01-01T00:00:00Z", current.Status.Info[cephclient.MirroringEnabledAtInfoKey])
+	assert.True(t, fakeClock.Now().Equal(mirroringEnabledAt(current)))
+
+	// the time is kept while mirroring is not enabled again
+	fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+	err = r.reconcileMirroring(current, cephBlockPool, log)
+	assert.NoError(t, err)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "2025-01-01T00:00:00Z", current.Status.Info[cephclient.MirroringEnabledAtInfoKey])
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+// supportedMirroringModes are the mirroring modes of a rados namespace
+var supportedMirroringModes = []cephv1.RadosNamespaceMirroringMode{cephv1.RadosNamespaceMirroringModePool, cephv1.RadosNamespaceMirroringModeImage}
+
+// validateMirroringMode checks that the mirroring mode is one of the supported modes, since the mode is passed
+// to ceph and compared to the mode reported by ceph as is. The empty mode allowed by the CRD is rejected.
+func validateMirroringMode(mode cephv1.RadosNamespaceMirroringMode) error {
+	if mode == "" {
+		return errors.Errorf("the mirroring mode is required, supported modes are %q and %q", cephv1.RadosNamespaceMirroringModePool, cephv1.RadosNamespaceMirroringModeImage)
+	}
+	for _, supported := range supportedMirroringModes {
+		if mode == supported {
+			return nil
+		}
+	}
+	return errors.Errorf("unknown mirroring mode %q, supported modes are %q and %q", mode, cephv1.RadosNamespaceMirroringModePool, cephv1.RadosNamespaceMirroringModeImage)
+}
+
+// isMirroringModeSwitch returns whether the mirroring enabled by the operator on the rados namespace is
+// configured with another mode than the one of the spec
+func isMirroringModeSwitch(radosNamespace *cephv1.CephBlockPoolRadosNamespace, mirrorInfo *cephv1.MirroringInfo) bool {
+	if radosNamespace.Spec.Mirroring == nil || mirrorInfo == nil || !isMirroringRecorded(radosNamespace) {
+		return false
+	}
+	return mirrorInfo.Mode != "" && mirrorInfo.Mode != "disabled" && mirrorInfo.Mode != string(radosNamespace.Spec.Mirroring.Mode)
+}
+
+// replicatingImages returns the number of images whose replication is starting, stopping or syncing
+func replicatingImages(states cephv1.StatesSpec) int {
+	return states.StartingReplay + states.Syncing + states.StopReplaying
+}
+
+// prepareMirroringModeSwitch removes the artifacts of the current mirroring mode before the mode of the spec
+// is enabled. A MirroringModeSwitchInProgressError is returned while images are mid-replication, the mode is
+// switched by a later reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) prepareMirroringModeSwitch(poolAndRadosNamespaceName string, mirrorInfo *cephv1.MirroringInfo, mode cephv1.RadosNamespaceMirroringMode, log *reconcileLogger) error {
+	var mirrorStatus *cephv1.MirroringStatus
+	err := log.timeCephCall("get mirroring status", func() error {
+		var err error
+		mirrorStatus, err = cephclient.GetPoolMirroringStatus(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the mirroring status of radosnamespace %q", poolAndRadosNamespaceName)
+	}
+	if mirrorStatus.Summary != nil {
+		if replicating := replicatingImages(mirrorStatus.Summary.States); replicating > 0 {
+			return &MirroringModeSwitchInProgressError{RadosNamespace: poolAndRadosNamespaceName, From: mirrorInfo.Mode, To: string(mode), Replicating: replicating}
+		}
+	}
+
+	log.Infof("switching the mirroring of radosnamespace %q from %q to %q mode", poolAndRadosNamespaceName, mirrorInfo.Mode, mode)
+	if mirrorInfo.Mode == string(cephv1.RadosNamespaceMirroringModeImage) {
+		// the snapshot schedules only apply to the image mode, the schedules of the spec are set again
+		// once the image mode is enabled
+		err = log.timeCephCall("remove snapshot schedules", func() error {
+			return cephclient.ReconcileSnapshotSchedules(r.context, r.clusterInfo, poolAndRadosNamespaceName, nil)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove the snapshot schedules of radosnamespace %q before switching the mirroring mode", poolAndRadosNamespaceName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestMirroringModeSwitch(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	newRadosNamespace := func(mode cephv1.RadosNamespaceMirroringMode, schedules []cephv1.SnapshotScheduleSpec) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 2},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: mode, SnapshotSchedules: schedules},
+			},
+			// mirroring was enabled by the operator for the previous generation
+			Status: &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{mirroringEnabledInfoKey: "1"}},
+		}
+	}
+
+	var commands []string
+	var mirroringMode, poolStatus, schedules string
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) *ReconcileCephBlockPoolRadosNamespace {
+		commands = nil
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client: cl,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+							return `{"mode":"` + mirroringMode + `"}`, nil
+						}
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+							return poolStatus, nil
+						}
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "enable" {
+							commands = append(commands, strings.Join(args[:5], " "))
+							mirroringMode = args[4]
+							return "", nil
+						}
+						if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+							return schedules, nil
+						}
+						if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" {
+							commands = append(commands, strings.Join(args[:4], " "))
+							schedules = "[]"
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+			opManagerContext:       ctx,
+			radosNamespaceContexts: map[string]*mirrorHealth{},
+		}
+	}
+
+	t.Run("image to snapshot", func(t *testing.T) {
+		// the snapshot-based mirroring is configured with the image mode and snapshot schedules
+		mirroringMode = "pool"
+		poolStatus = `{"summary":{"health":"OK","states":{"replaying":3}}}`
+		schedules = "[]"
+		radosNamespace := newRadosNamespace(cephv1.RadosNamespaceMirroringModeImage, []cephv1.SnapshotScheduleSpec{{Interval: "24h"}})
+		r := newReconciler(radosNamespace)
+
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"mirror pool enable replicapool/namespace-a image",
+			"mirror snapshot schedule add",
+		}, commands)
+		assert.Equal(t, "image", mirroringMode)
+	})
+
+	t.Run("snapshot to image", func(t *testing.T) {
+		mirroringMode = "image"
+		poolStatus = `{"summary":{"health":"OK","states":{"replaying":3}}}`
+		schedules = `[{"interval":"24h"}]`
+		radosNamespace := newRadosNamespace(cephv1.RadosNamespaceMirroringModePool, nil)
+		r := newReconciler(radosNamespace)
+
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		assert.NoError(t, err)
+		// the snapshot schedules are removed before the journal-based mirroring is enabled
+		assert.Equal(t, []string{
+			"mirror snapshot schedule remove",
+			"mirror pool enable replicapool/namespace-a pool",
+		}, commands)
+		assert.Equal(t, "pool", mirroringMode)
+	})
+
+	t.Run("switch waits for images mid-replication", func(t *testing.T) {
+		mirroringMode = "image"
+		poolStatus = `{"summary":{"health":"WARNING","states":{"replaying":1,"syncing":2}}}`
+		schedules = `[{"interval":"24h"}]`
+		radosNamespace := newRadosNamespace(cephv1.RadosNamespaceMirroringModePool, nil)
+		r := newReconciler(radosNamespace)
+
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		var modeSwitchErr *MirroringModeSwitchInProgressError
+		assert.True(t, errors.As(err, &modeSwitchErr))
+		assert.Equal(t, 2, modeSwitchErr.Replicating)
+		assert.Empty(t, commands)
+		assert.Equal(t, "image", mirroringMode)
+
+		// the mode is switched once the replication settles
+		poolStatus = `{"summary":{"health":"OK","states":{"replaying":3}}}`
+		err = r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		assert.NoError(t, err)
+		assert.Equal(t, "pool", mirroringMode)
+	})
+}
+
+func TestIsMirroringModeSwitch(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			Mirroring: &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage},
+		},
+	}
+	// mirroring not enabled by the operator yet
+	assert.False(t, isMirroringModeSwitch(radosNamespace, &cephv1.MirroringInfo{Mode: "pool"}))
+
+	radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{mirroringEnabledInfoKey: "1"}}
+	assert.True(t, isMirroringModeSwitch(radosNamespace, &cephv1.MirroringInfo{Mode: "pool"}))
+	assert.False(t, isMirroringModeSwitch(radosNamespace, &cephv1.MirroringInfo{Mode: "image"}))
+	assert.False(t, isMirroringModeSwitch(radosNamespace, &cephv1.MirroringInfo{Mode: "disabled"}))
+	assert.False(t, isMirroringModeSwitch(radosNamespace, nil))
+}
+
+func TestValidateMirroringMode(t *testing.T) {
+	tests := []struct {
+		mode    cephv1.RadosNamespaceMirroringMode
+		wantErr string
+	}{
+		{mode: "image"},
+		{mode: "pool"},
+		{mode: "Image", wantErr: "unknown mirroring mode"},
+		{mode: "POOL", wantErr: "unknown mirroring mode"},
+		{mode: "journal", wantErr: "unknown mirroring mode"},
+		{mode: "snapshot", wantErr: "unknown mirroring mode"},
+		{mode: "", wantErr: "the mirroring mode is required"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			err := validateMirroringMode(tt.mode)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.ErrorContains(t, validateMirroring(&cephv1.RadosNamespaceMirroring{Mode: tt.mode}), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, validateMirroring(&cephv1.RadosNamespaceMirroring{Mode: tt.mode}))
+		})
+	}
+}
+
+func TestReconcileMirroringMode(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	newRadosNamespace := func(mode cephv1.RadosNamespaceMirroringMode) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: mode},
+			},
+			Status: &cephv1.CephBlockPoolRadosNamespaceStatus{},
+		}
+	}
+	var enabled []string
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) *ReconcileCephBlockPoolRadosNamespace {
+		enabled = nil
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+							return `{"mode":"disabled"}`, nil
+						}
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "enable" {
+							enabled = append(enabled, args[4])
+						}
+						if args[0] == "mirror" && args[1] == "snapshot" && args[3] == "ls" {
+							return "[]", nil
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+			opManagerContext:       ctx,
+			radosNamespaceContexts: map[string]*mirrorHealth{},
+		}
+	}
+
+	t.Run("valid mode", func(t *testing.T) {
+		radosNamespace := newRadosNamespace(cephv1.RadosNamespaceMirroringModeImage)
+		r := newReconciler(radosNamespace)
+		assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+		assert.Equal(t, []string{"image"}, enabled)
+	})
+
+	t.Run("case variant is not passed to ceph", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("Image")
+		r := newReconciler(radosNamespace)
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		assert.ErrorContains(t, err, "unknown mirroring mode \"Image\"")
+		assert.Empty(t, enabled)
+	})
+
+	t.Run("unknown mode is not passed to ceph", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("journal")
+		r := newReconciler(radosNamespace)
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		assert.ErrorContains(t, err, "unknown mirroring mode \"journal\"")
+		assert.Empty(t, enabled)
+	})
+}
+
+func TestReconcileUnknownMirroringMode(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+
+	tests := []struct {
+		mode    cephv1.RadosNamespaceMirroringMode
+		wantErr string
+	}{
+		{mode: "journal", wantErr: "unknown mirroring mode \"journal\""},
+		{mode: "", wantErr: "the mirroring mode is required"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "namespace-a",
+					Namespace:  namespace,
+					Generation: 1,
+					Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+				},
+				TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+				Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+					BlockPoolName: "replicapool",
+					Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: tt.mode},
+				},
+			}
+			cephBlockPool := newTestCephBlockPool(namespace)
+			cephBlockPool.Spec.Mirroring.Enabled = true
+
+			var cephCommands []string
+			r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+				cephCommands = append(cephCommands, strings.Join(args, " "))
+				return "", nil
+			}, radosNamespace, newTestCephCluster(namespace), cephBlockPool)
+			cl := r.client
+			createTestCSIConfigMap(t, r, namespace)
+
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+
+			_, err := r.Reconcile(ctx, req)
+			assert.ErrorContains(t, err, tt.wantErr)
+			for _, command := range cephCommands {
+				assert.False(t, strings.HasPrefix(command, "mirror pool enable"), command)
+			}
+
+			current := &cephv1.CephBlockPoolRadosNamespace{}
+			assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+			assert.Equal(t, cephv1.ConditionFailure, current.Status.Phase)
+			condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionFailure)
+			assert.NotNil(t, condition)
+			assert.Equal(t, v1.ConditionTrue, condition.Status)
+			assert.Contains(t, condition.Message, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requestsMirroringPeerDirection returns whether the mirroring spec requests the direction of any peer
+func requestsMirroringPeerDirection(mirroring *cephv1.RadosNamespaceMirroring) bool {
+	if mirroring == nil {
+		return false
+	}
+	if mirroring.Direction != "" {
+		return true
+	}
+	for _, peer := range mirroring.Peers {
+		if peer.Direction != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMirroringPeerDirections returns an error if the peer directions requested by the rados namespace conflict
+// with the directions requested by the other rados namespaces of the pool. The peers belong to the pool, their
+// direction is only set by the CephBlockPool when their bootstrap peer is imported.
+func (r *ReconcileCephBlockPoolRadosNamespace) checkMirroringPeerDirections(radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	if !requestsMirroringPeerDirection(radosNamespace.Spec.Mirroring) {
+		return nil
+	}
+
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	err := r.client.List(r.opManagerContext, radosNamespaces, client.MatchingFields{blockPoolNameIndex: blockPoolKey(blockPoolNamespace(radosNamespace), radosNamespace.Spec.BlockPoolName)})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the rados namespaces of pool %q", radosNamespace.Spec.BlockPoolName)
+	}
+	_, _, err = cephv1.GetMirroringPeerDirections(radosNamespaces.Items)
+	if err != nil {
+		return errors.Wrapf(err, "invalid mirroring direction of rados namespace %q", radosNamespace.Name)
+	}
+	return nil
+}
+
+// checkMirroringPeersConfigured returns an error if the peer sites listed in the mirroring spec are not
+// configured on the pool
+func checkMirroringPeersConfigured(mirroring *cephv1.RadosNamespaceMirroring, mirrorInfo *cephv1.MirroringInfo) error {
+	missing := sets.New[string]()
+	for _, peer := range mirroring.Peers {
+		missing.Insert(peer.SiteName)
+	}
+	for _, peer := range mirrorInfo.Peers {
+		missing.Delete(peer.SiteName)
+	}
+	if missing.Len() > 0 {
+		return errors.Errorf("mirroring peer sites %v are not configured on the pool", sets.List(missing))
+	}
+	return nil
+}
+
+// verifyMirroringPeers checks that the peer sites listed in the mirroring spec of the rados namespace are
+// configured once mirroring is enabled
+func (r *ReconcileCephBlockPoolRadosNamespace) verifyMirroringPeers(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) error {
+	if len(radosNamespace.Spec.Mirroring.Peers) == 0 {
+		return nil
+	}
+
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	var mirrorInfo *cephv1.MirroringInfo
+	err := log.timeCephCall("get mirroring info", func() error {
+		var err error
+		mirrorInfo, err = cephclient.GetPoolMirroringInfo(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get mirroring info of %q", poolAndRadosNamespaceName)
+	}
+	if err := checkMirroringPeersConfigured(radosNamespace.Spec.Mirroring, mirrorInfo); err != nil {
+		return errors.Wrapf(err, "invalid mirroring peers of %q", poolAndRadosNamespaceName)
+	}
+	return nil
+}
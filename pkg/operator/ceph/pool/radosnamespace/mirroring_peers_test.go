@@ -0,0 +1,81 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckMirroringPeerDirections(t *testing.T) {
+	newRadosNamespace := func(name, pool string, mirroring *cephv1.RadosNamespaceMirroring) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: pool, Mirroring: mirroring},
+		}
+	}
+	newReconciler := func(objects ...runtime.Object) *ReconcileCephBlockPoolRadosNamespace {
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objects...).
+			WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, blockPoolNameIndex, indexBlockPoolName).Build()
+		return &ReconcileCephBlockPoolRadosNamespace{client: cl, opManagerContext: context.TODO()}
+	}
+	rxOnly := &cephv1.RadosNamespaceMirroring{Mode: "image", Direction: cephv1.RadosNamespaceMirroringDirectionRxOnly}
+	rxTx := &cephv1.RadosNamespaceMirroring{Mode: "image", Direction: cephv1.RadosNamespaceMirroringDirectionRxTx}
+
+	t.Run("no direction requested", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("namespace-a", "replicapool", &cephv1.RadosNamespaceMirroring{Mode: "image"})
+		// the index is not needed without a requested direction
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+			opManagerContext: context.TODO(),
+		}
+		assert.NoError(t, r.checkMirroringPeerDirections(radosNamespace))
+	})
+
+	t.Run("same direction", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("namespace-a", "replicapool", rxOnly)
+		r := newReconciler(radosNamespace, newRadosNamespace("namespace-b", "replicapool", rxOnly))
+		assert.NoError(t, r.checkMirroringPeerDirections(radosNamespace))
+	})
+
+	t.Run("conflicting direction in another pool", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("namespace-a", "replicapool", rxOnly)
+		r := newReconciler(radosNamespace, newRadosNamespace("namespace-b", "otherpool", rxTx))
+		assert.NoError(t, r.checkMirroringPeerDirections(radosNamespace))
+	})
+
+	t.Run("conflicting direction in the same pool", func(t *testing.T) {
+		radosNamespace := newRadosNamespace("namespace-a", "replicapool", rxOnly)
+		r := newReconciler(radosNamespace, newRadosNamespace("namespace-b", "replicapool", rxTx))
+		assert.ErrorContains(t, r.checkMirroringPeerDirections(radosNamespace), "conflicting mirroring peer directions")
+	})
+}
+
+func TestCheckMirroringPeersConfigured(t *testing.T) {
+	mirroring := &cephv1.RadosNamespaceMirroring{Mode: "image", Peers: []cephv1.RadosNamespaceMirroringPeer{{SiteName: "site-b"}, {SiteName: "site-c"}}}
+
+	assert.NoError(t, checkMirroringPeersConfigured(mirroring, &cephv1.MirroringInfo{Peers: []cephv1.PeersSpec{{SiteName: "site-b"}, {SiteName: "site-c"}}}))
+	assert.ErrorContains(t, checkMirroringPeersConfigured(mirroring, &cephv1.MirroringInfo{Peers: []cephv1.PeersSpec{{SiteName: "site-b"}}}),
+		"mirroring peer sites [site-c] are not configured on the pool")
+}
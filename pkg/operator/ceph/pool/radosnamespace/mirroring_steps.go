@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// mirroringEnabledInfoKey is the status info key of the generation of the rados namespace for which mirroring
+// was enabled, so that a reconcile failing on a later mirroring step does not enable mirroring again
+const mirroringEnabledInfoKey = "mirroringEnabledGeneration"
+
+// isMirroringEnabledForGeneration returns whether mirroring was already enabled for the current generation
+// of the rados namespace
+func isMirroringEnabledForGeneration(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	if radosNamespace.Status == nil {
+		return false
+	}
+	return radosNamespace.Status.Info[mirroringEnabledInfoKey] == strconv.FormatInt(radosNamespace.Generation, 10)
+}
+
+// isMirroringRecorded returns whether mirroring was enabled by the operator for any generation of the rados
+// namespace, which is not the case for the mirroring configured before the rados namespace was adopted
+func isMirroringRecorded(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.Status != nil && radosNamespace.Status.Info[mirroringEnabledInfoKey] != ""
+}
+
+// mirroringMatchesSpec returns whether the mirroring configured in ceph matches the mirroring spec of the rados
+// namespace. The mode, the remote namespace and the direction of the peers are compared, the remote namespace
+// is only compared if it is reported by ceph and the direction only if it is set in the spec. The peers listed
+// in the spec must be configured.
+func mirroringMatchesSpec(radosNamespace *cephv1.CephBlockPoolRadosNamespace, mirrorInfo *cephv1.MirroringInfo) bool {
+	mirroring := radosNamespace.Spec.Mirroring
+	if mirroring == nil || mirrorInfo == nil || mirrorInfo.Mode != string(mirroring.Mode) {
+		return false
+	}
+
+	remoteNamespace := cephv1.GetRadosNamespaceName(radosNamespace)
+	if mirroring.RemoteNamespace != nil {
+		remoteNamespace = *mirroring.RemoteNamespace
+		if remoteNamespace == cephv1.ImplicitNamespaceKey {
+			remoteNamespace = cephv1.ImplicitNamespaceVal
+		}
+	}
+	if (mirrorInfo.RemoteNamespace != "" || remoteNamespace == cephv1.ImplicitNamespaceVal) && mirrorInfo.RemoteNamespace != remoteNamespace {
+		return false
+	}
+
+	direction := string(mirroring.Direction)
+	peerDirections := getPeerMirroringDirections(mirroring)
+	found := 0
+	for _, peer := range mirrorInfo.Peers {
+		peerDirection := direction
+		if override, ok := peerDirections[peer.SiteName]; ok {
+			peerDirection = override
+			found++
+		}
+		if peerDirection != "" && peer.Direction != "" && peer.Direction != peerDirection {
+			return false
+		}
+	}
+	// all the peers of the spec must be configured
+	if found != len(peerDirections) {
+		return false
+	}
+
+	return true
+}
+
+// recordMirroringEnabled records in the status the generation for which mirroring was enabled, the record is
+// removed if the generation is empty
+func (r *ReconcileCephBlockPoolRadosNamespace) recordMirroringEnabled(name types.NamespacedName, generation string, log *reconcileLogger) {
+	r.recordMirroringInfo(name, mirroringEnabledInfoKey, generation, log)
+}
+
+// recordMirroringInfo records a mirroring state in the status info, the key is removed if the value is empty
+func (r *ReconcileCephBlockPoolRadosNamespace) recordMirroringInfo(name types.NamespacedName, key, value string, log *reconcileLogger) {
+	err := r.mutateStatus(name, func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+		if radosNamespace.Status.Info[key] == value {
+			return false
+		}
+		if value == "" {
+			delete(radosNamespace.Status.Info, key)
+		} else {
+			if radosNamespace.Status.Info == nil {
+				radosNamespace.Status.Info = map[string]string{}
+			}
+			radosNamespace.Status.Info[key] = value
+		}
+		return true
+	})
+	if err != nil {
+		log.Errorf("failed to record the mirroring state of ceph blockpool rados namespace %q. %v", name, err)
+	}
+}
+
+// resolvedSnapshotScheduleConditions returns the condition clearing a previous snapshot schedule failure once
+// the snapshot schedules are set
+func resolvedSnapshotScheduleConditions(radosNamespace *cephv1.CephBlockPoolRadosNamespace) []cephv1.Condition {
+	if radosNamespace.Status == nil {
+		return nil
+	}
+	condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionFailure)
+	if condition == nil || condition.Reason != cephv1.SnapshotScheduleFailedReason || condition.Status != v1.ConditionTrue {
+		return nil
+	}
+	return []cephv1.Condition{{
+		Type:    cephv1.ConditionFailure,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.ReconcileSucceeded,
+		Message: "snapshot schedules are set",
+	}}
+}
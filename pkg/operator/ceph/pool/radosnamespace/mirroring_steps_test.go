@@ -0,0 +1,411 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMirroringPartialFailure(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:              "image",
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h"}},
+			},
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	mirroringMode := "disabled"
+	enableCalls := 0
+	scheduleCalls := 0
+	scheduleFails := true
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"` + mirroringMode + `"}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "enable" {
+						enableCalls++
+						mirroringMode = "image"
+						return "", nil
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+						return "[]", nil
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "add" {
+						scheduleCalls++
+						if scheduleFails {
+							return "", errors.New("failed to add schedule")
+						}
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+
+	// mirroring is enabled but the snapshot schedule fails
+	err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	var scheduleErr *SnapshotSchedulesError
+	assert.True(t, errors.As(err, &scheduleErr))
+	assert.Equal(t, 1, enableCalls)
+	assert.Equal(t, 1, scheduleCalls)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "1", current.Status.Info[mirroringEnabledInfoKey])
+
+	// the retry only sets the snapshot schedules
+	scheduleFails = false
+	err = r.reconcileMirroring(current, cephBlockPool, log)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, enableCalls)
+	assert.Equal(t, 2, scheduleCalls)
+
+	// a new generation enables mirroring again
+	current.Generation = 2
+	err = r.reconcileMirroring(current, cephBlockPool, log)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, enableCalls)
+}
+
+func TestResolvedSnapshotScheduleConditions(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.Empty(t, resolvedSnapshotScheduleConditions(radosNamespace))
+
+	radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{
+		Conditions: []cephv1.Condition{{Type: cephv1.ConditionFailure, Status: v1.ConditionTrue, Reason: cephv1.ReconcileFailed}},
+	}
+	assert.Empty(t, resolvedSnapshotScheduleConditions(radosNamespace))
+
+	radosNamespace.Status.Conditions[0].Reason = cephv1.SnapshotScheduleFailedReason
+	conditions := resolvedSnapshotScheduleConditions(radosNamespace)
+	assert.Len(t, conditions, 1)
+	assert.Equal(t, v1.ConditionFalse, conditions[0].Status)
+	assert.Equal(t, cephv1.ReconcileSucceeded, conditions[0].Reason)
+}
+
+func TestMirroringDrainOnDisable(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image", DrainOnDisable: true},
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	defer func(max int) { maxImagesDrainedPerReconcile = max }(maxImagesDrainedPerReconcile)
+	maxImagesDrainedPerReconcile = 2
+
+	mirroringMode := "image"
+	images := []string{"img1", "img2", "img3", "img4", "img5"}
+	disabledImages := []string{}
+	disableCalls := 0
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"` + mirroringMode + `"}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+						output := `{"images":[`
+						for i, image := range images {
+							if i > 0 {
+								output += ","
+							}
+							output += `{"name":"` + image + `"}`
+						}
+						return output + `]}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "image" && args[2] == "disable" {
+						assert.Equal(t, "replicapool/namespace-a/"+images[0], args[3])
+						disabledImages = append(disabledImages, images[0])
+						images = images[1:]
+						return "", nil
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "disable" {
+						disableCalls++
+						mirroringMode = "disabled"
+						return "", nil
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+						return "[]", nil
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+
+	// the drain setting is recorded while mirroring is enabled
+	err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	assert.NoError(t, err)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.True(t, isDrainOnDisableRecorded(current))
+
+	// the mirrored images are drained in batches across the reconciles
+	current.Spec.Mirroring = nil
+	for _, remaining := range []int{3, 1} {
+		err = r.reconcileMirroring(current, cephBlockPool, log)
+		var drainErr *MirroringDrainInProgressError
+		assert.True(t, errors.As(err, &drainErr))
+		assert.Equal(t, remaining, drainErr.Remaining)
+		assert.Equal(t, 0, disableCalls)
+	}
+
+	// the last batch is drained and mirroring is disabled on the rados namespace
+	err = r.reconcileMirroring(current, cephBlockPool, log)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"img1", "img2", "img3", "img4", "img5"}, disabledImages)
+	assert.Equal(t, 1, disableCalls)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.False(t, isDrainOnDisableRecorded(current))
+}
+
+func TestMirroringDisableWithImagesWithoutDrain(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"image"}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+						return `{"images":[{"name":"img1"}]}`, nil
+					}
+					assert.Fail(t, "unexpected command", args)
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+
+	err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "drainOnDisable")
+}
+
+func TestMirroringMatchesSpec(t *testing.T) {
+	remoteNamespace := "namespace-b"
+	implicitNamespace := cephv1.ImplicitNamespaceKey
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		remoteNamespace *string
+		direction       cephv1.RadosNamespaceMirroringDirection
+		mirrorInfo      *cephv1.MirroringInfo
+		matches         bool
+	}{
+		{"no mirroring info", nil, "", nil, false},
+		{"disabled", nil, "", &cephv1.MirroringInfo{Mode: "disabled"}, false},
+		{"different mode", nil, "", &cephv1.MirroringInfo{Mode: "pool"}, false},
+		{"same mode", nil, "", &cephv1.MirroringInfo{Mode: "image"}, true},
+		{"same rados namespace", nil, "", &cephv1.MirroringInfo{Mode: "image", RemoteNamespace: "namespace-a"}, true},
+		{"different default remote namespace", nil, "", &cephv1.MirroringInfo{Mode: "image", RemoteNamespace: "namespace-b"}, false},
+		{"same remote namespace", &remoteNamespace, "", &cephv1.MirroringInfo{Mode: "image", RemoteNamespace: "namespace-b"}, true},
+		{"different remote namespace", &remoteNamespace, "", &cephv1.MirroringInfo{Mode: "image", RemoteNamespace: "namespace-a"}, false},
+		{"implicit remote namespace", &implicitNamespace, "", &cephv1.MirroringInfo{Mode: "image"}, true},
+		{"implicit remote namespace not configured", &implicitNamespace, "", &cephv1.MirroringInfo{Mode: "image", RemoteNamespace: "namespace-a"}, false},
+		{"same direction", nil, cephv1.RadosNamespaceMirroringDirectionRxOnly,
+			&cephv1.MirroringInfo{Mode: "image", Peers: []cephv1.PeersSpec{{UUID: "peer-a", Direction: "rx-only"}}}, true},
+		{"different direction", nil, cephv1.RadosNamespaceMirroringDirectionRxOnly,
+			&cephv1.MirroringInfo{Mode: "image", Peers: []cephv1.PeersSpec{{UUID: "peer-a", Direction: "rx-tx"}, {UUID: "peer-b", Direction: "rx-only"}}}, false},
+		{"no direction", nil, "",
+			&cephv1.MirroringInfo{Mode: "image", Peers: []cephv1.PeersSpec{{UUID: "peer-a", Direction: "rx-tx"}, {UUID: "peer-b", Direction: "rx-only"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radosNamespace.Spec.Mirroring.RemoteNamespace = tt.remoteNamespace
+			radosNamespace.Spec.Mirroring.Direction = tt.direction
+			assert.Equal(t, tt.matches, mirroringMatchesSpec(radosNamespace, tt.mirrorInfo))
+		})
+	}
+}
+
+func TestMirroringMatchesSpecPeers(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{Mode: "image", Peers: []cephv1.RadosNamespaceMirroringPeer{
+				{SiteName: "site-b"},
+				{SiteName: "site-c", Direction: cephv1.RadosNamespaceMirroringDirectionTxOnly},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		peers   []cephv1.PeersSpec
+		matches bool
+	}{
+		{"direction of each peer", []cephv1.PeersSpec{{SiteName: "site-b", Direction: "rx-tx"}, {SiteName: "site-c", Direction: "tx-only"}}, true},
+		{"peer without a requested direction", []cephv1.PeersSpec{{SiteName: "site-b", Direction: "rx-only"}, {SiteName: "site-c", Direction: "tx-only"}}, true},
+		{"different direction of a peer", []cephv1.PeersSpec{{SiteName: "site-b", Direction: "rx-tx"}, {SiteName: "site-c", Direction: "rx-tx"}}, false},
+		{"peer not configured", []cephv1.PeersSpec{{SiteName: "site-b", Direction: "rx-tx"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, mirroringMatchesSpec(radosNamespace, &cephv1.MirroringInfo{Mode: "image", Peers: tt.peers}))
+		})
+	}
+}
+
+func TestMirroringAdoption(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+
+	newReconciler := func(mirrorInfo string, enableCalls *[]string) (*ReconcileCephBlockPoolRadosNamespace, *cephv1.CephBlockPoolRadosNamespace) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 3},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+			},
+		}
+		cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+		r := &ReconcileCephBlockPoolRadosNamespace{
+			client: cl,
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+							return mirrorInfo, nil
+						}
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "enable" {
+							*enableCalls = append(*enableCalls, args[4])
+							return "", nil
+						}
+						if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" && args[3] == "ls" {
+							return "[]", nil
+						}
+						if args[0] == "mirror" && args[1] == "pool" && args[2] == "status" {
+							return `{"summary":{"health":"OK"}}`, nil
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+			opManagerContext:       ctx,
+			radosNamespaceContexts: map[string]*mirrorHealth{},
+		}
+		return r, radosNamespace
+	}
+
+	t.Run("matching mirroring is adopted", func(t *testing.T) {
+		enableCalls := []string{}
+		r, radosNamespace := newReconciler(`{"mode":"image","remote_namespace":"namespace-a","peers":[{"uuid":"peer-a","direction":"rx-tx"}]}`, &enableCalls)
+		defer r.stopAllMirrorMonitoring(mirrorCheckersShutdownTimeout)
+
+		assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+		assert.Empty(t, enableCalls)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, "3", current.Status.Info[mirroringEnabledInfoKey])
+		// the mirroring status checker is started
+		assert.True(t, r.radosNamespaceContexts[mirrorMonitoringChannelKey(radosNamespace)].started)
+	})
+
+	t.Run("mismatching mirroring is reconciled", func(t *testing.T) {
+		enableCalls := []string{}
+		r, radosNamespace := newReconciler(`{"mode":"pool","remote_namespace":"namespace-a"}`, &enableCalls)
+		defer r.stopAllMirrorMonitoring(mirrorCheckersShutdownTimeout)
+
+		assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+		assert.Equal(t, []string{"image"}, enableCalls)
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, "3", current.Status.Info[mirroringEnabledInfoKey])
+	})
+
+	t.Run("mismatching remote namespace is reconciled", func(t *testing.T) {
+		enableCalls := []string{}
+		r, radosNamespace := newReconciler(`{"mode":"image","remote_namespace":"namespace-b"}`, &enableCalls)
+		defer r.stopAllMirrorMonitoring(mirrorCheckersShutdownTimeout)
+
+		assert.NoError(t, r.reconcileMirroring(radosNamespace, cephBlockPool, log))
+		assert.Equal(t, []string{"image"}, enableCalls)
+	})
+}
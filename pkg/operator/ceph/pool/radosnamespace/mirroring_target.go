@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	mirroringTargetBaseDelay = 10 * time.Second
+	mirroringTargetMaxDelay  = 5 * time.Minute
+)
+
+// mirroringTargetTracker tracks the consecutive reconciles of each rados namespace whose mirroring has not
+// reached its target health
+type mirroringTargetTracker struct {
+	attempts map[types.NamespacedName]int
+}
+
+func (t *mirroringTargetTracker) recordAttempt(name types.NamespacedName) int {
+	if t.attempts == nil {
+		t.attempts = map[types.NamespacedName]int{}
+	}
+	t.attempts[name]++
+	return t.attempts[name]
+}
+
+func (t *mirroringTargetTracker) forget(name types.NamespacedName) {
+	delete(t.attempts, name)
+}
+
+// mirroringTargetBackoff returns the delay before checking the mirroring health again, doubling with each
+// attempt up to mirroringTargetMaxDelay
+func mirroringTargetBackoff(attempts int) time.Duration {
+	delay := mirroringTargetBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= mirroringTargetMaxDelay {
+			return mirroringTargetMaxDelay
+		}
+	}
+	return delay
+}
+
+// mirroringTargetReached returns whether the mirroring of the rados namespace reached the target health of the
+// spec, which is always the case without a target
+func mirroringTargetReached(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	if radosNamespace.Spec.Mirroring == nil {
+		return true
+	}
+	switch radosNamespace.Spec.Mirroring.WaitUntil {
+	case cephv1.RadosNamespaceMirroringTargetHealthy:
+		return mirroringHealth(radosNamespace.Status) == "OK"
+	default:
+		return true
+	}
+}
+
+// waitForMirroringTarget reports that the rados namespace is not ready until its mirroring reaches the target
+// health, and returns the result requeuing the reconcile with a backoff since the mirroring checker updates the
+// mirroring status without triggering a reconcile
+func (r *ReconcileCephBlockPoolRadosNamespace) waitForMirroringTarget(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) reconcile.Result {
+	health := mirroringHealth(radosNamespace.Status)
+	if health == "" {
+		health = "unknown"
+	}
+	attempts := r.mirroringTargets.recordAttempt(name)
+	message := fmt.Sprintf("waiting for the mirroring to be %s, the mirroring health is %q", radosNamespace.Spec.Mirroring.WaitUntil, health)
+	log.Info(message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.WaitingForMirroringTargetReason,
+		Message: message,
+	})
+	return reconcile.Result{Requeue: true, RequeueAfter: mirroringTargetBackoff(attempts)}
+}
+
+// resolvedMirroringTargetConditions returns the condition clearing a previous wait for the mirroring target
+// health once it is reached
+func resolvedMirroringTargetConditions(radosNamespace *cephv1.CephBlockPoolRadosNamespace) []cephv1.Condition {
+	if radosNamespace.Status == nil {
+		return nil
+	}
+	condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionProgressing)
+	if condition == nil || condition.Reason != cephv1.WaitingForMirroringTargetReason || condition.Status != v1.ConditionTrue {
+		return nil
+	}
+	return []cephv1.Condition{{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.ReconcileSucceeded,
+		Message: fmt.Sprintf("the mirroring is %s", radosNamespace.Spec.Mirroring.WaitUntil),
+	}}
+}
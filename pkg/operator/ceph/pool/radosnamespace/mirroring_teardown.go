@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// needsMirroringTeardown returns whether the mirroring of the rados namespace must be disabled before the rados
+// namespace is deleted. The mirroring of a rados namespace of an external cluster is left to the admin of the
+// cluster.
+func needsMirroringTeardown(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster) bool {
+	if cephCluster.Spec.External.Enable {
+		return false
+	}
+	return radosNamespace.Spec.Mirroring != nil || isMirroringRecorded(radosNamespace)
+}
+
+// teardownMirroring removes the snapshot schedules and disables the mirroring of the rados namespace before it is
+// deleted, so that no schedule or mirroring config of the namespace is left in the pool. The peers are configured
+// on the pool and shared with the other rados namespaces, disabling the mirroring only detaches the rados
+// namespace from them. The mirroring is kept while the rados namespace contains images since its deletion is
+// blocked anyway.
+func (r *ReconcileCephBlockPoolRadosNamespace) teardownMirroring(radosNamespace *cephv1.CephBlockPoolRadosNamespace, nsName types.NamespacedName, log *reconcileLogger) error {
+	poolAndRadosNamespaceName := getPoolAndRadosNamespaceName(radosNamespace)
+	var empty bool
+	err := r.withCephTimeout("check rados namespace images", log, func(clusterInfo *cephclient.ClusterInfo) error {
+		var err error
+		empty, err = cephclient.IsRadosNamespaceEmpty(clusterInfo.Context, r.context, clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace))
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to check the images of rados namespace %q before disabling its mirroring", poolAndRadosNamespaceName)
+	}
+	if !empty {
+		log.Infof("rados namespace %q contains images, keeping its mirroring", poolAndRadosNamespaceName)
+		return nil
+	}
+
+	log.Infof("disabling mirroring of rados namespace %q before deleting it", poolAndRadosNamespaceName)
+	err = log.timeCephCall("remove snapshot schedules", func() error {
+		return cephclient.ReconcileSnapshotSchedules(r.context, r.clusterInfo, poolAndRadosNamespaceName, nil)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove the snapshot schedules of rados namespace %q", poolAndRadosNamespaceName)
+	}
+
+	err = log.timeCephCall("disable mirroring", func() error {
+		return cephclient.DisableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolAndRadosNamespaceName)
+	})
+	r.mirroringInfo.invalidate(r.clusterInfo, poolAndRadosNamespaceName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable mirroring of rados namespace %q", poolAndRadosNamespaceName)
+	}
+	r.recordMirroringEnabled(nsName, "", log)
+	r.recordMirroringInfo(nsName, snapshotSchedulesInfoKey, "", log)
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTeardownMirroringBeforeDeletion(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	newRadosNamespace := func(mirroring *cephv1.RadosNamespaceMirroring, info map[string]string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool", Mirroring: mirroring},
+			Status:     &cephv1.CephBlockPoolRadosNamespaceStatus{Info: info},
+		}
+	}
+	mirroring := &cephv1.RadosNamespaceMirroring{
+		Mode:              "image",
+		SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+	}
+	mirroringInfo := map[string]string{mirroringEnabledInfoKey: "1", snapshotSchedulesInfoKey: "1h"}
+	newReconciler := func(radosNamespace *cephv1.CephBlockPoolRadosNamespace, imageCount int, cephCommands *[]string) *ReconcileCephBlockPoolRadosNamespace {
+		return &ReconcileCephBlockPoolRadosNamespace{
+			client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+			context: &clusterd.Context{
+				Executor: &exectest.MockExecutor{
+					MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+						*cephCommands = append(*cephCommands, strings.Join(args, " "))
+						if args[0] == "pool" && args[1] == "stats" {
+							return fmt.Sprintf(`{"images":{"count":%d,"snap_count":0}}`, imageCount), nil
+						}
+						if args[0] == "mirror" && args[1] == "snapshot" && args[3] == "ls" {
+							return `[{"interval":"1h"}]`, nil
+						}
+						return "", nil
+					},
+				},
+			},
+			clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+			opManagerContext: ctx,
+			recorder:         record.NewFakeRecorder(5),
+		}
+	}
+	commandIndex := func(cephCommands []string, prefix string) int {
+		for i, command := range cephCommands {
+			if strings.HasPrefix(command, prefix) {
+				return i
+			}
+		}
+		return -1
+	}
+	mirrorCommands := func(cephCommands []string) []string {
+		var commands []string
+		for _, command := range cephCommands {
+			if strings.HasPrefix(command, "mirror ") {
+				commands = append(commands, command)
+			}
+		}
+		return commands
+	}
+
+	t.Run("mirroring is torn down before the rados namespace is deleted", func(t *testing.T) {
+		var cephCommands []string
+		radosNamespace := newRadosNamespace(mirroring, mirroringInfo)
+		r := newReconciler(radosNamespace, 0, &cephCommands)
+
+		blocked, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+		assert.NoError(t, err)
+		assert.False(t, blocked)
+
+		scheduleRemoved := commandIndex(cephCommands, "mirror snapshot schedule remove --pool replicapool/namespace-a 1h")
+		mirroringDisabled := commandIndex(cephCommands, "mirror pool disable replicapool/namespace-a")
+		namespaceRemoved := commandIndex(cephCommands, "namespace remove --pool replicapool --namespace namespace-a")
+		assert.NotEqual(t, -1, scheduleRemoved)
+		assert.NotEqual(t, -1, mirroringDisabled)
+		assert.NotEqual(t, -1, namespaceRemoved)
+		assert.Less(t, scheduleRemoved, mirroringDisabled)
+		assert.Less(t, mirroringDisabled, namespaceRemoved)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.NotContains(t, current.Status.Info, mirroringEnabledInfoKey)
+		assert.NotContains(t, current.Status.Info, snapshotSchedulesInfoKey)
+	})
+
+	t.Run("mirroring removed from the spec but still enabled is torn down", func(t *testing.T) {
+		var cephCommands []string
+		radosNamespace := newRadosNamespace(nil, map[string]string{mirroringEnabledInfoKey: "1"})
+		r := newReconciler(radosNamespace, 0, &cephCommands)
+
+		_, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+		assert.NoError(t, err)
+		assert.Less(t, commandIndex(cephCommands, "mirror pool disable"), commandIndex(cephCommands, "namespace remove"))
+		assert.NotEqual(t, -1, commandIndex(cephCommands, "mirror pool disable"))
+	})
+
+	t.Run("mirroring is kept while the rados namespace contains images", func(t *testing.T) {
+		var cephCommands []string
+		radosNamespace := newRadosNamespace(mirroring, mirroringInfo)
+		r := newReconciler(radosNamespace, 2, &cephCommands)
+
+		blocked, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+		assert.Error(t, err)
+		assert.True(t, blocked)
+		assert.Empty(t, mirrorCommands(cephCommands))
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, "1", current.Status.Info[mirroringEnabledInfoKey])
+	})
+
+	t.Run("mirroring of an external cluster is not torn down", func(t *testing.T) {
+		var cephCommands []string
+		radosNamespace := newRadosNamespace(mirroring, mirroringInfo)
+		r := newReconciler(radosNamespace, 0, &cephCommands)
+		cephCluster := &cephv1.CephCluster{Spec: cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}}}
+
+		_, err := r.deleteRadosNamespace(radosNamespace, cephCluster, log)
+		assert.NoError(t, err)
+		assert.Empty(t, mirrorCommands(cephCommands))
+		assert.NotEqual(t, -1, commandIndex(cephCommands, "namespace remove"))
+	})
+
+	t.Run("rados namespace without mirroring", func(t *testing.T) {
+		var cephCommands []string
+		radosNamespace := newRadosNamespace(nil, nil)
+		r := newReconciler(radosNamespace, 0, &cephCommands)
+
+		_, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+		assert.NoError(t, err)
+		assert.Empty(t, mirrorCommands(cephCommands))
+		assert.NotEqual(t, -1, commandIndex(cephCommands, "namespace remove"))
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// pausedAnnotation pauses the reconcile of a rados namespace when set to "true"
+	pausedAnnotation = "ceph.rook.io/paused"
+	// pauseStopsMirrorMonitoringSettingName is the operator setting to stop the mirroring status checker of a
+	// paused rados namespace, by default the checker keeps running while the reconcile is paused
+	pauseStopsMirrorMonitoringSettingName = "ROOK_RADOS_NAMESPACE_PAUSE_STOPS_MIRROR_MONITORING"
+)
+
+func isReconcilePaused(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	return radosNamespace.GetAnnotations()[pausedAnnotation] == "true"
+}
+
+// pausedAnnotationChangedPredicate triggers a reconcile when the paused annotation is added, changed or removed
+// since annotation changes are otherwise ignored by the controller predicate
+func pausedAnnotationChangedPredicate() predicate.TypedFuncs[*cephv1.CephBlockPoolRadosNamespace] {
+	return predicate.TypedFuncs[*cephv1.CephBlockPoolRadosNamespace]{
+		CreateFunc: func(e event.TypedCreateEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return e.ObjectOld.GetAnnotations()[pausedAnnotation] != e.ObjectNew.GetAnnotations()[pausedAnnotation]
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*cephv1.CephBlockPoolRadosNamespace]) bool {
+			return false
+		},
+	}
+}
+
+// pauseReconcile reports the rados namespace as paused without running any ceph command. The mirroring
+// status checker is kept running unless the operator is configured to stop it.
+func (r *ReconcileCephBlockPoolRadosNamespace) pauseReconcile(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) {
+	log.Infof("reconcile of rados namespace %q is paused by the %q annotation", name, pausedAnnotation)
+
+	stopMonitoring, err := strconv.ParseBool(k8sutil.GetOperatorSetting(pauseStopsMirrorMonitoringSettingName, "false"))
+	if err != nil {
+		log.Warningf("failed to parse setting %q, keeping mirror monitoring running. %v", pauseStopsMirrorMonitoringSettingName, err)
+	}
+	if stopMonitoring {
+		log.Debugf("stopping mirror monitoring of paused rados namespace %q", name)
+		r.cancelMirrorMonitoring(mirrorMonitoringChannelKey(radosNamespace))
+	}
+
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+		Type:    cephv1.ConditionProgressing,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.PausedReason,
+		Message: fmt.Sprintf("reconcile is paused, remove the %q annotation to resume", pausedAnnotation),
+	})
+}
+
+// isPausedCondition returns whether the rados namespace still has the Progressing condition of a paused reconcile
+func isPausedCondition(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	if radosNamespace.Status == nil {
+		return false
+	}
+	condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionProgressing)
+	return condition != nil && condition.Reason == cephv1.PausedReason
+}
+
+// clearPausedCondition removes the Progressing condition of the paused reconcile once it is resumed. The
+// Progressing condition of the resumed reconcile is then set by the standard conditions of its phase.
+func (r *ReconcileCephBlockPoolRadosNamespace) clearPausedCondition(name types.NamespacedName, log *reconcileLogger) {
+	err := r.mutateStatus(name, func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+		if !isPausedCondition(radosNamespace) {
+			return false
+		}
+		conditions := []cephv1.Condition{}
+		for _, condition := range radosNamespace.Status.Conditions {
+			if condition.Type != cephv1.ConditionProgressing {
+				conditions = append(conditions, condition)
+			}
+		}
+		radosNamespace.Status.Conditions = conditions
+		return true
+	})
+	if err != nil {
+		log.Errorf("failed to clear the paused condition of ceph blockpool rados namespace %q. %v", name, err)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPausedReconcile(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"}
+	newReconciler := func(t *testing.T, radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCommands *[]string) *ReconcileCephBlockPoolRadosNamespace {
+		// the ceph cluster is not ready so that a resumed reconcile stops before running any ceph command
+		cephCluster := &cephv1.CephCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name.Namespace, Namespace: name.Namespace},
+		}
+		return newTestReconciler(t, name.Namespace, func(command string, args ...string) (string, error) {
+			*cephCommands = append(*cephCommands, strings.Join(args, " "))
+			return "", nil
+		}, radosNamespace, cephCluster)
+	}
+	newRadosNamespace := func() *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name.Name,
+				Namespace:   name.Namespace,
+				Finalizers:  []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+				Annotations: map[string]string{pausedAnnotation: "true"},
+			},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring:     &cephv1.RadosNamespaceMirroring{Mode: "image"},
+			},
+		}
+	}
+	channelKey := mirrorMonitoringChannelKey(newRadosNamespace())
+
+	t.Run("paused rados namespace runs no ceph command and keeps monitoring", func(t *testing.T) {
+		var cephCommands []string
+		r := newReconciler(t, newRadosNamespace(), &cephCommands)
+		internalCtx, internalCancel := context.WithCancel(ctx)
+		defer internalCancel()
+		r.radosNamespaceContexts[channelKey] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel, started: true}
+
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Empty(t, cephCommands)
+		assert.Contains(t, r.radosNamespaceContexts, channelKey)
+		assert.NoError(t, internalCtx.Err())
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.PausedReason, condition.Reason)
+
+		// unpausing resumes the reconcile
+		current.Annotations = nil
+		assert.NoError(t, r.client.Update(ctx, current))
+		res, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.True(t, res.Requeue)
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		// the paused condition is replaced by the Progressing condition of the resumed reconcile
+		condition = cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.WaitingForCephClusterReason, condition.Reason)
+		assert.False(t, isPausedCondition(current))
+	})
+
+	t.Run("paused rados namespace stops monitoring when configured", func(t *testing.T) {
+		t.Setenv(pauseStopsMirrorMonitoringSettingName, "true")
+		var cephCommands []string
+		r := newReconciler(t, newRadosNamespace(), &cephCommands)
+		internalCtx, internalCancel := context.WithCancel(ctx)
+		r.radosNamespaceContexts[channelKey] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel, started: true}
+
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Empty(t, cephCommands)
+		assert.NotContains(t, r.radosNamespaceContexts, channelKey)
+		assert.Error(t, internalCtx.Err())
+	})
+}
+
+func TestPausedAnnotationChangedPredicate(t *testing.T) {
+	p := pausedAnnotationChangedPredicate()
+	paused := &cephv1.CephBlockPoolRadosNamespace{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{pausedAnnotation: "true"}}}
+	notPaused := &cephv1.CephBlockPoolRadosNamespace{}
+
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: notPaused, ObjectNew: paused}))
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: paused, ObjectNew: notPaused}))
+	assert.False(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPoolRadosNamespace]{ObjectOld: paused, ObjectNew: paused}))
+	assert.False(t, p.Create(event.TypedCreateEvent[*cephv1.CephBlockPoolRadosNamespace]{Object: paused}))
+}
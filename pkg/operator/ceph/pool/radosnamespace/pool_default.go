@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// poolDefaultConditions reports that setAsPoolDefault is not applied. ceph-csi provisions the volumes of a
+// StorageClass in the rados namespace of its clusterID and has no default rados namespace per pool, the
+// StorageClass has to set the clusterID of the rados namespace instead. The condition is cleared once
+// setAsPoolDefault is removed.
+func poolDefaultConditions(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) []cephv1.Condition {
+	if !radosNamespace.Spec.SetAsPoolDefault {
+		if radosNamespace.Status == nil || cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionPoolDefault) == nil {
+			return nil
+		}
+		return []cephv1.Condition{{
+			Type:    cephv1.ConditionPoolDefault,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.PoolDefaultUnsetReason,
+			Message: "setAsPoolDefault is not set",
+		}}
+	}
+
+	message := fmt.Sprintf("setAsPoolDefault is not supported since ceph-csi has no default rados namespace per pool, set the clusterID %q in the StorageClass to provision the volumes in rados namespace %q",
+		buildClusterID(radosNamespace), cephv1.GetRadosNamespaceName(radosNamespace))
+	log.Warningf("%s", message)
+	return []cephv1.Condition{{
+		Type:    cephv1.ConditionPoolDefault,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.PoolDefaultUnsupportedReason,
+		Message: message,
+	}}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPoolDefaultConditions(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+
+	t.Run("not requested", func(t *testing.T) {
+		assert.Nil(t, poolDefaultConditions(radosNamespace, log))
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		radosNamespace.Spec.SetAsPoolDefault = true
+		conditions := poolDefaultConditions(radosNamespace, log)
+		assert.Len(t, conditions, 1)
+		assert.Equal(t, cephv1.ConditionPoolDefault, conditions[0].Type)
+		assert.Equal(t, v1.ConditionFalse, conditions[0].Status)
+		assert.Equal(t, cephv1.PoolDefaultUnsupportedReason, conditions[0].Reason)
+		assert.Contains(t, conditions[0].Message, buildClusterID(radosNamespace))
+		radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{Conditions: conditions}
+	})
+
+	t.Run("no longer requested", func(t *testing.T) {
+		radosNamespace.Spec.SetAsPoolDefault = false
+		conditions := poolDefaultConditions(radosNamespace, log)
+		assert.Len(t, conditions, 1)
+		assert.Equal(t, v1.ConditionFalse, conditions[0].Status)
+		assert.Equal(t, cephv1.PoolDefaultUnsetReason, conditions[0].Reason)
+	})
+}
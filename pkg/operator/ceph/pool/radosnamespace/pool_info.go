@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Keys of the status info describing the parent CephBlockPool
+const (
+	poolTypeInfoKey           = "poolType"
+	poolFailureDomainInfoKey  = "poolFailureDomain"
+	poolReplicatedSizeInfoKey = "poolReplicatedSize"
+	poolDataChunksInfoKey     = "poolDataChunks"
+	poolCodingChunksInfoKey   = "poolCodingChunks"
+	// poolDeviceClassInfoKey is only reported when the pool is restricted to a device class, e.g. "ssd" on a
+	// cluster mixing HDDs and SSDs
+	poolDeviceClassInfoKey = "poolDeviceClass"
+)
+
+var poolInfoKeys = []string{poolTypeInfoKey, poolFailureDomainInfoKey, poolReplicatedSizeInfoKey, poolDataChunksInfoKey, poolCodingChunksInfoKey, poolDeviceClassInfoKey}
+
+// poolStatusInfo returns the durability characteristics and the device class of the pool to report in the rados namespace status
+func poolStatusInfo(cephBlockPool *cephv1.CephBlockPool) map[string]string {
+	m := map[string]string{}
+	if cephBlockPool.Spec.IsReplicated() {
+		m[poolTypeInfoKey] = "Replicated"
+		m[poolReplicatedSizeInfoKey] = strconv.FormatUint(uint64(cephBlockPool.Spec.Replicated.Size), 10)
+	} else {
+		m[poolTypeInfoKey] = "Erasure Coded"
+		m[poolDataChunksInfoKey] = strconv.FormatUint(uint64(cephBlockPool.Spec.ErasureCoded.DataChunks), 10)
+		m[poolCodingChunksInfoKey] = strconv.FormatUint(uint64(cephBlockPool.Spec.ErasureCoded.CodingChunks), 10)
+	}
+
+	if cephBlockPool.Spec.FailureDomain != "" {
+		m[poolFailureDomainInfoKey] = cephBlockPool.Spec.FailureDomain
+	} else {
+		m[poolFailureDomainInfoKey] = cephv1.DefaultFailureDomain
+	}
+
+	if cephBlockPool.Spec.DeviceClass != "" {
+		m[poolDeviceClassInfoKey] = cephBlockPool.Spec.DeviceClass
+	}
+	return m
+}
+
+// updatePoolStatusInfo copies the info of the parent pool into the rados namespace status, the status is
+// only updated when the info has changed
+func (r *ReconcileCephBlockPoolRadosNamespace) updatePoolStatusInfo(name types.NamespacedName, cephBlockPool *cephv1.CephBlockPool, log *reconcileLogger) {
+	info := poolStatusInfo(cephBlockPool)
+	err := r.mutateStatus(name, func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+		current := map[string]string{}
+		for _, key := range poolInfoKeys {
+			if value, ok := radosNamespace.Status.Info[key]; ok {
+				current[key] = value
+			}
+		}
+		if reflect.DeepEqual(current, info) {
+			return false
+		}
+		if radosNamespace.Status.Info == nil {
+			radosNamespace.Status.Info = map[string]string{}
+		}
+		for _, key := range poolInfoKeys {
+			delete(radosNamespace.Status.Info, key)
+		}
+		for key, value := range info {
+			radosNamespace.Status.Info[key] = value
+		}
+		return true
+	})
+	if err != nil {
+		log.Errorf("failed to update the pool info of ceph blockpool rados namespace %q. %v", name, err)
+		return
+	}
+	log.Debugf("ceph blockpool rados namespace %q pool info updated", name)
+}
+
+// blockPoolNamespace returns the namespace of the CephBlockPool of the rados namespace, the namespace of the CR
+// unless spec.blockPoolNamespace is set
+func blockPoolNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace) string {
+	if radosNamespace.Spec.BlockPoolNamespace != "" {
+		return radosNamespace.Spec.BlockPoolNamespace
+	}
+	return radosNamespace.Namespace
+}
+
+// blockPoolKey returns the key of the rados namespaces of a CephBlockPool in the blockPoolNameIndex
+func blockPoolKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// radosNamespacesForPool maps a CephBlockPool to the requests of the rados namespaces created in it, including
+// the rados namespaces of other namespaces
+func radosNamespacesForPool(ctx context.Context, c client.Client, cephBlockPool *cephv1.CephBlockPool) []reconcile.Request {
+	radosNamespaces := &cephv1.CephBlockPoolRadosNamespaceList{}
+	err := c.List(ctx, radosNamespaces, &client.MatchingFields{blockPoolNameIndex: blockPoolKey(cephBlockPool.Namespace, cephBlockPool.Name)})
+	if err != nil {
+		logger.Errorf("failed to list CephBlockPoolRadosNamespace(s) while handling event for CephBlockPool %q in namespace %q. %v", cephBlockPool.Name, cephBlockPool.Namespace, err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(radosNamespaces.Items))
+	for i, item := range radosNamespaces.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace},
+		}
+	}
+	return requests
+}
+
+// poolPhaseChangedPredicate triggers a reconcile of the rados namespaces when the phase of the parent pool
+// changes, for example when the pool becomes ready, since status changes are otherwise ignored
+func poolPhaseChangedPredicate() predicate.TypedFuncs[*cephv1.CephBlockPool] {
+	return predicate.TypedFuncs[*cephv1.CephBlockPool]{
+		CreateFunc: func(e event.TypedCreateEvent[*cephv1.CephBlockPool]) bool {
+			return false
+		},
+		UpdateFunc: func(e event.TypedUpdateEvent[*cephv1.CephBlockPool]) bool {
+			return poolPhase(e.ObjectOld) != poolPhase(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.TypedDeleteEvent[*cephv1.CephBlockPool]) bool {
+			return false
+		},
+		GenericFunc: func(e event.TypedGenericEvent[*cephv1.CephBlockPool]) bool {
+			return false
+		},
+	}
+}
+
+func poolPhase(cephBlockPool *cephv1.CephBlockPool) cephv1.ConditionType {
+	if cephBlockPool.Status == nil {
+		return ""
+	}
+	return cephBlockPool.Status.Phase
+}
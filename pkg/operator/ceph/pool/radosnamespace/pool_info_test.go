@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPoolStatusInfo(t *testing.T) {
+	replicated := &cephv1.CephBlockPool{
+		Spec: cephv1.NamedBlockPoolSpec{PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}},
+	}
+	assert.Equal(t, map[string]string{
+		poolTypeInfoKey:           "Replicated",
+		poolReplicatedSizeInfoKey: "3",
+		poolFailureDomainInfoKey:  cephv1.DefaultFailureDomain,
+	}, poolStatusInfo(replicated))
+
+	erasureCoded := &cephv1.CephBlockPool{
+		Spec: cephv1.NamedBlockPoolSpec{PoolSpec: cephv1.PoolSpec{
+			FailureDomain: "osd",
+			DeviceClass:   "hdd",
+			ErasureCoded:  cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1},
+		}},
+	}
+	assert.Equal(t, map[string]string{
+		poolTypeInfoKey:          "Erasure Coded",
+		poolDataChunksInfoKey:    "2",
+		poolCodingChunksInfoKey:  "1",
+		poolFailureDomainInfoKey: "osd",
+		poolDeviceClassInfoKey:   "hdd",
+	}, poolStatusInfo(erasureCoded))
+}
+
+func TestUpdatePoolStatusInfo(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{
+			Info: map[string]string{"clusterID": "cluster-id"},
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace},
+		Spec:       cephv1.NamedBlockPoolSpec{PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 3}}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{client: cl, opManagerContext: ctx}
+
+	r.updatePoolStatusInfo(name, cephBlockPool, log)
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "cluster-id", current.Status.Info["clusterID"])
+	assert.Equal(t, "Replicated", current.Status.Info[poolTypeInfoKey])
+	assert.Equal(t, "3", current.Status.Info[poolReplicatedSizeInfoKey])
+	assert.Equal(t, cephv1.DefaultFailureDomain, current.Status.Info[poolFailureDomainInfoKey])
+	assert.NotContains(t, current.Status.Info, poolDeviceClassInfoKey)
+
+	// the info is refreshed when the pool spec changes
+	cephBlockPool.Spec.Replicated.Size = 2
+	cephBlockPool.Spec.FailureDomain = "zone"
+	r.updatePoolStatusInfo(name, cephBlockPool, log)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "cluster-id", current.Status.Info["clusterID"])
+	assert.Equal(t, "2", current.Status.Info[poolReplicatedSizeInfoKey])
+	assert.Equal(t, "zone", current.Status.Info[poolFailureDomainInfoKey])
+
+	// the device class of the pool is propagated, and removed when the pool is no longer restricted to it
+	cephBlockPool.Spec.DeviceClass = "ssd"
+	r.updatePoolStatusInfo(name, cephBlockPool, log)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "ssd", current.Status.Info[poolDeviceClassInfoKey])
+	cephBlockPool.Spec.DeviceClass = "hdd"
+	r.updatePoolStatusInfo(name, cephBlockPool, log)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, "hdd", current.Status.Info[poolDeviceClassInfoKey])
+	cephBlockPool.Spec.DeviceClass = ""
+	r.updatePoolStatusInfo(name, cephBlockPool, log)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.NotContains(t, current.Status.Info, poolDeviceClassInfoKey)
+	assert.Equal(t, "zone", current.Status.Info[poolFailureDomainInfoKey])
+
+	// the status is not updated when the info has not changed
+	resourceVersion := current.ResourceVersion
+	r.updatePoolStatusInfo(name, cephBlockPool, log)
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, resourceVersion, current.ResourceVersion)
+}
+
+func TestRadosNamespacesForPool(t *testing.T) {
+	radosNamespace := func(name, pool string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "rook-ceph"},
+			Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: pool},
+		}
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(
+		radosNamespace("namespace-a", "replicapool"),
+		radosNamespace("namespace-b", "otherpool"),
+		radosNamespace("namespace-c", "replicapool"),
+	).WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, blockPoolNameIndex, indexBlockPoolName).Build()
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"}}
+
+	requests := radosNamespacesForPool(context.TODO(), cl, cephBlockPool)
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}},
+		{NamespacedName: types.NamespacedName{Name: "namespace-c", Namespace: "rook-ceph"}},
+	}, requests)
+
+	// a pool without rados namespaces enqueues nothing
+	cephBlockPool.Name = "emptypool"
+	assert.Empty(t, radosNamespacesForPool(context.TODO(), cl, cephBlockPool))
+}
+
+func TestPoolPhaseChangedPredicate(t *testing.T) {
+	p := poolPhaseChangedPredicate()
+	progressing := &cephv1.CephBlockPool{Status: &cephv1.CephBlockPoolStatus{Phase: cephv1.ConditionProgressing}}
+	ready := &cephv1.CephBlockPool{Status: &cephv1.CephBlockPoolStatus{Phase: cephv1.ConditionReady}}
+
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPool]{ObjectOld: progressing, ObjectNew: ready}))
+	assert.True(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPool]{ObjectOld: &cephv1.CephBlockPool{}, ObjectNew: ready}))
+	assert.False(t, p.Update(event.TypedUpdateEvent[*cephv1.CephBlockPool]{ObjectOld: ready, ObjectNew: ready}))
+	assert.False(t, p.Create(event.TypedCreateEvent[*cephv1.CephBlockPool]{Object: ready}))
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+// crossNamespacePoolsSettingName is the operator setting with the comma separated "<namespace>:<pool namespace>"
+// pairs allowing the rados namespaces of a namespace to reference the CephBlockPools of another namespace
+const crossNamespacePoolsSettingName = "ROOK_RADOS_NAMESPACE_CROSS_NAMESPACE_POOLS"
+
+// checkBlockPoolNamespace returns an error if the rados namespace references a CephBlockPool of another namespace
+// that the operator settings do not allow. The rados namespace is managed with the CephCluster of the namespace of
+// the pool and its ceph admin credentials, so the admin must trust the namespace of the CR with that cluster.
+func checkBlockPoolNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	poolNamespace := blockPoolNamespace(radosNamespace)
+	if poolNamespace == radosNamespace.Namespace {
+		return nil
+	}
+
+	allowed := radosNamespace.Namespace + ":" + poolNamespace
+	for _, pair := range strings.Split(k8sutil.GetOperatorSetting(crossNamespacePoolsSettingName, ""), ",") {
+		if strings.TrimSpace(pair) == allowed {
+			return nil
+		}
+	}
+	return errors.Errorf("rados namespaces of namespace %q are not allowed to reference the CephBlockPools of namespace %q, add %q to the %s operator setting to allow it",
+		radosNamespace.Namespace, poolNamespace, allowed, crossNamespacePoolsSettingName)
+}
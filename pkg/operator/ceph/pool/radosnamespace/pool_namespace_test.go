@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckBlockPoolNamespace(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "tenant-a"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+
+	t.Run("pool in the namespace of the CR", func(t *testing.T) {
+		assert.NoError(t, checkBlockPoolNamespace(radosNamespace))
+		radosNamespace.Spec.BlockPoolNamespace = "tenant-a"
+		assert.NoError(t, checkBlockPoolNamespace(radosNamespace))
+	})
+
+	t.Run("pool in another namespace is rejected by default", func(t *testing.T) {
+		radosNamespace.Spec.BlockPoolNamespace = "rook-ceph"
+		err := checkBlockPoolNamespace(radosNamespace)
+		assert.ErrorContains(t, err, `rados namespaces of namespace "tenant-a" are not allowed to reference the CephBlockPools of namespace "rook-ceph"`)
+	})
+
+	t.Run("pool in another namespace allowed by the operator setting", func(t *testing.T) {
+		radosNamespace.Spec.BlockPoolNamespace = "rook-ceph"
+		t.Setenv(crossNamespacePoolsSettingName, "tenant-b:rook-ceph, tenant-a:rook-ceph")
+		assert.NoError(t, checkBlockPoolNamespace(radosNamespace))
+
+		// the pair only allows the pools of its namespace
+		radosNamespace.Spec.BlockPoolNamespace = "other-cluster"
+		assert.Error(t, checkBlockPoolNamespace(radosNamespace))
+	})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// postCreateConfigInfoKey is the status info key recording the keys of the post create config applied to the
+// rados namespace, so that the keys removed from spec.postCreateConfig are removed from the rados namespace
+const postCreateConfigInfoKey = "postCreateConfig"
+
+// allowedPostCreateConfigKeys are the rbd config options that can be set on a rados namespace with
+// spec.postCreateConfig. The rbd_compression_hint option is managed by spec.compression.
+var allowedPostCreateConfigKeys = map[string]bool{
+	"rbd_default_features":         true,
+	"rbd_default_order":            true,
+	"rbd_default_stripe_count":     true,
+	"rbd_default_stripe_unit":      true,
+	"rbd_qos_bps_limit":            true,
+	"rbd_qos_iops_limit":           true,
+	"rbd_qos_read_bps_limit":       true,
+	"rbd_qos_read_iops_limit":      true,
+	"rbd_qos_write_bps_limit":      true,
+	"rbd_qos_write_iops_limit":     true,
+	"rbd_read_from_replica_policy": true,
+}
+
+// sortedAllowedPostCreateConfigKeys returns the sorted keys that can be set with spec.postCreateConfig
+func sortedAllowedPostCreateConfigKeys() []string {
+	keys := make([]string, 0, len(allowedPostCreateConfigKeys))
+	for key := range allowedPostCreateConfigKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// postCreateConfigKeys returns the sorted keys of the post create config recorded in the status info
+func postCreateConfigKeys(info string) []string {
+	if info == "" {
+		return nil
+	}
+	return strings.Split(info, ",")
+}
+
+// postCreateConfigInfo returns the post create config keys recorded in the status info, e.g.
+// "rbd_default_features,rbd_qos_iops_limit"
+func postCreateConfigInfo(config map[string]string) string {
+	return strings.Join(sortedConfigKeys(config), ",")
+}
+
+// sortedConfigKeys returns the sorted keys of the config
+func sortedConfigKeys(config map[string]string) []string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reconcilePostCreateConfig sets the rbd config options of spec.postCreateConfig on the rados namespace, and
+// removes the options that were applied by a previous reconcile and are no longer desired
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcilePostCreateConfig(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) error {
+	config := map[string]string{}
+	for _, entry := range radosNamespace.Spec.PostCreateConfig {
+		config[entry.Key] = entry.Value
+	}
+	info := postCreateConfigInfo(config)
+	recorded := ""
+	if radosNamespace.Status != nil {
+		recorded = radosNamespace.Status.Info[postCreateConfigInfoKey]
+	}
+	if info == "" && recorded == "" {
+		return nil
+	}
+
+	// the keys no longer desired are removed only if they were applied by the operator
+	managed := map[string]string{}
+	for _, key := range postCreateConfigKeys(recorded) {
+		managed[key] = ""
+	}
+	for key, value := range config {
+		managed[key] = value
+	}
+	keys := sortedConfigKeys(managed)
+
+	err := log.timeCephCall("set post create config", func() error {
+		return cephclient.SetRadosNamespaceConfig(r.context, r.clusterInfo, radosNamespace.Spec.BlockPoolName, cephv1.GetRadosNamespaceName(radosNamespace), keys, config)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the post create config of rados namespace %q", name)
+	}
+	if info != recorded {
+		log.Infof("post create config keys of rados namespace %q set to %q", name, info)
+	}
+	r.recordMirroringInfo(name, postCreateConfigInfoKey, info, log)
+	return nil
+}
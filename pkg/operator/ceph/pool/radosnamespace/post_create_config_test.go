@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcilePostCreateConfig(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+
+	// an option set on the rados namespace outside of the operator
+	config := map[string]string{"rbd_qos_bps_limit": "100M"}
+	var cmds []string
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "config" && args[1] == "namespace" {
+						assert.Equal(t, "replicapool/namespace-a", args[3])
+						switch args[2] {
+						case "list":
+							options := []map[string]string{}
+							for key, value := range config {
+								options = append(options, map[string]string{"name": key, "value": value, "source": "namespace"})
+							}
+							output, err := json.Marshal(options)
+							return string(output), err
+						case "set":
+							cmds = append(cmds, strings.Join(args[2:6], " "))
+							config[args[4]] = args[5]
+						case "remove":
+							cmds = append(cmds, strings.Join(args[2:5], " "))
+							delete(config, args[4])
+						}
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:      &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		opManagerContext: ctx,
+	}
+	reconcilePostCreateConfig := func(entries []cephv1.RadosNamespaceConfigEntry) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, current))
+		current.Spec.PostCreateConfig = entries
+		cmds = nil
+		assert.NoError(t, r.reconcilePostCreateConfig(current, name, newReconcileLogger(name)))
+		assert.NoError(t, cl.Get(ctx, name, current))
+		return current
+	}
+
+	t.Run("no post create config", func(t *testing.T) {
+		reconcilePostCreateConfig(nil)
+		assert.Nil(t, cmds)
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		current := reconcilePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{
+			{Key: "rbd_qos_iops_limit", Value: "1000"},
+			{Key: "rbd_default_features", Value: "layering,exclusive-lock"},
+		})
+		assert.Equal(t, []string{
+			"set replicapool/namespace-a rbd_default_features layering,exclusive-lock",
+			"set replicapool/namespace-a rbd_qos_iops_limit 1000",
+		}, cmds)
+		assert.Equal(t, "rbd_default_features,rbd_qos_iops_limit", current.Status.Info[postCreateConfigInfoKey])
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		reconcilePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{
+			{Key: "rbd_qos_iops_limit", Value: "1000"},
+			{Key: "rbd_default_features", Value: "layering,exclusive-lock"},
+		})
+		assert.Nil(t, cmds)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		current := reconcilePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{
+			{Key: "rbd_qos_iops_limit", Value: "2000"},
+			{Key: "rbd_qos_read_iops_limit", Value: "500"},
+		})
+		assert.Equal(t, []string{
+			"remove replicapool/namespace-a rbd_default_features",
+			"set replicapool/namespace-a rbd_qos_iops_limit 2000",
+			"set replicapool/namespace-a rbd_qos_read_iops_limit 500",
+		}, cmds)
+		assert.Equal(t, "rbd_qos_iops_limit,rbd_qos_read_iops_limit", current.Status.Info[postCreateConfigInfoKey])
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		current := reconcilePostCreateConfig(nil)
+		assert.Equal(t, []string{
+			"remove replicapool/namespace-a rbd_qos_iops_limit",
+			"remove replicapool/namespace-a rbd_qos_read_iops_limit",
+		}, cmds)
+		assert.NotContains(t, current.Status.Info, postCreateConfigInfoKey)
+
+		// the option not set by the operator is kept
+		assert.Equal(t, map[string]string{"rbd_qos_bps_limit": "100M"}, config)
+
+		reconcilePostCreateConfig(nil)
+		assert.Nil(t, cmds)
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// maxReconcilesPerMinuteSettingName is the operator setting with the number of reconciles running the ceph
+	// commands allowed per minute for each rados namespace, 0 disables the limit
+	maxReconcilesPerMinuteSettingName = "ROOK_RADOS_NAMESPACE_MAX_RECONCILES_PER_MINUTE"
+	defaultMaxReconcilesPerMinute     = 0
+)
+
+// maxReconcilesPerMinute returns the number of reconciles running the ceph commands allowed per minute for each
+// rados namespace, or 0 if the reconciles are not limited
+func maxReconcilesPerMinute(log *reconcileLogger) int {
+	value := k8sutil.GetOperatorSetting(maxReconcilesPerMinuteSettingName, strconv.Itoa(defaultMaxReconcilesPerMinute))
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Warningf("invalid setting %q value %q, using the default %d. %v", maxReconcilesPerMinuteSettingName, value, defaultMaxReconcilesPerMinute, err)
+		return defaultMaxReconcilesPerMinute
+	}
+	return limit
+}
+
+// tokenBucket holds the reconciles a rados namespace can still run, refilled continuously up to the limit
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// reconcileRateLimiter limits the reconciles running the ceph commands of each rados namespace, whatever
+// triggered them: the watch events of a spec that keeps changing, the requeues and the retries. The reconciles
+// are allowed in bursts up to the limit, then at the rate of the limit per minute. The buckets are shared by the
+// concurrent reconciles.
+type reconcileRateLimiter struct {
+	lock    sync.Mutex
+	buckets map[types.NamespacedName]*tokenBucket
+}
+
+// take consumes a reconcile of the rados namespace, and returns 0 if the reconcile is allowed or the delay
+// after which the next reconcile is allowed. A delayed reconcile does not consume a reconcile, so the events
+// received in the meantime are delayed until the same time.
+func (l *reconcileRateLimiter) take(name types.NamespacedName, limit int, now time.Time) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.buckets == nil {
+		l.buckets = map[types.NamespacedName]*tokenBucket{}
+	}
+	perSecond := float64(limit) / time.Minute.Seconds()
+	// drop the full buckets so that the buckets of the deleted rados namespaces do not accumulate
+	for bucketName, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*perSecond >= float64(limit) {
+			delete(l.buckets, bucketName)
+		}
+	}
+	bucket, ok := l.buckets[name]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit), last: now}
+		l.buckets[name] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens += elapsed.Seconds() * perSecond
+		bucket.last = now
+	}
+	if bucket.tokens > float64(limit) {
+		bucket.tokens = float64(limit)
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+	delay := time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second))
+	if delay < time.Millisecond {
+		delay = time.Millisecond
+	}
+	return delay
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestMaxReconcilesPerMinute(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.Equal(t, 0, maxReconcilesPerMinute(log))
+
+	t.Setenv(maxReconcilesPerMinuteSettingName, "5")
+	assert.Equal(t, 5, maxReconcilesPerMinute(log))
+
+	t.Setenv(maxReconcilesPerMinuteSettingName, "0")
+	assert.Equal(t, 0, maxReconcilesPerMinute(log))
+
+	t.Setenv(maxReconcilesPerMinuteSettingName, "-1")
+	assert.Equal(t, defaultMaxReconcilesPerMinute, maxReconcilesPerMinute(log))
+
+	t.Setenv(maxReconcilesPerMinuteSettingName, "often")
+	assert.Equal(t, defaultMaxReconcilesPerMinute, maxReconcilesPerMinute(log))
+}
+
+func TestReconcileRateLimiter(t *testing.T) {
+	nameA := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	nameB := types.NamespacedName{Name: "namespace-b", Namespace: "rook-ceph"}
+	now := time.Now()
+
+	t.Run("burst up to the limit", func(t *testing.T) {
+		l := &reconcileRateLimiter{}
+		for i := 0; i < 3; i++ {
+			assert.Zero(t, l.take(nameA, 3, now))
+		}
+		assert.Equal(t, 20*time.Second, l.take(nameA, 3, now))
+		// the delayed reconciles do not consume the limit
+		assert.Equal(t, 20*time.Second, l.take(nameA, 3, now))
+		// other rados namespaces are not limited
+		assert.Zero(t, l.take(nameB, 3, now))
+	})
+
+	t.Run("refill at the limit per minute", func(t *testing.T) {
+		l := &reconcileRateLimiter{}
+		for i := 0; i < 3; i++ {
+			assert.Zero(t, l.take(nameA, 3, now))
+		}
+		assert.Equal(t, 10*time.Second, l.take(nameA, 3, now.Add(10*time.Second)))
+		assert.Zero(t, l.take(nameA, 3, now.Add(20*time.Second)))
+		assert.NotZero(t, l.take(nameA, 3, now.Add(20*time.Second)))
+
+		// the bucket does not refill above the limit
+		for i := 0; i < 3; i++ {
+			assert.Zero(t, l.take(nameA, 3, now.Add(time.Hour)))
+		}
+		assert.NotZero(t, l.take(nameA, 3, now.Add(time.Hour)))
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		l := &reconcileRateLimiter{}
+		for i := 0; i < 100; i++ {
+			assert.Zero(t, l.take(nameA, 0, now))
+		}
+		assert.Empty(t, l.buckets)
+	})
+
+	t.Run("the full buckets are dropped", func(t *testing.T) {
+		l := &reconcileRateLimiter{}
+		assert.Zero(t, l.take(nameA, 3, now))
+		assert.Zero(t, l.take(nameB, 3, now.Add(time.Minute)))
+		assert.Len(t, l.buckets, 1)
+	})
+}
+
+func TestReconcileRateLimit(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		TypeMeta: metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:     cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+
+	creates := 0
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" {
+			creates++
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	createTestCSIConfigMap(t, r, namespace)
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+	r.clock = fakeClock
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+	// each change of the CR triggers a full reconcile running the ceph commands, like the watch events of a
+	// spec that keeps changing
+	changes := 0
+	flapSpec := func() {
+		changes++
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, req.NamespacedName, current))
+		current.Annotations = map[string]string{forceReconcileAnnotation: strconv.Itoa(changes)}
+		assert.NoError(t, r.client.Update(ctx, current))
+	}
+
+	t.Run("not limited by default", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			flapSpec()
+			res, err := r.Reconcile(ctx, req)
+			assert.NoError(t, err)
+			assert.False(t, res.Requeue)
+		}
+		assert.Equal(t, 5, creates)
+	})
+
+	t.Setenv(maxReconcilesPerMinuteSettingName, "2")
+	creates = 0
+	for i := 0; i < 2; i++ {
+		flapSpec()
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+	}
+	assert.Equal(t, 2, creates)
+
+	t.Run("rapid reconciles are throttled", func(t *testing.T) {
+		flapSpec()
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.True(t, res.Requeue)
+		assert.Equal(t, 30*time.Second, res.RequeueAfter)
+		assert.Equal(t, 2, creates)
+	})
+
+	t.Run("the reconcile runs once the limit allows it", func(t *testing.T) {
+		fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.Equal(t, 3, creates)
+	})
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// resyncIntervalSettingName is the operator setting with the base interval of the periodic reconcile of the
+	// rados namespaces, e.g. "1h". The periodic reconcile is disabled by default.
+	resyncIntervalSettingName = "ROOK_RADOS_NAMESPACE_RESYNC_INTERVAL"
+	// resyncJitterFactor spreads the periodic reconciles of the rados namespaces up to half of the base interval
+	// after it so that they do not all run the ceph commands at the same time
+	resyncJitterFactor = 0.5
+)
+
+// resyncInterval returns the base interval of the periodic reconcile, or 0 if it is disabled
+func resyncInterval(log *reconcileLogger) time.Duration {
+	value := k8sutil.GetOperatorSetting(resyncIntervalSettingName, "0")
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warningf("invalid setting %q value %q, the periodic reconcile is disabled. %v", resyncIntervalSettingName, value, err)
+		return 0
+	}
+	return interval
+}
+
+// resyncResult returns the result of a successful reconcile, which is requeued after the base interval plus
+// a random jitter if the periodic reconcile is enabled
+func resyncResult(interval time.Duration) reconcile.Result {
+	if interval <= 0 {
+		return reconcile.Result{}
+	}
+	return reconcile.Result{RequeueAfter: wait.Jitter(interval, resyncJitterFactor)}
+}
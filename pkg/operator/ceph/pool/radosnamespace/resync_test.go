@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestResyncResult(t *testing.T) {
+	assert.Equal(t, reconcile.Result{}, resyncResult(0))
+
+	interval := time.Hour
+	for i := 0; i < 100; i++ {
+		res := resyncResult(interval)
+		assert.False(t, res.Requeue)
+		assert.GreaterOrEqual(t, res.RequeueAfter, interval)
+		assert.Less(t, res.RequeueAfter, interval+time.Duration(resyncJitterFactor*float64(interval)))
+	}
+}
+
+func TestResyncInterval(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.Equal(t, time.Duration(0), resyncInterval(log))
+
+	t.Setenv(resyncIntervalSettingName, "30m")
+	assert.Equal(t, 30*time.Minute, resyncInterval(log))
+
+	t.Setenv(resyncIntervalSettingName, "often")
+	assert.Equal(t, time.Duration(0), resyncInterval(log))
+
+	t.Setenv(resyncIntervalSettingName, "-1h")
+	assert.Equal(t, time.Duration(0), resyncInterval(log))
+}
+
+func TestFingerprintTrackerIsOlderThan(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	tracker := reconcileFingerprintTracker{}
+	assert.False(t, tracker.isOlderThan(name, 0))
+	assert.True(t, tracker.isOlderThan(name, time.Hour))
+
+	tracker.record(name, reconcileFingerprint{generation: 1})
+	assert.False(t, tracker.isOlderThan(name, time.Hour))
+	tracker.reconciledAt[name] = time.Now().Add(-2 * time.Hour)
+	assert.True(t, tracker.isOlderThan(name, time.Hour))
+
+	tracker.forget(name)
+	assert.True(t, tracker.isOlderThan(name, time.Hour))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"time"
+)
+
+// mirrorCheckersShutdownTimeout is how long the operator shutdown waits for the mirroring checkers to exit
+var mirrorCheckersShutdownTimeout = 10 * time.Second
+
+// stopMirrorMonitoringOnShutdown is run by the manager and stops the mirroring checkers when the manager stops
+func (r *ReconcileCephBlockPoolRadosNamespace) stopMirrorMonitoringOnShutdown(ctx context.Context) error {
+	<-ctx.Done()
+	r.stopAllMirrorMonitoring(mirrorCheckersShutdownTimeout)
+	return nil
+}
+
+// stopAllMirrorMonitoring cancels the contexts of all the mirroring checkers and waits for their go routines
+// to exit, until the timeout
+func (r *ReconcileCephBlockPoolRadosNamespace) stopAllMirrorMonitoring(timeout time.Duration) {
+	r.radosNamespaceContextsLock.Lock()
+	running := []chan struct{}{}
+	for channelKey, health := range r.radosNamespaceContexts {
+		if health.started && health.done != nil {
+			running = append(running, health.done)
+		}
+		r.cancelMirrorMonitoringLocked(channelKey)
+	}
+	r.radosNamespaceContextsLock.Unlock()
+
+	deadline := time.After(timeout)
+	for i, done := range running {
+		select {
+		case <-done:
+		case <-deadline:
+			logger.Warningf("timed out waiting for the mirroring checkers to stop, %d of %d stopped", i, len(running))
+			return
+		}
+	}
+	logger.Infof("stopped %d mirroring checkers of the rados namespaces", len(running))
+}
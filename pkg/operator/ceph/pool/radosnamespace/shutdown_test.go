@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopMirrorMonitoringOnShutdown(t *testing.T) {
+	opManagerContext, cancel := context.WithCancel(context.TODO())
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		opManagerContext:       opManagerContext,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+
+	var exited int32
+	checkMirroring := func(ctx context.Context) {
+		<-ctx.Done()
+		atomic.AddInt32(&exited, 1)
+	}
+	for _, channelKey := range []string{"rook-ceph/replicapool/namespace-a", "rook-ceph/replicapool/namespace-b"} {
+		internalCtx, internalCancel := context.WithCancel(opManagerContext)
+		r.radosNamespaceContexts[channelKey] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel}
+		r.startMirrorMonitoring(channelKey, checkMirroring)
+	}
+	// a context without a running checker is removed as well
+	internalCtx, internalCancel := context.WithCancel(opManagerContext)
+	r.radosNamespaceContexts["rook-ceph/replicapool/namespace-c"] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel}
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- r.stopMirrorMonitoringOnShutdown(opManagerContext)
+	}()
+	cancel()
+
+	select {
+	case err := <-stopped:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the mirroring checkers were not stopped")
+	}
+	assert.Empty(t, r.radosNamespaceContexts)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&exited))
+}
+
+func TestStopAllMirrorMonitoringTimeout(t *testing.T) {
+	r := &ReconcileCephBlockPoolRadosNamespace{radosNamespaceContexts: map[string]*mirrorHealth{}}
+
+	release := make(chan struct{})
+	defer close(release)
+	internalCtx, internalCancel := context.WithCancel(context.TODO())
+	r.radosNamespaceContexts["rook-ceph/replicapool/namespace-a"] = &mirrorHealth{internalCtx: internalCtx, internalCancel: internalCancel}
+	// the checker ignores the cancellation of its context
+	r.startMirrorMonitoring("rook-ceph/replicapool/namespace-a", func(ctx context.Context) { <-release })
+
+	start := time.Now()
+	r.stopAllMirrorMonitoring(10 * time.Millisecond)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Empty(t, r.radosNamespaceContexts)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// minSnapshotIntervalSettingName is the operator setting with the minimum interval of the snapshot schedules,
+	// e.g. "5m". Shorter intervals take a snapshot of every mirrored image too often and overload the cluster.
+	minSnapshotIntervalSettingName = "ROOK_RADOS_NAMESPACE_MIN_SNAPSHOT_INTERVAL"
+	defaultMinSnapshotInterval     = "5m"
+
+	// snapshotIntervalTooShortEventReason is the reason of the warning event recorded when a snapshot schedule
+	// interval is below the minimum
+	snapshotIntervalTooShortEventReason = "SnapshotIntervalTooShort"
+)
+
+// minSnapshotInterval returns the minimum interval of the snapshot schedules, the default minimum is used if
+// the setting is invalid. A minimum of 0 disables the check.
+func minSnapshotInterval(log *reconcileLogger) time.Duration {
+	value := k8sutil.GetOperatorSetting(minSnapshotIntervalSettingName, defaultMinSnapshotInterval)
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Warningf("invalid setting %q value %q, using the default minimum snapshot interval %q. %v", minSnapshotIntervalSettingName, value, defaultMinSnapshotInterval, err)
+		interval, _ = time.ParseDuration(defaultMinSnapshotInterval)
+	}
+	return interval
+}
+
+// parseSnapshotScheduleInterval returns the duration of a snapshot schedule interval in days, hours or minutes
+func parseSnapshotScheduleInterval(interval string) (time.Duration, error) {
+	if !snapshotScheduleIntervalRegex.MatchString(interval) {
+		return 0, errors.Errorf("invalid interval %q", interval)
+	}
+	count, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid interval %q", interval)
+	}
+	unit := time.Minute
+	switch interval[len(interval)-1] {
+	case 'd':
+		unit = 24 * time.Hour
+	case 'h':
+		unit = time.Hour
+	}
+	return time.Duration(count) * unit, nil
+}
+
+// validateSnapshotScheduleMinInterval checks that no snapshot schedule is more frequent than the minimum
+// interval, all the schedules that are too frequent are reported
+func validateSnapshotScheduleMinInterval(schedules []cephv1.SnapshotScheduleSpec, minInterval time.Duration) error {
+	if minInterval <= 0 {
+		return nil
+	}
+	var tooShort []string
+	for i, schedule := range schedules {
+		interval, err := parseSnapshotScheduleInterval(schedule.Interval)
+		if err != nil {
+			// the malformed intervals are reported by the spec validation
+			continue
+		}
+		if interval < minInterval {
+			tooShort = append(tooShort, fmt.Sprintf("schedule %d has interval %q", i, schedule.Interval))
+		}
+	}
+	if len(tooShort) > 0 {
+		return errors.Errorf("%s, below the minimum snapshot interval %q, set %q in the operator config to allow shorter intervals",
+			strings.Join(tooShort, "; "), minInterval.String(), minSnapshotIntervalSettingName)
+	}
+
+	return nil
+}
+
+// checkSnapshotScheduleMinInterval rejects the snapshot schedules of the rados namespace that are more frequent
+// than the minimum interval, with a warning event
+func (r *ReconcileCephBlockPoolRadosNamespace) checkSnapshotScheduleMinInterval(radosNamespace *cephv1.CephBlockPoolRadosNamespace, log *reconcileLogger) error {
+	if radosNamespace.Spec.Mirroring == nil || len(radosNamespace.Spec.Mirroring.SnapshotSchedules) == 0 {
+		return nil
+	}
+	err := validateSnapshotScheduleMinInterval(radosNamespace.Spec.Mirroring.SnapshotSchedules, minSnapshotInterval(log))
+	if err != nil {
+		r.recorder.Event(radosNamespace, v1.EventTypeWarning, snapshotIntervalTooShortEventReason, err.Error())
+		return errors.Wrap(err, "invalid snapshot schedules")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestParseSnapshotScheduleInterval(t *testing.T) {
+	for interval, expected := range map[string]time.Duration{"1m": time.Minute, "90m": 90 * time.Minute, "2h": 2 * time.Hour, "1d": 24 * time.Hour} {
+		duration, err := parseSnapshotScheduleInterval(interval)
+		assert.NoError(t, err, interval)
+		assert.Equal(t, expected, duration, interval)
+	}
+	_, err := parseSnapshotScheduleInterval("1s")
+	assert.Error(t, err)
+}
+
+func TestValidateSnapshotScheduleMinInterval(t *testing.T) {
+	schedules := func(intervals ...string) []cephv1.SnapshotScheduleSpec {
+		var s []cephv1.SnapshotScheduleSpec
+		for _, interval := range intervals {
+			s = append(s, cephv1.SnapshotScheduleSpec{Interval: interval})
+		}
+		return s
+	}
+
+	assert.NoError(t, validateSnapshotScheduleMinInterval(schedules("5m", "1h", "1d"), 5*time.Minute))
+	err := validateSnapshotScheduleMinInterval(schedules("1m", "1h", "4m"), 5*time.Minute)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `schedule 0 has interval "1m"`)
+	assert.Contains(t, err.Error(), `schedule 2 has interval "4m"`)
+	assert.NotContains(t, err.Error(), "schedule 1")
+	// the malformed intervals are left to the spec validation
+	assert.NoError(t, validateSnapshotScheduleMinInterval(schedules("1x"), 5*time.Minute))
+	// a minimum of 0 disables the check
+	assert.NoError(t, validateSnapshotScheduleMinInterval(schedules("1m"), 0))
+}
+
+func TestMinSnapshotInterval(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	assert.Equal(t, 5*time.Minute, minSnapshotInterval(log))
+
+	t.Setenv(minSnapshotIntervalSettingName, "1m")
+	assert.Equal(t, time.Minute, minSnapshotInterval(log))
+
+	t.Setenv(minSnapshotIntervalSettingName, "invalid")
+	assert.Equal(t, 5*time.Minute, minSnapshotInterval(log))
+}
+
+func TestCheckSnapshotScheduleMinInterval(t *testing.T) {
+	log := newReconcileLogger(types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"})
+	newRadosNamespace := func(interval string) *cephv1.CephBlockPoolRadosNamespace {
+		return &cephv1.CephBlockPoolRadosNamespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+			Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+				BlockPoolName: "replicapool",
+				Mirroring: &cephv1.RadosNamespaceMirroring{
+					Mode:              "image",
+					SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: interval}},
+				},
+			},
+		}
+	}
+
+	t.Run("below the minimum", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(1)
+		r := &ReconcileCephBlockPoolRadosNamespace{recorder: recorder}
+		assert.Error(t, r.checkSnapshotScheduleMinInterval(newRadosNamespace("1m"), log))
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning "+snapshotIntervalTooShortEventReason)
+	})
+
+	t.Run("at and above the minimum", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(1)
+		r := &ReconcileCephBlockPoolRadosNamespace{recorder: recorder}
+		assert.NoError(t, r.checkSnapshotScheduleMinInterval(newRadosNamespace("5m"), log))
+		assert.NoError(t, r.checkSnapshotScheduleMinInterval(newRadosNamespace("1h"), log))
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("minimum lowered in the operator config", func(t *testing.T) {
+		t.Setenv(minSnapshotIntervalSettingName, "1m")
+		recorder := record.NewFakeRecorder(1)
+		r := &ReconcileCephBlockPoolRadosNamespace{recorder: recorder}
+		assert.NoError(t, r.checkSnapshotScheduleMinInterval(newRadosNamespace("1m"), log))
+		assert.Empty(t, recorder.Events)
+	})
+}
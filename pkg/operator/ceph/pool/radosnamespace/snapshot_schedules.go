@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+// snapshotSchedulesInfoKey is the status info key recording whether the snapshot schedules of the rados
+// namespace are active or paused
+const snapshotSchedulesInfoKey = "snapshotSchedules"
+
+const (
+	snapshotSchedulesActive = "active"
+	snapshotSchedulesPaused = "paused"
+)
+
+// desiredSnapshotSchedules returns the snapshot schedules to set in ceph. Ceph cannot pause the snapshot
+// schedules, so the schedules are removed while they are paused and added back from the spec on resume.
+func desiredSnapshotSchedules(mirroring *cephv1.RadosNamespaceMirroring) []cephv1.SnapshotScheduleSpec {
+	if mirroring == nil || mirroring.SnapshotSchedulesPaused {
+		return nil
+	}
+	return mirroring.SnapshotSchedules
+}
+
+// snapshotSchedulesInfo returns the state of the snapshot schedules recorded in the status info, or an empty
+// string if there are no snapshot schedules
+func snapshotSchedulesInfo(mirroring *cephv1.RadosNamespaceMirroring) string {
+	if mirroring == nil || len(mirroring.SnapshotSchedules) == 0 {
+		return ""
+	}
+	if mirroring.SnapshotSchedulesPaused {
+		return snapshotSchedulesPaused
+	}
+	return snapshotSchedulesActive
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSnapshotSchedulesInfo(t *testing.T) {
+	assert.Equal(t, "", snapshotSchedulesInfo(nil))
+	assert.Equal(t, "", snapshotSchedulesInfo(&cephv1.RadosNamespaceMirroring{SnapshotSchedulesPaused: true}))
+
+	mirroring := &cephv1.RadosNamespaceMirroring{SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h"}}}
+	assert.Equal(t, snapshotSchedulesActive, snapshotSchedulesInfo(mirroring))
+	assert.Equal(t, mirroring.SnapshotSchedules, desiredSnapshotSchedules(mirroring))
+
+	mirroring.SnapshotSchedulesPaused = true
+	assert.Equal(t, snapshotSchedulesPaused, snapshotSchedulesInfo(mirroring))
+	assert.Nil(t, desiredSnapshotSchedules(mirroring))
+}
+
+func TestReconcileSnapshotSchedulesPaused(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+	cephBlockPool.Spec.Mirroring.Enabled = true
+	cephBlockPool.Spec.StatusCheck.Mirror.Disabled = true
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace, Generation: 1},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:              cephv1.RadosNamespaceMirroringModeImage,
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "24h"}},
+			},
+		},
+		// mirroring was enabled by the operator for this generation
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{Info: map[string]string{mirroringEnabledInfoKey: "1"}},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+
+	var commands []string
+	schedules := "[]"
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					if args[0] == "mirror" && args[1] == "pool" && args[2] == "info" {
+						return `{"mode":"image"}`, nil
+					}
+					if args[0] == "mirror" && args[1] == "snapshot" && args[2] == "schedule" {
+						switch args[3] {
+						case "ls":
+							return schedules, nil
+						case "add":
+							schedules = `[{"interval":"24h"}]`
+						case "remove":
+							schedules = "[]"
+						}
+						commands = append(commands, strings.Join(args[:4], " "))
+					}
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx, CephVersion: cephver.CephVersion{Major: 20}},
+		opManagerContext:       ctx,
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+	reconcileMirroring := func(paused bool) string {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, name, current))
+		current.Spec.Mirroring.SnapshotSchedulesPaused = paused
+		commands = nil
+		assert.NoError(t, r.reconcileMirroring(current, cephBlockPool, log))
+		assert.NoError(t, cl.Get(ctx, name, current))
+		return current.Status.Info[snapshotSchedulesInfoKey]
+	}
+
+	t.Run("active", func(t *testing.T) {
+		assert.Equal(t, snapshotSchedulesActive, reconcileMirroring(false))
+		assert.Equal(t, []string{"mirror snapshot schedule add"}, commands)
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		assert.Equal(t, snapshotSchedulesPaused, reconcileMirroring(true))
+		assert.Equal(t, []string{"mirror snapshot schedule remove"}, commands)
+
+		// the schedules stay removed while paused
+		assert.Equal(t, snapshotSchedulesPaused, reconcileMirroring(true))
+		assert.Nil(t, commands)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		assert.Equal(t, snapshotSchedulesActive, reconcileMirroring(false))
+		assert.Equal(t, []string{"mirror snapshot schedule add"}, commands)
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// staleAfterSettingName is the operator setting with the duration after the last successful reconcile of a
+// rados namespace after which it gets the Stale condition, e.g. "6h". The staleness is not tracked by default.
+const staleAfterSettingName = "ROOK_RADOS_NAMESPACE_STALE_AFTER"
+
+// staleAfter returns the staleness threshold, or 0 if the staleness is not tracked
+func staleAfter(log *reconcileLogger) time.Duration {
+	value := k8sutil.GetOperatorSetting(staleAfterSettingName, "0")
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold < 0 {
+		log.Warningf("invalid setting %q value %q, the staleness is not tracked. %v", staleAfterSettingName, value, err)
+		return 0
+	}
+	return threshold
+}
+
+// now returns the current time of the reconciler clock, the real clock is used if none is set
+func (r *ReconcileCephBlockPoolRadosNamespace) now() time.Time {
+	if r.clock == nil {
+		return time.Now()
+	}
+	return r.clock.Now()
+}
+
+// recordReconcileTime sets the time of the last successful reconcile in the status, and resets the Stale
+// condition if it was set
+func (r *ReconcileCephBlockPoolRadosNamespace) recordReconcileTime(status *cephv1.CephBlockPoolRadosNamespaceStatus) {
+	status.LastReconcileTime = &metav1.Time{Time: r.now()}
+	if condition := cephv1.FindStatusCondition(status.Conditions, cephv1.ConditionStale); condition != nil && condition.Status == v1.ConditionTrue {
+		cephv1.SetStatusCondition(&status.Conditions, cephv1.Condition{
+			Type:    cephv1.ConditionStale,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.ReconcileSucceeded,
+			Message: "the rados namespace is reconciled successfully",
+		})
+	}
+}
+
+// checkStaleness sets the Stale condition if the last successful reconcile of the rados namespace is older
+// than the staleness threshold. It is checked at the start of each reconcile, including the periodic and the
+// failed ones that are requeued.
+func (r *ReconcileCephBlockPoolRadosNamespace) checkStaleness(radosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName, log *reconcileLogger) {
+	threshold := staleAfter(log)
+	if threshold <= 0 || radosNamespace.Status == nil || radosNamespace.Status.LastReconcileTime == nil {
+		return
+	}
+	lastReconcileTime := radosNamespace.Status.LastReconcileTime.Time
+	age := r.now().Sub(lastReconcileTime)
+	if age <= threshold {
+		return
+	}
+	if condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionStale); condition != nil && condition.Status == v1.ConditionTrue {
+		return
+	}
+
+	message := fmt.Sprintf("the last successful reconcile at %s is older than %s", lastReconcileTime.UTC().Format(time.RFC3339), threshold.String())
+	log.Warningf("rados namespace %q is stale, %s", name, message)
+	r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, radosNamespace.Status.Phase, log, cephv1.Condition{
+		Type:    cephv1.ConditionStale,
+		Status:  v1.ConditionTrue,
+		Reason:  cephv1.ReconcileStaleReason,
+		Message: message,
+	})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckStaleness(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	reconciledAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		Status: &cephv1.CephBlockPoolRadosNamespaceStatus{
+			Phase:             cephv1.ConditionReady,
+			LastReconcileTime: &metav1.Time{Time: reconciledAt},
+		},
+	}
+	fakeClock := clocktesting.NewFakePassiveClock(reconciledAt)
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+		opManagerContext: ctx,
+		clock:            fakeClock,
+	}
+	getCurrent := func(t *testing.T) *cephv1.CephBlockPoolRadosNamespace {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		return current
+	}
+
+	t.Run("staleness is not tracked by default", func(t *testing.T) {
+		fakeClock.SetTime(reconciledAt.Add(24 * time.Hour))
+		r.checkStaleness(getCurrent(t), name, log)
+		assert.Nil(t, cephv1.FindStatusCondition(getCurrent(t).Status.Conditions, cephv1.ConditionStale))
+	})
+
+	t.Run("recent reconcile is not stale", func(t *testing.T) {
+		t.Setenv(staleAfterSettingName, "1h")
+		fakeClock.SetTime(reconciledAt.Add(30 * time.Minute))
+		r.checkStaleness(getCurrent(t), name, log)
+		assert.Nil(t, cephv1.FindStatusCondition(getCurrent(t).Status.Conditions, cephv1.ConditionStale))
+	})
+
+	t.Run("old reconcile is stale", func(t *testing.T) {
+		t.Setenv(staleAfterSettingName, "1h")
+		fakeClock.SetTime(reconciledAt.Add(2 * time.Hour))
+		r.checkStaleness(getCurrent(t), name, log)
+		current := getCurrent(t)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionStale)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.ReconcileStaleReason, condition.Reason)
+		assert.Contains(t, condition.Message, "2025-01-01T00:00:00Z")
+		assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+
+		// the condition is not written again while the rados namespace stays stale
+		fakeClock.SetTime(reconciledAt.Add(3 * time.Hour))
+		r.checkStaleness(current, name, log)
+		assert.Equal(t, current.ResourceVersion, getCurrent(t).ResourceVersion)
+	})
+
+	t.Run("successful reconcile resets the staleness", func(t *testing.T) {
+		t.Setenv(staleAfterSettingName, "1h")
+		r.updateStatus(1, name, cephv1.ConditionReady, log)
+		current := getCurrent(t)
+		assert.True(t, current.Status.LastReconcileTime.Time.Equal(reconciledAt.Add(3*time.Hour)))
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionStale)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.ReconcileSucceeded, condition.Reason)
+
+		fakeClock.SetTime(reconciledAt.Add(3*time.Hour + 30*time.Minute))
+		r.checkStaleness(current, name, log)
+		condition = cephv1.FindStatusCondition(getCurrent(t).Status.Conditions, cephv1.ConditionStale)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+	})
+
+	t.Run("invalid setting disables the staleness", func(t *testing.T) {
+		t.Setenv(staleAfterSettingName, "soon")
+		assert.Equal(t, time.Duration(0), staleAfter(log))
+	})
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"sync"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// statusMutation changes the status of the rados namespace, and returns whether the status was changed. The
+// status is never nil.
+type statusMutation func(radosNamespace *cephv1.CephBlockPoolRadosNamespace) bool
+
+// statusBatcher collects the status mutations of the rados namespaces being reconciled, so that a reconcile
+// writes the status with a single update at its end instead of one update per mutation. The mutations are
+// applied in order to the latest version of the CR, and applied again if the update conflicts.
+type statusBatcher struct {
+	mutex   sync.Mutex
+	pending map[types.NamespacedName][]statusMutation
+}
+
+// begin starts collecting the status mutations of the rados namespace
+func (b *statusBatcher) begin(name types.NamespacedName) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.pending == nil {
+		b.pending = map[types.NamespacedName][]statusMutation{}
+	}
+	b.pending[name] = []statusMutation{}
+}
+
+// add collects the mutation, and returns false if the status of the rados namespace is not being collected
+func (b *statusBatcher) add(name types.NamespacedName, mutation statusMutation) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	mutations, ok := b.pending[name]
+	if !ok {
+		return false
+	}
+	b.pending[name] = append(mutations, mutation)
+	return true
+}
+
+// end stops collecting the status mutations of the rados namespace and returns the collected mutations
+func (b *statusBatcher) end(name types.NamespacedName) []statusMutation {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	mutations := b.pending[name]
+	delete(b.pending, name)
+	return mutations
+}
+
+// mutateStatus changes the status of the rados namespace. The change is written at the end of the reconcile
+// of the rados namespace, or immediately outside of a reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) mutateStatus(name types.NamespacedName, mutation statusMutation) error {
+	if r.statusBatcher.add(name, mutation) {
+		return nil
+	}
+	return r.writeStatus(name, []statusMutation{mutation})
+}
+
+// flushStatus writes the status mutations collected during the reconcile of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) flushStatus(name types.NamespacedName, log *reconcileLogger) {
+	mutations := r.statusBatcher.end(name)
+	if len(mutations) == 0 {
+		return
+	}
+	if err := r.writeStatus(name, mutations); err != nil {
+		log.Errorf("failed to update the status of ceph blockpool rados namespace %q. %v", name, err)
+	}
+}
+
+// writeStatus applies the mutations to the latest version of the rados namespace and updates its status once,
+// the status is not updated if no mutation changed it
+func (r *ReconcileCephBlockPoolRadosNamespace) writeStatus(name types.NamespacedName, mutations []statusMutation) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+		if err := r.client.Get(r.opManagerContext, name, radosNamespace); err != nil {
+			if kerrors.IsNotFound(err) {
+				logger.Debugf("CephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", name)
+				return nil
+			}
+			return err
+		}
+		if radosNamespace.Status == nil {
+			radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+		}
+		changed := false
+		for _, mutation := range mutations {
+			if mutation(radosNamespace) {
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return reporting.UpdateStatus(r.client, radosNamespace)
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// newStatusWriteCounter returns a fake client that counts the status updates of the rados namespaces
+func newStatusWriteCounter(writes *int, objects ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).
+		WithStatusSubresource(&cephv1.CephBlockPoolRadosNamespace{}).
+		WithIndex(&cephv1.CephBlockPoolRadosNamespace{}, cephRNSNameIndex, indexRadosNamespaceName).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if _, ok := obj.(*cephv1.CephBlockPoolRadosNamespace); ok && subResourceName == "status" {
+					*writes++
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).Build()
+}
+
+func TestStatusBatcher(t *testing.T) {
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	other := types.NamespacedName{Name: "namespace-b", Namespace: "rook-ceph"}
+	noop := func(*cephv1.CephBlockPoolRadosNamespace) bool { return false }
+
+	b := statusBatcher{}
+	assert.False(t, b.add(name, noop))
+	assert.Empty(t, b.end(name))
+
+	b.begin(name)
+	assert.True(t, b.add(name, noop))
+	assert.True(t, b.add(name, noop))
+	// the mutations of the other rados namespaces are not collected
+	assert.False(t, b.add(other, noop))
+	assert.Len(t, b.end(name), 2)
+
+	// the batch is closed
+	assert.False(t, b.add(name, noop))
+	assert.Empty(t, b.end(name))
+}
+
+func TestWriteStatus(t *testing.T) {
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	setInfo := func(key, value string) statusMutation {
+		return func(current *cephv1.CephBlockPoolRadosNamespace) bool {
+			if current.Status.Info[key] == value {
+				return false
+			}
+			if current.Status.Info == nil {
+				current.Status.Info = map[string]string{}
+			}
+			current.Status.Info[key] = value
+			return true
+		}
+	}
+	getInfo := func(t *testing.T, r *ReconcileCephBlockPoolRadosNamespace) map[string]string {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, r.client.Get(ctx, name, current))
+		assert.NotNil(t, current.Status)
+		return current.Status.Info
+	}
+
+	t.Run("written immediately outside of a reconcile", func(t *testing.T) {
+		writes := 0
+		r := &ReconcileCephBlockPoolRadosNamespace{client: newStatusWriteCounter(&writes, radosNamespace.DeepCopy()), opManagerContext: ctx}
+		assert.NoError(t, r.mutateStatus(name, setInfo("a", "1")))
+		assert.Equal(t, 1, writes)
+		assert.Equal(t, "1", getInfo(t, r)["a"])
+	})
+
+	t.Run("mutations are written once in order", func(t *testing.T) {
+		writes := 0
+		r := &ReconcileCephBlockPoolRadosNamespace{client: newStatusWriteCounter(&writes, radosNamespace.DeepCopy()), opManagerContext: ctx}
+		r.statusBatcher.begin(name)
+		assert.NoError(t, r.mutateStatus(name, setInfo("a", "1")))
+		assert.NoError(t, r.mutateStatus(name, setInfo("b", "1")))
+		assert.NoError(t, r.mutateStatus(name, setInfo("a", "2")))
+		assert.Zero(t, writes)
+
+		r.flushStatus(name, log)
+		assert.Equal(t, 1, writes)
+		assert.Equal(t, map[string]string{"a": "2", "b": "1"}, getInfo(t, r))
+	})
+
+	t.Run("unchanged status is not written", func(t *testing.T) {
+		writes := 0
+		r := &ReconcileCephBlockPoolRadosNamespace{client: newStatusWriteCounter(&writes, radosNamespace.DeepCopy()), opManagerContext: ctx}
+		r.statusBatcher.begin(name)
+		assert.NoError(t, r.mutateStatus(name, setInfo("a", "1")))
+		r.flushStatus(name, log)
+		assert.Equal(t, 1, writes)
+
+		r.statusBatcher.begin(name)
+		assert.NoError(t, r.mutateStatus(name, setInfo("a", "1")))
+		r.flushStatus(name, log)
+		assert.Equal(t, 1, writes)
+	})
+
+	t.Run("deleted rados namespace", func(t *testing.T) {
+		writes := 0
+		r := &ReconcileCephBlockPoolRadosNamespace{client: newStatusWriteCounter(&writes), opManagerContext: ctx}
+		assert.NoError(t, r.mutateStatus(name, setInfo("a", "1")))
+		assert.Zero(t, writes)
+	})
+}
+
+func TestReconcileWritesStatusOnce(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	name := types.NamespacedName{Name: "namespace-a", Namespace: namespace}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Spec:       cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}},
+		Status: cephv1.ClusterStatus{
+			Phase:      cephv1.ConditionReady,
+			CephStatus: &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+
+	writes := 0
+	r := newTestReconciler(t, namespace, nil)
+	cl := newStatusWriteCounter(&writes, radosNamespace, cephCluster)
+	r.client = cl
+	r.context.Client = cl
+	createTestCSIConfigMap(t, r, namespace)
+
+	// the new CR goes through the progressing and the ready status in a single status update
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, writes)
+
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(ctx, name, current))
+	assert.Equal(t, cephv1.ConditionReady, current.Status.Phase)
+	assert.Equal(t, buildClusterID(current), current.Status.Info["clusterID"])
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// summaryConfigMapName is the name of the config map in the operator namespace with the summary of the
+// status of all the rados namespaces
+const summaryConfigMapName = "rook-ceph-rados-namespace-summary"
+
+// radosNamespaceSummary is the summary of the status of a rados namespace
+type radosNamespaceSummary struct {
+	Phase           cephv1.ConditionType `json:"phase"`
+	MirroringHealth string               `json:"mirroringHealth,omitempty"`
+	DeletionBlocked bool                 `json:"deletionBlocked"`
+}
+
+// summaryKey returns the key of the rados namespace in the summary config map, the '/' separator is not
+// allowed in config map keys
+func summaryKey(name types.NamespacedName) string {
+	return fmt.Sprintf("%s.%s", name.Namespace, name.Name)
+}
+
+func newRadosNamespaceSummary(radosNamespace *cephv1.CephBlockPoolRadosNamespace) radosNamespaceSummary {
+	summary := radosNamespaceSummary{}
+	if radosNamespace.Status == nil {
+		return summary
+	}
+	summary.Phase = radosNamespace.Status.Phase
+	if status := radosNamespace.Status.MirroringStatus; status != nil && status.Summary != nil {
+		summary.MirroringHealth = status.Summary.Health
+	}
+	if condition := cephv1.FindStatusCondition(radosNamespace.Status.Conditions, cephv1.ConditionRadosNSDeletionIsBlocked); condition != nil {
+		summary.DeletionBlocked = condition.Status == v1.ConditionTrue
+	}
+	return summary
+}
+
+// updateSummary adds, updates or removes the entry of the rados namespace in the summary config map after
+// each reconcile. The writes are debounced by only updating the config map when the entry changed since
+// the last write.
+func (r *ReconcileCephBlockPoolRadosNamespace) updateSummary(name types.NamespacedName, log *reconcileLogger) {
+	if r.opConfig.OperatorNamespace == "" {
+		return
+	}
+
+	key := summaryKey(name)
+	value := ""
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	err := r.client.Get(r.opManagerContext, name, radosNamespace)
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Warningf("failed to get rados namespace %q to update the summary. %v", name, err)
+		return
+	}
+	if err == nil {
+		data, err := json.Marshal(newRadosNamespaceSummary(radosNamespace))
+		if err != nil {
+			log.Warningf("failed to marshal the summary of rados namespace %q. %v", name, err)
+			return
+		}
+		value = string(data)
+	}
+
+	if last, ok := r.summaries[key]; ok && last == value {
+		return
+	}
+	if err := r.writeSummary(key, value); err != nil {
+		log.Warningf("failed to update the summary of rados namespace %q. %v", name, err)
+		return
+	}
+	if r.summaries == nil {
+		r.summaries = map[string]string{}
+	}
+	r.summaries[key] = value
+}
+
+// writeSummary sets the entry of the summary config map, or removes it if the value is empty
+func (r *ReconcileCephBlockPoolRadosNamespace) writeSummary(key, value string) error {
+	configMaps := r.context.Clientset.CoreV1().ConfigMaps(r.opConfig.OperatorNamespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(r.opManagerContext, summaryConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			if !kerrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get config map %q", summaryConfigMapName)
+			}
+			if value == "" {
+				return nil
+			}
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: summaryConfigMapName, Namespace: r.opConfig.OperatorNamespace},
+				Data:       map[string]string{key: value},
+			}
+			_, err = configMaps.Create(r.opManagerContext, cm, metav1.CreateOptions{})
+			return errors.Wrapf(err, "failed to create config map %q", summaryConfigMapName)
+		}
+
+		if value == "" {
+			if _, ok := cm.Data[key]; !ok {
+				return nil
+			}
+			delete(cm.Data, key)
+		} else {
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[key] = value
+		}
+		_, err = configMaps.Update(r.opManagerContext, cm, metav1.UpdateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update config map %q", summaryConfigMapName)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpdateSummary(t *testing.T) {
+	ctx := context.TODO()
+	operatorNamespace := "rook-ceph-operator"
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+		Status:     &cephv1.CephBlockPoolRadosNamespaceStatus{Phase: cephv1.ConditionReady},
+	}
+
+	clientset := k8sfake.NewSimpleClientset()
+	writes := 0
+	clientset.PrependReactor("*", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() == "create" || action.GetVerb() == "update" {
+			writes++
+		}
+		return false, nil, nil
+	})
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build(),
+		context:          &clusterd.Context{Clientset: clientset},
+		opManagerContext: ctx,
+		opConfig:         opcontroller.OperatorConfig{OperatorNamespace: operatorNamespace},
+	}
+	getSummary := func() (radosNamespaceSummary, bool) {
+		cm, err := clientset.CoreV1().ConfigMaps(operatorNamespace).Get(ctx, summaryConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		value, ok := cm.Data["rook-ceph.namespace-a"]
+		summary := radosNamespaceSummary{}
+		if ok {
+			assert.NoError(t, json.Unmarshal([]byte(value), &summary))
+		}
+		return summary, ok
+	}
+
+	// the entry is added
+	r.updateSummary(name, log)
+	summary, ok := getSummary()
+	assert.True(t, ok)
+	assert.Equal(t, radosNamespaceSummary{Phase: cephv1.ConditionReady}, summary)
+	assert.Equal(t, 1, writes)
+
+	// the config map is not written again if the entry did not change
+	r.updateSummary(name, log)
+	assert.Equal(t, 1, writes)
+
+	// the entry is updated
+	current := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, r.client.Get(ctx, name, current))
+	current.Status.Phase = cephv1.ConditionDeleting
+	current.Status.MirroringStatus = &cephv1.MirroringStatusSpec{MirroringStatus: cephv1.MirroringStatus{Summary: &cephv1.MirroringStatusSummarySpec{Health: "WARNING"}}}
+	current.Status.Conditions = []cephv1.Condition{{Type: cephv1.ConditionRadosNSDeletionIsBlocked, Status: v1.ConditionTrue}}
+	assert.NoError(t, r.client.Update(ctx, current))
+	r.updateSummary(name, log)
+	summary, ok = getSummary()
+	assert.True(t, ok)
+	assert.Equal(t, radosNamespaceSummary{Phase: cephv1.ConditionDeleting, MirroringHealth: "WARNING", DeletionBlocked: true}, summary)
+	assert.Equal(t, 2, writes)
+
+	// the entry is removed once the CR is deleted
+	assert.NoError(t, r.client.Delete(ctx, current))
+	r.updateSummary(name, log)
+	_, ok = getSummary()
+	assert.False(t, ok)
+	assert.Equal(t, 3, writes)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"time"
+
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+)
+
+const (
+	// cephCallTimeoutSettingName is the operator setting bounding the duration of the ceph calls of a reconcile
+	// and of the mirroring health checks, so that a hung mon fails the call instead of stalling the worker
+	cephCallTimeoutSettingName = "ROOK_RADOS_NAMESPACE_CEPH_TIMEOUT"
+	defaultCephCallTimeout     = 60 * time.Second
+)
+
+func cephCallTimeout() time.Duration {
+	setting := k8sutil.GetOperatorSetting(cephCallTimeoutSettingName, defaultCephCallTimeout.String())
+	timeout, err := time.ParseDuration(setting)
+	if err != nil || timeout <= 0 {
+		logger.Warningf("invalid setting %q value %q, using the default timeout %s", cephCallTimeoutSettingName, setting, defaultCephCallTimeout.String())
+		return defaultCephCallTimeout
+	}
+	return timeout
+}
+
+// withCephTimeout runs the ceph calls of fn with a deadline derived from the operator context, and times them
+// as the given operation. The ceph commands still running at the deadline are killed, but the results of fn must
+// not be used if the call timed out since fn may still be returning.
+func (r *ReconcileCephBlockPoolRadosNamespace) withCephTimeout(operation string, log *reconcileLogger, fn func(clusterInfo *cephclient.ClusterInfo) error) error {
+	return log.timeCephCall(operation, func() error {
+		return cephclient.CallWithTimeout(r.opManagerContext, r.clusterInfo, cephCallTimeout(), fn)
+	})
+}
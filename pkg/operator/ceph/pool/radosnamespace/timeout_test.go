@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCephCallTimeout(t *testing.T) {
+	assert.Equal(t, defaultCephCallTimeout, cephCallTimeout())
+
+	t.Setenv(cephCallTimeoutSettingName, "5s")
+	assert.Equal(t, 5*time.Second, cephCallTimeout())
+
+	t.Setenv(cephCallTimeoutSettingName, "invalid")
+	assert.Equal(t, defaultCephCallTimeout, cephCallTimeout())
+
+	t.Setenv(cephCallTimeoutSettingName, "0s")
+	assert.Equal(t, defaultCephCallTimeout, cephCallTimeout())
+}
+
+func TestCephCallsTimeOut(t *testing.T) {
+	t.Setenv(cephCallTimeoutSettingName, "50ms")
+	ctx := context.TODO()
+	name := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+	log := newReconcileLogger(name)
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		TypeMeta:   metav1.TypeMeta{Kind: "CephBlockPoolRadosNamespace"},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: name.Namespace}}
+
+	// the executor blocks like a command waiting on a hung mon
+	unblock := make(chan struct{})
+	defer close(unblock)
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client: cl,
+		context: &clusterd.Context{
+			Executor: &exectest.MockExecutor{
+				MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+					<-unblock
+					return "", nil
+				},
+			},
+		},
+		clusterInfo:            &cephclient.ClusterInfo{Namespace: name.Namespace, Context: ctx},
+		opManagerContext:       ctx,
+		recorder:               record.NewFakeRecorder(5),
+		radosNamespaceContexts: map[string]*mirrorHealth{},
+	}
+
+	t.Run("create", func(t *testing.T) {
+		err := r.createOrUpdateRadosNamespace(radosNamespace, log)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		blocked, err := r.deleteRadosNamespace(radosNamespace, &cephv1.CephCluster{}, log)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.False(t, blocked)
+	})
+
+	t.Run("mirroring info", func(t *testing.T) {
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool, log)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// transientErrorsSettingName is the operator setting extending the error substrings classified as transient,
+// as a comma separated list
+const transientErrorsSettingName = "ROOK_RADOS_NAMESPACE_TRANSIENT_ERRORS"
+
+// defaultTransientErrors are the substrings of the ceph errors that resolve by themselves, such as during a mon
+// election
+var defaultTransientErrors = []string{
+	"election in progress",
+	"Resource temporarily unavailable",
+	"error connecting to the cluster",
+}
+
+// waitForRequeueIfTransientError retries the reconcile after a transient ceph error, without reporting a failure
+var waitForRequeueIfTransientError = reconcile.Result{Requeue: true, RequeueAfter: 15 * time.Second}
+
+// transientErrors returns the default transient error substrings and the ones added by the operator setting
+func transientErrors() []string {
+	substrings := append([]string{}, defaultTransientErrors...)
+	for _, substring := range strings.Split(k8sutil.GetOperatorSetting(transientErrorsSettingName, ""), ",") {
+		if substring = strings.TrimSpace(substring); substring != "" {
+			substrings = append(substrings, substring)
+		}
+	}
+	return substrings
+}
+
+// isTransientCephError returns whether the error contains one of the transient error substrings
+func isTransientCephError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	for _, substring := range transientErrors() {
+		if strings.Contains(message, substring) {
+			return true
+		}
+	}
+	return false
+}
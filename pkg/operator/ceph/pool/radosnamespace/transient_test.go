@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIsTransientCephError(t *testing.T) {
+	assert.False(t, isTransientCephError(nil))
+	assert.False(t, isTransientCephError(errors.New("rados: ret=-1, Operation not permitted")))
+	assert.True(t, isTransientCephError(errors.New("rados: ret=-11, Resource temporarily unavailable")))
+	assert.True(t, isTransientCephError(errors.Wrap(errors.New("mon election in progress"), "failed to create rados namespace")))
+
+	// the operator setting extends the defaults
+	t.Setenv(transientErrorsSettingName, " Operation not permitted, ,osd map busy")
+	assert.True(t, isTransientCephError(errors.New("rados: ret=-1, Operation not permitted")))
+	assert.True(t, isTransientCephError(errors.New("osd map busy")))
+	assert.True(t, isTransientCephError(errors.New("rados: ret=-11, Resource temporarily unavailable")))
+	assert.False(t, isTransientCephError(errors.New("rados: ret=-2, No such file or directory")))
+}
+
+func TestReconcileTransientCephError(t *testing.T) {
+	logBuf := &bytes.Buffer{}
+	capnslog.SetFormatter(capnslog.NewLogFormatter(logBuf, "", 0))
+	defer capnslog.SetFormatter(capnslog.NewPrettyFormatter(os.Stderr, false))
+	capnslog.SetGlobalLogLevel(capnslog.INFO)
+	defer capnslog.SetGlobalLogLevel(capnslog.DEBUG)
+
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "namespace-a",
+			Namespace:  namespace,
+			Generation: 1,
+			Finalizers: []string{"cephblockpoolradosnamespace.ceph.rook.io"},
+		},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	var createErr error
+	r := newTestReconciler(t, namespace, func(command string, args ...string) (string, error) {
+		if args[0] == "namespace" && args[1] == "create" {
+			return "", createErr
+		}
+		return "", nil
+	}, radosNamespace, newTestCephCluster(namespace), newTestCephBlockPool(namespace))
+	cl := r.client
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "namespace-a", Namespace: namespace}}
+	getPhase := func() cephv1.ConditionType {
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, current))
+		if current.Status == nil {
+			return ""
+		}
+		return current.Status.Phase
+	}
+
+	t.Run("transient error is retried without a failure", func(t *testing.T) {
+		createErr = errors.New("rados: ret=-11, Resource temporarily unavailable")
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, waitForRequeueIfTransientError, res)
+		assert.NotEqual(t, cephv1.ConditionFailure, getPhase())
+		assert.NotContains(t, logBuf.String(), "failed to reconcile")
+	})
+
+	t.Run("permanent error sets the failure", func(t *testing.T) {
+		logBuf.Reset()
+		createErr = errors.New("rados: ret=-1, Operation not permitted")
+		_, err := r.Reconcile(ctx, req)
+		assert.Error(t, err)
+		assert.Equal(t, cephv1.ConditionFailure, getPhase())
+		assert.Contains(t, logBuf.String(), "failed to reconcile")
+	})
+}
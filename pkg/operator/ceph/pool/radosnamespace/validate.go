@@ -0,0 +1,379 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+)
+
+const maxApplicationMetadataValueLength = 256
+
+// applicationMetadataKeyRegex matches the keys allowed in the application metadata, up to 63 alphanumeric
+// characters, '-', '_' or '.', starting and ending with an alphanumeric character
+var applicationMetadataKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]{0,61}[a-zA-Z0-9])?$`)
+
+// snapshotScheduleIntervalRegex matches the snapshot schedule intervals accepted by ceph, in days, hours or minutes
+var snapshotScheduleIntervalRegex = regexp.MustCompile(`^[1-9][0-9]*[dhm]$`)
+
+// snapshotScheduleStartTimeLayouts are the ISO 8601 time formats accepted for the start time of a snapshot schedule
+var snapshotScheduleStartTimeLayouts = []string{
+	"15:04",
+	"15:04:05",
+	"15:04Z07:00",
+	"15:04:05Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z07:00",
+}
+
+// rbdMapOptionRegex matches a single krbd map or unmap option, a name with an optional value, e.g. 'queue_depth=1024'
+var rbdMapOptionRegex = regexp.MustCompile(`^[a-z0-9_]+(=[^,=\s]+)?$`)
+
+// clusterIDRegex matches the cluster IDs of the csi config set in the spec, up to 36 alphanumeric characters, '-',
+// '_' or '.', starting and ending with an alphanumeric character. The cluster ID is part of the volume handles of
+// ceph-csi, which limits its length.
+var clusterIDRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]{0,34}[a-zA-Z0-9])?$`)
+
+// radosNamespaceNameRegex matches the rados namespace names, up to 253 alphanumeric characters, '-', '_' or '.',
+// starting and ending with an alphanumeric character. The '/' and '@' separators of the rbd image specs are not
+// allowed. The length limit is the one of the CR names so that any valid CR name is accepted.
+var radosNamespaceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.-]{0,251}[a-zA-Z0-9])?$`)
+
+// validateRadosNamespace validates the rados namespace CR settings, starting with the constraints between the
+// fields of the spec
+func validateRadosNamespace(radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	if err := radosNamespace.Spec.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateRadosNamespaceName(radosNamespace); err != nil {
+		return err
+	}
+
+	if err := validateClusterID(radosNamespace.Spec.ClusterID); err != nil {
+		return err
+	}
+
+	if radosNamespace.Spec.External != nil {
+		if err := validateMonitorEndpoints(radosNamespace.Spec.External.Monitors); err != nil {
+			return errors.Wrap(err, "invalid external monitors")
+		}
+	}
+
+	if radosNamespace.Spec.Mirroring != nil {
+		if err := validateMirroring(radosNamespace.Spec.Mirroring); err != nil {
+			return errors.Wrap(err, "invalid mirroring settings")
+		}
+	}
+
+	if len(radosNamespace.Spec.ApplicationMetadata) > 0 {
+		if err := validateApplicationMetadata(radosNamespace.Spec.ApplicationMetadata); err != nil {
+			return errors.Wrap(err, "invalid application metadata")
+		}
+	}
+
+	if radosNamespace.Spec.Compression != nil {
+		if err := validateCompression(radosNamespace.Spec.Compression); err != nil {
+			return errors.Wrap(err, "invalid compression settings")
+		}
+	}
+
+	if len(radosNamespace.Spec.PostCreateConfig) > 0 {
+		if err := validatePostCreateConfig(radosNamespace.Spec.PostCreateConfig); err != nil {
+			return errors.Wrap(err, "invalid post create config")
+		}
+	}
+
+	if err := validateRBDMapOptions(radosNamespace.Spec.MapOptions); err != nil {
+		return errors.Wrap(err, "invalid map options")
+	}
+	if err := validateRBDMapOptions(radosNamespace.Spec.UnmapOptions); err != nil {
+		return errors.Wrap(err, "invalid unmap options")
+	}
+
+	return nil
+}
+
+// validateRBDMapOptions validates the syntax of a comma separated list of krbd options, the options
+// themselves are only checked by the kernel when the volumes are mapped
+func validateRBDMapOptions(options string) error {
+	if options == "" {
+		return nil
+	}
+	for _, option := range strings.Split(options, ",") {
+		if !rbdMapOptionRegex.MatchString(option) {
+			return errors.Errorf("invalid option %q in %q, options must be a comma separated list of 'name' or 'name=value'", option, options)
+		}
+	}
+
+	return nil
+}
+
+// validateRadosNamespaceName validates the name of the rados namespace created in ceph, which is spec.name or
+// the CR name if spec.name is not set
+func validateRadosNamespaceName(radosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	name := cephv1.GetRadosNamespaceName(radosNamespace)
+	if name == cephv1.ImplicitNamespaceVal {
+		return nil
+	}
+	if !radosNamespaceNameRegex.MatchString(name) {
+		source := "spec.name"
+		if radosNamespace.Spec.Name == "" {
+			source = "CR name"
+		}
+		return errors.Errorf("invalid rados namespace name %q from the %s, names must be up to 253 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", name, source)
+	}
+
+	return nil
+}
+
+// validateClusterID validates the cluster ID override of the spec
+func validateClusterID(clusterID string) error {
+	if clusterID == "" {
+		return nil
+	}
+	if !clusterIDRegex.MatchString(clusterID) {
+		return errors.Errorf("invalid cluster ID %q, it must be up to 36 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", clusterID)
+	}
+	return nil
+}
+
+// validateMonitorEndpoints validates the mon endpoints in the "host:port" format of the csi config, all the
+// malformed endpoints are reported
+func validateMonitorEndpoints(endpoints []string) error {
+	var invalid []string
+	for _, endpoint := range endpoints {
+		host, port, err := net.SplitHostPort(endpoint)
+		if err == nil && host == "" {
+			err = errors.New("missing host")
+		}
+		if err == nil {
+			if p, perr := strconv.Atoi(port); perr != nil || p <= 0 || p > 65535 {
+				err = errors.Errorf("invalid port %q", port)
+			}
+		}
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("endpoint %q must be in the 'host:port' format, e.g. '10.0.0.1:3300': %v", endpoint, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.New(strings.Join(invalid, "; "))
+	}
+
+	return nil
+}
+
+// validateExternalMonitorsOverride checks that the mon endpoints are only overridden for an external cluster, the
+// csi config of the rados namespaces of a cluster managed by rook always uses the mons of the cluster
+func validateExternalMonitorsOverride(radosNamespace *cephv1.CephBlockPoolRadosNamespace, cephCluster *cephv1.CephCluster) error {
+	if radosNamespace.Spec.External == nil || len(radosNamespace.Spec.External.Monitors) == 0 || cephCluster.Spec.External.Enable {
+		return nil
+	}
+	return errors.Errorf("the external monitors of rados namespace %q are only supported when CephCluster %q is external", radosNamespace.Name, cephCluster.Name)
+}
+
+// validateApplicationMetadata validates the keys and values of the application metadata
+func validateApplicationMetadata(metadata map[string]string) error {
+	for key, value := range metadata {
+		if !applicationMetadataKeyRegex.MatchString(key) {
+			return errors.Errorf("invalid key %q, keys must be up to 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", key)
+		}
+		if value == "" {
+			return errors.Errorf("empty value for key %q", key)
+		}
+		if len(value) > maxApplicationMetadataValueLength {
+			return errors.Errorf("value of key %q is longer than %d characters", key, maxApplicationMetadataValueLength)
+		}
+	}
+
+	return nil
+}
+
+// validateCompression validates the compression hint of the rados namespace
+func validateCompression(compression *cephv1.RadosNamespaceCompression) error {
+	switch compression.Hint {
+	case "", "none", "compressible", "incompressible":
+	default:
+		return errors.Errorf("unknown compression hint %q, supported hints are \"none\", \"compressible\" and \"incompressible\"", compression.Hint)
+	}
+
+	return nil
+}
+
+// validatePostCreateConfig validates that the post create config only sets the allowed rbd config options, once each
+func validatePostCreateConfig(config []cephv1.RadosNamespaceConfigEntry) error {
+	keys := map[string]bool{}
+	for _, entry := range config {
+		if !allowedPostCreateConfigKeys[entry.Key] {
+			return errors.Errorf("config key %q is not allowed, allowed keys are %q", entry.Key, sortedAllowedPostCreateConfigKeys())
+		}
+		if entry.Value == "" {
+			return errors.Errorf("empty value for config key %q", entry.Key)
+		}
+		if keys[entry.Key] {
+			return errors.Errorf("duplicate config key %q", entry.Key)
+		}
+		keys[entry.Key] = true
+	}
+
+	return nil
+}
+
+// validateMirroring validates the mirroring settings of the rados namespace
+func validateMirroring(mirroring *cephv1.RadosNamespaceMirroring) error {
+	if err := validateMirroringMode(mirroring.Mode); err != nil {
+		return err
+	}
+
+	switch mirroring.Direction {
+	case "", cephv1.RadosNamespaceMirroringDirectionRxOnly, cephv1.RadosNamespaceMirroringDirectionTxOnly, cephv1.RadosNamespaceMirroringDirectionRxTx:
+	default:
+		return errors.Errorf("unknown mirroring direction %q, supported directions are %q, %q and %q",
+			mirroring.Direction, cephv1.RadosNamespaceMirroringDirectionRxOnly, cephv1.RadosNamespaceMirroringDirectionTxOnly, cephv1.RadosNamespaceMirroringDirectionRxTx)
+	}
+
+	if err := validateMirroringPeers(mirroring.Peers); err != nil {
+		return errors.Wrap(err, "invalid mirroring peers")
+	}
+
+	if err := validateSnapshotSchedules(mirroring.SnapshotSchedules); err != nil {
+		return errors.Wrap(err, "invalid snapshot schedules")
+	}
+
+	if mirroring.ImageFilter != nil {
+		if err := validateImageFilter(mirroring.ImageFilter); err != nil {
+			return errors.Wrap(err, "invalid image filter")
+		}
+	}
+
+	if mirroring.HealthCheck != nil && mirroring.HealthCheck.Interval != nil && mirroring.HealthCheck.Interval.Duration <= 0 {
+		return errors.Errorf("invalid mirroring health check interval %q, the interval must be positive", mirroring.HealthCheck.Interval.Duration.String())
+	}
+
+	return nil
+}
+
+// validateMirroringPeers checks that the site names of the mirroring peers are set and unique and that their
+// directions are known, all the invalid peers are reported
+func validateMirroringPeers(peers []cephv1.RadosNamespaceMirroringPeer) error {
+	var invalid []string
+	siteNames := map[string]bool{}
+	for _, peer := range peers {
+		if peer.SiteName == "" {
+			invalid = append(invalid, "the site name of a peer is empty")
+			continue
+		}
+		if siteNames[peer.SiteName] {
+			invalid = append(invalid, fmt.Sprintf("peer site %q is listed more than once", peer.SiteName))
+		}
+		siteNames[peer.SiteName] = true
+		switch peer.Direction {
+		case "", cephv1.RadosNamespaceMirroringDirectionRxOnly, cephv1.RadosNamespaceMirroringDirectionTxOnly, cephv1.RadosNamespaceMirroringDirectionRxTx:
+		default:
+			invalid = append(invalid, fmt.Sprintf("unknown mirroring direction %q of peer site %q", peer.Direction, peer.SiteName))
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.New(strings.Join(invalid, "; "))
+	}
+
+	return nil
+}
+
+// validatePoolMirroringSupport checks that the mirroring mode of the rados namespace is supported by its parent
+// pool. The journal-based mirroring of the pool mode keeps the image journals in the pool, which is not supported
+// on erasure coded pools. The snapshot-based mirroring of the image mode is supported on both pool types.
+func validatePoolMirroringSupport(mirroring *cephv1.RadosNamespaceMirroring, cephBlockPool *cephv1.CephBlockPool) error {
+	if mirroring == nil || !cephBlockPool.Spec.IsErasureCoded() || cephBlockPool.Spec.IsReplicated() {
+		return nil
+	}
+	if mirroring.Mode == cephv1.RadosNamespaceMirroringModePool {
+		return &PoolMirroringUnsupportedError{PoolName: cephBlockPool.Name, Mode: mirroring.Mode}
+	}
+	return nil
+}
+
+// validateImageFilter validates the syntax of the glob patterns of the image filter, all the malformed
+// patterns are reported
+func validateImageFilter(filter *cephv1.RadosNamespaceMirroringImageFilter) error {
+	var invalid []string
+	for _, pattern := range append(append([]string{}, filter.Include...), filter.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			invalid = append(invalid, fmt.Sprintf("pattern %q is malformed", pattern))
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.New(strings.Join(invalid, "; "))
+	}
+
+	return nil
+}
+
+// validateSnapshotSchedules validates the interval and start time of the snapshot schedules in the format
+// accepted by ceph, all the malformed entries are reported
+func validateSnapshotSchedules(schedules []cephv1.SnapshotScheduleSpec) error {
+	var invalid []string
+	for i, schedule := range schedules {
+		if !snapshotScheduleIntervalRegex.MatchString(schedule.Interval) {
+			invalid = append(invalid, fmt.Sprintf("schedule %d has invalid interval %q, it must be a number followed by 'd', 'h' or 'm', e.g. '1h'", i, schedule.Interval))
+		}
+		if schedule.StartTime != "" && !isValidSnapshotScheduleStartTime(schedule.StartTime) {
+			invalid = append(invalid, fmt.Sprintf("schedule %d has invalid start time %q, it must be in the ISO 8601 format, e.g. '14:00:00-05:00'", i, schedule.StartTime))
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.New(strings.Join(invalid, "; "))
+	}
+
+	return nil
+}
+
+func isValidSnapshotScheduleStartTime(startTime string) bool {
+	for _, layout := range snapshotScheduleStartTimeLayouts {
+		if _, err := time.Parse(layout, startTime); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getPeerMirroringDirections returns the direction requested for each peer site listed in the mirroring spec,
+// the direction of the mirroring if the peer does not set one. The direction is empty if none is requested.
+func getPeerMirroringDirections(mirroring *cephv1.RadosNamespaceMirroring) map[string]string {
+	if len(mirroring.Peers) == 0 {
+		return nil
+	}
+	directions := map[string]string{}
+	for _, peer := range mirroring.Peers {
+		direction := peer.Direction
+		if direction == "" {
+			direction = mirroring.Direction
+		}
+		directions[peer.SiteName] = string(direction)
+	}
+	return directions
+}
@@ -0,0 +1,385 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateMirroring(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction cephv1.RadosNamespaceMirroringDirection
+		wantErr   bool
+	}{
+		{"no direction", "", false},
+		{"rx-only", cephv1.RadosNamespaceMirroringDirectionRxOnly, false},
+		{"tx-only", cephv1.RadosNamespaceMirroringDirectionTxOnly, false},
+		{"rx-tx", cephv1.RadosNamespaceMirroringDirectionRxTx, false},
+		{"unknown direction", "tx-rx", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mirroring := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage, Direction: tt.direction}
+			err := validateMirroring(mirroring)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateMirroringPeers(t *testing.T) {
+	mirroring := &cephv1.RadosNamespaceMirroring{
+		Mode:      cephv1.RadosNamespaceMirroringModeImage,
+		Direction: cephv1.RadosNamespaceMirroringDirectionRxOnly,
+		Peers: []cephv1.RadosNamespaceMirroringPeer{
+			{SiteName: "site-b"},
+			{SiteName: "site-c", Direction: cephv1.RadosNamespaceMirroringDirectionTxOnly},
+		},
+	}
+	assert.NoError(t, validateMirroring(mirroring))
+	// the peers without a direction use the direction of the mirroring
+	assert.Equal(t, map[string]string{"site-b": "rx-only", "site-c": "tx-only"}, getPeerMirroringDirections(mirroring))
+	assert.Nil(t, getPeerMirroringDirections(&cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage}))
+	// no direction is requested for a peer if the mirroring does not set one
+	assert.Equal(t, map[string]string{"site-b": ""}, getPeerMirroringDirections(&cephv1.RadosNamespaceMirroring{
+		Mode: cephv1.RadosNamespaceMirroringModeImage, Peers: []cephv1.RadosNamespaceMirroringPeer{{SiteName: "site-b"}},
+	}))
+
+	mirroring.Peers = append(mirroring.Peers,
+		cephv1.RadosNamespaceMirroringPeer{SiteName: "site-b"},
+		cephv1.RadosNamespaceMirroringPeer{SiteName: ""},
+		cephv1.RadosNamespaceMirroringPeer{SiteName: "site-d", Direction: "tx-rx"})
+	err := validateMirroring(mirroring)
+	assert.ErrorContains(t, err, `peer site "site-b" is listed more than once`)
+	assert.ErrorContains(t, err, "the site name of a peer is empty")
+	assert.ErrorContains(t, err, `unknown mirroring direction "tx-rx" of peer site "site-d"`)
+}
+
+func TestValidateSnapshotSchedulesMode(t *testing.T) {
+	schedules := []cephv1.SnapshotScheduleSpec{{Interval: "1h"}}
+
+	t.Run("schedules are rejected in pool mode", func(t *testing.T) {
+		mirroring := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModePool, SnapshotSchedules: schedules}
+		assert.NoError(t, validateMirroring(mirroring))
+
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+		radosNamespace.Name = "namespace-a"
+		radosNamespace.Spec.Mirroring = mirroring
+		assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "snapshot schedules require the snapshot-based mirroring")
+	})
+
+	t.Run("schedules are accepted in image mode", func(t *testing.T) {
+		mirroring := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage, SnapshotSchedules: schedules}
+		assert.NoError(t, validateMirroring(mirroring))
+	})
+
+	t.Run("no schedules are accepted with any mode", func(t *testing.T) {
+		for _, mode := range []cephv1.RadosNamespaceMirroringMode{cephv1.RadosNamespaceMirroringModePool, cephv1.RadosNamespaceMirroringModeImage} {
+			assert.NoError(t, validateMirroring(&cephv1.RadosNamespaceMirroring{Mode: mode}))
+		}
+	})
+}
+
+func TestValidateImageFilter(t *testing.T) {
+	filter := &cephv1.RadosNamespaceMirroringImageFilter{Include: []string{"db-*"}, Exclude: []string{"*-tmp"}}
+
+	t.Run("the filter is accepted in image mode", func(t *testing.T) {
+		mirroring := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage, ImageFilter: filter}
+		assert.NoError(t, validateMirroring(mirroring))
+	})
+
+	t.Run("the filter is rejected in pool mode", func(t *testing.T) {
+		radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+		radosNamespace.Name = "namespace-a"
+		radosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModePool, ImageFilter: filter}
+		assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "the image filter requires the \"image\" mirroring mode")
+	})
+
+	t.Run("malformed patterns are reported", func(t *testing.T) {
+		mirroring := &cephv1.RadosNamespaceMirroring{
+			Mode:        cephv1.RadosNamespaceMirroringModeImage,
+			ImageFilter: &cephv1.RadosNamespaceMirroringImageFilter{Include: []string{"db-[", "db-*"}, Exclude: []string{"\\"}},
+		}
+		err := validateMirroring(mirroring)
+		assert.ErrorContains(t, err, "invalid image filter")
+		assert.ErrorContains(t, err, `pattern "db-[" is malformed`)
+		assert.ErrorContains(t, err, `pattern "\\" is malformed`)
+		assert.NotContains(t, err.Error(), `"db-*"`)
+	})
+}
+
+func TestValidateMirrorHealthCheckInterval(t *testing.T) {
+	newMirroring := func(interval time.Duration) *cephv1.RadosNamespaceMirroring {
+		return &cephv1.RadosNamespaceMirroring{
+			Mode:        cephv1.RadosNamespaceMirroringModeImage,
+			HealthCheck: &cephv1.RadosNamespaceMirroringHealthCheck{Interval: &metav1.Duration{Duration: interval}},
+		}
+	}
+
+	assert.NoError(t, validateMirroring(newMirroring(30*time.Second)))
+	assert.NoError(t, validateMirroring(&cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage, HealthCheck: &cephv1.RadosNamespaceMirroringHealthCheck{}}))
+	assert.ErrorContains(t, validateMirroring(newMirroring(0)), "the interval must be positive")
+	assert.ErrorContains(t, validateMirroring(newMirroring(-time.Minute)), "the interval must be positive")
+}
+
+func TestValidateApplicationMetadata(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		wantErr  bool
+	}{
+		{"no metadata", nil, false},
+		{"valid metadata", map[string]string{"team": "storage", "cost.center_1-a": "42"}, false},
+		{"key with invalid character", map[string]string{"team/name": "storage"}, true},
+		{"key starting with a dot", map[string]string{".team": "storage"}, true},
+		{"key too long", map[string]string{strings.Repeat("a", 64): "storage"}, true},
+		{"empty value", map[string]string{"team": ""}, true},
+		{"value too long", map[string]string{"team": strings.Repeat("a", 257)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radosNamespace.Spec.ApplicationMetadata = tt.metadata
+			err := validateRadosNamespace(radosNamespace)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("implicit rados namespace", func(t *testing.T) {
+		radosNamespace.Spec.Name = cephv1.ImplicitNamespaceKey
+		radosNamespace.Spec.ApplicationMetadata = map[string]string{"team": "storage"}
+		assert.Error(t, validateRadosNamespace(radosNamespace))
+	})
+}
+
+func TestValidateSnapshotSchedules(t *testing.T) {
+	t.Run("valid schedules", func(t *testing.T) {
+		schedules := []cephv1.SnapshotScheduleSpec{
+			{Interval: "1d"},
+			{Interval: "24h", StartTime: "14:00:00-05:00"},
+			{Interval: "30m", StartTime: "2020-01-01T14:00:00"},
+			{Interval: "12h", StartTime: "02:30"},
+		}
+		assert.NoError(t, validateSnapshotSchedules(schedules))
+	})
+
+	t.Run("invalid intervals", func(t *testing.T) {
+		for _, interval := range []string{"", "1hr", "h", "0h", "1.5h", "1w", "-1h", "1 h"} {
+			err := validateSnapshotSchedules([]cephv1.SnapshotScheduleSpec{{Interval: interval}})
+			assert.Error(t, err, interval)
+		}
+	})
+
+	t.Run("invalid start time", func(t *testing.T) {
+		err := validateSnapshotSchedules([]cephv1.SnapshotScheduleSpec{{Interval: "1h", StartTime: "2pm"}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `schedule 0 has invalid start time "2pm"`)
+	})
+
+	t.Run("all malformed entries are reported", func(t *testing.T) {
+		schedules := []cephv1.SnapshotScheduleSpec{
+			{Interval: "1h"},
+			{Interval: "1hr"},
+			{Interval: "1d", StartTime: "25:00"},
+		}
+		err := validateSnapshotSchedules(schedules)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `schedule 1 has invalid interval "1hr"`)
+		assert.Contains(t, err.Error(), `schedule 2 has invalid start time "25:00"`)
+		assert.NotContains(t, err.Error(), "schedule 0")
+
+		mirroring := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage, SnapshotSchedules: schedules}
+		assert.Error(t, validateMirroring(mirroring))
+	})
+}
+
+func TestValidateRadosNamespaceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		crName   string
+		specName string
+		wantErr  bool
+	}{
+		{"cr name", "namespace-a", "", false},
+		{"spec name", "namespace-a", "ns_b.1", false},
+		{"implicit namespace", "namespace-a", cephv1.ImplicitNamespaceKey, false},
+		{"single character", "a", "", false},
+		{"spec name with slash", "namespace-a", "ns/b", true},
+		{"spec name with at sign", "namespace-a", "ns@b", true},
+		{"spec name with space", "namespace-a", "ns b", true},
+		{"spec name starting with a dash", "namespace-a", "-nsb", true},
+		{"spec name ending with a dot", "namespace-a", "nsb.", true},
+		{"spec name too long", "namespace-a", strings.Repeat("a", 254), true},
+		{"spec name with max length", "namespace-a", strings.Repeat("a", 253), false},
+		{"cr name with max length", strings.Repeat("a", 253), "", false},
+		{"cr name with invalid character", "namespace:a", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+			radosNamespace.Name = tt.crName
+			radosNamespace.Spec.Name = tt.specName
+			err := validateRadosNamespaceName(radosNamespace)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, validateRadosNamespace(radosNamespace))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRBDMapOptions(t *testing.T) {
+	for _, options := range []string{"", "force", "lock_on_read,queue_depth=1024", "read_from_replica=localize,ms_mode=secure"} {
+		assert.NoError(t, validateRBDMapOptions(options), options)
+	}
+	for _, options := range []string{",", "force,", "queue_depth=", "queue depth=1", "a=b=c", "Force"} {
+		assert.Error(t, validateRBDMapOptions(options), options)
+	}
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+	radosNamespace.Spec.UnmapOptions = "force,"
+	assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "invalid unmap options")
+}
+
+func TestValidateClusterID(t *testing.T) {
+	for _, clusterID := range []string{"", "a", "site-a_tenant.1", "123456789012345678901234567890123456"} {
+		assert.NoError(t, validateClusterID(clusterID), clusterID)
+	}
+	for _, clusterID := range []string{"-a", "a-", "a/b", "tenant 1", "1234567890123456789012345678901234567"} {
+		assert.Error(t, validateClusterID(clusterID), clusterID)
+	}
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+	radosNamespace.Spec.ClusterID = "a/b"
+	assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "invalid cluster ID")
+}
+
+func TestValidateMonitorEndpoints(t *testing.T) {
+	assert.NoError(t, validateMonitorEndpoints(nil))
+	assert.NoError(t, validateMonitorEndpoints([]string{"10.0.0.1:6789", "[fd00::1]:3300", "mon-a.tenant-a.svc:3300"}))
+	for _, endpoint := range []string{"10.0.0.1", ":6789", "10.0.0.1:", "10.0.0.1:mon", "10.0.0.1:0", "10.0.0.1:65536", "fd00::1:3300"} {
+		assert.Error(t, validateMonitorEndpoints([]string{endpoint}), endpoint)
+	}
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+	radosNamespace.Spec.External = &cephv1.RadosNamespaceExternalSpec{Monitors: []string{"10.0.0.1:6789", "10.0.0.2"}}
+	err := validateRadosNamespace(radosNamespace)
+	assert.ErrorContains(t, err, "invalid external monitors")
+	assert.ErrorContains(t, err, `"10.0.0.2"`)
+	assert.NotContains(t, err.Error(), `"10.0.0.1:6789"`)
+}
+
+func TestValidateExternalMonitorsOverride(t *testing.T) {
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+	cephCluster := &cephv1.CephCluster{}
+	cephCluster.Name = "rook-ceph"
+	assert.NoError(t, validateExternalMonitorsOverride(radosNamespace, cephCluster))
+
+	radosNamespace.Spec.External = &cephv1.RadosNamespaceExternalSpec{Monitors: []string{"10.0.0.1:6789"}}
+	assert.Error(t, validateExternalMonitorsOverride(radosNamespace, cephCluster))
+
+	cephCluster.Spec.External.Enable = true
+	assert.NoError(t, validateExternalMonitorsOverride(radosNamespace, cephCluster))
+}
+
+func TestValidateCompression(t *testing.T) {
+	for _, compression := range []cephv1.RadosNamespaceCompression{
+		{},
+		{Hint: "none"},
+		{Hint: "compressible"},
+		{Hint: "incompressible"},
+	} {
+		assert.NoError(t, validateCompression(&compression), compression)
+	}
+
+	assert.ErrorContains(t, validateCompression(&cephv1.RadosNamespaceCompression{Hint: "aggressive"}), `unknown compression hint "aggressive"`)
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+	radosNamespace.Spec.Compression = &cephv1.RadosNamespaceCompression{Hint: "always"}
+	assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "invalid compression settings")
+
+	radosNamespace.Spec.Name = cephv1.ImplicitNamespaceKey
+	radosNamespace.Spec.Compression = &cephv1.RadosNamespaceCompression{Hint: "compressible"}
+	assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "not supported for the implicit rados namespace")
+}
+
+func TestValidatePoolMirroringSupport(t *testing.T) {
+	replicatedPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool"}}
+	replicatedPool.Spec.Replicated.Size = 3
+	ecPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "ecpool"}}
+	ecPool.Spec.ErasureCoded.DataChunks = 2
+	ecPool.Spec.ErasureCoded.CodingChunks = 1
+
+	poolMode := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModePool}
+	imageMode := &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage}
+
+	assert.NoError(t, validatePoolMirroringSupport(nil, ecPool))
+	assert.NoError(t, validatePoolMirroringSupport(poolMode, replicatedPool))
+	assert.NoError(t, validatePoolMirroringSupport(imageMode, replicatedPool))
+	assert.NoError(t, validatePoolMirroringSupport(imageMode, ecPool))
+
+	err := validatePoolMirroringSupport(poolMode, ecPool)
+	var unsupportedErr *PoolMirroringUnsupportedError
+	assert.ErrorAs(t, err, &unsupportedErr)
+	assert.Equal(t, "ecpool", unsupportedErr.PoolName)
+	assert.Contains(t, err.Error(), "requires a replicated pool")
+}
+
+func TestValidatePostCreateConfig(t *testing.T) {
+	assert.NoError(t, validatePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{
+		{Key: "rbd_qos_iops_limit", Value: "1000"},
+		{Key: "rbd_default_features", Value: "layering,exclusive-lock"},
+	}))
+
+	err := validatePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{{Key: "rbd_compression_hint", Value: "compressible"}})
+	assert.ErrorContains(t, err, `config key "rbd_compression_hint" is not allowed`)
+	err = validatePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{{Key: "rbd_qos_iops_limit"}})
+	assert.ErrorContains(t, err, `empty value for config key "rbd_qos_iops_limit"`)
+	err = validatePostCreateConfig([]cephv1.RadosNamespaceConfigEntry{
+		{Key: "rbd_qos_iops_limit", Value: "1000"},
+		{Key: "rbd_qos_iops_limit", Value: "2000"},
+	})
+	assert.ErrorContains(t, err, `duplicate config key "rbd_qos_iops_limit"`)
+
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	radosNamespace.Name = "namespace-a"
+	radosNamespace.Spec.PostCreateConfig = []cephv1.RadosNamespaceConfigEntry{{Key: "osd_pool_default_size", Value: "1"}}
+	assert.ErrorContains(t, validateRadosNamespace(radosNamespace), "invalid post create config")
+}
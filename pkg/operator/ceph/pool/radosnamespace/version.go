@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// maxCephVersionFetchFailures is the number of consecutive failures to fetch the ceph version
+	// after which the rados namespace is reported as progressing
+	maxCephVersionFetchFailures = 5
+	cephVersionFetchBaseDelay   = 5 * time.Second
+	cephVersionFetchMaxDelay    = 5 * time.Minute
+)
+
+// cephVersionTracker tracks the consecutive failures to fetch the ceph version of each rados
+// namespace and the last known ceph version of each cluster
+type cephVersionTracker struct {
+	failures  map[types.NamespacedName]int
+	lastKnown map[string]cephver.CephVersion
+}
+
+func (t *cephVersionTracker) recordFailure(name types.NamespacedName) int {
+	if t.failures == nil {
+		t.failures = map[types.NamespacedName]int{}
+	}
+	t.failures[name]++
+	return t.failures[name]
+}
+
+func (t *cephVersionTracker) recordSuccess(name types.NamespacedName, clusterNamespace string, version cephver.CephVersion) int {
+	if t.lastKnown == nil {
+		t.lastKnown = map[string]cephver.CephVersion{}
+	}
+	t.lastKnown[clusterNamespace] = version
+	failures := t.failures[name]
+	delete(t.failures, name)
+	return failures
+}
+
+// cephVersionFetchBackoff returns the delay before the next attempt to fetch the ceph version,
+// doubling with each failure up to cephVersionFetchMaxDelay
+func cephVersionFetchBackoff(failures int) time.Duration {
+	delay := cephVersionFetchBaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= cephVersionFetchMaxDelay {
+			return cephVersionFetchMaxDelay
+		}
+	}
+	return delay
+}
+
+// loadCephVersion sets the ceph version of the cluster in the clusterInfo. If the version cannot be
+// fetched, the last known version of the cluster is used. Without a known version, the reconcile is
+// retried with a backoff and the rados namespace is reported as progressing after repeated failures.
+func (r *ReconcileCephBlockPoolRadosNamespace) loadCephVersion(cephCluster *cephv1.CephCluster, name types.NamespacedName, log *reconcileLogger) (reconcile.Result, error) {
+	cephVersion, err := opcontroller.GetImageVersion(*cephCluster)
+	if err == nil {
+		if cephVersion == nil {
+			return reconcile.Result{}, nil
+		}
+		r.clusterInfo.CephVersion = *cephVersion
+		if failures := r.cephVersions.recordSuccess(name, cephCluster.Namespace, *cephVersion); failures >= maxCephVersionFetchFailures {
+			r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+				Type:    cephv1.ConditionProgressing,
+				Status:  v1.ConditionFalse,
+				Reason:  cephv1.CephVersionDetectedReason,
+				Message: fmt.Sprintf("detected ceph version %q of cephcluster %q", cephVersion.String(), cephCluster.Name),
+			})
+		}
+		return reconcile.Result{}, nil
+	}
+
+	failures := r.cephVersions.recordFailure(name)
+	if lastKnown, ok := r.cephVersions.lastKnown[cephCluster.Namespace]; ok {
+		log.Warningf("failed to fetch ceph version from cephcluster %q, using the last known version %q. %v", cephCluster.Name, lastKnown.String(), err)
+		r.clusterInfo.CephVersion = lastKnown
+		return reconcile.Result{}, nil
+	}
+
+	if failures >= maxCephVersionFetchFailures {
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, name, cephv1.ConditionProgressing, log, cephv1.Condition{
+			Type:    cephv1.ConditionProgressing,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.CephVersionUnknownReason,
+			Message: fmt.Sprintf("waiting for the ceph version of cephcluster %q to be detected after %d attempts", cephCluster.Name, failures),
+		})
+	}
+
+	res := reconcile.Result{Requeue: true, RequeueAfter: cephVersionFetchBackoff(failures)}
+	return res, errors.Wrapf(err, "failed to fetch ceph version from cephcluster %q running in namespace %q", cephCluster.Name, cephCluster.Namespace)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCephVersionFetchBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, cephVersionFetchBackoff(1))
+	assert.Equal(t, 10*time.Second, cephVersionFetchBackoff(2))
+	assert.Equal(t, 40*time.Second, cephVersionFetchBackoff(4))
+	assert.Equal(t, cephVersionFetchMaxDelay, cephVersionFetchBackoff(10))
+	assert.Equal(t, cephVersionFetchMaxDelay, cephVersionFetchBackoff(100))
+}
+
+func TestLoadCephVersion(t *testing.T) {
+	name := types.NamespacedName{Namespace: "rook-ceph", Name: "namespace-a"}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec:       cephv1.CephBlockPoolRadosNamespaceSpec{BlockPoolName: "replicapool"},
+	}
+	// the version of the current image has not been detected yet
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: name.Namespace},
+		Spec:       cephv1.ClusterSpec{CephVersion: cephv1.CephVersionSpec{Image: "ceph/ceph:v20.0.0"}},
+		Status: cephv1.ClusterStatus{
+			CephVersion: &cephv1.ClusterVersion{Image: "ceph/ceph:v19.2.0", Version: "19.2.0-0"},
+		},
+	}
+
+	s := scheme.Scheme
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           cl,
+		scheme:           s,
+		opManagerContext: context.TODO(),
+		clusterInfo:      &cephclient.ClusterInfo{},
+	}
+	log := newReconcileLogger(name)
+
+	t.Run("backoff grows until the rados namespace is progressing", func(t *testing.T) {
+		for i := 1; i < maxCephVersionFetchFailures; i++ {
+			res, err := r.loadCephVersion(cephCluster, name, log)
+			assert.Error(t, err)
+			assert.Equal(t, cephVersionFetchBackoff(i), res.RequeueAfter)
+		}
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(context.TODO(), name, current))
+		assert.Nil(t, current.Status)
+
+		res, err := r.loadCephVersion(cephCluster, name, log)
+		assert.Error(t, err)
+		assert.Equal(t, cephVersionFetchBackoff(maxCephVersionFetchFailures), res.RequeueAfter)
+		assert.NoError(t, cl.Get(context.TODO(), name, current))
+		assert.Equal(t, cephv1.ConditionProgressing, current.Status.Phase)
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.NotNil(t, condition)
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.CephVersionUnknownReason, condition.Reason)
+	})
+
+	t.Run("detected version resets the failures", func(t *testing.T) {
+		cephCluster.Status.CephVersion = &cephv1.ClusterVersion{Image: "ceph/ceph:v20.0.0", Version: "20.0.0-0"}
+		res, err := r.loadCephVersion(cephCluster, name, log)
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Equal(t, 20, r.clusterInfo.CephVersion.Major)
+		assert.Empty(t, r.cephVersions.failures)
+
+		current := &cephv1.CephBlockPoolRadosNamespace{}
+		assert.NoError(t, cl.Get(context.TODO(), name, current))
+		condition := cephv1.FindStatusCondition(current.Status.Conditions, cephv1.ConditionProgressing)
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.CephVersionDetectedReason, condition.Reason)
+	})
+
+	t.Run("last known version is used when the version cannot be fetched", func(t *testing.T) {
+		cephCluster.Spec.CephVersion.Image = "ceph/ceph:v20.1.0"
+		r.clusterInfo.CephVersion = cephver.CephVersion{}
+		res, err := r.loadCephVersion(cephCluster, name, log)
+		assert.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Equal(t, 20, r.clusterInfo.CephVersion.Major)
+	})
+}
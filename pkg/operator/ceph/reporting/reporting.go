@@ -100,6 +100,13 @@ func copyObject(obj client.Object) client.Object {
 	return nonNilCopy
 }
 
+// ReconcileLogger logs the result of a reconcile. It is implemented by the package loggers as well as by the
+// loggers of the controllers that prefix the messages of a single reconcile.
+type ReconcileLogger interface {
+	Debugf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
 // ReportReconcileResult will report the result of an object's reconcile in 2 ways:
 // 1. to the given logger
 // 2. as an event on the object (via the given event recorder)
@@ -112,7 +119,7 @@ func copyObject(obj client.Object) client.Object {
 // The function is designed to return the appropriate values needed for the controller-runtime
 // framework's Reconcile() method.
 func ReportReconcileResult(
-	logger *capnslog.PackageLogger,
+	logger ReconcileLogger,
 	recorder record.EventRecorder,
 	reconcileRequest reconcile.Request,
 	obj client.Object,
@@ -158,13 +165,13 @@ func ReportReconcileResult(
 		}
 	} else if reconcileResponse.Requeue {
 		msg := fmt.Sprintf("requeuing %s %q", kind, nsName)
-		logger.Debug(msg)
+		logger.Debugf("%s", msg)
 		recorder.Event(objCopy, corev1.EventTypeNormal, string(cephv1.ReconcileRequeuing), msg)
 	} else {
 		successMsg := fmt.Sprintf("successfully configured %s %q", kind, nsName)
 
 		// 1. log
-		logger.Debug(successMsg)
+		logger.Debugf("%s", successMsg)
 
 		// 2. event
 		recorder.Event(objCopy, corev1.EventTypeNormal, string(cephv1.ReconcileSucceeded), successMsg)
@@ -70,6 +70,21 @@ func DeletionBlockedDueToNonEmptyRadosNSCondition(blocked bool, message string)
 	}
 }
 
+func DeletionBlockedDueToMirrorPrimaryCondition(blocked bool, message string) cephv1.Condition {
+	status := corev1.ConditionFalse
+	reason := cephv1.RadosNamespaceNotMirrorPrimaryReason
+	if blocked {
+		status = corev1.ConditionTrue
+		reason = cephv1.RadosNamespaceMirrorPrimaryReason
+	}
+	return cephv1.Condition{
+		Type:    cephv1.ConditionDeletionBlockedMirrorPrimary,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
 // A DependentList represents a list of dependents of a resource. Each dependent has a plural Kind
 // and a list of names of dependent resources.
 type DependentList struct {
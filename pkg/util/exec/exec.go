@@ -19,6 +19,7 @@ package exec
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -49,6 +50,12 @@ type Executor interface {
 	ExecuteCommandWithStdin(timeout time.Duration, command string, stdin *string, arg ...string) error
 }
 
+// ContextExecutor is implemented by the executors that can kill a command when its context is done
+type ContextExecutor interface {
+	ExecuteCommandWithOutputContext(ctx context.Context, command string, arg ...string) (string, error)
+	ExecuteCommandWithCombinedOutputContext(ctx context.Context, command string, arg ...string) (string, error)
+}
+
 // CommandExecutor is the type of the Executor
 type CommandExecutor struct{}
 
@@ -166,6 +173,31 @@ func (*CommandExecutor) ExecuteCommandWithCombinedOutput(command string, arg ...
 	return runCommandWithOutput(cmd, true)
 }
 
+// ExecuteCommandWithOutputContext executes a command with output and kills it when the context is done
+func (*CommandExecutor) ExecuteCommandWithOutputContext(ctx context.Context, command string, arg ...string) (string, error) {
+	logCommand(command, arg...)
+	//nolint:gosec // Rook controls the input to the exec arguments
+	cmd := exec.CommandContext(ctx, command, arg...)
+	return runCommandWithOutputContext(ctx, cmd, false)
+}
+
+// ExecuteCommandWithCombinedOutputContext executes a command with combined output and kills it when the context is done
+func (*CommandExecutor) ExecuteCommandWithCombinedOutputContext(ctx context.Context, command string, arg ...string) (string, error) {
+	logCommand(command, arg...)
+	//nolint:gosec // Rook controls the input to the exec arguments
+	cmd := exec.CommandContext(ctx, command, arg...)
+	return runCommandWithOutputContext(ctx, cmd, true)
+}
+
+func runCommandWithOutputContext(ctx context.Context, cmd *exec.Cmd, combinedOutput bool) (string, error) {
+	output, err := runCommandWithOutput(cmd, combinedOutput)
+	if err != nil && ctx.Err() != nil {
+		// the process was killed because the context is done
+		return output, fmt.Errorf("%s the command %s to return: %w", TimeoutWaitingForMessage, cmd.Path, ctx.Err())
+	}
+	return output, err
+}
+
 func startCommand(env []string, command string, arg ...string) (*exec.Cmd, io.ReadCloser, io.ReadCloser, error) {
 	logCommand(command, arg...)
 
@@ -17,6 +17,7 @@ limitations under the License.
 package exec
 
 import (
+	"context"
 	"os/exec"
 	"testing"
 	"time"
@@ -202,3 +203,34 @@ func TestExecuteCommandWithTimeout(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteCommandWithOutputContext(t *testing.T) {
+	executor := &CommandExecutor{}
+
+	t.Run("the output is returned", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+		defer cancel()
+		got, err := executor.ExecuteCommandWithOutputContext(ctx, "echo", "hello")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", got)
+	})
+
+	t.Run("the command is killed at the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := executor.ExecuteCommandWithCombinedOutputContext(ctx, "sleep", "10")
+		assert.Error(t, err)
+		assert.True(t, IsTimeout(err))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("a failed command is not a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+		defer cancel()
+		_, err := executor.ExecuteCommandWithOutputContext(ctx, "false")
+		assert.Error(t, err)
+		assert.False(t, IsTimeout(err))
+	})
+}